@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// APIKeyHeader is the header carrying an API key. Keys are also accepted
+	// as a Bearer token, told apart from JWTs by apiKeyPrefix.
+	APIKeyHeader = "X-API-Key"
+	apiKeyPrefix = "wuk_"
+	// the last use of a key is written at most once per interval
+	apiKeyLastUsedInterval = time.Minute
+)
+
+// CreateAPIKey issues an API key for the service account. The key is
+// returned only here, the webui DB holds its hash.
+func CreateAPIKey(username string, params configmodels.CreateAPIKeyParams) (*configmodels.DBAPIKey, string, error) {
+	id, err := randomToken(12)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate API key ID: %w", err)
+	}
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := apiKeyPrefix + secret
+	apiKey := &configmodels.DBAPIKey{
+		ID:          id,
+		Name:        params.Name,
+		Username:    username,
+		KeyHash:     hashToken(key),
+		Permissions: params.Permissions,
+		CreatedAt:   time.Now().Unix(),
+	}
+	if params.ExpiresAt != nil {
+		apiKey.ExpiresAt = params.ExpiresAt.Unix()
+	}
+	filter := bson.M{"id": id}
+	if _, err = dbadapter.WebuiDBClient.RestfulAPIPost(configmodels.APIKeyDataColl, filter, configmodels.ToBsonM(apiKey)); err != nil {
+		return nil, "", fmt.Errorf("failed to store API key: %w", err)
+	}
+	return apiKey, key, nil
+}
+
+// RevokeAPIKeys deletes every API key of the account.
+func RevokeAPIKeys(username string) error {
+	return dbadapter.WebuiDBClient.RestfulAPIDeleteMany(configmodels.APIKeyDataColl, bson.M{"username": username})
+}
+
+func fetchAPIKeyByKey(key string) (*configmodels.DBAPIKey, error) {
+	rawAPIKey, err := dbadapter.WebuiDBClient.RestfulAPIGetOne(configmodels.APIKeyDataColl, bson.M{"keyHash": hashToken(key)})
+	if err != nil {
+		return nil, err
+	}
+	if len(rawAPIKey) == 0 {
+		return nil, nil
+	}
+	var apiKey configmodels.DBAPIKey
+	if err = json.Unmarshal(configmodels.MapToByte(rawAPIKey), &apiKey); err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+func fetchServiceAccount(username string) (*configmodels.DBUserAccount, error) {
+	rawUserAccount, err := dbadapter.WebuiDBClient.RestfulAPIGetOne(configmodels.UserAccountDataColl, bson.M{"username": username})
+	if err != nil {
+		return nil, err
+	}
+	if len(rawUserAccount) == 0 {
+		return nil, nil
+	}
+	var dbUser configmodels.DBUserAccount
+	if err = json.Unmarshal(configmodels.MapToByte(rawUserAccount), &dbUser); err != nil {
+		return nil, err
+	}
+	if !dbUser.ServiceAccount {
+		return nil, nil
+	}
+	return &dbUser, nil
+}
+
+// getClaimsFromAPIKey authenticates an API key as its service account, with
+// the permissions of the key as scope.
+func getClaimsFromAPIKey(key string) (*jwtWebconsoleClaims, error) {
+	apiKey, err := fetchAPIKeyByKey(key)
+	if err != nil {
+		logger.AuthLog.Errorf("failed to retrieve API key: %v", err)
+		return nil, fmt.Errorf("API key could not be checked")
+	}
+	if apiKey == nil {
+		return nil, fmt.Errorf("API key is not valid")
+	}
+	now := time.Now()
+	if apiKey.ExpiresAt != 0 && apiKey.ExpiresAt <= now.Unix() {
+		return nil, fmt.Errorf("API key has expired")
+	}
+	dbUser, err := fetchServiceAccount(apiKey.Username)
+	if err != nil {
+		logger.AuthLog.Errorf("failed to retrieve service account: %v", err)
+		return nil, fmt.Errorf("API key could not be checked")
+	}
+	if dbUser == nil {
+		return nil, fmt.Errorf("API key is not valid")
+	}
+	if now.Unix()-apiKey.LastUsedAt >= int64(apiKeyLastUsedInterval.Seconds()) {
+		// only the field is set, so that a key revoked meanwhile is not
+		// written back
+		filter := bson.M{"id": apiKey.ID}
+		if _, err = dbadapter.WebuiDBClient.RestfulAPIUpdateOne(configmodels.APIKeyDataColl, filter, bson.M{"lastUsedAt": now.Unix()}); err != nil {
+			logger.AuthLog.Warnf("failed to record the use of API key %s: %v", apiKey.ID, err)
+		}
+	}
+	claims := &jwtWebconsoleClaims{
+		Username: dbUser.Username,
+		Role:     dbUser.Role,
+		RoleName: dbUser.RoleName,
+	}
+	if len(apiKey.Permissions) > 0 {
+		claims.scope = apiKey.Permissions
+	}
+	return claims, nil
+}
+
+// getClaimsFromRequest authenticates the API key or the JWT of the request.
+func getClaimsFromRequest(req *http.Request, signingKeys *SigningKeys) (*jwtWebconsoleClaims, error) {
+	if key := req.Header.Get(APIKeyHeader); key != "" {
+		return getClaimsFromAPIKey(key)
+	}
+	header := req.Header.Get("Authorization")
+	if key, found := strings.CutPrefix(header, "Bearer "+apiKeyPrefix); found {
+		return getClaimsFromAPIKey(apiKeyPrefix + key)
+	}
+	return getClaimsFromAuthorizationHeader(header, signingKeys)
+}
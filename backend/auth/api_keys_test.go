@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func setUpAPIKeyRouter(t *testing.T) (*gin.Engine, *MockMongoClientSessions) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	signingKeys := NewSigningKeys([]byte("mockSecret"))
+	router.GET("/network", AdminOrUserAuthMiddleware(signingKeys), RequirePermission(configmodels.PermissionNetworkRead), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+	router.GET("/k4", AdminOrUserAuthMiddleware(signingKeys), RequirePermission(configmodels.PermissionK4Read), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+	mockDB := newMockMongoClientSessions()
+	mockDB.collections[configmodels.UserAccountDataColl] = append(mockDB.collections[configmodels.UserAccountDataColl],
+		map[string]any{"username": "ci", "role": float64(configmodels.UserRole), "roleName": "network-planner", "serviceAccount": true},
+		map[string]any{"username": "johndoe", "role": float64(configmodels.UserRole)},
+	)
+	dbadapter.WebuiDBClient = mockDB
+	if err := SeedBuiltinRoles(); err != nil {
+		t.Fatalf("failed to seed roles: %v", err)
+	}
+	return router, mockDB
+}
+
+func createAPIKey(t *testing.T, username string, params configmodels.CreateAPIKeyParams) string {
+	t.Helper()
+	_, key, err := CreateAPIKey(username, params)
+	if err != nil {
+		t.Fatalf("failed to create API key: %v", err)
+	}
+	return key
+}
+
+func getWithHeader(t *testing.T, router *gin.Engine, path, header, value string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set(header, value)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	router, mockDB := setUpAPIKeyRouter(t)
+	key := createAPIKey(t, "ci", configmodels.CreateAPIKeyParams{Name: "pipeline"})
+
+	if code := getWithHeader(t, router, "/network", APIKeyHeader, key); code != http.StatusOK {
+		t.Errorf("expected the key to be accepted in %s, got %v", APIKeyHeader, code)
+	}
+	if code := getWithHeader(t, router, "/network", "Authorization", "Bearer "+key); code != http.StatusOK {
+		t.Errorf("expected the key to be accepted as a Bearer token, got %v", code)
+	}
+	if code := getWithHeader(t, router, "/k4", APIKeyHeader, key); code != http.StatusForbidden {
+		t.Errorf("expected the role of the service account to apply, got %v", code)
+	}
+	apiKey, err := fetchAPIKeyByKey(key)
+	if err != nil || apiKey == nil || apiKey.LastUsedAt == 0 {
+		t.Errorf("expected the last use to be recorded, got %+v %v", apiKey, err)
+	}
+
+	if err = RevokeAPIKeys("ci"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code := getWithHeader(t, router, "/network", APIKeyHeader, key); code != http.StatusUnauthorized {
+		t.Errorf("expected a revoked key to be rejected, got %v", code)
+	}
+	if len(mockDB.collections[configmodels.APIKeyDataColl]) != 0 {
+		t.Errorf("expected the keys to be deleted")
+	}
+}
+
+// revokingMockMongoClient revokes the API keys right after they are read, as
+// a concurrent revocation would.
+type revokingMockMongoClient struct {
+	*MockMongoClientSessions
+}
+
+func (db *revokingMockMongoClient) RestfulAPIGetOne(collName string, filter bson.M) (map[string]any, error) {
+	document, err := db.MockMongoClientSessions.RestfulAPIGetOne(collName, filter)
+	if collName == configmodels.APIKeyDataColl {
+		db.collections[collName] = nil
+	}
+	return document, err
+}
+
+func TestAPIKeyAuthentication_RevokedWhileInUse(t *testing.T) {
+	router, mockDB := setUpAPIKeyRouter(t)
+	key := createAPIKey(t, "ci", configmodels.CreateAPIKeyParams{Name: "pipeline"})
+	dbadapter.WebuiDBClient = &revokingMockMongoClient{mockDB}
+
+	getWithHeader(t, router, "/network", APIKeyHeader, key)
+	if keys := mockDB.collections[configmodels.APIKeyDataColl]; len(keys) != 0 {
+		t.Errorf("expected the revoked key not to be written back, got %+v", keys)
+	}
+}
+
+func TestAPIKeyAuthentication_FailureCases(t *testing.T) {
+	testCases := []struct {
+		name         string
+		username     string
+		params       configmodels.CreateAPIKeyParams
+		key          func(key string) string
+		path         string
+		expectedCode int
+	}{
+		{
+			name:         "UnknownKey",
+			username:     "ci",
+			key:          func(string) string { return apiKeyPrefix + "unknown" },
+			path:         "/network",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "ExpiredKey",
+			username:     "ci",
+			params:       configmodels.CreateAPIKeyParams{Name: "pipeline", ExpiresAt: func() *time.Time { t := time.Now().Add(-time.Minute); return &t }()},
+			path:         "/network",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "KeyOfRegularAccount",
+			username:     "johndoe",
+			path:         "/network",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "KeyOfDeletedAccount",
+			username:     "deleted",
+			path:         "/network",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "PermissionOutOfScope",
+			username:     "ci",
+			params:       configmodels.CreateAPIKeyParams{Name: "pipeline", Permissions: []string{configmodels.PermissionSubscribersRead}},
+			path:         "/network",
+			expectedCode: http.StatusForbidden,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, _ := setUpAPIKeyRouter(t)
+			key := createAPIKey(t, tc.username, tc.params)
+			if tc.key != nil {
+				key = tc.key(key)
+			}
+			if code := getWithHeader(t, router, tc.path, APIKeyHeader, key); code != tc.expectedCode {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedCode, code)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// adminRoleMapping is the role of a RoleMapping giving AdminRole.
+const adminRoleMapping = "admin"
+
+var (
+	errNoRoleMapping   = errors.New("no role is mapped to the groups of the user")
+	errAccountConflict = errors.New("a local account has the same username")
+)
+
+// mapGroupsToRole returns the role and role name of the first mapping
+// matching one of the groups, or of the default role.
+func mapGroupsToRole(mappings []factory.RoleMapping, defaultRole string, groups []string) (int, string, error) {
+	roleName := defaultRole
+	for _, mapping := range mappings {
+		if slices.Contains(groups, mapping.Group) {
+			roleName = mapping.Role
+			break
+		}
+	}
+	switch roleName {
+	case "":
+		return 0, "", errNoRoleMapping
+	case adminRoleMapping:
+		return configmodels.AdminRole, "", nil
+	}
+	role, err := FetchRole(roleName)
+	if err != nil {
+		return 0, "", err
+	}
+	if role == nil {
+		return 0, "", fmt.Errorf("role %s mapped to the groups of the user does not exist", roleName)
+	}
+	return configmodels.UserRole, roleName, nil
+}
+
+// upsertExternalAccount creates or updates the shadow account of a user
+// authenticated by an identity provider, so that the role given by the
+// provider applies to the account API and the refreshed tokens. Local
+// accounts are never taken over.
+func upsertExternalAccount(username, displayName, identityProvider string, role int, roleName string) (*configmodels.DBUserAccount, error) {
+	filter := bson.M{"username": username}
+	rawUserAccount, err := dbadapter.WebuiDBClient.RestfulAPIGetOne(configmodels.UserAccountDataColl, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user account: %w", err)
+	}
+	if len(rawUserAccount) != 0 {
+		var existing configmodels.DBUserAccount
+		if err = json.Unmarshal(configmodels.MapToByte(rawUserAccount), &existing); err != nil {
+			return nil, fmt.Errorf("failed to retrieve user account: %w", err)
+		}
+		if existing.IdentityProvider != identityProvider {
+			return nil, errAccountConflict
+		}
+	}
+	dbUser := &configmodels.DBUserAccount{
+		Username:         username,
+		DisplayName:      displayName,
+		Role:             role,
+		RoleName:         roleName,
+		IdentityProvider: identityProvider,
+	}
+	update := configmodels.ToBsonM(dbUser)
+	// the fields are set, so the role name of a user becoming admin is cleared
+	update["roleName"] = roleName
+	if _, err = dbadapter.WebuiDBClient.RestfulAPIPost(configmodels.UserAccountDataColl, filter, update); err != nil {
+		return nil, fmt.Errorf("failed to store user account: %w", err)
+	}
+	return dbUser, nil
+}
+
+// CountLocalUserAccounts returns the number of accounts which are not the
+// shadow account of an identity provider user. Only these accounts make the
+// webui initialized, so the first local account is still the admin even if
+// a mapped admin group logged in before.
+func CountLocalUserAccounts() (int64, error) {
+	filter := bson.M{"identityProvider": bson.M{"$exists": false}}
+	return dbadapter.WebuiDBClient.RestfulAPICount(configmodels.UserAccountDataColl, filter)
+}
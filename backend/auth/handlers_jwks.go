@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSResponse struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// GetJWKS godoc
+//
+// @Description  Get the public keys the tokens are signed with, to verify them without calling Webui. Empty with HS256 keys. Only available if enableAuthentication is enabled.
+// @Tags         Auth
+// @Success      200  {object}  JWKSResponse  "JSON Web Key Set"
+// @Failure      404  {object}  nil           "Page not found if enableAuthentication is disabled"
+// @Router       /.well-known/jwks.json  [get]
+func GetJWKS(signingKeys *SigningKeys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, JWKSResponse{Keys: signingKeys.publicKeys()})
+	}
+}
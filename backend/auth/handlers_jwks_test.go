@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/factory"
+)
+
+func getJWKS(t *testing.T, signingKeys *SigningKeys) JWKSResponse {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	AddAuthenticationService(router, signingKeys, nil, nil)
+	req, err := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected `%v`, got `%v`", http.StatusOK, w.Code)
+	}
+	var response JWKSResponse
+	if err = json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unable to unmarshal response `%v`", w.Body.String())
+	}
+	return response
+}
+
+func TestJWKS(t *testing.T) {
+	keys, err := LoadSigningKeys(&factory.JwtKeys{
+		Key:                 &factory.JwtKey{Algorithm: "ES256", File: ecKeyFile(t, elliptic.P256())},
+		PreviousKey:         &factory.JwtKey{Algorithm: "RS256", File: rsaKeyFile(t)},
+		PreviousKeyRetireAt: time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	response := getJWKS(t, keys)
+	if len(response.Keys) != 2 {
+		t.Fatalf("expected the current and previous keys, got %+v", response.Keys)
+	}
+	current, previous := response.Keys[0], response.Keys[1]
+	if current.Kid != keys.current.kid || current.Kty != "EC" || current.Alg != "ES256" || current.Crv != "P-256" || current.X == "" || current.Y == "" {
+		t.Errorf("unexpected current key %+v", current)
+	}
+	if previous.Kid != keys.previous.kid || previous.Kty != "RSA" || previous.Alg != "RS256" || previous.N == "" || previous.E != "AQAB" {
+		t.Errorf("unexpected previous key %+v", previous)
+	}
+}
+
+func TestJWKS_HMACKeysNotPublished(t *testing.T) {
+	response := getJWKS(t, NewSigningKeys([]byte("mockSecret")))
+	if response.Keys == nil || len(response.Keys) != 0 {
+		t.Errorf("expected an empty key set, got %+v", response.Keys)
+	}
+}
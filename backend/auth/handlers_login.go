@@ -5,7 +5,10 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,12 +21,17 @@ import (
 )
 
 const (
+	errorAccountLocked        = "the account is locked after too many failed log in attempts. Try again later"
 	errorIncorrectCredentials = "incorrect username or password. Try again"
 	errorInvalidDataProvided  = "invalid data provided"
+	errorLDAPUnavailable      = "the directory is not available"
+	errorLocalAccountConflict = "a local user account has the same username"
 	errorLogin                = "failed to log in"
 	errorMissingPassword      = "password is required"
 	errorMissingUsername      = "username is required"
+	errorNoMappedRole         = "forbidden: no role is mapped to the groups of the user"
 	errorRetrieveUserAccount  = "failed to retrieve user account"
+	errorTooManyLoginAttempts = "too many failed log in attempts. Try again later"
 )
 
 type LoginParams struct {
@@ -32,21 +40,25 @@ type LoginParams struct {
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
 }
 
 // LoginPost godoc
 //
-// @Description  Log in. Only available if enableAuthentication is enabled.
+// @Description  Log in. Only available if enableAuthentication is enabled. If ldap is enabled, the users of the directory log in with their directory password, and local accounts with their local password. After a failed attempt, the next attempts of the username and of the client are delayed, and the account is locked after too many failures.
 // @Tags         Auth
 // @Param        loginParams    body    LoginParams    true    " "
 // @Success      200  {object}  LoginResponse  "Authorization token"
 // @Failure      400  {object}  nil            "Bad request"
 // @Failure      401  {object}  nil            "Authentication failed"
+// @Failure      403  {object}  nil            "No role mapped to the directory groups of the user"
 // @Failure      404  {object}  nil            "Page not found if enableAuthentication is disabled"
+// @Failure      429  {object}  nil            "Too many failed attempts or account locked, retry after the Retry-After header"
 // @Failure      500  {object}  nil            "Internal server error"
+// @Failure      502  {object}  nil            "Directory not available"
 // @Router       /login  [post]
-func Login(jwtSecret []byte) gin.HandlerFunc {
+func Login(signingKeys *SigningKeys, ldapAuthenticator *LDAPAuthenticator, loginThrottle *LoginThrottle) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var loginParams LoginParams
 		err := c.ShouldBindJSON(&loginParams)
@@ -64,52 +76,156 @@ func Login(jwtSecret []byte) gin.HandlerFunc {
 			return
 		}
 
+		now := time.Now()
+		if retryAfter := loginThrottle.clientRetryAfter(c.ClientIP(), now); retryAfter > 0 {
+			tooManyLoginAttempts(c, retryAfter, errorTooManyLoginAttempts)
+			return
+		}
 		filter := bson.M{"username": loginParams.Username}
 		rawUserAccount, err := dbadapter.WebuiDBClient.RestfulAPIGetOne(configmodels.UserAccountDataColl, filter)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveUserAccount})
 			return
 		}
-		if len(rawUserAccount) == 0 {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": errorIncorrectCredentials})
-			return
+		var dbUser *configmodels.DBUserAccount
+		if len(rawUserAccount) != 0 {
+			dbUser = &configmodels.DBUserAccount{}
+			err = json.Unmarshal(configmodels.MapToByte(rawUserAccount), dbUser)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveUserAccount})
+				return
+			}
+			if retryAfter, locked := loginThrottle.accountRetryAfter(dbUser, now); retryAfter > 0 {
+				if locked {
+					tooManyLoginAttempts(c, retryAfter, errorAccountLocked)
+				} else {
+					tooManyLoginAttempts(c, retryAfter, errorTooManyLoginAttempts)
+				}
+				return
+			}
 		}
-		var dbUser configmodels.DBUserAccount
-		err = json.Unmarshal(configmodels.MapToByte(rawUserAccount), &dbUser)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveUserAccount})
+		// local accounts keep logging in with their password
+		if ldapAuthenticator != nil && (dbUser == nil || dbUser.IdentityProvider == ldapIdentityProvider) {
+			loginLDAP(c, signingKeys, ldapAuthenticator, loginThrottle, dbUser, &loginParams, now)
 			return
 		}
-		if err = bcrypt.CompareHashAndPassword([]byte(dbUser.HashedPassword), []byte(loginParams.Password)); err != nil {
-			logger.AuthLog.Errorln(err.Error())
-			c.JSON(http.StatusUnauthorized, gin.H{"error": errorIncorrectCredentials})
+		if dbUser == nil {
+			loginFailed(c, loginThrottle, nil, now)
 			return
 		}
-		token, err := GenerateJWT(dbUser.Username, dbUser.Role, jwtSecret)
-		if err != nil {
+		if err = bcrypt.CompareHashAndPassword([]byte(dbUser.HashedPassword), []byte(loginParams.Password)); err != nil {
 			logger.AuthLog.Errorln(err.Error())
-			c.JSON(http.StatusInternalServerError, gin.H{"error": errorLogin})
+			loginFailed(c, loginThrottle, dbUser, now)
 			return
 		}
-		loginResponse := LoginResponse{
-			Token: token,
+		loginSucceeded(c, signingKeys, loginThrottle, dbUser)
+	}
+}
+
+func tooManyLoginAttempts(c *gin.Context, retryAfter time.Duration, message string) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": message})
+}
+
+// loginFailed records the failed attempt of the client and of the account,
+// if any.
+func loginFailed(c *gin.Context, loginThrottle *LoginThrottle, dbUser *configmodels.DBUserAccount, now time.Time) {
+	loginThrottle.clientFailed(c.ClientIP(), now)
+	if dbUser != nil {
+		if err := loginThrottle.recordAccountFailure(dbUser, now); err != nil {
+			logger.AuthLog.Warnln(err.Error())
 		}
-		c.JSON(http.StatusOK, loginResponse)
 	}
+	c.JSON(http.StatusUnauthorized, gin.H{"error": errorIncorrectCredentials})
+}
+
+func loginSucceeded(c *gin.Context, signingKeys *SigningKeys, loginThrottle *LoginThrottle, dbUser *configmodels.DBUserAccount) {
+	loginThrottle.clientSucceeded(c.ClientIP())
+	loginResponse, err := startSession(dbUser, signingKeys)
+	if err != nil {
+		logger.AuthLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorLogin})
+		return
+	}
+	c.JSON(http.StatusOK, loginResponse)
+}
+
+// loginLDAP authenticates a user of the directory and creates or updates its
+// shadow account, if any, with the role mapped to its groups.
+func loginLDAP(c *gin.Context, signingKeys *SigningKeys, ldapAuthenticator *LDAPAuthenticator, loginThrottle *LoginThrottle,
+	shadowAccount *configmodels.DBUserAccount, loginParams *LoginParams, now time.Time,
+) {
+	groups, err := ldapAuthenticator.authenticate(loginParams.Username, loginParams.Password)
+	if errors.Is(err, errLDAPInvalidCredentials) {
+		logger.AuthLog.Errorf("ldap log in of %s failed: %v", loginParams.Username, err)
+		loginFailed(c, loginThrottle, shadowAccount, now)
+		return
+	}
+	if err != nil {
+		logger.AuthLog.Errorln(err.Error())
+		c.JSON(http.StatusBadGateway, gin.H{"error": errorLDAPUnavailable})
+		return
+	}
+	role, roleName, err := mapGroupsToRole(ldapAuthenticator.config.RoleMappings, ldapAuthenticator.config.DefaultRole, groups)
+	if errors.Is(err, errNoRoleMapping) {
+		logger.AuthLog.Warnf("ldap user %s denied: %v", loginParams.Username, err)
+		c.JSON(http.StatusForbidden, gin.H{"error": errorNoMappedRole})
+		return
+	}
+	if err != nil {
+		logger.AuthLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorLogin})
+		return
+	}
+	dbUser, err := upsertExternalAccount(loginParams.Username, "", ldapIdentityProvider, role, roleName)
+	if errors.Is(err, errAccountConflict) {
+		logger.AuthLog.Warnf("ldap user %s denied: %v", loginParams.Username, err)
+		c.JSON(http.StatusConflict, gin.H{"error": errorLocalAccountConflict})
+		return
+	}
+	if err != nil {
+		logger.AuthLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorLogin})
+		return
+	}
+	loginSucceeded(c, signingKeys, loginThrottle, dbUser)
+}
+
+// startSession creates a session for the authenticated user and issues its
+// tokens. The log in is recorded on the account.
+func startSession(dbUser *configmodels.DBUserAccount, signingKeys *SigningKeys) (*LoginResponse, error) {
+	sessionID, refreshToken, err := createSession(dbUser.Username)
+	if err != nil {
+		return nil, err
+	}
+	if err = recordLoginSuccess(dbUser.Username, time.Now()); err != nil {
+		logger.AuthLog.Warnln(err.Error())
+	}
+	token, err := GenerateJWT(dbUser.Username, dbUser.Role, dbUser.RoleName, sessionID, signingKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
 }
 
-func GenerateJWT(username string, role int, jwtSecret []byte) (string, error) {
+// GenerateJWT issues an access token of the session. It is accepted until it
+// expires or the session is revoked.
+func GenerateJWT(username string, role int, roleName string, sessionID string, signingKeys *SigningKeys) (string, error) {
 	tokenExpirationDuration := time.Hour * 1
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtWebconsoleClaims{
+	tokenString, err := signingKeys.sign(jwtWebconsoleClaims{
 		Username: username,
 		Role:     role,
+		RoleName: roleName,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID: sessionID,
 			ExpiresAt: &jwt.NumericDate{
 				Time: time.Now().Add(tokenExpirationDuration),
 			},
 		},
 	})
-	tokenString, err := token.SignedString(jwtSecret)
 	if err != nil {
 		return "", err
 	}
@@ -121,10 +121,21 @@ func (db *MockMongoClientSuccess) RestfulAPIPost(collName string, filter bson.M,
 	return true, nil
 }
 
+func (db *MockMongoClientSuccess) RestfulAPIIncrementOne(collName string, filter bson.M, incData map[string]any, setData map[string]any) (map[string]any, error) {
+	rawUser := map[string]any{
+		"username": "janedoe", "role": 1, "failedLoginAttempts": 1,
+	}
+	return rawUser, nil
+}
+
 func (db *MockMongoClientSuccess) RestfulAPICount(collName string, filter bson.M) (int64, error) {
 	return 5, nil
 }
 
+func (db *MockMongoClientSuccess) RestfulAPIDeleteMany(collName string, filter bson.M) error {
+	return nil
+}
+
 func (db *MockMongoClientRegularUser) RestfulAPIGetOne(coll string, filter bson.M) (map[string]any, error) {
 	rawUser := map[string]any{
 		"username": "johndoe", "password": hashPassword("password-123"), "role": 0,
@@ -136,11 +147,19 @@ func (db *MockMongoClientRegularUser) RestfulAPIDeleteOne(collName string, filte
 	return nil
 }
 
+func (db *MockMongoClientRegularUser) RestfulAPIPost(collName string, filter bson.M, postData map[string]any) (bool, error) {
+	return true, nil
+}
+
+func (db *MockMongoClientRegularUser) RestfulAPIDeleteMany(collName string, filter bson.M) error {
+	return nil
+}
+
 func TestLogin_FailureCases(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	mockJWTSecret := []byte("mockSecret")
-	AddAuthenticationService(router, mockJWTSecret)
+	AddAuthenticationService(router, NewSigningKeys(mockJWTSecret), nil, nil)
 
 	testCases := []struct {
 		dbAdapter    dbadapter.DBInterface
@@ -224,7 +243,7 @@ func TestLogin_SuccessCases(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	mockJWTSecret := []byte("mockSecret")
-	AddAuthenticationService(router, mockJWTSecret)
+	AddAuthenticationService(router, NewSigningKeys(mockJWTSecret), nil, nil)
 
 	testCases := []struct {
 		dbAdapter        dbadapter.DBInterface
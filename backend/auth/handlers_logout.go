@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/logger"
+)
+
+const errorLogout = "failed to log out"
+
+// Logout godoc
+//
+// @Description  Log out. The token and the refresh token of the session stop being accepted. Only available if enableAuthentication is enabled.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Success      200  {object}  nil  "Logged out"
+// @Failure      401  {object}  nil  "Authorization failed"
+// @Failure      404  {object}  nil  "Page not found if enableAuthentication is disabled"
+// @Failure      500  {object}  nil  "Internal server error"
+// @Router       /logout  [post]
+func Logout(signingKeys *SigningKeys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := getClaimsFromAuthorizationHeader(c.Request.Header.Get("Authorization"), signingKeys)
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("auth failed: %s", err.Error())})
+			return
+		}
+		if err = revokeSession(claims.ID); err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errorLogout})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{})
+	}
+}
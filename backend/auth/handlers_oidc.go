@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/omec-project/webconsole/backend/logger"
+)
+
+const (
+	errorInvalidOIDCState = "the log in with the identity provider has expired or is not valid"
+	errorMissingOIDCCode  = "code is required"
+	errorOIDCLogin        = "failed to log in with the identity provider"
+	errorOIDCProvider     = "the identity provider is not available"
+	// the state of a log in is kept in a cookie signed with the token keys
+	oidcStateCookie             = "webui_oidc_state"
+	oidcStateAudience           = "webui-oidc-state"
+	oidcStateExpirationDuration = 10 * time.Minute
+)
+
+type oidcStateClaims struct {
+	jwt.RegisteredClaims
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+func AddOIDCService(engine *gin.Engine, signingKeys *SigningKeys, provider *OIDCProvider) {
+	group := engine.Group("/")
+	addRoutes(group, getOIDCRoutes(signingKeys, provider))
+}
+
+func getOIDCRoutes(signingKeys *SigningKeys, provider *OIDCProvider) Routes {
+	return Routes{
+		{
+			"OIDCLogin",
+			http.MethodGet,
+			"/oidc/login",
+			OIDCLogin(signingKeys, provider),
+		},
+		{
+			"OIDCCallback",
+			http.MethodGet,
+			"/oidc/callback",
+			OIDCCallback(signingKeys, provider),
+		},
+	}
+}
+
+func setOIDCStateCookie(c *gin.Context, provider *OIDCProvider, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	secure := strings.HasPrefix(provider.config.RedirectURL, "https://")
+	c.SetCookie(oidcStateCookie, value, maxAge, "/oidc", "", secure, true)
+}
+
+// OIDCLogin godoc
+//
+// @Description  Log in through the OpenID Connect provider. Redirects to the provider, which redirects back to the callback. Only available if enableAuthentication and oidc are enabled.
+// @Tags         Auth
+// @Success      302  {object}  nil  "Redirect to the identity provider"
+// @Failure      404  {object}  nil  "Page not found if enableAuthentication or oidc is disabled"
+// @Failure      500  {object}  nil  "Internal server error"
+// @Failure      502  {object}  nil  "Identity provider not available"
+// @Router       /oidc/login  [get]
+func OIDCLogin(signingKeys *SigningKeys, provider *OIDCProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		discovery, err := provider.getDiscovery()
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusBadGateway, gin.H{"error": errorOIDCProvider})
+			return
+		}
+		var values [3]string
+		for i := range values {
+			if values[i], err = randomToken(32); err != nil {
+				logger.AuthLog.Errorln(err.Error())
+				c.JSON(http.StatusInternalServerError, gin.H{"error": errorOIDCLogin})
+				return
+			}
+		}
+		state, nonce, verifier := values[0], values[1], values[2]
+		stateToken, err := signingKeys.sign(oidcStateClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        state,
+				Audience:  jwt.ClaimStrings{oidcStateAudience},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(oidcStateExpirationDuration)),
+			},
+			Nonce:    nonce,
+			Verifier: verifier,
+		})
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errorOIDCLogin})
+			return
+		}
+		setOIDCStateCookie(c, provider, stateToken, int(oidcStateExpirationDuration.Seconds()))
+		c.Redirect(http.StatusFound, provider.authCodeURL(discovery, state, nonce, verifier))
+	}
+}
+
+func getOIDCState(c *gin.Context, signingKeys *SigningKeys) (*oidcStateClaims, error) {
+	stateToken, err := c.Cookie(oidcStateCookie)
+	if err != nil {
+		return nil, err
+	}
+	claims := oidcStateClaims{}
+	if _, err = jwt.ParseWithClaims(stateToken, &claims, signingKeys.verificationKey, jwt.WithAudience(oidcStateAudience)); err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.ID), []byte(c.Query("state"))) != 1 {
+		return nil, fmt.Errorf("state does not match")
+	}
+	return &claims, nil
+}
+
+// OIDCCallback godoc
+//
+// @Description  Complete the log in through the OpenID Connect provider, which redirects here with an authorization code. The account of the user is created or updated with the role mapped to its groups. Only available if enableAuthentication and oidc are enabled.
+// @Tags         Auth
+// @Param        code     query    string    true    "Authorization code"
+// @Param        state    query    string    true    "State of the log in"
+// @Success      200  {object}  LoginResponse  "Authorization token"
+// @Failure      400  {object}  nil            "Bad request"
+// @Failure      401  {object}  nil            "Log in failed"
+// @Failure      403  {object}  nil            "No role mapped to the groups of the user"
+// @Failure      404  {object}  nil            "Page not found if enableAuthentication or oidc is disabled"
+// @Failure      409  {object}  nil            "Local user account with the same username"
+// @Failure      500  {object}  nil            "Internal server error"
+// @Failure      502  {object}  nil            "Identity provider not available"
+// @Router       /oidc/callback  [get]
+func OIDCCallback(signingKeys *SigningKeys, provider *OIDCProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state, err := getOIDCState(c, signingKeys)
+		if err != nil {
+			logger.AuthLog.Errorf("invalid oidc state: %v", err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errorInvalidOIDCState})
+			return
+		}
+		setOIDCStateCookie(c, provider, "", -1)
+		if providerError := c.Query("error"); providerError != "" {
+			logger.AuthLog.Errorf("oidc provider error: %s %s", providerError, c.Query("error_description"))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("%s: %s", errorOIDCLogin, providerError)})
+			return
+		}
+		code := c.Query("code")
+		if code == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errorMissingOIDCCode})
+			return
+		}
+		discovery, err := provider.getDiscovery()
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusBadGateway, gin.H{"error": errorOIDCProvider})
+			return
+		}
+		rawIDToken, err := provider.exchange(discovery, code, state.Verifier)
+		if err != nil {
+			logger.AuthLog.Errorf("failed to exchange oidc code: %v", err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errorOIDCLogin})
+			return
+		}
+		claims, err := provider.verifyIDToken(discovery, rawIDToken, state.Nonce)
+		if err != nil {
+			logger.AuthLog.Errorf("invalid oidc ID token: %v", err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errorOIDCLogin})
+			return
+		}
+		username, displayName, groups, err := provider.userFromClaims(claims)
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errorOIDCLogin})
+			return
+		}
+		role, roleName, err := mapGroupsToRole(provider.config.RoleMappings, provider.config.DefaultRole, groups)
+		if errors.Is(err, errNoRoleMapping) {
+			logger.AuthLog.Warnf("oidc user %s (%s) denied: %v", displayName, username, err)
+			c.JSON(http.StatusForbidden, gin.H{"error": errorNoMappedRole})
+			return
+		}
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errorOIDCLogin})
+			return
+		}
+		dbUser, err := upsertExternalAccount(username, displayName, oidcIdentityProvider, role, roleName)
+		if errors.Is(err, errAccountConflict) {
+			logger.AuthLog.Warnf("oidc user %s (%s) denied: %v", displayName, username, err)
+			c.JSON(http.StatusConflict, gin.H{"error": errorLocalAccountConflict})
+			return
+		}
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errorOIDCLogin})
+			return
+		}
+		loginResponse, err := startSession(dbUser, signingKeys)
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errorLogin})
+			return
+		}
+		c.JSON(http.StatusOK, loginResponse)
+	}
+}
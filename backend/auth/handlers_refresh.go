@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	errorInvalidRefreshToken = "refresh token is not valid"
+	errorMissingRefreshToken = "refreshToken is required"
+	errorRefresh             = "failed to refresh token"
+)
+
+type RefreshParams struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// Refresh godoc
+//
+// @Description  Get a new token with a refresh token. The refresh token is replaced by the one returned. Only available if enableAuthentication is enabled.
+// @Tags         Auth
+// @Param        refreshParams    body    RefreshParams    true    " "
+// @Success      200  {object}  LoginResponse  "Authorization token"
+// @Failure      400  {object}  nil            "Bad request"
+// @Failure      401  {object}  nil            "Refresh token not valid"
+// @Failure      404  {object}  nil            "Page not found if enableAuthentication is disabled"
+// @Failure      500  {object}  nil            "Internal server error"
+// @Router       /refresh  [post]
+func Refresh(signingKeys *SigningKeys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var refreshParams RefreshParams
+		if err := c.ShouldBindJSON(&refreshParams); err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": errorInvalidDataProvided})
+			return
+		}
+		if refreshParams.RefreshToken == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errorMissingRefreshToken})
+			return
+		}
+		session, err := fetchSessionByRefreshToken(refreshParams.RefreshToken)
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errorRefresh})
+			return
+		}
+		if session == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errorInvalidRefreshToken})
+			return
+		}
+		if time.Now().Unix() >= session.ExpiresAt {
+			if err = revokeSession(session.SessionID); err != nil {
+				logger.AuthLog.Errorln(err.Error())
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errorInvalidRefreshToken})
+			return
+		}
+		// the role of the user may have changed since the login
+		rawUserAccount, err := dbadapter.WebuiDBClient.RestfulAPIGetOne(configmodels.UserAccountDataColl, bson.M{"username": session.Username})
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveUserAccount})
+			return
+		}
+		if len(rawUserAccount) == 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errorInvalidRefreshToken})
+			return
+		}
+		var dbUser configmodels.DBUserAccount
+		if err = json.Unmarshal(configmodels.MapToByte(rawUserAccount), &dbUser); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveUserAccount})
+			return
+		}
+		refreshToken, err := rotateRefreshToken(session)
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errorRefresh})
+			return
+		}
+		if refreshToken == "" {
+			// a concurrent refresh already used the token
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errorInvalidRefreshToken})
+			return
+		}
+		token, err := GenerateJWT(dbUser.Username, dbUser.Role, dbUser.RoleName, session.SessionID, signingKeys)
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errorRefresh})
+			return
+		}
+		c.JSON(http.StatusOK, LoginResponse{
+			Token:        token,
+			RefreshToken: refreshToken,
+		})
+	}
+}
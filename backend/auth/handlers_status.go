@@ -8,9 +8,6 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/omec-project/webconsole/backend/logger"
-	"github.com/omec-project/webconsole/configmodels"
-	"github.com/omec-project/webconsole/dbadapter"
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 type StatusResponse struct {
@@ -27,7 +24,7 @@ type StatusResponse struct {
 // @Router       /status  [get]
 func GetStatus() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		numOfUserAccounts, err := dbadapter.WebuiDBClient.RestfulAPICount(configmodels.UserAccountDataColl, bson.M{})
+		numOfUserAccounts, err := CountLocalUserAccounts()
 		if err != nil {
 			logger.AuthLog.Errorln(err.Error())
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "couldn't generate status"})
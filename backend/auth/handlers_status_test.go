@@ -10,13 +10,27 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
+// MockMongoClientExternalAccounts only holds the shadow accounts of identity
+// provider users.
+type MockMongoClientExternalAccounts struct {
+	dbadapter.DBInterface
+}
+
+func (db *MockMongoClientExternalAccounts) RestfulAPICount(collName string, filter bson.M) (int64, error) {
+	if _, external := filter["identityProvider"]; external {
+		return 0, nil
+	}
+	return 2, nil
+}
+
 func TestStatus(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	mockJWTSecret := []byte("mockSecret")
-	AddAuthenticationService(router, mockJWTSecret)
+	AddAuthenticationService(router, NewSigningKeys(mockJWTSecret), nil, nil)
 
 	testCases := []struct {
 		dbAdapter    dbadapter.DBInterface
@@ -36,6 +50,12 @@ func TestStatus(t *testing.T) {
 			expectedCode: http.StatusOK,
 			expectedBody: `{"initialized":false}`,
 		},
+		{
+			name:         "StatusIsNotInitializedByExternalAccounts",
+			dbAdapter:    &MockMongoClientExternalAccounts{},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"initialized":false}`,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/backend/logger"
+)
+
+const (
+	ldapIdentityProvider      = "ldap"
+	ldapTimeout               = 10 * time.Second
+	defaultLDAPGroupFilter    = "(member={dn})"
+	defaultLDAPGroupAttribute = "cn"
+)
+
+var (
+	errLDAPInvalidCredentials = errors.New("invalid LDAP credentials")
+	errLDAPUnavailable        = errors.New("the directory is not available")
+)
+
+// LDAPAuthenticator authenticates the users of a directory by binding as
+// them, and maps the groups they are a member of to a role.
+type LDAPAuthenticator struct {
+	config    factory.Ldap
+	useTLS    bool
+	tlsConfig *tls.Config
+}
+
+func NewLDAPAuthenticator(config *factory.Ldap) (*LDAPAuthenticator, error) {
+	if config.URL == "" || config.UserDNTemplate == "" {
+		return nil, fmt.Errorf("ldap url and user-dn-template are required")
+	}
+	if !strings.Contains(config.UserDNTemplate, "{username}") {
+		return nil, fmt.Errorf("ldap user-dn-template must contain {username}")
+	}
+	ldapURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ldap url: %w", err)
+	}
+	authenticator := &LDAPAuthenticator{config: *config}
+	switch ldapURL.Scheme {
+	case "ldap":
+	case "ldaps":
+		authenticator.useTLS = true
+	default:
+		return nil, fmt.Errorf("ldap url scheme must be ldap or ldaps")
+	}
+	if authenticator.useTLS && config.StartTLS {
+		return nil, fmt.Errorf("ldap start-tls cannot be used with ldaps")
+	}
+	if !authenticator.useTLS && !config.StartTLS {
+		if !config.AllowInsecure {
+			return nil, fmt.Errorf("ldap url must be ldaps or use start-tls, unless allow-insecure is set")
+		}
+		logger.AuthLog.Warnf("the passwords of the ldap users are sent in clear text to %s", ldapURL.Host)
+	}
+	authenticator.tlsConfig = &tls.Config{ServerName: ldapURL.Hostname(), MinVersion: tls.VersionTLS12}
+	if config.CaFile != "" {
+		pem, readErr := os.ReadFile(config.CaFile)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read ldap ca-file: %w", readErr)
+		}
+		authenticator.tlsConfig.RootCAs = x509.NewCertPool()
+		if !authenticator.tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ldap ca-file has no certificate")
+		}
+	}
+	if authenticator.config.GroupFilter == "" {
+		authenticator.config.GroupFilter = defaultLDAPGroupFilter
+	}
+	if authenticator.config.GroupAttribute == "" {
+		authenticator.config.GroupAttribute = defaultLDAPGroupAttribute
+	}
+	if _, err = ldap.CompileFilter(authenticator.groupFilter("dn", "username")); err != nil {
+		return nil, fmt.Errorf("invalid ldap group-filter: %w", err)
+	}
+	return authenticator, nil
+}
+
+func (a *LDAPAuthenticator) groupFilter(userDN, username string) string {
+	return strings.NewReplacer(
+		"{dn}", ldap.EscapeFilter(userDN),
+		"{username}", ldap.EscapeFilter(username),
+	).Replace(a.config.GroupFilter)
+}
+
+// authenticate binds as the user and returns the groups of the user. Groups
+// are not searched if GroupBaseDN is empty.
+func (a *LDAPAuthenticator) authenticate(username, password string) ([]string, error) {
+	// an empty password would be an unauthenticated bind, RFC 4513
+	if username == "" || password == "" {
+		return nil, errLDAPInvalidCredentials
+	}
+	conn, err := a.dial()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errLDAPUnavailable, err)
+	}
+	defer closeLDAPConn(conn)
+	userDN := strings.ReplaceAll(a.config.UserDNTemplate, "{username}", ldap.EscapeDN(username))
+	if err = conn.Bind(userDN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) || ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, errLDAPInvalidCredentials
+		}
+		return nil, fmt.Errorf("%w: %v", errLDAPUnavailable, err)
+	}
+	if a.config.GroupBaseDN == "" {
+		return []string{}, nil
+	}
+	request := ldap.NewSearchRequest(
+		a.config.GroupBaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0,
+		int(ldapTimeout.Seconds()),
+		false,
+		a.groupFilter(userDN, username),
+		[]string{a.config.GroupAttribute},
+		nil,
+	)
+	result, err := conn.Search(request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to search the groups of %s: %v", errLDAPUnavailable, username, err)
+	}
+	// referrals to other servers are not followed
+	groups := []string{}
+	for _, entry := range result.Entries {
+		groups = append(groups, entry.GetEqualFoldAttributeValues(a.config.GroupAttribute)...)
+	}
+	return groups, nil
+}
+
+func (a *LDAPAuthenticator) dial() (*ldap.Conn, error) {
+	dialer := &net.Dialer{Timeout: ldapTimeout}
+	conn, err := ldap.DialURL(a.config.URL, ldap.DialWithDialer(dialer), ldap.DialWithTLSConfig(a.tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(ldapTimeout)
+	if a.config.StartTLS {
+		if err = conn.StartTLS(a.tlsConfig); err != nil {
+			closeLDAPConn(conn)
+			return nil, fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func closeLDAPConn(conn *ldap.Conn) {
+	if err := conn.Close(); err != nil {
+		logger.AuthLog.Warnf("failed to close LDAP connection: %v", err)
+	}
+}
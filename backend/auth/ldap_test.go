@@ -0,0 +1,445 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+)
+
+type mockLDAPEntry struct {
+	password string
+	// attribute names are lower case
+	attributes map[string][]string
+}
+
+// mockLDAPServer is an in-process directory answering simple binds and
+// searches of its entries.
+type mockLDAPServer struct {
+	listener net.Listener
+	entries  map[string]mockLDAPEntry
+}
+
+func newMockLDAPServer(t *testing.T) *mockLDAPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := &mockLDAPServer{
+		listener: listener,
+		entries: map[string]mockLDAPEntry{
+			"uid=alice,ou=people,dc=example,dc=org":   {password: "alicePassword"},
+			"uid=bob,ou=people,dc=example,dc=org":     {password: "bobPassword"},
+			"uid=carol,ou=people,dc=example,dc=org":   {password: "carolPassword"},
+			"uid=janedoe,ou=people,dc=example,dc=org": {password: "directoryPassword"},
+			"cn=noc-operators,ou=groups,dc=example,dc=org": {attributes: map[string][]string{
+				"cn":        {"noc-operators"},
+				"member":    {"uid=alice,ou=people,dc=example,dc=org"},
+				"memberuid": {"alice"},
+			}},
+			"cn=noc-admins,ou=groups,dc=example,dc=org": {attributes: map[string][]string{
+				"cn":        {"noc-admins"},
+				"member":    {"uid=carol,ou=people,dc=example,dc=org"},
+				"memberuid": {"carol"},
+			}},
+		},
+	}
+	go server.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return server
+}
+
+func (s *mockLDAPServer) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *mockLDAPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func mockLDAPResult(tag ber.Tag, code int64) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, ""))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	return result
+}
+
+func (s *mockLDAPServer) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	bound := false
+	for {
+		message, err := ber.ReadPacket(conn)
+		if err != nil || len(message.Children) < 2 {
+			return
+		}
+		reply := func(protocolOp *ber.Packet) {
+			response := ber.NewSequence("")
+			response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, message.Children[0].Value, ""))
+			response.AppendChild(protocolOp)
+			_, _ = conn.Write(response.Bytes())
+		}
+		request := message.Children[1].Children
+		switch message.Children[1].Tag {
+		case ldap.ApplicationBindRequest:
+			entry, found := s.entries[strings.ToLower(request[1].Data.String())]
+			bound = found && entry.password != "" && entry.password == request[2].Data.String()
+			if bound {
+				reply(mockLDAPResult(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess))
+			} else {
+				reply(mockLDAPResult(ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials))
+			}
+		case ldap.ApplicationSearchRequest:
+			if !bound {
+				reply(mockLDAPResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights))
+				continue
+			}
+			baseDN := strings.ToLower(request[0].Data.String())
+			for dn, entry := range s.entries {
+				if !strings.HasSuffix(dn, ","+baseDN) || !mockLDAPMatch(request[6], entry.attributes) {
+					continue
+				}
+				partialAttributes := ber.NewSequence("")
+				for _, attribute := range request[7].Children {
+					name := attribute.Data.String()
+					partialAttribute := ber.NewSequence("")
+					partialAttribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, ""))
+					values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+					for _, value := range entry.attributes[strings.ToLower(name)] {
+						values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, ""))
+					}
+					partialAttribute.AppendChild(values)
+					partialAttributes.AppendChild(partialAttribute)
+				}
+				searchEntry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "")
+				searchEntry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, ""))
+				searchEntry.AppendChild(partialAttributes)
+				reply(searchEntry)
+			}
+			reply(mockLDAPResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess))
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			reply(mockLDAPResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError))
+		}
+	}
+}
+
+func mockLDAPMatch(filter *ber.Packet, attributes map[string][]string) bool {
+	switch filter.Tag {
+	case ldap.FilterPresent:
+		return len(attributes[strings.ToLower(filter.Data.String())]) > 0
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !mockLDAPMatch(child, attributes) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if mockLDAPMatch(child, attributes) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !mockLDAPMatch(filter.Children[0], attributes)
+	case ldap.FilterEqualityMatch:
+		for _, value := range attributes[strings.ToLower(filter.Children[0].Data.String())] {
+			if strings.EqualFold(value, filter.Children[1].Data.String()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func mockLDAPConfig(server *mockLDAPServer) factory.Ldap {
+	return factory.Ldap{
+		URL:            server.url(),
+		AllowInsecure:  true,
+		UserDNTemplate: "uid={username},ou=people,dc=example,dc=org",
+		GroupBaseDN:    "ou=groups,dc=example,dc=org",
+		RoleMappings: []factory.RoleMapping{
+			{Group: "noc-admins", Role: "admin"},
+			{Group: "noc-operators", Role: "subscriber-operator"},
+		},
+	}
+}
+
+func setUpLDAPRouter(t *testing.T, config factory.Ldap) (*gin.Engine, *MockMongoClientSessions) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	authenticator, err := NewLDAPAuthenticator(&config)
+	if err != nil {
+		t.Fatalf("failed to create LDAP authenticator: %v", err)
+	}
+	AddAuthenticationService(router, NewSigningKeys([]byte("mockSecret")), authenticator, nil)
+	mockDB := newMockMongoClientSessions()
+	dbadapter.WebuiDBClient = mockDB
+	if err = SeedBuiltinRoles(); err != nil {
+		t.Fatalf("failed to seed roles: %v", err)
+	}
+	return router, mockDB
+}
+
+func postLogin(t *testing.T, router *gin.Engine, username, password string) *httptest.ResponseRecorder {
+	t.Helper()
+	body := fmt.Sprintf(`{"username":%q,"password":%q}`, username, password)
+	req, err := http.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestLDAPLogin(t *testing.T) {
+	server := newMockLDAPServer(t)
+	memberUIDConfig := mockLDAPConfig(server)
+	memberUIDConfig.GroupFilter = "(&(cn=*)(memberUid={username}))"
+	defaultRoleConfig := mockLDAPConfig(server)
+	defaultRoleConfig.DefaultRole = "read-only"
+
+	testCases := []struct {
+		name             string
+		config           factory.Ldap
+		username         string
+		password         string
+		expectedCode     int
+		expectedRole     int
+		expectedRoleName string
+		expectedProvider string
+	}{
+		{
+			name:             "MappedRole",
+			config:           mockLDAPConfig(server),
+			username:         "alice",
+			password:         "alicePassword",
+			expectedCode:     http.StatusOK,
+			expectedRole:     configmodels.UserRole,
+			expectedRoleName: "subscriber-operator",
+			expectedProvider: ldapIdentityProvider,
+		},
+		{
+			name:             "MappedAdmin",
+			config:           mockLDAPConfig(server),
+			username:         "carol",
+			password:         "carolPassword",
+			expectedCode:     http.StatusOK,
+			expectedRole:     configmodels.AdminRole,
+			expectedProvider: ldapIdentityProvider,
+		},
+		{
+			name:             "GroupFilterWithUsername",
+			config:           memberUIDConfig,
+			username:         "alice",
+			password:         "alicePassword",
+			expectedCode:     http.StatusOK,
+			expectedRole:     configmodels.UserRole,
+			expectedRoleName: "subscriber-operator",
+			expectedProvider: ldapIdentityProvider,
+		},
+		{
+			name:             "DefaultRole",
+			config:           defaultRoleConfig,
+			username:         "bob",
+			password:         "bobPassword",
+			expectedCode:     http.StatusOK,
+			expectedRole:     configmodels.UserRole,
+			expectedRoleName: "read-only",
+			expectedProvider: ldapIdentityProvider,
+		},
+		{
+			name:         "NoRoleMapped",
+			config:       mockLDAPConfig(server),
+			username:     "bob",
+			password:     "bobPassword",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "IncorrectPassword",
+			config:       mockLDAPConfig(server),
+			username:     "alice",
+			password:     "carolPassword",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "UnknownUser",
+			config:       mockLDAPConfig(server),
+			username:     "dave",
+			password:     "davePassword",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "DNInjection",
+			config:       mockLDAPConfig(server),
+			username:     "alice,ou=people,dc=example,dc=org",
+			password:     "alicePassword",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "LocalAccount",
+			config:       mockLDAPConfig(server),
+			username:     "janedoe",
+			password:     "password123!",
+			expectedCode: http.StatusOK,
+			expectedRole: configmodels.AdminRole,
+		},
+		{
+			name:         "LocalAccountWithDirectoryPassword",
+			config:       mockLDAPConfig(server),
+			username:     "janedoe",
+			password:     "directoryPassword",
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, mockDB := setUpLDAPRouter(t, tc.config)
+			w := postLogin(t, router, tc.username, tc.password)
+			if w.Code != tc.expectedCode {
+				t.Fatalf("expected `%v`, got `%v` %s", tc.expectedCode, w.Code, w.Body.String())
+			}
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+			var response LoginResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Token == "" {
+				t.Fatalf("expected a token, got %s", w.Body.String())
+			}
+			rawUser, err := mockDB.RestfulAPIGetOne(configmodels.UserAccountDataColl, map[string]any{"username": tc.username})
+			if err != nil {
+				t.Fatalf("failed to get user account: %v", err)
+			}
+			var dbUser configmodels.DBUserAccount
+			if err = json.Unmarshal(configmodels.MapToByte(rawUser), &dbUser); err != nil {
+				t.Fatalf("failed to decode user account: %v", err)
+			}
+			if dbUser.Role != tc.expectedRole || dbUser.RoleName != tc.expectedRoleName || dbUser.IdentityProvider != tc.expectedProvider {
+				t.Errorf("expected role %v %q from %q, got %+v", tc.expectedRole, tc.expectedRoleName, tc.expectedProvider, dbUser)
+			}
+		})
+	}
+}
+
+func TestLDAPLogin_DirectoryUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	config := mockLDAPConfig(&mockLDAPServer{listener: listener})
+	if err = listener.Close(); err != nil {
+		t.Fatalf("failed to close listener: %v", err)
+	}
+	router, _ := setUpLDAPRouter(t, config)
+
+	if w := postLogin(t, router, "alice", "alicePassword"); w.Code != http.StatusBadGateway {
+		t.Errorf("expected `%v`, got `%v` %s", http.StatusBadGateway, w.Code, w.Body.String())
+	}
+	// break-glass admins log in with their local account
+	if w := postLogin(t, router, "janedoe", "password123!"); w.Code != http.StatusOK {
+		t.Errorf("expected `%v`, got `%v` %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestLDAPAuthenticate_EmptyPassword(t *testing.T) {
+	server := newMockLDAPServer(t)
+	config := mockLDAPConfig(server)
+	authenticator, err := NewLDAPAuthenticator(&config)
+	if err != nil {
+		t.Fatalf("failed to create LDAP authenticator: %v", err)
+	}
+	if _, err = authenticator.authenticate("alice", ""); !errors.Is(err, errLDAPInvalidCredentials) {
+		t.Errorf("expected an unauthenticated bind to be rejected, got %v", err)
+	}
+}
+
+func TestNewLDAPAuthenticator(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        factory.Ldap
+		expectedError string
+	}{
+		{
+			name:   "Valid",
+			config: factory.Ldap{URL: "ldaps://ldap.example.org", UserDNTemplate: "uid={username},dc=example,dc=org"},
+		},
+		{
+			name:   "StartTLS",
+			config: factory.Ldap{URL: "ldap://ldap.example.org", StartTLS: true, UserDNTemplate: "uid={username},dc=example,dc=org"},
+		},
+		{
+			name:   "SubstringGroupFilter",
+			config: factory.Ldap{URL: "ldaps://ldap.example.org", UserDNTemplate: "uid={username},dc=example,dc=org", GroupFilter: "(cn=noc-*)"},
+		},
+		{
+			name:          "MissingURL",
+			config:        factory.Ldap{UserDNTemplate: "uid={username},dc=example,dc=org"},
+			expectedError: "ldap url and user-dn-template are required",
+		},
+		{
+			name:          "TemplateWithoutUsername",
+			config:        factory.Ldap{URL: "ldap://ldap.example.org", UserDNTemplate: "dc=example,dc=org"},
+			expectedError: "ldap user-dn-template must contain {username}",
+		},
+		{
+			name:          "UnsupportedScheme",
+			config:        factory.Ldap{URL: "http://ldap.example.org", UserDNTemplate: "uid={username},dc=example,dc=org"},
+			expectedError: "ldap url scheme must be ldap or ldaps",
+		},
+		{
+			name:          "StartTLSWithLDAPS",
+			config:        factory.Ldap{URL: "ldaps://ldap.example.org", StartTLS: true, UserDNTemplate: "uid={username},dc=example,dc=org"},
+			expectedError: "ldap start-tls cannot be used with ldaps",
+		},
+		{
+			name:          "ClearTextPasswords",
+			config:        factory.Ldap{URL: "ldap://ldap.example.org", UserDNTemplate: "uid={username},dc=example,dc=org"},
+			expectedError: "ldap url must be ldaps or use start-tls, unless allow-insecure is set",
+		},
+		{
+			name:          "InvalidGroupFilter",
+			config:        factory.Ldap{URL: "ldaps://ldap.example.org", UserDNTemplate: "uid={username},dc=example,dc=org", GroupFilter: "(cn=noc"},
+			expectedError: "invalid ldap group-filter: ",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewLDAPAuthenticator(&tc.config)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tc.expectedError) {
+				t.Errorf("expected error %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
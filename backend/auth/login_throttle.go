@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultMaxFailedAttempts = 5
+	defaultLockoutMinute     = 15
+	defaultBackoffBaseSecond = 1
+	defaultBackoffMaxSecond  = 60
+	// the failures of the clients are pruned above this number of clients
+	maxThrottledClients = 10000
+)
+
+type loginFailures struct {
+	count int
+	last  time.Time
+}
+
+// LoginThrottle delays the log in attempts after failures. The failures of
+// the accounts are stored in the webui DB, so that they are shared by the
+// replicas and kept on restart, those of the client IPs in memory.
+type LoginThrottle struct {
+	maxFailedAttempts int
+	lockout           time.Duration
+	backoffBase       time.Duration
+	backoffMax        time.Duration
+
+	mu      sync.Mutex
+	clients map[string]*loginFailures
+}
+
+// NewLoginThrottle returns nil if the throttling is disabled, in which case
+// the attempts are recorded but never delayed.
+func NewLoginThrottle(config *factory.LoginThrottling) *LoginThrottle {
+	if config == nil {
+		config = &factory.LoginThrottling{}
+	}
+	if config.Disable {
+		return nil
+	}
+	t := &LoginThrottle{
+		maxFailedAttempts: config.MaxFailedAttempts,
+		lockout:           time.Duration(config.LockoutMinute) * time.Minute,
+		backoffBase:       time.Duration(config.BackoffBaseSecond) * time.Second,
+		backoffMax:        time.Duration(config.BackoffMaxSecond) * time.Second,
+		clients:           map[string]*loginFailures{},
+	}
+	if t.maxFailedAttempts <= 0 {
+		t.maxFailedAttempts = defaultMaxFailedAttempts
+	}
+	if t.lockout <= 0 {
+		t.lockout = defaultLockoutMinute * time.Minute
+	}
+	if t.backoffBase <= 0 {
+		t.backoffBase = defaultBackoffBaseSecond * time.Second
+	}
+	if t.backoffMax <= 0 {
+		t.backoffMax = defaultBackoffMaxSecond * time.Second
+	}
+	return t
+}
+
+// backoff is the delay after the given number of consecutive failures.
+func (t *LoginThrottle) backoff(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	delay := t.backoffBase
+	for i := 1; i < failures && delay < t.backoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, t.backoffMax)
+}
+
+// clientRetryAfter returns how long the client must wait before its next
+// attempt. The failures of a client are forgotten after the lockout duration.
+func (t *LoginThrottle) clientRetryAfter(clientIP string, now time.Time) time.Duration {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	failures, found := t.clients[clientIP]
+	if !found {
+		return 0
+	}
+	if now.Sub(failures.last) >= t.lockout {
+		delete(t.clients, clientIP)
+		return 0
+	}
+	return failures.last.Add(t.backoff(failures.count)).Sub(now)
+}
+
+func (t *LoginThrottle) clientFailed(clientIP string, now time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.clients) >= maxThrottledClients {
+		for ip, failures := range t.clients {
+			if now.Sub(failures.last) >= t.lockout {
+				delete(t.clients, ip)
+			}
+		}
+	}
+	failures, found := t.clients[clientIP]
+	if !found {
+		failures = &loginFailures{}
+		t.clients[clientIP] = failures
+	}
+	failures.count++
+	failures.last = now
+}
+
+func (t *LoginThrottle) clientSucceeded(clientIP string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.clients, clientIP)
+}
+
+// accountRetryAfter returns how long the user must wait before the next
+// attempt on the account, and whether the account is locked.
+func (t *LoginThrottle) accountRetryAfter(dbUser *configmodels.DBUserAccount, now time.Time) (time.Duration, bool) {
+	if t == nil {
+		return 0, false
+	}
+	if lockedUntil := time.Unix(dbUser.LockedUntil, 0); dbUser.LockedUntil != 0 && lockedUntil.After(now) {
+		return lockedUntil.Sub(now), true
+	}
+	if dbUser.FailedLoginAttempts == 0 {
+		return 0, false
+	}
+	// failures are stored in seconds, the delay starts at the end of the second
+	lastFailure := time.Unix(dbUser.LastFailedLoginAt+1, 0)
+	return lastFailure.Add(t.backoff(dbUser.FailedLoginAttempts)).Sub(now), false
+}
+
+// recordAccountFailure stores a failed attempt on the account, and locks it
+// after too many consecutive failures. The counter is incremented in the DB,
+// so that concurrent failures on the replicas are all counted, and the delay
+// and the lockout follow the stored count.
+func (t *LoginThrottle) recordAccountFailure(dbUser *configmodels.DBUserAccount, now time.Time) error {
+	filter := bson.M{"username": dbUser.Username}
+	inc := bson.M{"failedLoginAttempts": 1}
+	update := bson.M{"lastFailedLoginAt": now.Unix()}
+	rawUser, err := dbadapter.WebuiDBClient.RestfulAPIIncrementOne(configmodels.UserAccountDataColl, filter, inc, update)
+	if err != nil {
+		return fmt.Errorf("failed to record failed log in of %s: %w", dbUser.Username, err)
+	}
+	if rawUser == nil {
+		return fmt.Errorf("failed to record failed log in of %s: user account not found", dbUser.Username)
+	}
+	var stored configmodels.DBUserAccount
+	if err = json.Unmarshal(configmodels.MapToByte(rawUser), &stored); err != nil {
+		return fmt.Errorf("failed to record failed log in of %s: %w", dbUser.Username, err)
+	}
+	dbUser.FailedLoginAttempts = stored.FailedLoginAttempts
+	dbUser.LastFailedLoginAt = stored.LastFailedLoginAt
+	if t == nil || dbUser.FailedLoginAttempts < t.maxFailedAttempts {
+		return nil
+	}
+	dbUser.LockedUntil = now.Add(t.lockout).Unix()
+	logger.AuthLog.Warnf("user account %s locked after %d failed log in attempts", dbUser.Username, dbUser.FailedLoginAttempts)
+	if _, err = dbadapter.WebuiDBClient.RestfulAPIUpdateOne(configmodels.UserAccountDataColl, filter, bson.M{"lockedUntil": dbUser.LockedUntil}); err != nil {
+		return fmt.Errorf("failed to lock user account %s: %w", dbUser.Username, err)
+	}
+	return nil
+}
+
+// recordLoginSuccess stores the time of the log in and resets the failed
+// attempts of the account.
+func recordLoginSuccess(username string, now time.Time) error {
+	update := bson.M{
+		"lastLoginAt":         now.Unix(),
+		"failedLoginAttempts": 0,
+		"lockedUntil":         0,
+	}
+	filter := bson.M{"username": username}
+	if _, err := dbadapter.WebuiDBClient.RestfulAPIPost(configmodels.UserAccountDataColl, filter, update); err != nil {
+		return fmt.Errorf("failed to record log in of %s: %w", username, err)
+	}
+	return nil
+}
+
+// UnlockUserAccount resets the failed log in attempts of the account, which
+// can log in again at once.
+func UnlockUserAccount(username string) error {
+	update := bson.M{
+		"failedLoginAttempts": 0,
+		"lockedUntil":         0,
+	}
+	filter := bson.M{"username": username}
+	if _, err := dbadapter.WebuiDBClient.RestfulAPIPost(configmodels.UserAccountDataColl, filter, update); err != nil {
+		return fmt.Errorf("failed to unlock user account %s: %w", username, err)
+	}
+	return nil
+}
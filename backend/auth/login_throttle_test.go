@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestNewLoginThrottle(t *testing.T) {
+	if throttle := NewLoginThrottle(&factory.LoginThrottling{Disable: true}); throttle != nil {
+		t.Errorf("expected no throttle when disabled, got %+v", throttle)
+	}
+	throttle := NewLoginThrottle(nil)
+	expected := []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second, time.Minute, time.Minute}
+	for failures, delay := range expected {
+		if backoff := throttle.backoff(failures); backoff != delay {
+			t.Errorf("expected a backoff of %v after %d failures, got %v", delay, failures, backoff)
+		}
+	}
+	if backoff := throttle.backoff(1000); backoff != time.Minute {
+		t.Errorf("expected the backoff to be capped, got %v", backoff)
+	}
+	if throttle.maxFailedAttempts != defaultMaxFailedAttempts || throttle.lockout != defaultLockoutMinute*time.Minute {
+		t.Errorf("expected the default lockout, got %+v", throttle)
+	}
+}
+
+type throttledLoginTest struct {
+	t        *testing.T
+	router   *gin.Engine
+	mockDB   *MockMongoClientSessions
+	throttle *LoginThrottle
+}
+
+func newThrottledLoginTest(t *testing.T, config *factory.LoginThrottling) *throttledLoginTest {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	throttle := NewLoginThrottle(config)
+	AddAuthenticationService(router, NewSigningKeys([]byte("mockSecret")), nil, throttle)
+	mockDB := newMockMongoClientSessions()
+	dbadapter.WebuiDBClient = mockDB
+	return &throttledLoginTest{t: t, router: router, mockDB: mockDB, throttle: throttle}
+}
+
+func (lt *throttledLoginTest) login(clientIP, username, password string) *httptest.ResponseRecorder {
+	lt.t.Helper()
+	return lt.loginVia(clientIP, "", username, password)
+}
+
+// loginVia logs in from the peer address, with the X-Forwarded-For header if
+// it is not empty.
+func (lt *throttledLoginTest) loginVia(peerIP, forwardedFor, username, password string) *httptest.ResponseRecorder {
+	lt.t.Helper()
+	body := fmt.Sprintf(`{"username":%q,"password":%q}`, username, password)
+	req, err := http.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	if err != nil {
+		lt.t.Fatalf("failed to create request: %v", err)
+	}
+	req.RemoteAddr = peerIP + ":12345"
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	lt.router.ServeHTTP(w, req)
+	return w
+}
+
+func (lt *throttledLoginTest) expect(w *httptest.ResponseRecorder, code int, message string) {
+	lt.t.Helper()
+	if w.Code != code {
+		lt.t.Fatalf("expected `%v`, got `%v` %s", code, w.Code, w.Body.String())
+	}
+	if message != "" && w.Body.String() != fmt.Sprintf(`{"error":"%s"}`, message) {
+		lt.t.Fatalf("expected error %q, got %s", message, w.Body.String())
+	}
+}
+
+func (lt *throttledLoginTest) expectRetryAfter(w *httptest.ResponseRecorder, minSeconds, maxSeconds int) {
+	lt.t.Helper()
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil || retryAfter < minSeconds || retryAfter > maxSeconds {
+		lt.t.Errorf("expected to retry after %d to %d seconds, got %q", minSeconds, maxSeconds, w.Header().Get("Retry-After"))
+	}
+}
+
+func (lt *throttledLoginTest) account(username string) configmodels.DBUserAccount {
+	lt.t.Helper()
+	rawUser, err := lt.mockDB.RestfulAPIGetOne(configmodels.UserAccountDataColl, bson.M{"username": username})
+	if err != nil {
+		lt.t.Fatalf("failed to get user account: %v", err)
+	}
+	var dbUser configmodels.DBUserAccount
+	if err = json.Unmarshal(configmodels.MapToByte(rawUser), &dbUser); err != nil {
+		lt.t.Fatalf("failed to decode user account: %v", err)
+	}
+	return dbUser
+}
+
+// rewind moves the failures of the clients and of the account back in time.
+func (lt *throttledLoginTest) rewind(username string, d time.Duration) {
+	lt.t.Helper()
+	if lt.throttle != nil {
+		for _, failures := range lt.throttle.clients {
+			failures.last = failures.last.Add(-d)
+		}
+	}
+	dbUser := lt.account(username)
+	update := bson.M{"lastFailedLoginAt": dbUser.LastFailedLoginAt - int64(d.Seconds())}
+	if dbUser.LockedUntil != 0 {
+		update["lockedUntil"] = dbUser.LockedUntil - int64(d.Seconds())
+	}
+	if _, err := lt.mockDB.RestfulAPIPost(configmodels.UserAccountDataColl, bson.M{"username": username}, update); err != nil {
+		lt.t.Fatalf("failed to update user account: %v", err)
+	}
+}
+
+func TestLogin_AccountLockout(t *testing.T) {
+	lt := newThrottledLoginTest(t, &factory.LoginThrottling{MaxFailedAttempts: 3, LockoutMinute: 10})
+
+	lt.expect(lt.login("192.0.2.1", "janedoe", "wrong"), http.StatusUnauthorized, errorIncorrectCredentials)
+	if dbUser := lt.account("janedoe"); dbUser.FailedLoginAttempts != 1 || dbUser.LastFailedLoginAt == 0 || dbUser.LockedUntil != 0 {
+		t.Fatalf("expected a failed attempt to be recorded, got %+v", dbUser)
+	}
+
+	// the next attempt of the account is delayed, even from another client
+	w := lt.login("192.0.2.2", "janedoe", "password123!")
+	lt.expect(w, http.StatusTooManyRequests, errorTooManyLoginAttempts)
+	lt.expectRetryAfter(w, 1, 2)
+
+	lt.rewind("janedoe", 2*time.Second)
+	lt.expect(lt.login("192.0.2.1", "janedoe", "wrong"), http.StatusUnauthorized, errorIncorrectCredentials)
+	w = lt.login("192.0.2.2", "janedoe", "password123!")
+	lt.expect(w, http.StatusTooManyRequests, errorTooManyLoginAttempts)
+	lt.expectRetryAfter(w, 2, 3)
+
+	lt.rewind("janedoe", 3*time.Second)
+	lt.expect(lt.login("192.0.2.1", "janedoe", "wrong"), http.StatusUnauthorized, errorIncorrectCredentials)
+	if dbUser := lt.account("janedoe"); dbUser.FailedLoginAttempts != 3 || dbUser.LockedUntil == 0 {
+		t.Fatalf("expected the account to be locked, got %+v", dbUser)
+	}
+
+	// the correct password is refused while the account is locked
+	lt.rewind("janedoe", 5*time.Minute)
+	w = lt.login("192.0.2.2", "janedoe", "password123!")
+	lt.expect(w, http.StatusTooManyRequests, errorAccountLocked)
+	lt.expectRetryAfter(w, 299, 300)
+
+	if err := UnlockUserAccount("janedoe"); err != nil {
+		t.Fatalf("failed to unlock user account: %v", err)
+	}
+	lt.expect(lt.login("192.0.2.2", "janedoe", "password123!"), http.StatusOK, "")
+	if dbUser := lt.account("janedoe"); dbUser.FailedLoginAttempts != 0 || dbUser.LockedUntil != 0 || dbUser.LastLoginAt == 0 || dbUser.LastFailedLoginAt == 0 {
+		t.Errorf("expected the log in to be recorded, got %+v", dbUser)
+	}
+}
+
+func TestRecordAccountFailure_ConcurrentFailures(t *testing.T) {
+	lt := newThrottledLoginTest(t, &factory.LoginThrottling{MaxFailedAttempts: 3})
+	now := time.Now()
+
+	// both replicas read the account before either failure is stored
+	first := lt.account("janedoe")
+	second := lt.account("janedoe")
+	if err := lt.throttle.recordAccountFailure(&first, now); err != nil {
+		t.Fatalf("failed to record failure: %v", err)
+	}
+	if err := lt.throttle.recordAccountFailure(&second, now); err != nil {
+		t.Fatalf("failed to record failure: %v", err)
+	}
+	if second.FailedLoginAttempts != 2 || second.LockedUntil != 0 {
+		t.Errorf("expected the failure to follow the stored count, got %+v", second)
+	}
+	third := lt.account("janedoe")
+	third.FailedLoginAttempts = 0
+	if err := lt.throttle.recordAccountFailure(&third, now); err != nil {
+		t.Fatalf("failed to record failure: %v", err)
+	}
+	if dbUser := lt.account("janedoe"); dbUser.FailedLoginAttempts != 3 || dbUser.LockedUntil != now.Add(lt.throttle.lockout).Unix() {
+		t.Errorf("expected the account to be locked after the third failure, got %+v", dbUser)
+	}
+}
+
+func TestLogin_AccountLockoutExpires(t *testing.T) {
+	lt := newThrottledLoginTest(t, &factory.LoginThrottling{MaxFailedAttempts: 1, LockoutMinute: 10})
+
+	lt.expect(lt.login("192.0.2.1", "janedoe", "wrong"), http.StatusUnauthorized, errorIncorrectCredentials)
+	lt.expect(lt.login("192.0.2.2", "janedoe", "password123!"), http.StatusTooManyRequests, errorAccountLocked)
+	lt.rewind("janedoe", 10*time.Minute)
+	lt.expect(lt.login("192.0.2.1", "janedoe", "password123!"), http.StatusOK, "")
+}
+
+func TestLogin_ClientThrottling(t *testing.T) {
+	lt := newThrottledLoginTest(t, nil)
+
+	// failures on unknown usernames delay the client
+	lt.expect(lt.login("192.0.2.1", "unknown", "password"), http.StatusUnauthorized, errorIncorrectCredentials)
+	lt.expect(lt.login("192.0.2.1", "janedoe", "password123!"), http.StatusTooManyRequests, errorTooManyLoginAttempts)
+	lt.expect(lt.login("192.0.2.2", "janedoe", "password123!"), http.StatusOK, "")
+
+	lt.rewind("janedoe", 2*time.Second)
+	lt.expect(lt.login("192.0.2.1", "janedoe", "password123!"), http.StatusOK, "")
+	if _, found := lt.throttle.clients["192.0.2.1"]; found {
+		t.Errorf("expected the failures of the client to be reset after a log in")
+	}
+}
+
+func TestLogin_ClientThrottlingBehindProxy(t *testing.T) {
+	lt := newThrottledLoginTest(t, nil)
+	if err := lt.router.SetTrustedProxies([]string{"10.0.0.1"}); err != nil {
+		t.Fatalf("failed to set trusted proxies: %v", err)
+	}
+
+	// a client cannot change its IP with X-Forwarded-For
+	lt.expect(lt.loginVia("192.0.2.1", "198.51.100.1", "unknown", "password"), http.StatusUnauthorized, errorIncorrectCredentials)
+	lt.expect(lt.loginVia("192.0.2.1", "198.51.100.2", "janedoe", "password123!"), http.StatusTooManyRequests, errorTooManyLoginAttempts)
+
+	// the clients behind a trusted proxy are throttled separately
+	lt.expect(lt.loginVia("10.0.0.1", "198.51.100.1", "unknown", "password"), http.StatusUnauthorized, errorIncorrectCredentials)
+	lt.expect(lt.loginVia("10.0.0.1", "198.51.100.1", "janedoe", "password123!"), http.StatusTooManyRequests, errorTooManyLoginAttempts)
+	lt.expect(lt.loginVia("10.0.0.1", "198.51.100.2", "janedoe", "password123!"), http.StatusOK, "")
+}
+
+func TestLogin_ThrottlingDisabled(t *testing.T) {
+	lt := newThrottledLoginTest(t, &factory.LoginThrottling{Disable: true})
+
+	for range defaultMaxFailedAttempts + 1 {
+		lt.expect(lt.login("192.0.2.1", "janedoe", "wrong"), http.StatusUnauthorized, errorIncorrectCredentials)
+	}
+	if dbUser := lt.account("janedoe"); dbUser.FailedLoginAttempts != defaultMaxFailedAttempts+1 || dbUser.LockedUntil != 0 {
+		t.Errorf("expected the failed attempts to be recorded without lockout, got %+v", dbUser)
+	}
+	lt.expect(lt.login("192.0.2.1", "janedoe", "password123!"), http.StatusOK, "")
+}
@@ -7,20 +7,22 @@ import (
 	"crypto/rand"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/omec-project/webconsole/backend/logger"
 	"github.com/omec-project/webconsole/configmodels"
-	"github.com/omec-project/webconsole/dbadapter"
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 type jwtWebconsoleClaims struct {
 	jwt.RegisteredClaims
 	Username string `json:"username"`
 	Role     int    `json:"role"`
+	RoleName string `json:"roleName,omitempty"`
+	// scope limits the permissions of API keys, it is not part of JWTs
+	scope []string
 }
 
 func GenerateJWTSecret() ([]byte, error) {
@@ -31,11 +33,11 @@ func GenerateJWTSecret() ([]byte, error) {
 	return bytes, nil
 }
 
-// AdminOrUserAuthMiddleware intercepts requests that need authorization to check if the user's token exists and is
-// permitted to use the endpoint
-func AdminOrUserAuthMiddleware(jwtSecret []byte) gin.HandlerFunc {
+// AdminOrUserAuthMiddleware intercepts requests that need authorization to check if the user's token or API key
+// exists and is permitted to use the endpoint. The claims of the token are kept for RequirePermission.
+func AdminOrUserAuthMiddleware(signingKeys *SigningKeys) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		claims, err := getClaimsFromAuthorizationHeader(c.Request.Header.Get("Authorization"), jwtSecret)
+		claims, err := getClaimsFromRequest(c.Request, signingKeys)
 		if err != nil {
 			logger.AuthLog.Errorln(err.Error())
 			c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("auth failed: %s", err.Error())})
@@ -45,16 +47,18 @@ func AdminOrUserAuthMiddleware(jwtSecret []byte) gin.HandlerFunc {
 		if claims.Role != configmodels.AdminRole && claims.Role != configmodels.UserRole {
 			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden: admin or user access required"})
 			c.Abort()
+			return
 		}
+		c.Set(claimsContextKey, claims)
 		c.Next()
 	}
 }
 
 // AdminOnly checks if the authorization token is valid for this endpoint.
 // Only tokens with AdminRole will be allowed.
-func AdminOnly(jwtSecret []byte, handler func(c *gin.Context)) func(c *gin.Context) {
+func AdminOnly(signingKeys *SigningKeys, handler func(c *gin.Context)) func(c *gin.Context) {
 	return func(c *gin.Context) {
-		claims, err := getClaimsFromAuthorizationHeader(c.Request.Header.Get("Authorization"), jwtSecret)
+		claims, err := getClaimsFromRequest(c.Request, signingKeys)
 		if err != nil {
 			logger.AuthLog.Errorln(err.Error())
 			c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("auth failed: %s", err.Error())})
@@ -66,6 +70,7 @@ func AdminOnly(jwtSecret []byte, handler func(c *gin.Context)) func(c *gin.Conte
 			c.Abort()
 			return
 		}
+		c.Set(claimsContextKey, claims)
 		handler(c)
 	}
 }
@@ -73,9 +78,9 @@ func AdminOnly(jwtSecret []byte, handler func(c *gin.Context)) func(c *gin.Conte
 // AdminOrMe checks if the authorization token is valid for this endpoint.
 // Admin role is allowed. UserRole is allowed with the condition of performing the action
 // over their own account
-func AdminOrMe(jwtSecret []byte, handler func(c *gin.Context)) func(c *gin.Context) {
+func AdminOrMe(signingKeys *SigningKeys, handler func(c *gin.Context)) func(c *gin.Context) {
 	return func(c *gin.Context) {
-		claims, err := getClaimsFromAuthorizationHeader(c.Request.Header.Get("Authorization"), jwtSecret)
+		claims, err := getClaimsFromRequest(c.Request, signingKeys)
 		if err != nil {
 			logger.AuthLog.Errorln(err.Error())
 			c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("auth failed: %s", err.Error())})
@@ -83,6 +88,7 @@ func AdminOrMe(jwtSecret []byte, handler func(c *gin.Context)) func(c *gin.Conte
 			return
 		}
 		if claims.Role == configmodels.AdminRole || (claims.Role == configmodels.UserRole && claims.Username == c.Param("username")) {
+			c.Set(claimsContextKey, claims)
 			handler(c)
 			return
 		}
@@ -93,9 +99,9 @@ func AdminOrMe(jwtSecret []byte, handler func(c *gin.Context)) func(c *gin.Conte
 
 // AdminOrFirstUser checks if the authorization token is valid for this endpoint.
 // check if the user has admin role or if the user is the first user before allowing access to the handler.
-func AdminOrFirstUser(jwtSecret []byte, handler func(c *gin.Context)) func(c *gin.Context) {
+func AdminOrFirstUser(signingKeys *SigningKeys, handler func(c *gin.Context)) func(c *gin.Context) {
 	return func(c *gin.Context) {
-		numOfUserAccounts, err := dbadapter.WebuiDBClient.RestfulAPICount(configmodels.UserAccountDataColl, bson.M{})
+		numOfUserAccounts, err := CountLocalUserAccounts()
 		if err != nil {
 			logger.AuthLog.Errorln(err.Error())
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to authorize"})
@@ -103,7 +109,7 @@ func AdminOrFirstUser(jwtSecret []byte, handler func(c *gin.Context)) func(c *gi
 			return
 		}
 		if numOfUserAccounts > 0 {
-			claims, err := getClaimsFromAuthorizationHeader(c.Request.Header.Get("Authorization"), jwtSecret)
+			claims, err := getClaimsFromRequest(c.Request, signingKeys)
 			if err != nil {
 				c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("auth failed: %s", err.Error())})
 				c.Abort()
@@ -114,12 +120,23 @@ func AdminOrFirstUser(jwtSecret []byte, handler func(c *gin.Context)) func(c *gi
 				c.Abort()
 				return
 			}
+			c.Set(claimsContextKey, claims)
 		}
 		handler(c)
 	}
 }
 
-func getClaimsFromAuthorizationHeader(header string, JwtSecret []byte) (*jwtWebconsoleClaims, error) {
+// AuthenticatedUsername returns the username of the token or API key
+// authenticated for the request, empty if the request is not authenticated.
+func AuthenticatedUsername(c *gin.Context) string {
+	value, authenticated := c.Get(claimsContextKey)
+	if !authenticated {
+		return ""
+	}
+	return value.(*jwtWebconsoleClaims).Username
+}
+
+func getClaimsFromAuthorizationHeader(header string, signingKeys *SigningKeys) (*jwtWebconsoleClaims, error) {
 	if header == "" {
 		return nil, fmt.Errorf("authorization header not found")
 	}
@@ -127,23 +144,30 @@ func getClaimsFromAuthorizationHeader(header string, JwtSecret []byte) (*jwtWebc
 	if len(bearerToken) != 2 || bearerToken[0] != "Bearer" {
 		return nil, fmt.Errorf("authorization header couldn't be processed. The expected format is 'Bearer token'")
 	}
-	claims, err := getClaimsFromJWT(bearerToken[1], JwtSecret)
+	claims, err := getClaimsFromJWT(bearerToken[1], signingKeys)
 	if err != nil {
 		return nil, fmt.Errorf("token is not valid")
 	}
+	active, err := isSessionActive(claims.ID)
+	if err != nil {
+		logger.AuthLog.Errorf("failed to check the session of the token: %v", err)
+		return nil, fmt.Errorf("token could not be checked")
+	}
+	if !active {
+		return nil, fmt.Errorf("token has been revoked")
+	}
 	return claims, nil
 }
 
-func getClaimsFromJWT(bearerToken string, JwtSecret []byte) (*jwtWebconsoleClaims, error) {
+func getClaimsFromJWT(bearerToken string, signingKeys *SigningKeys) (*jwtWebconsoleClaims, error) {
 	claims := jwtWebconsoleClaims{}
-	token, err := jwt.ParseWithClaims(bearerToken, &claims, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return JwtSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(bearerToken, &claims, signingKeys.verificationKey)
 	if err != nil || !token.Valid {
 		return nil, err
 	}
+	// the state of the OIDC log in is signed with the same keys
+	if slices.Contains(claims.Audience, oidcStateAudience) {
+		return nil, fmt.Errorf("token is not an access token")
+	}
 	return &claims, nil
 }
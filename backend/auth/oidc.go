@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/backend/logger"
+)
+
+const (
+	oidcIdentityProvider        = "oidc"
+	oidcHTTPTimeout             = 10 * time.Second
+	defaultOIDCDisplayNameClaim = "preferred_username"
+	defaultOIDCGroupsClaim      = "groups"
+)
+
+var defaultOIDCScopes = []string{"openid", "profile", "email", "groups"}
+
+// oidcDiscovery is the part of the provider metadata used by the
+// authorization code flow.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// OIDCProvider is an OpenID Connect provider the users log in through. Its
+// metadata and keys are fetched on the first log in, and the keys again when
+// the provider signs with an unknown key.
+type OIDCProvider struct {
+	config       factory.Oidc
+	clientSecret string
+	httpClient   *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]any
+}
+
+func NewOIDCProvider(config *factory.Oidc) (*OIDCProvider, error) {
+	if config.IssuerURL == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return nil, fmt.Errorf("oidc issuer-url, client-id and redirect-url are required")
+	}
+	provider := &OIDCProvider{
+		config:       *config,
+		clientSecret: config.ClientSecret,
+		httpClient:   &http.Client{Timeout: oidcHTTPTimeout},
+	}
+	if config.ClientSecretEnv != "" {
+		secret, found := os.LookupEnv(config.ClientSecretEnv)
+		if !found {
+			return nil, fmt.Errorf("environment variable %s of the oidc client secret is not set", config.ClientSecretEnv)
+		}
+		provider.clientSecret = strings.TrimSpace(secret)
+	}
+	if len(provider.config.Scopes) == 0 {
+		provider.config.Scopes = defaultOIDCScopes
+	}
+	if provider.config.DisplayNameClaim == "" {
+		provider.config.DisplayNameClaim = defaultOIDCDisplayNameClaim
+	}
+	if provider.config.GroupsClaim == "" {
+		provider.config.GroupsClaim = defaultOIDCGroupsClaim
+	}
+	return provider, nil
+}
+
+func closeResponseBody(resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		logger.AuthLog.Warnf("failed to close response body: %v", err)
+	}
+}
+
+func (p *OIDCProvider) getJSON(endpoint string, v any) error {
+	resp, err := p.httpClient.Get(endpoint)
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(resp)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", endpoint, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+func (p *OIDCProvider) getDiscovery() (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	issuer := strings.TrimSuffix(p.config.IssuerURL, "/")
+	var discovery oidcDiscovery
+	if err := p.getJSON(issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider: %w", err)
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc provider issuer %s does not match %s", discovery.Issuer, p.config.IssuerURL)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc provider metadata is incomplete")
+	}
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// pkceChallenge is the S256 code challenge of the verifier, RFC 7636.
+func pkceChallenge(verifier string) string {
+	digest := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+func (p *OIDCProvider) authCodeURL(discovery *oidcDiscovery, state, nonce, verifier string) string {
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {pkceChallenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode()
+}
+
+// exchange redeems the authorization code for the ID token of the user.
+func (p *OIDCProvider) exchange(discovery *oidcDiscovery, code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	if p.clientSecret == "" {
+		form.Set("client_id", p.config.ClientID)
+	}
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.clientSecret))
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer closeResponseBody(resp)
+	var tokenResponse oidcTokenResponse
+	if err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %s: %s %s", resp.Status, tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+	if tokenResponse.IDToken == "" {
+		return "", fmt.Errorf("token response has no ID token")
+	}
+	return tokenResponse.IDToken, nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+// parseJSONWebKey returns the public key of an RSA or P-256 JWK.
+func parseJSONWebKey(webKey JSONWebKey) (any, error) {
+	switch webKey.Kty {
+	case "RSA":
+		n, err := decodeBigInt(webKey.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(webKey.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if webKey.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", webKey.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(webKey.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(webKey.Y)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != 32 || len(y) != 32 {
+			return nil, fmt.Errorf("invalid P-256 point")
+		}
+		// rejects the points that are not on the curve
+		if _, err = ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", webKey.Kty)
+}
+
+// publicKey returns the key of the provider with the given ID, fetching the
+// keys again if it is unknown.
+func (p *OIDCProvider) publicKey(discovery *oidcDiscovery, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, found := p.keys[kid]; found {
+		return key, nil
+	}
+	var jwks JWKSResponse
+	if err := p.getJSON(discovery.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch oidc provider keys: %w", err)
+	}
+	p.keys = map[string]any{}
+	for _, webKey := range jwks.Keys {
+		if webKey.Use != "" && webKey.Use != "sig" {
+			continue
+		}
+		key, err := parseJSONWebKey(webKey)
+		if err != nil {
+			continue
+		}
+		p.keys[webKey.Kid] = key
+	}
+	key, found := p.keys[kid]
+	if !found {
+		return nil, fmt.Errorf("unknown oidc provider key %s", kid)
+	}
+	return key, nil
+}
+
+// verifyIDToken checks the signature, issuer, audience, expiry and nonce of
+// the ID token and returns its claims.
+func (p *OIDCProvider) verifyIDToken(discovery *oidcDiscovery, rawIDToken, nonce string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(discovery, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, fmt.Errorf("ID token nonce does not match")
+	}
+	return claims, nil
+}
+
+// oidcAccountUsername returns the username of the account of an OIDC
+// subject. The claims chosen by the user, such as preferred_username, are
+// neither unique nor stable, so the account is keyed on the issuer and the
+// subject.
+func oidcAccountUsername(issuer, subject string) string {
+	sum := sha256.Sum256([]byte(issuer + "\n" + subject))
+	return "oidc-" + hex.EncodeToString(sum[:12])
+}
+
+// userFromClaims returns the account username, the display name and the
+// groups of the ID token. The groups claim may be a list or a single value.
+func (p *OIDCProvider) userFromClaims(claims jwt.MapClaims) (string, string, []string, error) {
+	issuer, _ := claims["iss"].(string)
+	subject, _ := claims["sub"].(string)
+	if issuer == "" || subject == "" {
+		return "", "", nil, fmt.Errorf("ID token has no iss or sub claim")
+	}
+	displayName, _ := claims[p.config.DisplayNameClaim].(string)
+	if displayName == "" {
+		displayName = subject
+	}
+	groups := []string{}
+	switch value := claims[p.config.GroupsClaim].(type) {
+	case string:
+		groups = append(groups, value)
+	case []any:
+		for _, group := range value {
+			if name, ok := group.(string); ok {
+				groups = append(groups, name)
+			}
+		}
+	}
+	return oidcAccountUsername(issuer, subject), displayName, groups, nil
+}
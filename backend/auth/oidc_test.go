@@ -0,0 +1,422 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+)
+
+const (
+	mockOIDCClientID     = "webui"
+	mockOIDCClientSecret = "mockClientSecret"
+	mockOIDCSubject      = "mock-subject"
+	mockOIDCRedirectURL  = "http://webui.example.com/oidc/callback"
+)
+
+type mockOIDCGrant struct {
+	nonce     string
+	challenge string
+}
+
+// mockOIDCProvider is an OpenID Connect provider authenticating every user as
+// its claims, after an authorization request to /authorize.
+type mockOIDCProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	// claims of the ID tokens issued, along with iss, aud, exp and nonce
+	claims jwt.MapClaims
+	// overrides of the claims set by the provider
+	override jwt.MapClaims
+
+	mu     sync.Mutex
+	grants map[string]mockOIDCGrant
+}
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	mock := &mockOIDCProvider{key: key, grants: map[string]mockOIDCGrant{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeMockJSON(w, http.StatusOK, oidcDiscovery{
+			Issuer:                mock.server.URL,
+			AuthorizationEndpoint: mock.server.URL + "/authorize",
+			TokenEndpoint:         mock.server.URL + "/token",
+			JWKSURI:               mock.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeMockJSON(w, http.StatusOK, JWKSResponse{Keys: []JSONWebKey{{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: "mock-key",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/authorize", mock.authorize)
+	mux.HandleFunc("/token", mock.token)
+	mock.server = httptest.NewServer(mux)
+	t.Cleanup(mock.server.Close)
+	return mock
+}
+
+func writeMockJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (m *mockOIDCProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	code, _ := randomToken(16)
+	m.mu.Lock()
+	m.grants[code] = mockOIDCGrant{nonce: query.Get("nonce"), challenge: query.Get("code_challenge")}
+	m.mu.Unlock()
+	redirect := query.Get("redirect_uri") + "?" + url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+func (m *mockOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, _ := r.BasicAuth()
+	if clientID != mockOIDCClientID || clientSecret != mockOIDCClientSecret {
+		writeMockJSON(w, http.StatusUnauthorized, oidcTokenResponse{Error: "invalid_client"})
+		return
+	}
+	m.mu.Lock()
+	grant, found := m.grants[r.PostFormValue("code")]
+	delete(m.grants, r.PostFormValue("code"))
+	m.mu.Unlock()
+	if !found || pkceChallenge(r.PostFormValue("code_verifier")) != grant.challenge {
+		writeMockJSON(w, http.StatusBadRequest, oidcTokenResponse{Error: "invalid_grant"})
+		return
+	}
+	claims := jwt.MapClaims{
+		"iss":   m.server.URL,
+		"sub":   mockOIDCSubject,
+		"aud":   mockOIDCClientID,
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": grant.nonce,
+	}
+	for name, value := range m.claims {
+		claims[name] = value
+	}
+	for name, value := range m.override {
+		claims[name] = value
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "mock-key"
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		writeMockJSON(w, http.StatusInternalServerError, oidcTokenResponse{Error: "server_error"})
+		return
+	}
+	writeMockJSON(w, http.StatusOK, oidcTokenResponse{IDToken: idToken})
+}
+
+func setUpOIDCRouter(t *testing.T, mock *mockOIDCProvider, config factory.Oidc) (*gin.Engine, *MockMongoClientSessions) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	signingKeys := NewSigningKeys([]byte("mockSecret"))
+	config.IssuerURL = mock.server.URL
+	config.ClientID = mockOIDCClientID
+	config.ClientSecret = mockOIDCClientSecret
+	config.RedirectURL = mockOIDCRedirectURL
+	provider, err := NewOIDCProvider(&config)
+	if err != nil {
+		t.Fatalf("failed to create OIDC provider: %v", err)
+	}
+	AddOIDCService(router, signingKeys, provider)
+	router.GET("/subscribers", AdminOrUserAuthMiddleware(signingKeys), RequirePermission(configmodels.PermissionSubscribersWrite), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+	router.GET("/network", AdminOrUserAuthMiddleware(signingKeys), RequirePermission(configmodels.PermissionNetworkWrite), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+	mockDB := newMockMongoClientSessions()
+	dbadapter.WebuiDBClient = mockDB
+	if err = SeedBuiltinRoles(); err != nil {
+		t.Fatalf("failed to seed roles: %v", err)
+	}
+	return router, mockDB
+}
+
+// oidcLogin goes through the log in at the router and the authorization at
+// the mock provider, and returns the callback response.
+func oidcLogin(t *testing.T, router *gin.Engine, callback func(query url.Values, cookie *http.Cookie) (url.Values, *http.Cookie)) *httptest.ResponseRecorder {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, "/oidc/login", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected a redirect to the provider, got %v %s", w.Code, w.Body.String())
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != oidcStateCookie || !cookies[0].HttpOnly {
+		t.Fatalf("expected an HttpOnly state cookie, got %+v", cookies)
+	}
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("failed to authorize: %v", err)
+	}
+	_ = resp.Body.Close()
+	redirect, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || redirect.Scheme+"://"+redirect.Host+redirect.Path != mockOIDCRedirectURL {
+		t.Fatalf("expected a redirect to the callback, got %v", resp.Header.Get("Location"))
+	}
+	query, cookie := redirect.Query(), cookies[0]
+	if callback != nil {
+		query, cookie = callback(query, cookie)
+	}
+	req, err = http.NewRequest(http.MethodGet, "/oidc/callback?"+query.Encode(), nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOIDCLogin(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+	mock.claims = jwt.MapClaims{"preferred_username": "alice", "groups": []string{"staff", "sdcore-operators"}}
+	router, mockDB := setUpOIDCRouter(t, mock, factory.Oidc{
+		RoleMappings: []factory.RoleMapping{
+			{Group: "sdcore-admins", Role: "admin"},
+			{Group: "sdcore-operators", Role: "subscriber-operator"},
+		},
+	})
+
+	w := oidcLogin(t, router, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected `%v`, got `%v` %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response LoginResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Token == "" || response.RefreshToken == "" {
+		t.Fatalf("expected a token and a refresh token, got %s", w.Body.String())
+	}
+	if code := getWithHeader(t, router, "/subscribers", "Authorization", "Bearer "+response.Token); code != http.StatusOK {
+		t.Errorf("expected the mapped role to grant subscribers:write, got %v", code)
+	}
+	if code := getWithHeader(t, router, "/network", "Authorization", "Bearer "+response.Token); code != http.StatusForbidden {
+		t.Errorf("expected the mapped role not to grant network:write, got %v", code)
+	}
+	dbUser, err := fetchMockUserAccount(mockDB, oidcAccountUsername(mock.server.URL, mockOIDCSubject))
+	if err != nil || dbUser.IdentityProvider != oidcIdentityProvider || dbUser.RoleName != "subscriber-operator" || dbUser.HashedPassword != "" || dbUser.DisplayName != "alice" {
+		t.Errorf("expected a shadow account, got %+v %v", dbUser, err)
+	}
+
+	// the role follows the groups at the next log in
+	mock.claims["groups"] = "sdcore-admins"
+	if w = oidcLogin(t, router, nil); w.Code != http.StatusOK {
+		t.Fatalf("expected `%v`, got `%v` %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	dbUser, err = fetchMockUserAccount(mockDB, oidcAccountUsername(mock.server.URL, mockOIDCSubject))
+	if err != nil || dbUser.Role != configmodels.AdminRole || dbUser.RoleName != "" {
+		t.Errorf("expected the account to be updated to admin, got %+v %v", dbUser, err)
+	}
+}
+
+func TestOIDCLogin_AccountsAreKeyedOnSubject(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+	mock.claims = jwt.MapClaims{"preferred_username": "alice", "groups": "sdcore-operators"}
+	router, mockDB := setUpOIDCRouter(t, mock, factory.Oidc{
+		RoleMappings: []factory.RoleMapping{
+			{Group: "sdcore-admins", Role: "admin"},
+			{Group: "sdcore-operators", Role: "subscriber-operator"},
+		},
+	})
+	if w := oidcLogin(t, router, nil); w.Code != http.StatusOK {
+		t.Fatalf("expected `%v`, got `%v` %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	// another subject choosing the same preferred_username gets its own account
+	mock.claims = jwt.MapClaims{"preferred_username": "alice", "groups": "sdcore-admins", "sub": "other-subject"}
+	if w := oidcLogin(t, router, nil); w.Code != http.StatusOK {
+		t.Fatalf("expected `%v`, got `%v` %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	dbUser, err := fetchMockUserAccount(mockDB, oidcAccountUsername(mock.server.URL, mockOIDCSubject))
+	if err != nil || dbUser.Role != configmodels.UserRole || dbUser.RoleName != "subscriber-operator" {
+		t.Errorf("expected the first account to keep its role, got %+v %v", dbUser, err)
+	}
+	dbUser, err = fetchMockUserAccount(mockDB, oidcAccountUsername(mock.server.URL, "other-subject"))
+	if err != nil || dbUser.Role != configmodels.AdminRole || dbUser.DisplayName != "alice" {
+		t.Errorf("expected a separate admin account, got %+v %v", dbUser, err)
+	}
+}
+
+func fetchMockUserAccount(db *MockMongoClientSessions, username string) (*configmodels.DBUserAccount, error) {
+	rawUser, err := db.RestfulAPIGetOne(configmodels.UserAccountDataColl, map[string]any{"username": username})
+	if err != nil {
+		return nil, err
+	}
+	var dbUser configmodels.DBUserAccount
+	err = json.Unmarshal(configmodels.MapToByte(rawUser), &dbUser)
+	return &dbUser, err
+}
+
+func TestOIDCLogin_FailureCases(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       factory.Oidc
+		claims       jwt.MapClaims
+		override     jwt.MapClaims
+		callback     func(query url.Values, cookie *http.Cookie) (url.Values, *http.Cookie)
+		expectedCode int
+	}{
+		{
+			name:         "NoRoleMapped",
+			claims:       jwt.MapClaims{"preferred_username": "alice", "groups": []string{"staff"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "DefaultRole",
+			config:       factory.Oidc{DefaultRole: "read-only"},
+			claims:       jwt.MapClaims{"preferred_username": "alice", "groups": []string{"staff"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "MissingMappedRole",
+			config:       factory.Oidc{DefaultRole: "missing"},
+			claims:       jwt.MapClaims{"preferred_username": "alice"},
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "LocalAccountWithSamePreferredUsername",
+			config:       factory.Oidc{DefaultRole: "read-only"},
+			claims:       jwt.MapClaims{"preferred_username": "janedoe"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "MissingDisplayNameClaim",
+			config:       factory.Oidc{DefaultRole: "read-only", DisplayNameClaim: "email"},
+			claims:       jwt.MapClaims{"preferred_username": "alice"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "MissingSubject",
+			config:       factory.Oidc{DefaultRole: "read-only"},
+			claims:       jwt.MapClaims{"preferred_username": "alice"},
+			override:     jwt.MapClaims{"sub": ""},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "WrongNonce",
+			config:       factory.Oidc{DefaultRole: "read-only"},
+			claims:       jwt.MapClaims{"preferred_username": "alice"},
+			override:     jwt.MapClaims{"nonce": "other"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "WrongAudience",
+			config:       factory.Oidc{DefaultRole: "read-only"},
+			claims:       jwt.MapClaims{"preferred_username": "alice"},
+			override:     jwt.MapClaims{"aud": "other"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "ExpiredIDToken",
+			config:       factory.Oidc{DefaultRole: "read-only"},
+			claims:       jwt.MapClaims{"preferred_username": "alice"},
+			override:     jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "StateMismatch",
+			config: factory.Oidc{DefaultRole: "read-only"},
+			claims: jwt.MapClaims{"preferred_username": "alice"},
+			callback: func(query url.Values, cookie *http.Cookie) (url.Values, *http.Cookie) {
+				query.Set("state", "other")
+				return query, cookie
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "MissingStateCookie",
+			config: factory.Oidc{DefaultRole: "read-only"},
+			claims: jwt.MapClaims{"preferred_username": "alice"},
+			callback: func(query url.Values, _ *http.Cookie) (url.Values, *http.Cookie) {
+				return query, nil
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "ProviderError",
+			config: factory.Oidc{DefaultRole: "read-only"},
+			claims: jwt.MapClaims{"preferred_username": "alice"},
+			callback: func(query url.Values, cookie *http.Cookie) (url.Values, *http.Cookie) {
+				query.Del("code")
+				query.Set("error", "access_denied")
+				return query, cookie
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "UnknownCode",
+			config: factory.Oidc{DefaultRole: "read-only"},
+			claims: jwt.MapClaims{"preferred_username": "alice"},
+			callback: func(query url.Values, cookie *http.Cookie) (url.Values, *http.Cookie) {
+				query.Set("code", "other")
+				return query, cookie
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := newMockOIDCProvider(t)
+			mock.claims = tc.claims
+			mock.override = tc.override
+			router, _ := setUpOIDCRouter(t, mock, tc.config)
+			if w := oidcLogin(t, router, tc.callback); w.Code != tc.expectedCode {
+				t.Errorf("expected `%v`, got `%v` %s", tc.expectedCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOIDCStateIsNotAnAccessToken(t *testing.T) {
+	signingKeys := NewSigningKeys([]byte("mockSecret"))
+	stateToken, err := signingKeys.sign(oidcStateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "state",
+			Audience:  jwt.ClaimStrings{oidcStateAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to sign state: %v", err)
+	}
+	if _, err = getClaimsFromJWT(stateToken, signingKeys); err == nil {
+		t.Errorf("expected the state of a log in to be rejected as an access token")
+	}
+}
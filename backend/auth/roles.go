@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const claimsContextKey = "authClaims"
+
+// SeedBuiltinRoles creates the built-in roles missing from the webui DB. The
+// roles edited by an admin are kept as they are.
+func SeedBuiltinRoles() error {
+	for _, role := range configmodels.BuiltinRoles {
+		filter := bson.M{"name": role.Name}
+		if _, err := dbadapter.WebuiDBClient.RestfulAPIPutOneNotUpdate(configmodels.RoleDataColl, filter, configmodels.ToBsonM(role)); err != nil {
+			return fmt.Errorf("failed to create role %s: %w", role.Name, err)
+		}
+	}
+	return nil
+}
+
+// FetchRole returns the role with the given name, or nil if there is none.
+func FetchRole(name string) (*configmodels.Role, error) {
+	rawRole, err := dbadapter.WebuiDBClient.RestfulAPIGetOne(configmodels.RoleDataColl, bson.M{"name": name})
+	if err != nil {
+		return nil, err
+	}
+	if len(rawRole) == 0 {
+		return nil, nil
+	}
+	var role configmodels.Role
+	if err = json.Unmarshal(configmodels.MapToByte(rawRole), &role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// RequirePermission checks that the role of the token authenticated by
+// AdminOrUserAuthMiddleware grants the permission, as well as the scope of
+// API keys. The role is read on every request so that edits apply to the
+// tokens already issued. Requests are let through when authentication is
+// disabled.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, authenticated := c.Get(claimsContextKey)
+		if !authenticated {
+			c.Next()
+			return
+		}
+		claims := value.(*jwtWebconsoleClaims)
+		if claims.Role == configmodels.AdminRole {
+			c.Next()
+			return
+		}
+		roleName := claims.RoleName
+		if roleName == "" {
+			roleName = configmodels.DefaultRoleName
+		}
+		role, err := FetchRole(roleName)
+		if err != nil {
+			logger.AuthLog.Errorln(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to authorize"})
+			c.Abort()
+			return
+		}
+		if role == nil || !role.HasPermission(permission) || (claims.scope != nil && !slices.Contains(claims.scope, permission)) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("forbidden: %s permission required", permission)})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+)
+
+func TestRequirePermission(t *testing.T) {
+	testCases := []struct {
+		name         string
+		role         int
+		roleName     string
+		permission   string
+		expectedCode int
+	}{
+		{"admin account", configmodels.AdminRole, "", configmodels.PermissionK4Write, http.StatusOK},
+		{"account without role", configmodels.UserRole, "", configmodels.PermissionK4Write, http.StatusOK},
+		{"permission granted", configmodels.UserRole, "read-only", configmodels.PermissionNetworkRead, http.StatusOK},
+		{"permission missing", configmodels.UserRole, "read-only", configmodels.PermissionNetworkWrite, http.StatusForbidden},
+		{"deleted role", configmodels.UserRole, "deleted", configmodels.PermissionNetworkRead, http.StatusForbidden},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+			signingKeys := NewSigningKeys([]byte("mockSecret"))
+			router.GET("/protected", AdminOrUserAuthMiddleware(signingKeys), RequirePermission(tc.permission), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{})
+			})
+			mockDB := newMockMongoClientSessions()
+			dbadapter.WebuiDBClient = mockDB
+			if err := SeedBuiltinRoles(); err != nil {
+				t.Fatalf("failed to seed roles: %v", err)
+			}
+			sessionID, _, err := createSession("janedoe")
+			if err != nil {
+				t.Fatalf("failed to create session: %v", err)
+			}
+			token, err := GenerateJWT("janedoe", tc.role, tc.roleName, sessionID, signingKeys)
+			if err != nil {
+				t.Fatalf("failed to generate token: %v", err)
+			}
+			if code := getProtected(t, router, token); code != tc.expectedCode {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestRequirePermission_AuthenticationDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/protected", RequirePermission(configmodels.PermissionK4Write), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+	req, err := http.NewRequest(http.MethodGet, "/protected", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected `%v`, got `%v`", http.StatusOK, w.Code)
+	}
+}
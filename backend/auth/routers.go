@@ -18,9 +18,12 @@ type Route struct {
 
 type Routes []Route
 
-func AddAuthenticationService(engine *gin.Engine, jwtSecret []byte) {
+// AddAuthenticationService adds the log in routes. Users of the directory of
+// ldapAuthenticator can log in too if it is not nil, and the attempts are not
+// throttled if loginThrottle is nil.
+func AddAuthenticationService(engine *gin.Engine, signingKeys *SigningKeys, ldapAuthenticator *LDAPAuthenticator, loginThrottle *LoginThrottle) {
 	group := engine.Group("/")
-	addRoutes(group, getAuthenticationRoutes(jwtSecret))
+	addRoutes(group, getAuthenticationRoutes(signingKeys, ldapAuthenticator, loginThrottle))
 }
 
 func addRoutes(group *gin.RouterGroup, routes Routes) {
@@ -38,13 +41,25 @@ func addRoutes(group *gin.RouterGroup, routes Routes) {
 	}
 }
 
-func getAuthenticationRoutes(jwtSecret []byte) Routes {
+func getAuthenticationRoutes(signingKeys *SigningKeys, ldapAuthenticator *LDAPAuthenticator, loginThrottle *LoginThrottle) Routes {
 	return Routes{
 		{
 			"Login",
 			http.MethodPost,
 			"/login",
-			Login(jwtSecret),
+			Login(signingKeys, ldapAuthenticator, loginThrottle),
+		},
+		{
+			"Refresh",
+			http.MethodPost,
+			"/refresh",
+			Refresh(signingKeys),
+		},
+		{
+			"Logout",
+			http.MethodPost,
+			"/logout",
+			Logout(signingKeys),
 		},
 		{
 			"Status",
@@ -52,5 +67,11 @@ func getAuthenticationRoutes(jwtSecret []byte) Routes {
 			"/status",
 			GetStatus(),
 		},
+		{
+			"JWKS",
+			http.MethodGet,
+			"/.well-known/jwks.json",
+			GetJWKS(signingKeys),
+		},
 	}
 }
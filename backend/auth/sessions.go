@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const refreshTokenExpirationDuration = time.Hour * 24
+
+func randomToken(size int) (string, error) {
+	bytes := make([]byte, size)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func hashToken(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
+
+// storeRefreshToken issues the refresh token of a new session.
+func storeRefreshToken(sessionID, username string) (string, error) {
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	session := configmodels.DBRefreshToken{
+		SessionID: sessionID,
+		Username:  username,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(refreshTokenExpirationDuration).Unix(),
+	}
+	filter := bson.M{"sessionId": sessionID}
+	if _, err = dbadapter.WebuiDBClient.RestfulAPIPost(configmodels.RefreshTokenDataColl, filter, configmodels.ToBsonM(session)); err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return refreshToken, nil
+}
+
+// rotateRefreshToken replaces the refresh token of the session with a new
+// one. The replacement is conditioned on the token presented, so that of two
+// concurrent refreshes with the same token only one succeeds; the other gets
+// an empty token. The session keeps its expiry, which is set at the log in.
+func rotateRefreshToken(session *configmodels.DBRefreshToken) (string, error) {
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	filter := bson.M{"sessionId": session.SessionID, "tokenHash": session.TokenHash}
+	rotated, err := dbadapter.WebuiDBClient.RestfulAPIUpdateOne(configmodels.RefreshTokenDataColl, filter, bson.M{"tokenHash": hashToken(refreshToken)})
+	if err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	if !rotated {
+		return "", nil
+	}
+	return refreshToken, nil
+}
+
+// createSession starts a session for the user and returns its ID and
+// refresh token. The expired sessions of the user are removed on the way.
+func createSession(username string) (string, string, error) {
+	expired := bson.M{"username": username, "expiresAt": bson.M{"$lt": time.Now().Unix()}}
+	if err := dbadapter.WebuiDBClient.RestfulAPIDeleteMany(configmodels.RefreshTokenDataColl, expired); err != nil {
+		logger.AuthLog.Warnf("failed to remove expired sessions of %s: %v", username, err)
+	}
+	sessionID, err := randomToken(16)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	refreshToken, err := storeRefreshToken(sessionID, username)
+	if err != nil {
+		return "", "", err
+	}
+	return sessionID, refreshToken, nil
+}
+
+// fetchSessionByRefreshToken returns the session of a refresh token, or nil
+// if there is none.
+func fetchSessionByRefreshToken(refreshToken string) (*configmodels.DBRefreshToken, error) {
+	filter := bson.M{"tokenHash": hashToken(refreshToken)}
+	rawSession, err := dbadapter.WebuiDBClient.RestfulAPIGetOne(configmodels.RefreshTokenDataColl, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(rawSession) == 0 {
+		return nil, nil
+	}
+	var session configmodels.DBRefreshToken
+	if err = json.Unmarshal(configmodels.MapToByte(rawSession), &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// isSessionActive reports whether the session of an access token has not
+// been revoked.
+func isSessionActive(sessionID string) (bool, error) {
+	if sessionID == "" {
+		return false, nil
+	}
+	rawSession, err := dbadapter.WebuiDBClient.RestfulAPIGetOne(configmodels.RefreshTokenDataColl, bson.M{"sessionId": sessionID})
+	if err != nil {
+		return false, err
+	}
+	return len(rawSession) > 0, nil
+}
+
+func revokeSession(sessionID string) error {
+	return dbadapter.WebuiDBClient.RestfulAPIDeleteOne(configmodels.RefreshTokenDataColl, bson.M{"sessionId": sessionID})
+}
+
+// RevokeUserSessions logs the user out everywhere: the refresh tokens and the
+// access tokens of all the sessions of the user stop being accepted.
+func RevokeUserSessions(username string) error {
+	return dbadapter.WebuiDBClient.RestfulAPIDeleteMany(configmodels.RefreshTokenDataColl, bson.M{"username": username})
+}
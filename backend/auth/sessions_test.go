@@ -0,0 +1,346 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MockMongoClientSessions keeps the documents written to it, matching
+// filters on equal fields and $lt.
+type MockMongoClientSessions struct {
+	dbadapter.DBInterface
+	collections map[string][]map[string]any
+}
+
+func newMockMongoClientSessions() *MockMongoClientSessions {
+	return &MockMongoClientSessions{collections: map[string][]map[string]any{
+		configmodels.UserAccountDataColl: {
+			{"username": "janedoe", "password": hashPassword("password123!"), "role": configmodels.AdminRole},
+		},
+	}}
+}
+
+func matchesFilter(document map[string]any, filter bson.M) bool {
+	for key, value := range filter {
+		if condition, ok := value.(bson.M); ok {
+			// documents are written through JSON, so numbers are float64
+			if document[key].(float64) >= float64(condition["$lt"].(int64)) {
+				return false
+			}
+			continue
+		}
+		if document[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (db *MockMongoClientSessions) RestfulAPIGetOne(collName string, filter bson.M) (map[string]any, error) {
+	for _, document := range db.collections[collName] {
+		if matchesFilter(document, filter) {
+			return document, nil
+		}
+	}
+	return map[string]any{}, nil
+}
+
+func (db *MockMongoClientSessions) RestfulAPIPost(collName string, filter bson.M, postData map[string]any) (bool, error) {
+	for _, document := range db.collections[collName] {
+		if matchesFilter(document, filter) {
+			// the fields are set as with $set
+			for key, value := range postData {
+				document[key] = value
+			}
+			return true, nil
+		}
+	}
+	db.collections[collName] = append(db.collections[collName], postData)
+	return false, nil
+}
+
+func (db *MockMongoClientSessions) RestfulAPIUpdateOne(collName string, filter bson.M, setData map[string]any) (bool, error) {
+	for _, document := range db.collections[collName] {
+		if matchesFilter(document, filter) {
+			for key, value := range setData {
+				document[key] = value
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (db *MockMongoClientSessions) RestfulAPIIncrementOne(collName string, filter bson.M, incData map[string]any, setData map[string]any) (map[string]any, error) {
+	for _, document := range db.collections[collName] {
+		if matchesFilter(document, filter) {
+			for key, value := range incData {
+				count, _ := document[key].(float64)
+				document[key] = count + float64(value.(int))
+			}
+			for key, value := range setData {
+				document[key] = value
+			}
+			return document, nil
+		}
+	}
+	return nil, nil
+}
+
+func (db *MockMongoClientSessions) RestfulAPIPutOneNotUpdate(collName string, filter bson.M, putData map[string]any) (bool, error) {
+	for _, document := range db.collections[collName] {
+		if matchesFilter(document, filter) {
+			return true, nil
+		}
+	}
+	db.collections[collName] = append(db.collections[collName], putData)
+	return false, nil
+}
+
+func (db *MockMongoClientSessions) RestfulAPIDeleteOne(collName string, filter bson.M) error {
+	for i, document := range db.collections[collName] {
+		if matchesFilter(document, filter) {
+			db.collections[collName] = append(db.collections[collName][:i], db.collections[collName][i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (db *MockMongoClientSessions) RestfulAPIDeleteMany(collName string, filter bson.M) error {
+	kept := []map[string]any{}
+	for _, document := range db.collections[collName] {
+		if !matchesFilter(document, filter) {
+			kept = append(kept, document)
+		}
+	}
+	db.collections[collName] = kept
+	return nil
+}
+
+func setUpSessionRouter(t *testing.T) (*gin.Engine, *MockMongoClientSessions) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	signingKeys := NewSigningKeys([]byte("mockSecret"))
+	AddAuthenticationService(router, signingKeys, nil, nil)
+	router.GET("/protected", AdminOrUserAuthMiddleware(signingKeys), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+	mockDB := newMockMongoClientSessions()
+	dbadapter.WebuiDBClient = mockDB
+	return router, mockDB
+}
+
+func postSession(t *testing.T, router *gin.Engine, path, token, body string) (int, LoginResponse) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var response LoginResponse
+	if w.Code == http.StatusOK && path != "/logout" {
+		if err = json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("unable to unmarshal response `%v`", w.Body.String())
+		}
+	}
+	return w.Code, response
+}
+
+func getProtected(t *testing.T, router *gin.Engine, token string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, "/protected", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func login(t *testing.T, router *gin.Engine) LoginResponse {
+	t.Helper()
+	code, response := postSession(t, router, "/login", "", `{"username":"janedoe", "password":"password123!"}`)
+	if code != http.StatusOK || response.Token == "" || response.RefreshToken == "" {
+		t.Fatalf("expected a token and a refresh token, got %v %+v", code, response)
+	}
+	return response
+}
+
+func refresh(t *testing.T, router *gin.Engine, refreshToken string) (int, LoginResponse) {
+	t.Helper()
+	return postSession(t, router, "/refresh", "", `{"refreshToken":"`+refreshToken+`"}`)
+}
+
+func TestRefresh(t *testing.T) {
+	router, _ := setUpSessionRouter(t)
+	session := login(t, router)
+
+	code, refreshed := refresh(t, router, session.RefreshToken)
+	if code != http.StatusOK || refreshed.RefreshToken == session.RefreshToken {
+		t.Fatalf("expected a new refresh token, got %v %+v", code, refreshed)
+	}
+	if code = getProtected(t, router, refreshed.Token); code != http.StatusOK {
+		t.Errorf("expected the refreshed token to be accepted, got %v", code)
+	}
+	if code = getProtected(t, router, session.Token); code != http.StatusOK {
+		t.Errorf("expected the token of the session to be accepted until it expires, got %v", code)
+	}
+	if code, _ = refresh(t, router, session.RefreshToken); code != http.StatusUnauthorized {
+		t.Errorf("expected a used refresh token to be rejected, got %v", code)
+	}
+}
+
+func TestRefresh_KeepsTheSessionExpiry(t *testing.T) {
+	router, mockDB := setUpSessionRouter(t)
+	session := login(t, router)
+	expiresAt := float64(time.Now().Add(time.Hour).Unix())
+	mockDB.collections[configmodels.RefreshTokenDataColl][0]["expiresAt"] = expiresAt
+
+	code, refreshed := refresh(t, router, session.RefreshToken)
+	if code != http.StatusOK {
+		t.Fatalf("expected %v, got %v", http.StatusOK, code)
+	}
+	if sessions := mockDB.collections[configmodels.RefreshTokenDataColl]; len(sessions) != 1 || sessions[0]["expiresAt"] != expiresAt {
+		t.Errorf("expected the session to keep its expiry, got %+v", sessions)
+	}
+	if code, _ = refresh(t, router, refreshed.RefreshToken); code != http.StatusOK {
+		t.Errorf("expected the new refresh token to be accepted, got %v", code)
+	}
+}
+
+func TestRefresh_ConcurrentRotation(t *testing.T) {
+	router, _ := setUpSessionRouter(t)
+	session := login(t, router)
+	// a refresh reads the session, and another one rotates the token meanwhile
+	stale, err := fetchSessionByRefreshToken(session.RefreshToken)
+	if err != nil || stale == nil {
+		t.Fatalf("expected the session of the refresh token, got %+v %v", stale, err)
+	}
+	if code, _ := refresh(t, router, session.RefreshToken); code != http.StatusOK {
+		t.Fatalf("expected %v, got %v", http.StatusOK, code)
+	}
+	refreshToken, err := rotateRefreshToken(stale)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refreshToken != "" {
+		t.Errorf("expected the rotation of a used refresh token to fail")
+	}
+}
+
+func TestRefresh_FailureCases(t *testing.T) {
+	testCases := []struct {
+		name         string
+		prepare      func(db *MockMongoClientSessions)
+		body         func(refreshToken string) string
+		expectedCode int
+	}{
+		{
+			name:         "MissingRefreshToken",
+			body:         func(string) string { return `{}` },
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "InvalidData",
+			body:         func(string) string { return `{"refreshToken": 1}` },
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "UnknownRefreshToken",
+			body:         func(string) string { return `{"refreshToken":"unknown"}` },
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "ExpiredRefreshToken",
+			prepare: func(db *MockMongoClientSessions) {
+				db.collections[configmodels.RefreshTokenDataColl][0]["expiresAt"] = float64(0)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "DeletedUser",
+			prepare: func(db *MockMongoClientSessions) {
+				db.collections[configmodels.UserAccountDataColl] = nil
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, mockDB := setUpSessionRouter(t)
+			session := login(t, router)
+			if tc.prepare != nil {
+				tc.prepare(mockDB)
+			}
+			body := `{"refreshToken":"` + session.RefreshToken + `"}`
+			if tc.body != nil {
+				body = tc.body(session.RefreshToken)
+			}
+			if code, _ := postSession(t, router, "/refresh", "", body); code != tc.expectedCode {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	router, _ := setUpSessionRouter(t)
+	session := login(t, router)
+	other := login(t, router)
+
+	if code, _ := postSession(t, router, "/logout", session.Token, ""); code != http.StatusOK {
+		t.Fatalf("expected `%v`, got `%v`", http.StatusOK, code)
+	}
+	if code := getProtected(t, router, session.Token); code != http.StatusUnauthorized {
+		t.Errorf("expected the token to be revoked, got %v", code)
+	}
+	if code, _ := refresh(t, router, session.RefreshToken); code != http.StatusUnauthorized {
+		t.Errorf("expected the refresh token to be revoked, got %v", code)
+	}
+	if code, _ := postSession(t, router, "/logout", session.Token, ""); code != http.StatusUnauthorized {
+		t.Errorf("expected a second logout to be rejected, got %v", code)
+	}
+	if code := getProtected(t, router, other.Token); code != http.StatusOK {
+		t.Errorf("expected the other session to be kept, got %v", code)
+	}
+	if code, _ := postSession(t, router, "/logout", "", ""); code != http.StatusUnauthorized {
+		t.Errorf("expected a logout without token to be rejected, got %v", code)
+	}
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	router, _ := setUpSessionRouter(t)
+	first := login(t, router)
+	second := login(t, router)
+
+	if err := RevokeUserSessions("janedoe"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, session := range []LoginResponse{first, second} {
+		if code := getProtected(t, router, session.Token); code != http.StatusUnauthorized {
+			t.Errorf("expected the token to be revoked, got %v", code)
+		}
+		if code, _ := refresh(t, router, session.RefreshToken); code != http.StatusUnauthorized {
+			t.Errorf("expected the refresh token to be revoked, got %v", code)
+		}
+	}
+}
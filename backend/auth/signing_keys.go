@@ -0,0 +1,272 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/backend/ssm/apiclient"
+)
+
+const (
+	defaultVaultKeyField = "key"
+	minHMACSecretLength  = 32
+)
+
+// signingKey is a key tokens are signed with, identified by the kid header of
+// the tokens. For HS256 both keys are the secret.
+type signingKey struct {
+	kid        string
+	method     jwt.SigningMethod
+	privateKey any
+	publicKey  any
+	// the previous key is not accepted after notAfter
+	notAfter time.Time
+}
+
+// SigningKeys signs tokens with the current key and verifies them with the
+// current key or, for a grace period after a rotation, the previous one.
+type SigningKeys struct {
+	current  signingKey
+	previous *signingKey
+}
+
+// NewSigningKeys returns HS256 signing keys with the given secret.
+func NewSigningKeys(secret []byte) *SigningKeys {
+	kid, _ := keyID(secret) // digests of secrets never fail
+	return &SigningKeys{current: signingKey{kid: kid, method: jwt.SigningMethodHS256, privateKey: secret, publicKey: secret}}
+}
+
+// LoadSigningKeys reads the signing keys of the configuration. When none is
+// configured, a random secret is generated, so tokens do not outlive the
+// process and are not accepted by other replicas.
+func LoadSigningKeys(config *factory.JwtKeys) (*SigningKeys, error) {
+	if config == nil || config.Key == nil {
+		secret, err := GenerateJWTSecret()
+		if err != nil {
+			return nil, err
+		}
+		logger.AuthLog.Warnln("no JWT signing key configured, using a random key")
+		return NewSigningKeys(secret), nil
+	}
+	current, err := loadSigningKey(config.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWT signing key: %w", err)
+	}
+	keys := &SigningKeys{current: current}
+	logger.AuthLog.Infof("signing tokens with %s key %s", current.method.Alg(), current.kid)
+	if config.PreviousKey == nil {
+		return keys, nil
+	}
+	// the retirement time is absolute, so that restarts and replicas do not
+	// extend the life of the previous key
+	if config.PreviousKeyRetireAt == "" {
+		return nil, fmt.Errorf("previous-key-retire-at is required with previous-key")
+	}
+	notAfter, err := time.Parse(time.RFC3339, config.PreviousKeyRetireAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid previous-key-retire-at: %w", err)
+	}
+	previous, err := loadSigningKey(config.PreviousKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load previous JWT signing key: %w", err)
+	}
+	previous.notAfter = notAfter
+	keys.previous = &previous
+	logger.AuthLog.Infof("accepting tokens of previous %s key %s until %s", previous.method.Alg(), previous.kid, previous.notAfter.Format(time.RFC3339))
+	return keys, nil
+}
+
+func loadSigningKey(config *factory.JwtKey) (signingKey, error) {
+	material, err := readKeyMaterial(config)
+	if err != nil {
+		return signingKey{}, err
+	}
+	switch config.Algorithm {
+	case "", jwt.SigningMethodHS256.Alg():
+		secret := bytes.TrimSpace(material)
+		if len(secret) < minHMACSecretLength {
+			return signingKey{}, fmt.Errorf("HS256 secret must be at least %d bytes long", minHMACSecretLength)
+		}
+		return newSigningKey(jwt.SigningMethodHS256, secret, secret)
+	case jwt.SigningMethodRS256.Alg():
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(material)
+		if err != nil {
+			return signingKey{}, fmt.Errorf("invalid RS256 key: %w", err)
+		}
+		return newSigningKey(jwt.SigningMethodRS256, privateKey, &privateKey.PublicKey)
+	case jwt.SigningMethodES256.Alg():
+		privateKey, err := jwt.ParseECPrivateKeyFromPEM(material)
+		if err != nil {
+			return signingKey{}, fmt.Errorf("invalid ES256 key: %w", err)
+		}
+		if privateKey.Curve != elliptic.P256() {
+			return signingKey{}, fmt.Errorf("ES256 key must use the P-256 curve")
+		}
+		return newSigningKey(jwt.SigningMethodES256, privateKey, &privateKey.PublicKey)
+	default:
+		return signingKey{}, fmt.Errorf("unsupported algorithm %s", config.Algorithm)
+	}
+}
+
+// keyID derives the kid of a key from the digest of its public part, so that
+// replicas sharing a key agree on it.
+func keyID(publicKey any) (string, error) {
+	material, ok := publicKey.([]byte)
+	if !ok {
+		var err error
+		if material, err = x509.MarshalPKIXPublicKey(publicKey); err != nil {
+			return "", fmt.Errorf("failed to marshal public key: %w", err)
+		}
+	}
+	digest := sha256.Sum256(material)
+	return base64.RawURLEncoding.EncodeToString(digest[:12]), nil
+}
+
+func newSigningKey(method jwt.SigningMethod, privateKey, publicKey any) (signingKey, error) {
+	kid, err := keyID(publicKey)
+	if err != nil {
+		return signingKey{}, err
+	}
+	return signingKey{kid: kid, method: method, privateKey: privateKey, publicKey: publicKey}, nil
+}
+
+func readKeyMaterial(config *factory.JwtKey) ([]byte, error) {
+	switch {
+	case config.File != "":
+		material, err := os.ReadFile(config.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		return material, nil
+	case config.Env != "":
+		material := os.Getenv(config.Env)
+		if material == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", config.Env)
+		}
+		return []byte(material), nil
+	case config.VaultPath != "":
+		field := config.VaultField
+		if field == "" {
+			field = defaultVaultKeyField
+		}
+		return readVaultKey(config.VaultPath, field)
+	default:
+		return nil, fmt.Errorf("one of file, env or vault-path is required")
+	}
+}
+
+func readVaultKey(path, field string) ([]byte, error) {
+	client, err := apiclient.GetVaultClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Vault client: %w", err)
+	}
+	if apiclient.VaultAuthToken == "" && client.Token() == "" {
+		if _, err = apiclient.LoginVault(); err != nil {
+			return nil, fmt.Errorf("failed to authenticate to Vault: %w", err)
+		}
+	}
+	secret, err := client.Logical().Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Vault secret %s: %w", path, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("no data returned for Vault secret %s", path)
+	}
+	data := secret.Data
+	// KV version 2 nests the fields of the secret under data
+	if nested, ok := data["data"].(map[string]any); ok {
+		data = nested
+	}
+	material, ok := data[field].(string)
+	if !ok || material == "" {
+		return nil, fmt.Errorf("field %s not found in Vault secret %s", field, path)
+	}
+	return []byte(material), nil
+}
+
+func (k *SigningKeys) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.current.method, claims)
+	token.Header["kid"] = k.current.kid
+	return token.SignedString(k.current.privateKey)
+}
+
+// verificationKey is the jwt.Keyfunc of the tokens. Tokens without a kid are
+// verified with the current key.
+func (k *SigningKeys) verificationKey(token *jwt.Token) (any, error) {
+	key := &k.current
+	if kid, ok := token.Header["kid"].(string); ok && kid != k.current.kid {
+		if k.previous == nil || kid != k.previous.kid {
+			return nil, fmt.Errorf("unknown signing key %s", kid)
+		}
+		if time.Now().After(k.previous.notAfter) {
+			return nil, fmt.Errorf("signing key %s has been retired", kid)
+		}
+		key = k.previous
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.publicKey, nil
+}
+
+// acceptedKeys returns the keys tokens are verified with now.
+func (k *SigningKeys) acceptedKeys() []signingKey {
+	keys := []signingKey{k.current}
+	if k.previous != nil && time.Now().Before(k.previous.notAfter) {
+		keys = append(keys, *k.previous)
+	}
+	return keys
+}
+
+// JSONWebKey is the public part of an RS256 or ES256 signing key, as defined
+// by RFC 7517.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// publicKeys returns the accepted asymmetric keys. HS256 secrets are never
+// published.
+func (k *SigningKeys) publicKeys() []JSONWebKey {
+	webKeys := []JSONWebKey{}
+	for _, key := range k.acceptedKeys() {
+		webKey := JSONWebKey{Use: "sig", Alg: key.method.Alg(), Kid: key.kid}
+		switch publicKey := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			webKey.Kty = "RSA"
+			webKey.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			webKey.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case *ecdsa.PublicKey:
+			webKey.Kty = "EC"
+			webKey.Crv = publicKey.Curve.Params().Name
+			webKey.X = base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, 32)))
+			webKey.Y = base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, 32)))
+		default:
+			continue
+		}
+		webKeys = append(webKeys, webKey)
+	}
+	return webKeys
+}
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/configmodels"
+)
+
+const mockHMACSecret = "0123456789abcdef0123456789abcdef"
+
+func writeKeyFile(t *testing.T, pemType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: pemType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	return path
+}
+
+func rsaKeyFile(t *testing.T) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	return writeKeyFile(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+}
+
+func ecKeyFile(t *testing.T, curve elliptic.Curve) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate EC key: %v", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal EC key: %v", err)
+	}
+	return writeKeyFile(t, "EC PRIVATE KEY", der)
+}
+
+func TestLoadSigningKeys(t *testing.T) {
+	t.Setenv("WEBUI_TEST_JWT_SECRET", mockHMACSecret+"\n")
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte(mockHMACSecret), 0o600); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+	testCases := []struct {
+		name        string
+		key         *factory.JwtKey
+		expectedAlg string
+	}{
+		{"HS256 from a file", &factory.JwtKey{File: secretFile}, "HS256"},
+		{"HS256 from an environment variable", &factory.JwtKey{Algorithm: "HS256", Env: "WEBUI_TEST_JWT_SECRET"}, "HS256"},
+		{"RS256 from a file", &factory.JwtKey{Algorithm: "RS256", File: rsaKeyFile(t)}, "RS256"},
+		{"ES256 from a file", &factory.JwtKey{Algorithm: "ES256", File: ecKeyFile(t, elliptic.P256())}, "ES256"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := LoadSigningKeys(&factory.JwtKeys{Key: tc.key})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			token, err := GenerateJWT("janedoe", configmodels.UserRole, "", "mockSession", keys)
+			if err != nil {
+				t.Fatalf("failed to generate token: %v", err)
+			}
+			claims, err := getClaimsFromJWT(token, keys)
+			if err != nil {
+				t.Fatalf("failed to verify token: %v", err)
+			}
+			if claims.Username != "janedoe" || claims.Role != configmodels.UserRole {
+				t.Errorf("unexpected claims %+v", claims)
+			}
+			if keys.current.method.Alg() != tc.expectedAlg {
+				t.Errorf("expected %s, got %s", tc.expectedAlg, keys.current.method.Alg())
+			}
+		})
+	}
+}
+
+func TestLoadSigningKeys_SharedKey(t *testing.T) {
+	config := &factory.JwtKeys{Key: &factory.JwtKey{Algorithm: "ES256", File: ecKeyFile(t, elliptic.P256())}}
+	first, err := LoadSigningKeys(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := LoadSigningKeys(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	token, err := GenerateJWT("janedoe", configmodels.AdminRole, "", "mockSession", first)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	if _, err = getClaimsFromJWT(token, second); err != nil {
+		t.Errorf("expected a token of one replica to be accepted by another, got %v", err)
+	}
+}
+
+func TestLoadSigningKeys_Invalid(t *testing.T) {
+	shortSecret := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(shortSecret, []byte("short"), 0o600); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+	testCases := []struct {
+		name string
+		key  *factory.JwtKey
+	}{
+		{"no source", &factory.JwtKey{}},
+		{"missing file", &factory.JwtKey{File: filepath.Join(t.TempDir(), "missing")}},
+		{"unset environment variable", &factory.JwtKey{Env: "WEBUI_TEST_UNSET_JWT_SECRET"}},
+		{"short secret", &factory.JwtKey{File: shortSecret}},
+		{"secret as RS256 key", &factory.JwtKey{Algorithm: "RS256", File: shortSecret}},
+		{"ES256 key on another curve", &factory.JwtKey{Algorithm: "ES256", File: ecKeyFile(t, elliptic.P384())}},
+		{"unsupported algorithm", &factory.JwtKey{Algorithm: "none", File: shortSecret}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LoadSigningKeys(&factory.JwtKeys{Key: tc.key}); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestSigningKeys_Rotation(t *testing.T) {
+	t.Setenv("WEBUI_TEST_JWT_SECRET", mockHMACSecret)
+	previousKeys, err := LoadSigningKeys(&factory.JwtKeys{Key: &factory.JwtKey{Env: "WEBUI_TEST_JWT_SECRET"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	previousToken, err := GenerateJWT("janedoe", configmodels.UserRole, "", "mockSession", previousKeys)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	otherToken, err := GenerateJWT("janedoe", configmodels.UserRole, "", "mockSession", NewSigningKeys([]byte(strings.Repeat("x", 32))))
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	keys, err := LoadSigningKeys(&factory.JwtKeys{
+		Key:                 &factory.JwtKey{Algorithm: "RS256", File: rsaKeyFile(t)},
+		PreviousKey:         &factory.JwtKey{Env: "WEBUI_TEST_JWT_SECRET"},
+		PreviousKeyRetireAt: time.Now().Add(10 * time.Minute).Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = getClaimsFromJWT(previousToken, keys); err != nil {
+		t.Errorf("expected a token of the previous key to be accepted, got %v", err)
+	}
+	if _, err = getClaimsFromJWT(otherToken, keys); err == nil {
+		t.Errorf("expected a token of an unknown key to be rejected")
+	}
+	if webKeys := keys.publicKeys(); len(webKeys) != 1 || webKeys[0].Kid != keys.current.kid {
+		t.Errorf("expected only the RS256 key to be published, got %+v", webKeys)
+	}
+
+	keys, err = LoadSigningKeys(&factory.JwtKeys{
+		Key:                 &factory.JwtKey{Algorithm: "RS256", File: rsaKeyFile(t)},
+		PreviousKey:         &factory.JwtKey{Env: "WEBUI_TEST_JWT_SECRET"},
+		PreviousKeyRetireAt: time.Now().Add(-time.Minute).Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = getClaimsFromJWT(previousToken, keys); err == nil {
+		t.Errorf("expected a token of the previous key to be rejected after its retirement, even after a restart")
+	}
+}
+
+func TestSigningKeys_PreviousKeyRetirement(t *testing.T) {
+	t.Setenv("WEBUI_TEST_JWT_SECRET", mockHMACSecret)
+	for name, retireAt := range map[string]string{
+		"missing": "",
+		"invalid": "in an hour",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadSigningKeys(&factory.JwtKeys{
+				Key:                 &factory.JwtKey{Algorithm: "RS256", File: rsaKeyFile(t)},
+				PreviousKey:         &factory.JwtKey{Env: "WEBUI_TEST_JWT_SECRET"},
+				PreviousKeyRetireAt: retireAt,
+			})
+			if err == nil || !strings.Contains(err.Error(), "previous-key-retire-at") {
+				t.Errorf("expected a previous-key-retire-at error, got %v", err)
+			}
+		})
+	}
+}
@@ -31,16 +31,114 @@ type Info struct {
 }
 
 type Configuration struct {
-	Mongodb                 *Mongodb  `yaml:"mongodb"`
-	WebuiTLS                *TLS      `yaml:"webui-tls"`
-	NfConfigTLS             *TLS      `yaml:"nfconfig-tls"`
-	RocEnd                  *RocEndpt `yaml:"managedByConfigPod,omitempty"` // fetch config during bootup
-	SdfComp                 bool      `yaml:"spec-compliant-sdf"`
-	EnableAuthentication    bool      `yaml:"enableAuthentication,omitempty"`
-	SendPebbleNotifications bool      `yaml:"send-pebble-notifications,omitempty"`
-	CfgPort                 int       `yaml:"cfgport,omitempty"`
-	SSM                     *SSM      `yaml:"ssm,omitempty"`
-	Vault                   *Vault    `yaml:"vault,omitempty"`
+	Mongodb                 *Mongodb         `yaml:"mongodb"`
+	WebuiTLS                *TLS             `yaml:"webui-tls"`
+	NfConfigTLS             *TLS             `yaml:"nfconfig-tls"`
+	NfConfigGrpc            *NfConfigGrpc    `yaml:"nfconfig-grpc,omitempty"`
+	NfConfigCache           *NfConfigCache   `yaml:"nfconfig-cache,omitempty"`
+	RocEnd                  *RocEndpt        `yaml:"managedByConfigPod,omitempty"` // fetch config during bootup
+	SdfComp                 bool             `yaml:"spec-compliant-sdf"`
+	EnableAuthentication    bool             `yaml:"enableAuthentication,omitempty"`
+	JwtKeys                 *JwtKeys         `yaml:"jwt-keys,omitempty"`
+	Oidc                    *Oidc            `yaml:"oidc,omitempty"`
+	Ldap                    *Ldap            `yaml:"ldap,omitempty"`
+	LoginThrottling         *LoginThrottling `yaml:"login-throttling,omitempty"`
+	TrustedProxies          []string         `yaml:"trusted-proxies,omitempty"` // IPs or CIDRs setting X-Forwarded-For, none if empty
+	SendPebbleNotifications bool             `yaml:"send-pebble-notifications,omitempty"`
+	CfgPort                 int              `yaml:"cfgport,omitempty"`
+	SSM                     *SSM             `yaml:"ssm,omitempty"`
+	Vault                   *Vault           `yaml:"vault,omitempty"`
+}
+
+// NfConfigGrpc enables the gRPC NFConfig service, served with the NFConfig TLS
+// configuration when set.
+type NfConfigGrpc struct {
+	Enable bool `yaml:"enable,omitempty"`
+	Port   int  `yaml:"port,omitempty"`
+}
+
+// NfConfigCache persists the last configuration read from the database to
+// Path, so that the NFConfig server can start from it when the database is
+// unreachable.
+type NfConfigCache struct {
+	Path string `yaml:"path,omitempty"`
+}
+
+// JwtKeys sets the keys the authentication tokens are signed with. Without it,
+// a random HS256 key is generated on every start.
+type JwtKeys struct {
+	Key         *JwtKey `yaml:"key,omitempty"`
+	PreviousKey *JwtKey `yaml:"previous-key,omitempty"` // still accepted after a rotation
+	// RFC 3339 time the previous key is retired at, required with previous-key
+	PreviousKeyRetireAt string `yaml:"previous-key-retire-at,omitempty"`
+}
+
+// JwtKey is a signing key read from a file, an environment variable or a Vault
+// KV secret. HS256 keys are a secret of at least 32 bytes, RS256 and ES256
+// keys a PEM private key.
+type JwtKey struct {
+	Algorithm  string `yaml:"algorithm,omitempty"` // HS256 (default), RS256 or ES256
+	File       string `yaml:"file,omitempty"`
+	Env        string `yaml:"env,omitempty"`
+	VaultPath  string `yaml:"vault-path,omitempty"`  // e.g., "secret/data/webui/jwt"
+	VaultField string `yaml:"vault-field,omitempty"` // "key" by default
+}
+
+// Oidc enables the log in through an OpenID Connect provider, with the
+// authorization code flow. Users are given the role of the first RoleMapping
+// matching the values of the GroupsClaim of their ID token.
+type Oidc struct {
+	IssuerURL        string        `yaml:"issuer-url,omitempty"`
+	ClientID         string        `yaml:"client-id,omitempty"`
+	ClientSecret     string        `yaml:"client-secret,omitempty"`
+	ClientSecretEnv  string        `yaml:"client-secret-env,omitempty"`  // preferred to client-secret
+	RedirectURL      string        `yaml:"redirect-url,omitempty"`       // e.g., "https://webui.example.com/oidc/callback"
+	Scopes           []string      `yaml:"scopes,omitempty"`             // "openid", "profile", "email" and "groups" by default
+	DisplayNameClaim string        `yaml:"display-name-claim,omitempty"` // "preferred_username" by default
+	GroupsClaim      string        `yaml:"groups-claim,omitempty"`       // "groups" by default
+	RoleMappings     []RoleMapping `yaml:"role-mappings,omitempty"`
+	DefaultRole      string        `yaml:"default-role,omitempty"` // users matching no mapping are denied if empty
+}
+
+// Ldap enables the log in of directory users at /login, by binding as the
+// user. Users are given the role of the first RoleMapping matching the groups
+// they are a member of. Local accounts keep logging in with their password, so
+// that break-glass admins can log in when the directory is down.
+type Ldap struct {
+	URL      string `yaml:"url,omitempty"` // ldap:// or ldaps://
+	StartTLS bool   `yaml:"start-tls,omitempty"`
+	CaFile   string `yaml:"ca-file,omitempty"` // system roots if empty
+	// ldap:// without start-tls sends the passwords in clear text, and is
+	// refused unless AllowInsecure is set
+	AllowInsecure bool `yaml:"allow-insecure,omitempty"`
+	// DN users bind as, e.g., "uid={username},ou=people,dc=example,dc=org"
+	UserDNTemplate string `yaml:"user-dn-template,omitempty"`
+	// Groups are searched under GroupBaseDN with GroupFilter, where {dn} and
+	// {username} are replaced by the DN and the username of the user
+	GroupBaseDN    string        `yaml:"group-base-dn,omitempty"`
+	GroupFilter    string        `yaml:"group-filter,omitempty"`    // "(member={dn})" by default
+	GroupAttribute string        `yaml:"group-attribute,omitempty"` // "cn" by default
+	RoleMappings   []RoleMapping `yaml:"role-mappings,omitempty"`
+	DefaultRole    string        `yaml:"default-role,omitempty"` // users matching no mapping are denied if empty
+}
+
+// LoginThrottling limits the log in attempts, and is enabled with the default
+// values if not set. After a failed attempt, the next attempts of the username
+// and of the client IP are delayed exponentially, and the account is locked
+// after MaxFailedAttempts consecutive failures.
+type LoginThrottling struct {
+	Disable           bool `yaml:"disable,omitempty"`
+	MaxFailedAttempts int  `yaml:"max-failed-attempts,omitempty"` // 5 by default
+	LockoutMinute     int  `yaml:"lockout-minute,omitempty"`      // 15 by default
+	BackoffBaseSecond int  `yaml:"backoff-base-second,omitempty"` // 1 by default, doubled at each failure
+	BackoffMaxSecond  int  `yaml:"backoff-max-second,omitempty"`  // 60 by default
+}
+
+// RoleMapping gives a role to the members of a group of an identity provider.
+// Role is the name of a webui role, or "admin" for AdminRole.
+type RoleMapping struct {
+	Group string `yaml:"group"`
+	Role  string `yaml:"role"`
 }
 
 type SSM struct {
@@ -123,6 +221,10 @@ type Mongodb struct {
 	WebuiDbConns   int    `yaml:"webuiDbConns"`
 	CheckReplica   bool   `yaml:"checkReplica,omitempty"`
 	ConcurrencyOps int    `yaml:"concurrency-ops,omitempty"`
+	// Days finished sync jobs are kept for, 7 by default
+	SyncJobRetentionDay int `yaml:"sync-job-retention-day,omitempty"`
+	// Days the audit records are kept for, 90 by default
+	AuditRetentionDay int `yaml:"audit-retention-day,omitempty"`
 }
 
 type RocEndpt struct {
@@ -153,6 +153,12 @@ func InitConfigFactory(f string) error {
 	if mongoConfig.ConcurrencyOps == 0 {
 		mongoConfig.ConcurrencyOps = 30
 	}
+	if mongoConfig.SyncJobRetentionDay == 0 {
+		mongoConfig.SyncJobRetentionDay = 7
+	}
+	if mongoConfig.AuditRetentionDay == 0 {
+		mongoConfig.AuditRetentionDay = 90
+	}
 
 	return nil
 }
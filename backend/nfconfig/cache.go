@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package nfconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+)
+
+const (
+	// liveConfig is configuration read from the database by a sync.
+	liveConfig = "live"
+	// cachedConfig is configuration loaded from the last-known-good snapshot
+	// persisted on disk, served while the database is unreachable.
+	cachedConfig = "cached"
+)
+
+// persistedSnapshot is the data of a good sync as stored on disk. The
+// configuration is rebuilt from it rather than stored, so that it follows the
+// code reading it.
+type persistedSnapshot struct {
+	SyncedAt      time.Time                            `json:"syncedAt"`
+	NetworkSlices []configmodels.Slice                 `json:"networkSlices"`
+	DeviceGroups  map[string]configmodels.DeviceGroups `json:"deviceGroups"`
+}
+
+// syncState tracks the outcome of the syncs since the served snapshot may be
+// older than the last attempt.
+type syncState struct {
+	mu            sync.Mutex
+	lastError     string
+	lastFailureAt time.Time
+}
+
+func (s *syncState) record(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.lastError = ""
+		return
+	}
+	s.lastError = err.Error()
+	s.lastFailureAt = time.Now()
+}
+
+func (s *syncState) get() (string, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastError, s.lastFailureAt
+}
+
+// configStatus is the response of GET /nfconfig/status.
+type configStatus struct {
+	Source            string     `json:"source"`
+	SyncedAt          *time.Time `json:"syncedAt,omitempty"`
+	AgeSeconds        int64      `json:"ageSeconds"`
+	Revision          uint64     `json:"revision"`
+	LastSyncError     string     `json:"lastSyncError,omitempty"`
+	LastSyncFailureAt *time.Time `json:"lastSyncFailureAt,omitempty"`
+}
+
+func (n *NFConfigServer) cachePath() string {
+	if n.config == nil || n.config.NfConfigCache == nil {
+		return ""
+	}
+	return n.config.NfConfigCache.Path
+}
+
+// persistSnapshot writes the data of a good sync to the cache file. It is
+// written to a temporary file first so that a crash never leaves a partial
+// snapshot behind.
+func (n *NFConfigServer) persistSnapshot(config *inMemoryConfig) error {
+	path := n.cachePath()
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(persistedSnapshot{
+		SyncedAt:      config.syncedAt,
+		NetworkSlices: config.networkSlices,
+		DeviceGroups:  config.deviceGroups,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal NF configuration snapshot: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create NF configuration snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write NF configuration snapshot: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace NF configuration snapshot: %w", err)
+	}
+	return nil
+}
+
+// loadSnapshot publishes the configuration of the cache file.
+func (n *NFConfigServer) loadSnapshot() error {
+	path := n.cachePath()
+	if path == "" {
+		return fmt.Errorf("no NF configuration cache configured")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read NF configuration snapshot: %w", err)
+	}
+	var persisted persistedSnapshot
+	if err = json.Unmarshal(data, &persisted); err != nil {
+		return fmt.Errorf("failed to parse NF configuration snapshot %s: %w", path, err)
+	}
+	config := newInMemoryConfig(persisted.NetworkSlices, persisted.DeviceGroups)
+	config.source = cachedConfig
+	config.syncedAt = persisted.SyncedAt
+	n.publish(config)
+	logger.NfConfigLog.Warnf("Serving cached NF configuration synced at %s from %s", persisted.SyncedAt.Format(time.RFC3339), path)
+	return nil
+}
+
+func (n *NFConfigServer) GetConfigStatus(c *gin.Context) {
+	config := n.snapshot()
+	status := configStatus{
+		Source:   config.source,
+		Revision: config.revision,
+	}
+	if status.Source == "" {
+		status.Source = "none"
+	}
+	if !config.syncedAt.IsZero() {
+		syncedAt := config.syncedAt
+		status.SyncedAt = &syncedAt
+		status.AgeSeconds = int64(time.Since(syncedAt).Seconds())
+	}
+	lastError, lastFailureAt := n.syncState.get()
+	if lastError != "" {
+		status.LastSyncError = lastError
+		status.LastSyncFailureAt = &lastFailureAt
+	}
+	logger.NfConfigLog.Debugf("Handling GET request for config status %+v", status)
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+package nfconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/omec-project/openapi/nfConfigApi"
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+)
+
+func newCacheTestConfig(path string) *factory.Config {
+	return &factory.Config{
+		Configuration: &factory.Configuration{
+			NfConfigCache: &factory.NfConfigCache{Path: path},
+		},
+	}
+}
+
+func getConfigStatus(t *testing.T, n *NFConfigServer) configStatus {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/nfconfig/status", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	n.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, w.Code)
+	}
+	var status configStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("failed to unmarshal status: %v", err)
+	}
+	return status
+}
+
+func TestNewNFConfigServer_WarmStartFromCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nfconfig.json")
+	mockDB := &MockDBClient{Slices: []configmodels.Slice{makeNetworkSlice("001", "01", "1", "010203", []int32{1})}}
+	originalDBClient := dbadapter.CommonDBClient
+	defer func() { dbadapter.CommonDBClient = originalDBClient }()
+	dbadapter.CommonDBClient = mockDB
+
+	nf, err := NewNFConfigServer(newCacheTestConfig(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	live := nf.(*NFConfigServer)
+	if status := getConfigStatus(t, live); status.Source != liveConfig || status.SyncedAt == nil || status.LastSyncError != "" {
+		t.Errorf("expected live configuration, got %+v", status)
+	}
+	if _, err = os.Stat(path); err != nil {
+		t.Fatalf("expected the snapshot to be persisted: %v", err)
+	}
+
+	mockDB.err = errors.New("mock error")
+	nf, err = NewNFConfigServer(newCacheTestConfig(path))
+	if err != nil {
+		t.Fatalf("expected to start from the cache, got %v", err)
+	}
+	cached := nf.(*NFConfigServer)
+	expectedPlmn := []nfConfigApi.PlmnId{*nfConfigApi.NewPlmnId("001", "01")}
+	if !reflect.DeepEqual(cached.snapshot().plmn, expectedPlmn) {
+		t.Errorf("expected cached PLMN %v, got %v", expectedPlmn, cached.snapshot().plmn)
+	}
+	status := getConfigStatus(t, cached)
+	if status.Source != cachedConfig || status.LastSyncError != "mock error" || status.LastSyncFailureAt == nil {
+		t.Errorf("expected cached configuration with the sync error, got %+v", status)
+	}
+	if status.SyncedAt == nil || !status.SyncedAt.Equal(live.snapshot().syncedAt) || status.AgeSeconds < 0 {
+		t.Errorf("expected the age of the live sync, got %+v", status)
+	}
+
+	mockDB.err = nil
+	if err = cached.syncInMemoryConfig(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status := getConfigStatus(t, cached); status.Source != liveConfig || status.LastSyncError != "" {
+		t.Errorf("expected live configuration after the database recovers, got %+v", status)
+	}
+}
+
+func TestNewNFConfigServer_NoUsableCache(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0o600); err != nil {
+		t.Fatalf("failed to write cache: %v", err)
+	}
+	originalDBClient := dbadapter.CommonDBClient
+	defer func() { dbadapter.CommonDBClient = originalDBClient }()
+	dbadapter.CommonDBClient = &MockDBClient{err: errors.New("mock error")}
+
+	for name, config := range map[string]*factory.Config{
+		"no cache configured": {Configuration: &factory.Configuration{}},
+		"missing cache":       newCacheTestConfig(filepath.Join(dir, "missing.json")),
+		"corrupt cache":       newCacheTestConfig(corrupt),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewNFConfigServer(config); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestNewCachedNFConfigServer_DatabaseUnreachableAtStartup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nfconfig.json")
+	mockDB := &MockDBClient{Slices: []configmodels.Slice{makeNetworkSlice("001", "01", "1", "010203", []int32{1})}}
+	originalDBClient := dbadapter.CommonDBClient
+	defer func() { dbadapter.CommonDBClient = originalDBClient }()
+	dbadapter.CommonDBClient = mockDB
+	if _, err := NewNFConfigServer(newCacheTestConfig(path)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the database client is not initialized, any access panics
+	dbadapter.CommonDBClient = nil
+	nf, err := NewCachedNFConfigServer(newCacheTestConfig(path))
+	if err != nil {
+		t.Fatalf("expected to start from the cache, got %v", err)
+	}
+	cached := nf.(*NFConfigServer)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	syncChan := make(chan struct{}, 1)
+	cached.startSyncWorker(ctx, syncChan)
+	time.Sleep(50 * time.Millisecond)
+	if status := getConfigStatus(t, cached); status.Source != cachedConfig {
+		t.Fatalf("expected cached configuration until the database is initialized, got %+v", status)
+	}
+
+	dbadapter.CommonDBClient = mockDB
+	syncChan <- struct{}{}
+	deadline := time.Now().Add(5 * time.Second)
+	for getConfigStatus(t, cached).Source != liveConfig {
+		if time.Now().After(deadline) {
+			t.Fatal("expected live configuration once the database is initialized")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNewCachedNFConfigServer_NoUsableCache(t *testing.T) {
+	if _, err := NewCachedNFConfigServer(newCacheTestConfig(filepath.Join(t.TempDir(), "missing.json"))); err == nil {
+		t.Error("expected an error")
+	}
+}
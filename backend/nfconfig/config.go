@@ -6,11 +6,14 @@
 package nfconfig
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
 	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/omec-project/openapi/nfConfigApi"
 	"github.com/omec-project/webconsole/backend/factory"
@@ -30,11 +33,24 @@ type accessAndMobilityKey struct {
 }
 
 type imsiQosConfig struct {
-	imsis []string
-	dnn   string
-	qos   []nfConfigApi.ImsiQos
+	imsis      []string
+	imsiRanges []configmodels.DeviceGroupsImsiRange
+	dnn        string
+	qos        []nfConfigApi.ImsiQos
 }
 
+// MarshalJSON renders the configuration for its revision and content hash.
+func (c imsiQosConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Imsis      []string                             `json:"imsis,omitempty"`
+		ImsiRanges []configmodels.DeviceGroupsImsiRange `json:"imsi-ranges,omitempty"`
+		Dnn        string                               `json:"dnn"`
+		Qos        []nfConfigApi.ImsiQos                `json:"qos"`
+	}{c.imsis, c.imsiRanges, c.dnn, c.qos})
+}
+
+// inMemoryConfig is a snapshot of the NF configuration. It is built by a sync
+// and must not be modified once published.
 type inMemoryConfig struct {
 	plmn              []nfConfigApi.PlmnId
 	plmnSnssai        []nfConfigApi.PlmnSnssai
@@ -42,6 +58,15 @@ type inMemoryConfig struct {
 	sessionManagement []nfConfigApi.SessionManagement
 	policyControl     []nfConfigApi.PolicyControl
 	imsiQos           []imsiQosConfig
+	imsiQosIndex      *imsiQosIndex
+	networkSlices     []configmodels.Slice
+	deviceGroups      map[string]configmodels.DeviceGroups
+	source            string
+	syncedAt          time.Time
+	revision          uint64
+	hash              string
+	revisions         map[string]configRevision
+	renderings        *renderCache
 }
 
 var defaultPccRule = nfConfigApi.NewPccRule(
@@ -66,6 +91,19 @@ var defaultPccRule = nfConfigApi.NewPccRule(
 	255,
 )
 
+// newInMemoryConfig builds the configuration of the network functions from
+// the network slices and device groups.
+func newInMemoryConfig(networkSlices []configmodels.Slice, deviceGroups map[string]configmodels.DeviceGroups) *inMemoryConfig {
+	config := &inMemoryConfig{networkSlices: networkSlices, deviceGroups: deviceGroups, renderings: &renderCache{}}
+	config.syncPlmn(networkSlices)
+	config.syncPlmnSnssai(networkSlices)
+	config.syncAccessAndMobility(networkSlices)
+	config.syncSessionManagement(networkSlices, deviceGroups)
+	config.syncPolicyControl(networkSlices, deviceGroups)
+	config.syncImsiQos(deviceGroups)
+	return config
+}
+
 func (c *inMemoryConfig) syncPlmn(slices []configmodels.Slice) {
 	plmnSet := make(map[nfConfigApi.PlmnId]bool)
 	newPlmnConfig := []nfConfigApi.PlmnId{}
@@ -282,13 +320,15 @@ func extractIpDomains(groupNames []string, deviceGroupMap map[string]configmodel
 			logger.NfConfigLog.Warnf("Device group %s not found", name)
 			continue
 		}
-		ip := nfConfigApi.NewIpDomain(
-			dg.IpDomainExpanded.Dnn,
-			dg.IpDomainExpanded.DnsPrimary,
-			dg.IpDomainExpanded.UeIpPool,
-			dg.IpDomainExpanded.Mtu,
-		)
-		ipDomains = append(ipDomains, *ip)
+		for _, ipDomain := range dg.GetIpDomains() {
+			ip := nfConfigApi.NewIpDomain(
+				ipDomain.IpDomainExpanded.Dnn,
+				ipDomain.IpDomainExpanded.DnsPrimary,
+				ipDomain.IpDomainExpanded.UeIpPool,
+				ipDomain.IpDomainExpanded.Mtu,
+			)
+			ipDomains = append(ipDomains, *ip)
+		}
 	}
 	return ipDomains
 }
@@ -481,8 +521,7 @@ func getSupportedDnns(slice configmodels.Slice, deviceGroups map[string]configmo
 			logger.NfConfigLog.Warnf("DeviceGroup %s not found", dgName)
 			continue
 		}
-		dnn := deviceGroup.IpDomainExpanded.Dnn
-		dnns = append(dnns, dnn)
+		dnns = append(dnns, deviceGroup.Dnns()...)
 	}
 	sort.Strings(dnns)
 	return dnns
@@ -509,24 +548,29 @@ func (c *inMemoryConfig) syncImsiQos(deviceGroupMap map[string]configmodels.Devi
 	TODO: Remove this comment once Device Group validation is implemented.
 	*/
 	imsiQosConfigs := []imsiQosConfig{}
-	for _, dg := range deviceGroupMap {
-		imsiQos := extractQosConfigFromDeviceGroup(dg)
-		newImsiQosConfig := imsiQosConfig{
-			imsis: dg.Imsis,
-			dnn:   dg.IpDomainExpanded.Dnn,
-			qos:   []nfConfigApi.ImsiQos{imsiQos},
+	for _, name := range slices.Sorted(maps.Keys(deviceGroupMap)) {
+		dg := deviceGroupMap[name]
+		for _, ipDomain := range dg.GetIpDomains() {
+			imsiQos := extractQosConfigFromIpDomain(ipDomain.IpDomainExpanded)
+			newImsiQosConfig := imsiQosConfig{
+				imsis:      dg.Imsis,
+				imsiRanges: dg.ImsiRanges,
+				dnn:        ipDomain.IpDomainExpanded.Dnn,
+				qos:        []nfConfigApi.ImsiQos{imsiQos},
+			}
+			imsiQosConfigs = append(imsiQosConfigs, newImsiQosConfig)
 		}
-		imsiQosConfigs = append(imsiQosConfigs, newImsiQosConfig)
 	}
 	c.imsiQos = imsiQosConfigs
+	c.imsiQosIndex = newImsiQosIndex(imsiQosConfigs)
 	logger.NfConfigLog.Debugf("Updated IMSI QoS in-memory configuration. New configuration: %+v", c.imsiQos)
 }
 
-func extractQosConfigFromDeviceGroup(group configmodels.DeviceGroups) nfConfigApi.ImsiQos {
+func extractQosConfigFromIpDomain(ipDomain configmodels.DeviceGroupsIpDomainExpanded) nfConfigApi.ImsiQos {
 	return *nfConfigApi.NewImsiQos(
-		configapi.ConvertToString(uint64(group.IpDomainExpanded.UeDnnQos.DnnMbrUplink)),
-		configapi.ConvertToString(uint64(group.IpDomainExpanded.UeDnnQos.DnnMbrDownlink)),
-		group.IpDomainExpanded.UeDnnQos.TrafficClass.Qci,
-		group.IpDomainExpanded.UeDnnQos.TrafficClass.Arp,
+		configapi.ConvertToString(uint64(ipDomain.UeDnnQos.DnnMbrUplink)),
+		configapi.ConvertToString(uint64(ipDomain.UeDnnQos.DnnMbrDownlink)),
+		ipDomain.UeDnnQos.TrafficClass.Qci,
+		ipDomain.UeDnnQos.TrafficClass.Arp,
 	)
 }
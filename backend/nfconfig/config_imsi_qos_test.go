@@ -119,3 +119,50 @@ func TestSyncImsiQos(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncImsiQos_MultipleDnns(t *testing.T) {
+	_, group := makeDeviceGroup(deviceGroupParams{imsis: []string{"001010123456789"}})
+	group.IpDomains = []configmodels.DeviceGroupsIpDomain{
+		{
+			IpDomainName: "pool-internet",
+			IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{
+				Dnn: "internet",
+				UeDnnQos: &configmodels.DeviceGroupsIpDomainExpandedUeDnnQos{
+					DnnMbrUplink:   20000000,
+					DnnMbrDownlink: 200000000,
+					TrafficClass:   &configmodels.TrafficClassInfo{Qci: 6, Arp: 9},
+				},
+			},
+		},
+		{
+			IpDomainName: "pool-iot",
+			IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{
+				Dnn: "iot",
+				UeDnnQos: &configmodels.DeviceGroupsIpDomainExpandedUeDnnQos{
+					DnnMbrUplink:   1000000,
+					DnnMbrDownlink: 2000000,
+					TrafficClass:   &configmodels.TrafficClassInfo{Qci: 9, Arp: 1},
+				},
+			},
+		},
+	}
+
+	cfg := inMemoryConfig{}
+	cfg.syncImsiQos(map[string]configmodels.DeviceGroups{"dg-1": group})
+
+	expected := []imsiQosConfig{
+		{
+			imsis: []string{"001010123456789"},
+			dnn:   "internet",
+			qos:   []nfConfigApi.ImsiQos{*nfConfigApi.NewImsiQos("20 Mbps", "200 Mbps", 6, 9)},
+		},
+		{
+			imsis: []string{"001010123456789"},
+			dnn:   "iot",
+			qos:   []nfConfigApi.ImsiQos{*nfConfigApi.NewImsiQos("1 Mbps", "2 Mbps", 9, 1)},
+		},
+	}
+	if !reflect.DeepEqual(cfg.imsiQos, expected) {
+		t.Errorf("expected %+v, got %+v", expected, cfg.imsiQos)
+	}
+}
@@ -557,3 +557,25 @@ func TestSyncSessionManagement(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractIpDomainsAndSupportedDnns_MultipleDnns(t *testing.T) {
+	_, group := makeDeviceGroup(deviceGroupParams{})
+	group.IpDomains = []configmodels.DeviceGroupsIpDomain{
+		{IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{Dnn: "internet", DnsPrimary: "8.8.8.8", UeIpPool: "10.1.1.0/24", Mtu: 1500}},
+		{IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{Dnn: "iot", DnsPrimary: "1.1.1.1", UeIpPool: "10.2.1.0/24", Mtu: 1400}},
+	}
+	deviceGroups := map[string]configmodels.DeviceGroups{"dg-1": group}
+
+	expectedIpDomains := []nfConfigApi.IpDomain{
+		{DnnName: "internet", DnsIpv4: "8.8.8.8", UeSubnet: "10.1.1.0/24", Mtu: 1500},
+		{DnnName: "iot", DnsIpv4: "1.1.1.1", UeSubnet: "10.2.1.0/24", Mtu: 1400},
+	}
+	if ipDomains := extractIpDomains([]string{"dg-1"}, deviceGroups); !reflect.DeepEqual(ipDomains, expectedIpDomains) {
+		t.Errorf("expected %+v, got %+v", expectedIpDomains, ipDomains)
+	}
+
+	slice := configmodels.Slice{SliceName: "slice-1", SiteDeviceGroup: []string{"dg-1"}}
+	if dnns := getSupportedDnns(slice, deviceGroups); !reflect.DeepEqual(dnns, []string{"internet", "iot"}) {
+		t.Errorf("expected DNNs [internet iot], got %v", dnns)
+	}
+}
@@ -0,0 +1,239 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package nfconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+)
+
+var (
+	plmnFilterPattern = regexp.MustCompile(`^(\d{3})-(\d{2,3})$`)
+	sdFilterPattern   = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
+)
+
+// configFilter narrows the configuration down to the network slices relevant
+// to one network function, e.g. the slices served by the UPF of an SMF or the
+// TACs of the gNBs of an AMF. The zero value selects everything.
+type configFilter struct {
+	plmn *configmodels.SliceSiteInfoPlmn
+	sst  *int64
+	sd   string
+	site string
+	tac  *int32
+	upf  string
+}
+
+// parseConfigFilter reads the filter from the query parameters of the request:
+//
+//	plmn=<mcc>-<mnc>    e.g. 001-01
+//	snssai=<sst>[-<sd>] e.g. 1 or 1-010203
+//	site=<site name>
+//	tac=<tac>
+//	upf=<UPF hostname>
+func parseConfigFilter(c *gin.Context) (configFilter, error) {
+	filter := configFilter{
+		site: c.Query("site"),
+		upf:  c.Query("upf"),
+	}
+	if value := c.Query("plmn"); value != "" {
+		match := plmnFilterPattern.FindStringSubmatch(value)
+		if match == nil {
+			return configFilter{}, fmt.Errorf("invalid plmn %q, expected <mcc>-<mnc>", value)
+		}
+		filter.plmn = &configmodels.SliceSiteInfoPlmn{Mcc: match[1], Mnc: match[2]}
+	}
+	if value := c.Query("snssai"); value != "" {
+		sstValue, sd, hasSd := strings.Cut(value, "-")
+		sst, err := strconv.ParseInt(sstValue, 10, 64)
+		if err != nil || sst < 0 || sst > 255 || (hasSd && !sdFilterPattern.MatchString(sd)) {
+			return configFilter{}, fmt.Errorf("invalid snssai %q, expected <sst>[-<sd>]", value)
+		}
+		filter.sst = &sst
+		filter.sd = strings.ToLower(sd)
+	}
+	if value := c.Query("tac"); value != "" {
+		tac, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || tac < 0 {
+			return configFilter{}, fmt.Errorf("invalid tac %q", value)
+		}
+		tac32 := int32(tac)
+		filter.tac = &tac32
+	}
+	return filter, nil
+}
+
+func (f configFilter) empty() bool {
+	return f.plmn == nil && f.sst == nil && f.site == "" && f.tac == nil && f.upf == ""
+}
+
+func (f configFilter) matches(slice configmodels.Slice) bool {
+	if f.plmn != nil && slice.SiteInfo.Plmn != *f.plmn {
+		return false
+	}
+	if f.sst != nil {
+		sst, err := strconv.ParseInt(slice.SliceId.Sst, 10, 64)
+		if err != nil || sst != *f.sst {
+			return false
+		}
+		if f.sd != "" && strings.ToLower(slice.SliceId.Sd) != f.sd {
+			return false
+		}
+	}
+	if f.site != "" && slice.SiteInfo.SiteName != f.site {
+		return false
+	}
+	if f.upf != "" {
+		upf := extractUpf(slice)
+		if upf == nil || upf.GetHostname() != f.upf {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns the network slices selected by the filter. Filtering by TAC
+// also drops the gNBs of other TACs from the slices it keeps.
+func (f configFilter) apply(networkSlices []configmodels.Slice) []configmodels.Slice {
+	filtered := []configmodels.Slice{}
+	for _, slice := range networkSlices {
+		if !f.matches(slice) {
+			continue
+		}
+		if f.tac != nil {
+			gNodeBs := []configmodels.SliceSiteInfoGNodeBs{}
+			for _, gNodeB := range slice.SiteInfo.GNodeBs {
+				if gNodeB.Tac == *f.tac {
+					gNodeBs = append(gNodeBs, gNodeB)
+				}
+			}
+			if len(gNodeBs) == 0 {
+				continue
+			}
+			slice.SiteInfo.GNodeBs = gNodeBs
+		}
+		filtered = append(filtered, slice)
+	}
+	return filtered
+}
+
+// key identifies the filter in the render cache of a snapshot.
+func (f configFilter) key() string {
+	var b strings.Builder
+	if f.plmn != nil {
+		fmt.Fprintf(&b, "plmn=%s-%s;", f.plmn.Mcc, f.plmn.Mnc)
+	}
+	if f.sst != nil {
+		fmt.Fprintf(&b, "snssai=%d-%s;", *f.sst, f.sd)
+	}
+	if f.tac != nil {
+		fmt.Fprintf(&b, "tac=%d;", *f.tac)
+	}
+	fmt.Fprintf(&b, "site=%q;upf=%q", f.site, f.upf)
+	return b.String()
+}
+
+// renderCache keeps the filtered views of a snapshot and the rendered payloads
+// of a view, so that they are built once per revision rather than on every
+// request and watch wake-up. It goes away with the snapshot. Only views that
+// select some network slices are kept: their filter values are then all taken
+// from the snapshot, which bounds the cache whatever the clients ask for.
+type renderCache struct {
+	mu       sync.Mutex
+	views    map[string]*inMemoryConfig
+	payloads map[string]configRevision
+}
+
+func (r *renderCache) view(key string, build func() *inMemoryConfig) *inMemoryConfig {
+	if r == nil {
+		return build()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if view, ok := r.views[key]; ok {
+		return view
+	}
+	view := build()
+	if len(view.networkSlices) == 0 {
+		return view
+	}
+	if r.views == nil {
+		r.views = make(map[string]*inMemoryConfig)
+	}
+	r.views[key] = view
+	return view
+}
+
+func (r *renderCache) payload(configType string, render func() configRevision) configRevision {
+	if r == nil {
+		return render()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if config, ok := r.payloads[configType]; ok {
+		return config
+	}
+	if r.payloads == nil {
+		r.payloads = make(map[string]configRevision)
+	}
+	config := render()
+	r.payloads[configType] = config
+	return config
+}
+
+// filtered returns the view of the snapshot selected by the filter. The view
+// keeps the revisions of the snapshot, as it changes whenever they do.
+func (c *inMemoryConfig) filtered(f configFilter) *inMemoryConfig {
+	if f.empty() {
+		return c
+	}
+	return c.renderings.view(f.key(), func() *inMemoryConfig {
+		view := newInMemoryConfig(f.apply(c.networkSlices), c.deviceGroups)
+		view.revision = c.revision
+		view.hash = c.hash
+		view.revisions = c.revisions
+		return view
+	})
+}
+
+// filteredRevision renders a configuration type of the filtered view at the
+// revision of the snapshot.
+func (c *inMemoryConfig) filteredRevision(configType string, config configRevision, f configFilter) configRevision {
+	if f.empty() {
+		return config
+	}
+	view := c.filtered(f)
+	return view.renderings.payload(configType, func() configRevision {
+		payload, err := json.Marshal(view.configs()[configType])
+		if err != nil {
+			logger.NfConfigLog.Errorf("Failed to marshal filtered %s configuration: %+v", configType, err)
+			payload = nil
+		}
+		return configRevision{revision: config.revision, hash: contentHash(payload), payload: payload}
+	})
+}
+
+// filteredSnapshot returns the view of the current snapshot selected by the
+// query parameters of the request, or answers 400 Bad Request if they are
+// invalid.
+func (n *NFConfigServer) filteredSnapshot(c *gin.Context) (*inMemoryConfig, bool) {
+	filter, err := parseConfigFilter(c)
+	if err != nil {
+		logger.NfConfigLog.Warnf("Invalid NF configuration filter: %+v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return n.snapshot().filtered(filter), true
+}
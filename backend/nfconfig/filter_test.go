@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+package nfconfig
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/openapi/nfConfigApi"
+	"github.com/omec-project/webconsole/configmodels"
+)
+
+func makeFilterTestSlice(name, mnc, sd, site, upf string, tacs []int32) configmodels.Slice {
+	slice := makeNetworkSlice("001", mnc, "1", sd, tacs)
+	slice.SliceName = name
+	slice.SiteInfo.SiteName = site
+	slice.SiteInfo.Upf = map[string]any{"upf-name": upf, "upf-port": "8805"}
+	return slice
+}
+
+func newFilterTestServer() *NFConfigServer {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(enforceAcceptJSON())
+	n := &NFConfigServer{Router: router}
+	n.setupRoutes()
+	n.publish(newInMemoryConfig([]configmodels.Slice{
+		makeFilterTestSlice("slice-a", "01", "010203", "site-a", "upf-a", []int32{1, 2}),
+		makeFilterTestSlice("slice-b", "01", "040506", "site-b", "upf-b", []int32{3}),
+		makeFilterTestSlice("slice-c", "02", "010203", "site-c", "upf-a", []int32{2}),
+	}, map[string]configmodels.DeviceGroups{}))
+	return n
+}
+
+func TestFilteredConfig_SessionManagement(t *testing.T) {
+	n := newFilterTestServer()
+
+	testCases := []struct {
+		name           string
+		query          string
+		expectedSlices []string
+	}{
+		{"no filter", "", []string{"slice-a", "slice-b", "slice-c"}},
+		{"UPF hostname", "upf=upf-a", []string{"slice-a", "slice-c"}},
+		{"PLMN", "plmn=001-02", []string{"slice-c"}},
+		{"S-NSSAI", "snssai=1-040506", []string{"slice-b"}},
+		{"S-NSSAI without SD", "snssai=1", []string{"slice-a", "slice-b", "slice-c"}},
+		{"site name", "site=site-b", []string{"slice-b"}},
+		{"TAC", "tac=2", []string{"slice-a", "slice-c"}},
+		{"combined filters", "upf=upf-a&plmn=001-01", []string{"slice-a"}},
+		{"no match", "upf=unknown", []string{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/nfconfig/session-management?"+tc.query, nil)
+			req.Header.Set("Accept", "application/json")
+			w := httptest.NewRecorder()
+			n.Router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+			}
+			var sessionManagement []nfConfigApi.SessionManagement
+			if err := json.Unmarshal(w.Body.Bytes(), &sessionManagement); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			sliceNames := []string{}
+			for _, s := range sessionManagement {
+				sliceNames = append(sliceNames, s.SliceName)
+			}
+			if len(sliceNames) != len(tc.expectedSlices) {
+				t.Fatalf("expected slices %v, got %v", tc.expectedSlices, sliceNames)
+			}
+			for i := range sliceNames {
+				if sliceNames[i] != tc.expectedSlices[i] {
+					t.Errorf("expected slices %v, got %v", tc.expectedSlices, sliceNames)
+					break
+				}
+			}
+			if revision := w.Header().Get(revisionHeader); revision != "1" {
+				t.Errorf("expected revision 1, got %q", revision)
+			}
+		})
+	}
+}
+
+func TestFilteredConfig_AccessMobilityTacs(t *testing.T) {
+	n := newFilterTestServer()
+	req := httptest.NewRequest(http.MethodGet, "/nfconfig/access-mobility?tac=2", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	n.Router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+	}
+	expected := `[{"plmnId":{"mcc":"001","mnc":"01"},"snssai":{"sst":1,"sd":"010203"},"tacs":["2"]},` +
+		`{"plmnId":{"mcc":"001","mnc":"02"},"snssai":{"sst":1,"sd":"010203"},"tacs":["2"]}]`
+	if w.Body.String() != expected {
+		t.Errorf("expected body %s, got %s", expected, w.Body.String())
+	}
+	if etag := w.Header().Get("ETag"); etag != strconv.Quote(contentHash([]byte(expected))) {
+		t.Errorf("expected the ETag of the filtered body, got %s", etag)
+	}
+	if len(n.snapshot().networkSlices[0].SiteInfo.GNodeBs) != 2 {
+		t.Errorf("expected filtering not to modify the snapshot")
+	}
+}
+
+func TestFilteredConfig_InvalidFilter(t *testing.T) {
+	n := newFilterTestServer()
+	for _, query := range []string{"plmn=00101", "snssai=abc", "snssai=1-xyz", "snssai=256", "tac=-1"} {
+		t.Run(query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/nfconfig/plmn?"+query, nil)
+			req.Header.Set("Accept", "application/json")
+			w := httptest.NewRecorder()
+			n.Router.ServeHTTP(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
+
+func TestFilteredConfig_Watch(t *testing.T) {
+	n := newFilterTestServer()
+	req := httptest.NewRequest(http.MethodGet, "/nfconfig/plmn/watch?revision=0&upf=upf-b", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	n.Router.ServeHTTP(w, req)
+
+	expected := `[{"mcc":"001","mnc":"01"}]`
+	if w.Code != http.StatusOK || w.Body.String() != expected {
+		t.Errorf("expected %d %s, got %d %s", http.StatusOK, expected, w.Code, w.Body.String())
+	}
+}
+
+func TestFilteredConfig_CachedPerRevision(t *testing.T) {
+	n := newFilterTestServer()
+	filter := configFilter{upf: "upf-a"}
+	snapshot := n.snapshot()
+	if snapshot.filtered(filter) != snapshot.filtered(configFilter{upf: "upf-a"}) {
+		t.Error("expected the filtered view to be built once per snapshot")
+	}
+	if snapshot.filtered(filter) == snapshot.filtered(configFilter{upf: "upf-b"}) {
+		t.Error("expected a view per filter")
+	}
+
+	config := snapshot.revisions[plmnConfig]
+	first := snapshot.filteredRevision(plmnConfig, config, filter)
+	second := snapshot.filteredRevision(plmnConfig, config, filter)
+	if &first.payload[0] != &second.payload[0] {
+		t.Error("expected the filtered payload to be rendered once per snapshot")
+	}
+}
+
+func TestFilteredConfig_CacheBoundedBySnapshot(t *testing.T) {
+	n := newFilterTestServer()
+	for i := range 1000 {
+		value := strconv.Itoa(i)
+		req := httptest.NewRequest(http.MethodGet, "/nfconfig/plmn?site=site-"+value+"&upf=upf-"+value, nil)
+		req.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		n.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected %d for an unknown site, got %d %s", http.StatusOK, w.Code, w.Body.String())
+		}
+	}
+	snapshot := n.snapshot()
+	for _, site := range []string{"site-a", "site-b", "site-c"} {
+		snapshot.filtered(configFilter{site: site})
+	}
+	if len(snapshot.renderings.views) != 3 {
+		t.Errorf("expected only the views of the known sites to be cached, got %d", len(snapshot.renderings.views))
+	}
+}
+
+func TestFilteredConfig_WatchIgnoresChangesOutsideFilter(t *testing.T) {
+	n := newFilterTestServer()
+	req := httptest.NewRequest(http.MethodGet, "/nfconfig/plmn?upf=upf-b", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	n.Router.ServeHTTP(w, req)
+	etag := w.Header().Get("ETag")
+	revision := w.Header().Get(revisionHeader)
+
+	n.publish(newInMemoryConfig([]configmodels.Slice{
+		makeFilterTestSlice("slice-a", "01", "010203", "site-a", "upf-a", []int32{1, 2}),
+		makeFilterTestSlice("slice-b", "01", "040506", "site-b", "upf-b", []int32{3}),
+		makeFilterTestSlice("slice-c", "03", "010203", "site-c", "upf-a", []int32{2}),
+	}, map[string]configmodels.DeviceGroups{}))
+
+	req = httptest.NewRequest(http.MethodGet, "/nfconfig/plmn/watch?upf=upf-b&timeout=100ms&revision="+revision, nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	n.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected %d for a change outside of the filter, got %d %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if w.Header().Get(revisionHeader) == revision {
+		t.Error("expected the revision header to move on to the new snapshot")
+	}
+}
@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package nfconfig
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/backend/nfconfig/nfconfigpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultGrpcPort = 9876
+
+// grpcConfigTypes maps the gRPC configuration types to those of the snapshot
+// and to the field holding them in their gRPC message.
+var grpcConfigTypes = map[nfconfigpb.ConfigType]struct {
+	configType string
+	field      string
+}{
+	nfconfigpb.ConfigType_CONFIG_TYPE_PLMN:               {plmnConfig, "plmn"},
+	nfconfigpb.ConfigType_CONFIG_TYPE_PLMN_SNSSAI:        {plmnSnssaiConfig, "plmn_snssai"},
+	nfconfigpb.ConfigType_CONFIG_TYPE_ACCESS_MOBILITY:    {accessMobilityConfig, "access_mobility"},
+	nfconfigpb.ConfigType_CONFIG_TYPE_SESSION_MANAGEMENT: {sessionManagementConfig, "session_management"},
+	nfconfigpb.ConfigType_CONFIG_TYPE_POLICY_CONTROL:     {policyControlConfig, "policy_control"},
+	nfconfigpb.ConfigType_CONFIG_TYPE_IMSI_QOS:           {imsiQosConfigType, "imsi_qos"},
+}
+
+// nfTypeConfigTypes are the configuration types sent to a network function
+// that does not ask for specific ones.
+var nfTypeConfigTypes = map[nfconfigpb.NfType][]nfconfigpb.ConfigType{
+	nfconfigpb.NfType_NF_TYPE_AMF: {
+		nfconfigpb.ConfigType_CONFIG_TYPE_PLMN,
+		nfconfigpb.ConfigType_CONFIG_TYPE_PLMN_SNSSAI,
+		nfconfigpb.ConfigType_CONFIG_TYPE_ACCESS_MOBILITY,
+	},
+	nfconfigpb.NfType_NF_TYPE_SMF: {
+		nfconfigpb.ConfigType_CONFIG_TYPE_PLMN,
+		nfconfigpb.ConfigType_CONFIG_TYPE_PLMN_SNSSAI,
+		nfconfigpb.ConfigType_CONFIG_TYPE_SESSION_MANAGEMENT,
+		nfconfigpb.ConfigType_CONFIG_TYPE_IMSI_QOS,
+	},
+	nfconfigpb.NfType_NF_TYPE_PCF: {
+		nfconfigpb.ConfigType_CONFIG_TYPE_PLMN_SNSSAI,
+		nfconfigpb.ConfigType_CONFIG_TYPE_POLICY_CONTROL,
+	},
+}
+
+var defaultConfigTypes = []nfconfigpb.ConfigType{
+	nfconfigpb.ConfigType_CONFIG_TYPE_PLMN,
+	nfconfigpb.ConfigType_CONFIG_TYPE_PLMN_SNSSAI,
+}
+
+type grpcConfigServer struct {
+	nfconfigpb.UnimplementedNfConfigServer
+	n *NFConfigServer
+}
+
+func (n *NFConfigServer) newGrpcServer() (*grpc.Server, error) {
+	var opts []grpc.ServerOption
+	if n.config.NfConfigTLS != nil && n.config.NfConfigTLS.Key != "" && n.config.NfConfigTLS.PEM != "" {
+		creds, err := credentials.NewServerTLSFromFile(n.config.NfConfigTLS.PEM, n.config.NfConfigTLS.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load NFConfig TLS credentials: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	server := grpc.NewServer(opts...)
+	nfconfigpb.RegisterNfConfigServer(server, &grpcConfigServer{n: n})
+	return server, nil
+}
+
+// startGrpcServer serves the gRPC NFConfig service if it is enabled, sending
+// its error to errChan when it stops.
+func (n *NFConfigServer) startGrpcServer(errChan chan<- error) (*grpc.Server, error) {
+	grpcConfig := n.config.NfConfigGrpc
+	if grpcConfig == nil || !grpcConfig.Enable {
+		return nil, nil
+	}
+	server, err := n.newGrpcServer()
+	if err != nil {
+		return nil, err
+	}
+	port := grpcConfig.Port
+	if port == 0 {
+		port = defaultGrpcPort
+	}
+	addr := fmt.Sprintf(":%d", port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	go func() {
+		logger.GrpcLog.Infoln("Starting gRPC server on", addr)
+		errChan <- server.Serve(listener)
+	}()
+	return server, nil
+}
+
+func subscribedConfigTypes(req *nfconfigpb.SubscribeRequest) ([]nfconfigpb.ConfigType, error) {
+	if req.GetNfType() == nfconfigpb.NfType_NF_TYPE_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "nf_type must be set")
+	}
+	configTypes := req.GetConfigTypes()
+	if len(configTypes) == 0 {
+		if configTypes = nfTypeConfigTypes[req.GetNfType()]; configTypes == nil {
+			configTypes = defaultConfigTypes
+		}
+	}
+	for _, configType := range configTypes {
+		if _, ok := grpcConfigTypes[configType]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported config type %s", configType)
+		}
+	}
+	return configTypes, nil
+}
+
+// Subscribe sends the requested configuration types changed after the
+// revision of the client, then each change until the client goes away.
+func (s *grpcConfigServer) Subscribe(req *nfconfigpb.SubscribeRequest, stream grpc.ServerStreamingServer[nfconfigpb.ConfigUpdate]) error {
+	configTypes, err := subscribedConfigTypes(req)
+	if err != nil {
+		logger.GrpcLog.Warnf("Rejected subscription %+v: %+v", req, err)
+		return err
+	}
+	client := fmt.Sprintf("%s %s", req.GetNfType(), req.GetNfId())
+	if p, ok := peer.FromContext(stream.Context()); ok {
+		client += " (" + p.Addr.String() + ")"
+	}
+	logger.GrpcLog.Infof("%s subscribed to %v after revision %d", client, configTypes, req.GetRevision())
+
+	sent := make(map[nfconfigpb.ConfigType]uint64, len(configTypes))
+	for _, configType := range configTypes {
+		sent[configType] = req.GetRevision()
+	}
+	for {
+		changed := s.n.watcher.changes()
+		snapshot := s.n.snapshot()
+		for _, configType := range configTypes {
+			config, ok := snapshot.changedSince(grpcConfigTypes[configType].configType, sent[configType])
+			if !ok {
+				continue
+			}
+			update, err := toConfigUpdate(configType, config)
+			if err != nil {
+				logger.GrpcLog.Errorf("Failed to convert %s revision %d: %+v", configType, config.revision, err)
+				return status.Errorf(codes.Internal, "failed to convert %s", configType)
+			}
+			if err := stream.Send(update); err != nil {
+				logger.GrpcLog.Warnf("Failed to send %s to %s: %+v", configType, client, err)
+				return err
+			}
+			sent[configType] = config.revision
+		}
+		select {
+		case <-changed:
+		case <-s.n.watcher.stopped():
+			return status.Error(codes.Unavailable, "NFConfig server is shutting down")
+		case <-stream.Context().Done():
+			logger.GrpcLog.Infof("%s unsubscribed", client)
+			return nil
+		}
+	}
+}
+
+// toConfigUpdate converts the JSON payload served by the REST API to its gRPC
+// message, so that both APIs always serve the same snapshot.
+func toConfigUpdate(configType nfconfigpb.ConfigType, config configRevision) (*nfconfigpb.ConfigUpdate, error) {
+	update := &nfconfigpb.ConfigUpdate{
+		ConfigType: configType,
+		Revision:   config.revision,
+		Hash:       config.hash,
+	}
+	var msg proto.Message
+	switch configType {
+	case nfconfigpb.ConfigType_CONFIG_TYPE_PLMN:
+		plmn := &nfconfigpb.PlmnConfig{}
+		msg, update.Config = plmn, &nfconfigpb.ConfigUpdate_Plmn{Plmn: plmn}
+	case nfconfigpb.ConfigType_CONFIG_TYPE_PLMN_SNSSAI:
+		plmnSnssai := &nfconfigpb.PlmnSnssaiConfig{}
+		msg, update.Config = plmnSnssai, &nfconfigpb.ConfigUpdate_PlmnSnssai{PlmnSnssai: plmnSnssai}
+	case nfconfigpb.ConfigType_CONFIG_TYPE_ACCESS_MOBILITY:
+		accessMobility := &nfconfigpb.AccessMobilityConfig{}
+		msg, update.Config = accessMobility, &nfconfigpb.ConfigUpdate_AccessMobility{AccessMobility: accessMobility}
+	case nfconfigpb.ConfigType_CONFIG_TYPE_SESSION_MANAGEMENT:
+		sessionManagement := &nfconfigpb.SessionManagementConfig{}
+		msg, update.Config = sessionManagement, &nfconfigpb.ConfigUpdate_SessionManagement{SessionManagement: sessionManagement}
+	case nfconfigpb.ConfigType_CONFIG_TYPE_POLICY_CONTROL:
+		policyControl := &nfconfigpb.PolicyControlConfig{}
+		msg, update.Config = policyControl, &nfconfigpb.ConfigUpdate_PolicyControl{PolicyControl: policyControl}
+	case nfconfigpb.ConfigType_CONFIG_TYPE_IMSI_QOS:
+		imsiQos := &nfconfigpb.ImsiQosConfig{}
+		msg, update.Config = imsiQos, &nfconfigpb.ConfigUpdate_ImsiQos{ImsiQos: imsiQos}
+	default:
+		return nil, fmt.Errorf("unsupported config type %s", configType)
+	}
+	wrapped := fmt.Appendf(nil, `{%q:%s}`, grpcConfigTypes[configType].field, config.payload)
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(wrapped, msg); err != nil {
+		return nil, err
+	}
+	return update, nil
+}
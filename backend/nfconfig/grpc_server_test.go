@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+package nfconfig
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/omec-project/openapi/nfConfigApi"
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/backend/nfconfig/nfconfigpb"
+	"github.com/omec-project/webconsole/configmodels"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+func newGrpcTestClient(t *testing.T, n *NFConfigServer) nfconfigpb.NfConfigClient {
+	t.Helper()
+	server, err := n.newGrpcServer()
+	if err != nil {
+		t.Fatalf("failed to create gRPC server: %v", err)
+	}
+	listener := bufconn.Listen(1024 * 1024)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create gRPC client: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return nfconfigpb.NewNfConfigClient(conn)
+}
+
+func testSnapshot(mnc string) *inMemoryConfig {
+	port := int32(8805)
+	plmn := *nfConfigApi.NewPlmnId("001", mnc)
+	return &inMemoryConfig{
+		plmn: []nfConfigApi.PlmnId{plmn},
+		plmnSnssai: []nfConfigApi.PlmnSnssai{
+			{PlmnId: plmn, SNssaiList: []nfConfigApi.Snssai{makeSnssaiWithSd(1, "010203")}},
+		},
+		sessionManagement: []nfConfigApi.SessionManagement{
+			{
+				SliceName: "slice1",
+				PlmnId:    plmn,
+				Snssai:    makeSnssaiWithSd(1, "010203"),
+				IpDomain:  []nfConfigApi.IpDomain{{DnnName: "internet", DnsIpv4: "8.8.8.8", UeSubnet: "10.0.0.0/16", Mtu: 1400}},
+				Upf:       &nfConfigApi.Upf{Hostname: "upf", Port: &port},
+				GnbNames:  []string{"gnb1"},
+			},
+		},
+		imsiQos: []imsiQosConfig{
+			{
+				imsiRanges: []configmodels.DeviceGroupsImsiRange{{Prefix: "00101", Count: 100}},
+				dnn:        "internet",
+				qos:        []nfConfigApi.ImsiQos{*nfConfigApi.NewImsiQos("1 Mbps", "2 Mbps", 9, 1)},
+			},
+		},
+	}
+}
+
+func TestGrpcSubscribe_StreamsSnapshotAndUpdates(t *testing.T) {
+	n := &NFConfigServer{config: &factory.Configuration{}}
+	n.publish(testSnapshot("01"))
+	client := newGrpcTestClient(t, n)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Subscribe(ctx, &nfconfigpb.SubscribeRequest{NfType: nfconfigpb.NfType_NF_TYPE_SMF, NfId: "smf-1"})
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	updates := map[nfconfigpb.ConfigType]*nfconfigpb.ConfigUpdate{}
+	for range nfTypeConfigTypes[nfconfigpb.NfType_NF_TYPE_SMF] {
+		update, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("failed to receive update: %v", err)
+		}
+		updates[update.GetConfigType()] = update
+	}
+	snapshot := n.snapshot()
+	for configType, update := range updates {
+		expected := snapshot.revisions[grpcConfigTypes[configType].configType]
+		if update.GetRevision() != expected.revision || update.GetHash() != expected.hash {
+			t.Errorf("expected %s at revision %d with hash %s, got %d %s", configType, expected.revision, expected.hash, update.GetRevision(), update.GetHash())
+		}
+	}
+
+	expectedSessionManagement := &nfconfigpb.SessionManagementConfig{
+		SessionManagement: []*nfconfigpb.SessionManagement{
+			{
+				SliceName: "slice1",
+				PlmnId:    &nfconfigpb.PlmnId{Mcc: "001", Mnc: "01"},
+				Snssai:    &nfconfigpb.Snssai{Sst: 1, Sd: "010203"},
+				IpDomain:  []*nfconfigpb.IpDomain{{DnnName: "internet", DnsIpv4: "8.8.8.8", UeSubnet: "10.0.0.0/16", Mtu: 1400}},
+				Upf:       &nfconfigpb.Upf{Hostname: "upf", Port: 8805},
+				GnbNames:  []string{"gnb1"},
+			},
+		},
+	}
+	if got := updates[nfconfigpb.ConfigType_CONFIG_TYPE_SESSION_MANAGEMENT].GetSessionManagement(); !proto.Equal(got, expectedSessionManagement) {
+		t.Errorf("expected session management %v, got %v", expectedSessionManagement, got)
+	}
+	expectedImsiQos := &nfconfigpb.ImsiQosConfig{
+		ImsiQos: []*nfconfigpb.ImsiQosEntry{
+			{
+				ImsiRanges: []*nfconfigpb.ImsiRange{{Prefix: "00101", Count: 100}},
+				Dnn:        "internet",
+				Qos:        []*nfconfigpb.ImsiQos{{MbrUplink: "1 Mbps", MbrDownlink: "2 Mbps", FiveQi: 9, ArpPriorityLevel: 1}},
+			},
+		},
+	}
+	if got := updates[nfconfigpb.ConfigType_CONFIG_TYPE_IMSI_QOS].GetImsiQos(); !proto.Equal(got, expectedImsiQos) {
+		t.Errorf("expected IMSI QoS %v, got %v", expectedImsiQos, got)
+	}
+
+	n.publish(testSnapshot("02"))
+	for range 3 {
+		update, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("failed to receive update: %v", err)
+		}
+		if update.GetRevision() != 2 {
+			t.Errorf("expected revision 2, got %d for %s", update.GetRevision(), update.GetConfigType())
+		}
+		if update.GetConfigType() == nfconfigpb.ConfigType_CONFIG_TYPE_IMSI_QOS {
+			t.Errorf("expected unchanged IMSI QoS not to be sent again")
+		}
+	}
+}
+
+func TestGrpcSubscribe_ResumesFromRevision(t *testing.T) {
+	n := &NFConfigServer{config: &factory.Configuration{}}
+	n.publish(testSnapshot("01"))
+	client := newGrpcTestClient(t, n)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Subscribe(ctx, &nfconfigpb.SubscribeRequest{
+		NfType:      nfconfigpb.NfType_NF_TYPE_AMF,
+		Revision:    1,
+		ConfigTypes: []nfconfigpb.ConfigType{nfconfigpb.ConfigType_CONFIG_TYPE_PLMN},
+	})
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		n.publish(testSnapshot("02"))
+	}()
+
+	update, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("failed to receive update: %v", err)
+	}
+	expected := &nfconfigpb.PlmnConfig{Plmn: []*nfconfigpb.PlmnId{{Mcc: "001", Mnc: "02"}}}
+	if update.GetRevision() != 2 || !proto.Equal(update.GetPlmn(), expected) {
+		t.Errorf("expected %v at revision 2, got %v at revision %d", expected, update.GetPlmn(), update.GetRevision())
+	}
+}
+
+func TestGrpcSubscribe_InvalidRequest(t *testing.T) {
+	n := &NFConfigServer{config: &factory.Configuration{}}
+	n.publish(testSnapshot("01"))
+	client := newGrpcTestClient(t, n)
+
+	testCases := []struct {
+		name string
+		req  *nfconfigpb.SubscribeRequest
+	}{
+		{"missing NF type", &nfconfigpb.SubscribeRequest{}},
+		{"unsupported config type", &nfconfigpb.SubscribeRequest{
+			NfType:      nfconfigpb.NfType_NF_TYPE_AMF,
+			ConfigTypes: []nfconfigpb.ConfigType{nfconfigpb.ConfigType_CONFIG_TYPE_UNSPECIFIED},
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			stream, err := client.Subscribe(ctx, tc.req)
+			if err != nil {
+				t.Fatalf("failed to subscribe: %v", err)
+			}
+			if _, err = stream.Recv(); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected %s, got %v", codes.InvalidArgument, err)
+			}
+		})
+	}
+}
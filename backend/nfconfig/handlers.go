@@ -6,8 +6,9 @@
 package nfconfig
 
 import (
+	"fmt"
 	"net/http"
-	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -16,44 +17,108 @@ import (
 )
 
 func (n *NFConfigServer) GetAccessMobilityConfig(c *gin.Context) {
-	logger.NfConfigLog.Debugf("Handling GET request for access-mobility config %+v", n.inMemoryConfig.accessAndMobility)
-	c.JSON(http.StatusOK, n.inMemoryConfig.accessAndMobility)
+	config, ok := n.filteredSnapshot(c)
+	if !ok {
+		return
+	}
+	logger.NfConfigLog.Debugf("Handling GET request for access-mobility config %+v", config.accessAndMobility)
+	respondWithConfig(c, config, accessMobilityConfig, config.accessAndMobility)
 }
 
 func (n *NFConfigServer) GetPlmnConfig(c *gin.Context) {
-	logger.NfConfigLog.Debugf("Handling GET request for plmn config %+v", n.inMemoryConfig.plmn)
-	c.JSON(http.StatusOK, n.inMemoryConfig.plmn)
+	config, ok := n.filteredSnapshot(c)
+	if !ok {
+		return
+	}
+	logger.NfConfigLog.Debugf("Handling GET request for plmn config %+v", config.plmn)
+	respondWithConfig(c, config, plmnConfig, config.plmn)
 }
 
 func (n *NFConfigServer) GetPlmnSnssaiConfig(c *gin.Context) {
-	logger.NfConfigLog.Debugf("Handling GET request for plmn-snssai config %+v", n.inMemoryConfig.plmnSnssai)
-	c.JSON(http.StatusOK, n.inMemoryConfig.plmnSnssai)
+	config, ok := n.filteredSnapshot(c)
+	if !ok {
+		return
+	}
+	logger.NfConfigLog.Debugf("Handling GET request for plmn-snssai config %+v", config.plmnSnssai)
+	respondWithConfig(c, config, plmnSnssaiConfig, config.plmnSnssai)
 }
 
 func (n *NFConfigServer) GetPolicyControlConfig(c *gin.Context) {
-	logger.NfConfigLog.Debugf("Handling GET request for policy-control config %+v", n.inMemoryConfig.policyControl)
-	c.JSON(http.StatusOK, n.inMemoryConfig.policyControl)
+	config, ok := n.filteredSnapshot(c)
+	if !ok {
+		return
+	}
+	logger.NfConfigLog.Debugf("Handling GET request for policy-control config %+v", config.policyControl)
+	respondWithConfig(c, config, policyControlConfig, config.policyControl)
 }
 
 func (n *NFConfigServer) GetSessionManagementConfig(c *gin.Context) {
-	logger.NfConfigLog.Debugf("Handling GET request for session-management config %+v", n.inMemoryConfig.sessionManagement)
-	c.JSON(http.StatusOK, n.inMemoryConfig.sessionManagement)
+	config, ok := n.filteredSnapshot(c)
+	if !ok {
+		return
+	}
+	logger.NfConfigLog.Debugf("Handling GET request for session-management config %+v", config.sessionManagement)
+	respondWithConfig(c, config, sessionManagementConfig, config.sessionManagement)
 }
 
 func (n *NFConfigServer) GetImsiQosConfig(c *gin.Context) {
 	dnn := c.Param("dnn")
 	imsi := strings.TrimPrefix(c.Param("imsi"), "imsi-")
 	logger.NfConfigLog.Debugf("Handling GET request for QoS config for IMSI %s", imsi)
-	imsiQos := []nfConfigApi.ImsiQos{}
-	for _, imsiQosConfig := range n.inMemoryConfig.imsiQos {
-		if imsiQosConfig.dnn == dnn && slices.Contains(imsiQosConfig.imsis, imsi) {
-			imsiQos = imsiQosConfig.qos
-			break
-		}
-	}
+	config := n.snapshot()
+	imsiQos, _ := config.imsiQosIndex.lookup(dnn, imsi)
 	if len(imsiQos) > 0 {
-		c.JSON(http.StatusOK, imsiQos)
+		respondWithConfig(c, config, imsiQosConfigType, imsiQos)
+		return
+	}
+	c.Header(revisionHeader, strconv.FormatUint(config.revisions[imsiQosConfigType].revision, 10))
+	c.JSON(http.StatusNotFound, []nfConfigApi.ImsiQos{})
+}
+
+const (
+	qosBatchAction  = ":batch"
+	maxQosBatchSize = 1000
+)
+
+type imsiQosLookup struct {
+	Dnn  string `json:"dnn"`
+	Imsi string `json:"imsi"`
+}
+
+type imsiQosLookupResult struct {
+	Dnn  string                `json:"dnn"`
+	Imsi string                `json:"imsi"`
+	Qos  []nfConfigApi.ImsiQos `json:"qos"`
+}
+
+// PostImsiQosBatch resolves the QoS of many (DNN, IMSI) pairs in one request
+// to POST /nfconfig/qos:batch. The results follow the order of the lookups,
+// with an empty QoS for the IMSIs that have none.
+func (n *NFConfigServer) PostImsiQosBatch(c *gin.Context) {
+	if c.Param("action") != qosBatchAction {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown QoS action"})
+		return
+	}
+	var lookups []imsiQosLookup
+	if err := c.ShouldBindJSON(&lookups); err != nil {
+		logger.NfConfigLog.Warnf("Invalid QoS batch request: %+v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "request body must be a list of dnn and imsi pairs"})
 		return
 	}
-	c.JSON(http.StatusNotFound, imsiQos)
+	if len(lookups) > maxQosBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d lookups are allowed per request", maxQosBatchSize)})
+		return
+	}
+	logger.NfConfigLog.Debugf("Handling QoS batch request for %d IMSIs", len(lookups))
+	config := n.snapshot()
+	results := make([]imsiQosLookupResult, 0, len(lookups))
+	for _, lookup := range lookups {
+		imsiQos, _ := config.imsiQosIndex.lookup(lookup.Dnn, strings.TrimPrefix(lookup.Imsi, "imsi-"))
+		if imsiQos == nil {
+			imsiQos = []nfConfigApi.ImsiQos{}
+		}
+		results = append(results, imsiQosLookupResult{Dnn: lookup.Dnn, Imsi: lookup.Imsi, Qos: imsiQos})
+	}
+	c.Header(revisionHeader, strconv.FormatUint(config.revisions[imsiQosConfigType].revision, 10))
+	c.JSON(http.StatusOK, results)
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/omec-project/openapi/nfConfigApi"
+	"github.com/omec-project/webconsole/configmodels"
 )
 
 func TestGetImsiQosConfig(t *testing.T) {
@@ -49,6 +50,53 @@ func TestGetImsiQosConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "matching dnn and imsi in range found",
+			imsi: "imsi-001010000004242",
+			inMemoryData: []imsiQosConfig{
+				{
+					dnn:        "internet",
+					imsiRanges: []configmodels.DeviceGroupsImsiRange{{Prefix: "0010100000", Count: 10000}},
+					qos: []nfConfigApi.ImsiQos{
+						{
+							MbrUplink:        "20 Kbps",
+							MbrDownlink:      "100 Kbps",
+							FiveQi:           7,
+							ArpPriorityLevel: 32,
+						},
+					},
+				},
+			},
+			expectedCode: http.StatusOK,
+			expectedData: []nfConfigApi.ImsiQos{
+				{
+					MbrUplink:        "20 Kbps",
+					MbrDownlink:      "100 Kbps",
+					FiveQi:           7,
+					ArpPriorityLevel: 32,
+				},
+			},
+		},
+		{
+			name: "matching dnn but imsi outside range",
+			imsi: "imsi-001010000010000",
+			inMemoryData: []imsiQosConfig{
+				{
+					dnn:        "internet",
+					imsiRanges: []configmodels.DeviceGroupsImsiRange{{Start: "001010000000000", End: "001010000009999"}},
+					qos: []nfConfigApi.ImsiQos{
+						{
+							MbrUplink:        "20 Kbps",
+							MbrDownlink:      "100 Kbps",
+							FiveQi:           7,
+							ArpPriorityLevel: 32,
+						},
+					},
+				},
+			},
+			expectedCode: http.StatusNotFound,
+			expectedData: []nfConfigApi.ImsiQos{},
+		},
 		{
 			name: "matching dnn but no imsi found",
 			imsi: "imsi-001010000000001",
@@ -103,9 +151,9 @@ func TestGetImsiQosConfig(t *testing.T) {
 			router := gin.New()
 
 			nfServer := &NFConfigServer{
-				Router:         router,
-				inMemoryConfig: inMemoryConfig{imsiQos: tc.inMemoryData},
+				Router: router,
 			}
+			nfServer.inMemoryConfig.Store(&inMemoryConfig{imsiQos: tc.inMemoryData, imsiQosIndex: newImsiQosIndex(tc.inMemoryData)})
 			nfServer.setupRoutes()
 			w := httptest.NewRecorder()
 			req, err := http.NewRequest(http.MethodGet, "/nfconfig/qos/"+"internet/"+tc.imsi, nil)
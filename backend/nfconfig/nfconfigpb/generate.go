@@ -0,0 +1,9 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package nfconfigpb holds the gRPC definition of the NFConfig service.
+package nfconfigpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative nfconfig.proto
@@ -0,0 +1,1859 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: nfconfig.proto
+
+package nfconfigpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type NfType int32
+
+const (
+	NfType_NF_TYPE_UNSPECIFIED NfType = 0
+	NfType_NF_TYPE_AMF         NfType = 1
+	NfType_NF_TYPE_SMF         NfType = 2
+	NfType_NF_TYPE_PCF         NfType = 3
+	NfType_NF_TYPE_NSSF        NfType = 4
+	NfType_NF_TYPE_AUSF        NfType = 5
+	NfType_NF_TYPE_UDM         NfType = 6
+	NfType_NF_TYPE_UDR         NfType = 7
+	NfType_NF_TYPE_NRF         NfType = 8
+)
+
+// Enum value maps for NfType.
+var (
+	NfType_name = map[int32]string{
+		0: "NF_TYPE_UNSPECIFIED",
+		1: "NF_TYPE_AMF",
+		2: "NF_TYPE_SMF",
+		3: "NF_TYPE_PCF",
+		4: "NF_TYPE_NSSF",
+		5: "NF_TYPE_AUSF",
+		6: "NF_TYPE_UDM",
+		7: "NF_TYPE_UDR",
+		8: "NF_TYPE_NRF",
+	}
+	NfType_value = map[string]int32{
+		"NF_TYPE_UNSPECIFIED": 0,
+		"NF_TYPE_AMF":         1,
+		"NF_TYPE_SMF":         2,
+		"NF_TYPE_PCF":         3,
+		"NF_TYPE_NSSF":        4,
+		"NF_TYPE_AUSF":        5,
+		"NF_TYPE_UDM":         6,
+		"NF_TYPE_UDR":         7,
+		"NF_TYPE_NRF":         8,
+	}
+)
+
+func (x NfType) Enum() *NfType {
+	p := new(NfType)
+	*p = x
+	return p
+}
+
+func (x NfType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NfType) Descriptor() protoreflect.EnumDescriptor {
+	return file_nfconfig_proto_enumTypes[0].Descriptor()
+}
+
+func (NfType) Type() protoreflect.EnumType {
+	return &file_nfconfig_proto_enumTypes[0]
+}
+
+func (x NfType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NfType.Descriptor instead.
+func (NfType) EnumDescriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{0}
+}
+
+type ConfigType int32
+
+const (
+	ConfigType_CONFIG_TYPE_UNSPECIFIED        ConfigType = 0
+	ConfigType_CONFIG_TYPE_PLMN               ConfigType = 1
+	ConfigType_CONFIG_TYPE_PLMN_SNSSAI        ConfigType = 2
+	ConfigType_CONFIG_TYPE_ACCESS_MOBILITY    ConfigType = 3
+	ConfigType_CONFIG_TYPE_SESSION_MANAGEMENT ConfigType = 4
+	ConfigType_CONFIG_TYPE_POLICY_CONTROL     ConfigType = 5
+	ConfigType_CONFIG_TYPE_IMSI_QOS           ConfigType = 6
+)
+
+// Enum value maps for ConfigType.
+var (
+	ConfigType_name = map[int32]string{
+		0: "CONFIG_TYPE_UNSPECIFIED",
+		1: "CONFIG_TYPE_PLMN",
+		2: "CONFIG_TYPE_PLMN_SNSSAI",
+		3: "CONFIG_TYPE_ACCESS_MOBILITY",
+		4: "CONFIG_TYPE_SESSION_MANAGEMENT",
+		5: "CONFIG_TYPE_POLICY_CONTROL",
+		6: "CONFIG_TYPE_IMSI_QOS",
+	}
+	ConfigType_value = map[string]int32{
+		"CONFIG_TYPE_UNSPECIFIED":        0,
+		"CONFIG_TYPE_PLMN":               1,
+		"CONFIG_TYPE_PLMN_SNSSAI":        2,
+		"CONFIG_TYPE_ACCESS_MOBILITY":    3,
+		"CONFIG_TYPE_SESSION_MANAGEMENT": 4,
+		"CONFIG_TYPE_POLICY_CONTROL":     5,
+		"CONFIG_TYPE_IMSI_QOS":           6,
+	}
+)
+
+func (x ConfigType) Enum() *ConfigType {
+	p := new(ConfigType)
+	*p = x
+	return p
+}
+
+func (x ConfigType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ConfigType) Descriptor() protoreflect.EnumDescriptor {
+	return file_nfconfig_proto_enumTypes[1].Descriptor()
+}
+
+func (ConfigType) Type() protoreflect.EnumType {
+	return &file_nfconfig_proto_enumTypes[1]
+}
+
+func (x ConfigType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ConfigType.Descriptor instead.
+func (ConfigType) EnumDescriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{1}
+}
+
+type SubscribeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// nf_type identifies the client and selects the configuration types it
+	// gets when config_types is empty.
+	NfType NfType `protobuf:"varint,1,opt,name=nf_type,json=nfType,proto3,enum=nfconfig.v1.NfType" json:"nf_type,omitempty"`
+	// nf_id identifies the instance of the client in the logs.
+	NfId string `protobuf:"bytes,2,opt,name=nf_id,json=nfId,proto3" json:"nf_id,omitempty"`
+	// revision is the last revision the client saw. Only the types changed
+	// after it are sent first.
+	Revision      uint64       `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	ConfigTypes   []ConfigType `protobuf:"varint,4,rep,packed,name=config_types,json=configTypes,proto3,enum=nfconfig.v1.ConfigType" json:"config_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_nfconfig_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubscribeRequest) GetNfType() NfType {
+	if x != nil {
+		return x.NfType
+	}
+	return NfType_NF_TYPE_UNSPECIFIED
+}
+
+func (x *SubscribeRequest) GetNfId() string {
+	if x != nil {
+		return x.NfId
+	}
+	return ""
+}
+
+func (x *SubscribeRequest) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *SubscribeRequest) GetConfigTypes() []ConfigType {
+	if x != nil {
+		return x.ConfigTypes
+	}
+	return nil
+}
+
+type ConfigUpdate struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ConfigType ConfigType             `protobuf:"varint,1,opt,name=config_type,json=configType,proto3,enum=nfconfig.v1.ConfigType" json:"config_type,omitempty"`
+	// revision is the revision of the sync that last changed the type.
+	Revision uint64 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	// hash is the content hash also served as ETag by the REST API.
+	Hash string `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	// Types that are valid to be assigned to Config:
+	//
+	//	*ConfigUpdate_Plmn
+	//	*ConfigUpdate_PlmnSnssai
+	//	*ConfigUpdate_AccessMobility
+	//	*ConfigUpdate_SessionManagement
+	//	*ConfigUpdate_PolicyControl
+	//	*ConfigUpdate_ImsiQos
+	Config        isConfigUpdate_Config `protobuf_oneof:"config"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigUpdate) Reset() {
+	*x = ConfigUpdate{}
+	mi := &file_nfconfig_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigUpdate) ProtoMessage() {}
+
+func (x *ConfigUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigUpdate.ProtoReflect.Descriptor instead.
+func (*ConfigUpdate) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConfigUpdate) GetConfigType() ConfigType {
+	if x != nil {
+		return x.ConfigType
+	}
+	return ConfigType_CONFIG_TYPE_UNSPECIFIED
+}
+
+func (x *ConfigUpdate) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *ConfigUpdate) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *ConfigUpdate) GetConfig() isConfigUpdate_Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *ConfigUpdate) GetPlmn() *PlmnConfig {
+	if x != nil {
+		if x, ok := x.Config.(*ConfigUpdate_Plmn); ok {
+			return x.Plmn
+		}
+	}
+	return nil
+}
+
+func (x *ConfigUpdate) GetPlmnSnssai() *PlmnSnssaiConfig {
+	if x != nil {
+		if x, ok := x.Config.(*ConfigUpdate_PlmnSnssai); ok {
+			return x.PlmnSnssai
+		}
+	}
+	return nil
+}
+
+func (x *ConfigUpdate) GetAccessMobility() *AccessMobilityConfig {
+	if x != nil {
+		if x, ok := x.Config.(*ConfigUpdate_AccessMobility); ok {
+			return x.AccessMobility
+		}
+	}
+	return nil
+}
+
+func (x *ConfigUpdate) GetSessionManagement() *SessionManagementConfig {
+	if x != nil {
+		if x, ok := x.Config.(*ConfigUpdate_SessionManagement); ok {
+			return x.SessionManagement
+		}
+	}
+	return nil
+}
+
+func (x *ConfigUpdate) GetPolicyControl() *PolicyControlConfig {
+	if x != nil {
+		if x, ok := x.Config.(*ConfigUpdate_PolicyControl); ok {
+			return x.PolicyControl
+		}
+	}
+	return nil
+}
+
+func (x *ConfigUpdate) GetImsiQos() *ImsiQosConfig {
+	if x != nil {
+		if x, ok := x.Config.(*ConfigUpdate_ImsiQos); ok {
+			return x.ImsiQos
+		}
+	}
+	return nil
+}
+
+type isConfigUpdate_Config interface {
+	isConfigUpdate_Config()
+}
+
+type ConfigUpdate_Plmn struct {
+	Plmn *PlmnConfig `protobuf:"bytes,10,opt,name=plmn,proto3,oneof"`
+}
+
+type ConfigUpdate_PlmnSnssai struct {
+	PlmnSnssai *PlmnSnssaiConfig `protobuf:"bytes,11,opt,name=plmn_snssai,json=plmnSnssai,proto3,oneof"`
+}
+
+type ConfigUpdate_AccessMobility struct {
+	AccessMobility *AccessMobilityConfig `protobuf:"bytes,12,opt,name=access_mobility,json=accessMobility,proto3,oneof"`
+}
+
+type ConfigUpdate_SessionManagement struct {
+	SessionManagement *SessionManagementConfig `protobuf:"bytes,13,opt,name=session_management,json=sessionManagement,proto3,oneof"`
+}
+
+type ConfigUpdate_PolicyControl struct {
+	PolicyControl *PolicyControlConfig `protobuf:"bytes,14,opt,name=policy_control,json=policyControl,proto3,oneof"`
+}
+
+type ConfigUpdate_ImsiQos struct {
+	ImsiQos *ImsiQosConfig `protobuf:"bytes,15,opt,name=imsi_qos,json=imsiQos,proto3,oneof"`
+}
+
+func (*ConfigUpdate_Plmn) isConfigUpdate_Config() {}
+
+func (*ConfigUpdate_PlmnSnssai) isConfigUpdate_Config() {}
+
+func (*ConfigUpdate_AccessMobility) isConfigUpdate_Config() {}
+
+func (*ConfigUpdate_SessionManagement) isConfigUpdate_Config() {}
+
+func (*ConfigUpdate_PolicyControl) isConfigUpdate_Config() {}
+
+func (*ConfigUpdate_ImsiQos) isConfigUpdate_Config() {}
+
+type PlmnId struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mcc           string                 `protobuf:"bytes,1,opt,name=mcc,proto3" json:"mcc,omitempty"`
+	Mnc           string                 `protobuf:"bytes,2,opt,name=mnc,proto3" json:"mnc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlmnId) Reset() {
+	*x = PlmnId{}
+	mi := &file_nfconfig_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlmnId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlmnId) ProtoMessage() {}
+
+func (x *PlmnId) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlmnId.ProtoReflect.Descriptor instead.
+func (*PlmnId) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PlmnId) GetMcc() string {
+	if x != nil {
+		return x.Mcc
+	}
+	return ""
+}
+
+func (x *PlmnId) GetMnc() string {
+	if x != nil {
+		return x.Mnc
+	}
+	return ""
+}
+
+type Snssai struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sst           int32                  `protobuf:"varint,1,opt,name=sst,proto3" json:"sst,omitempty"`
+	Sd            string                 `protobuf:"bytes,2,opt,name=sd,proto3" json:"sd,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Snssai) Reset() {
+	*x = Snssai{}
+	mi := &file_nfconfig_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Snssai) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snssai) ProtoMessage() {}
+
+func (x *Snssai) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snssai.ProtoReflect.Descriptor instead.
+func (*Snssai) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Snssai) GetSst() int32 {
+	if x != nil {
+		return x.Sst
+	}
+	return 0
+}
+
+func (x *Snssai) GetSd() string {
+	if x != nil {
+		return x.Sd
+	}
+	return ""
+}
+
+type PlmnConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plmn          []*PlmnId              `protobuf:"bytes,1,rep,name=plmn,proto3" json:"plmn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlmnConfig) Reset() {
+	*x = PlmnConfig{}
+	mi := &file_nfconfig_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlmnConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlmnConfig) ProtoMessage() {}
+
+func (x *PlmnConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlmnConfig.ProtoReflect.Descriptor instead.
+func (*PlmnConfig) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PlmnConfig) GetPlmn() []*PlmnId {
+	if x != nil {
+		return x.Plmn
+	}
+	return nil
+}
+
+type PlmnSnssai struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlmnId        *PlmnId                `protobuf:"bytes,1,opt,name=plmn_id,json=plmnId,proto3" json:"plmn_id,omitempty"`
+	SNssaiList    []*Snssai              `protobuf:"bytes,2,rep,name=s_nssai_list,json=sNssaiList,proto3" json:"s_nssai_list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlmnSnssai) Reset() {
+	*x = PlmnSnssai{}
+	mi := &file_nfconfig_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlmnSnssai) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlmnSnssai) ProtoMessage() {}
+
+func (x *PlmnSnssai) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlmnSnssai.ProtoReflect.Descriptor instead.
+func (*PlmnSnssai) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PlmnSnssai) GetPlmnId() *PlmnId {
+	if x != nil {
+		return x.PlmnId
+	}
+	return nil
+}
+
+func (x *PlmnSnssai) GetSNssaiList() []*Snssai {
+	if x != nil {
+		return x.SNssaiList
+	}
+	return nil
+}
+
+type PlmnSnssaiConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlmnSnssai    []*PlmnSnssai          `protobuf:"bytes,1,rep,name=plmn_snssai,json=plmnSnssai,proto3" json:"plmn_snssai,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlmnSnssaiConfig) Reset() {
+	*x = PlmnSnssaiConfig{}
+	mi := &file_nfconfig_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlmnSnssaiConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlmnSnssaiConfig) ProtoMessage() {}
+
+func (x *PlmnSnssaiConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlmnSnssaiConfig.ProtoReflect.Descriptor instead.
+func (*PlmnSnssaiConfig) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PlmnSnssaiConfig) GetPlmnSnssai() []*PlmnSnssai {
+	if x != nil {
+		return x.PlmnSnssai
+	}
+	return nil
+}
+
+type AccessAndMobility struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlmnId        *PlmnId                `protobuf:"bytes,1,opt,name=plmn_id,json=plmnId,proto3" json:"plmn_id,omitempty"`
+	Snssai        *Snssai                `protobuf:"bytes,2,opt,name=snssai,proto3" json:"snssai,omitempty"`
+	Tacs          []string               `protobuf:"bytes,3,rep,name=tacs,proto3" json:"tacs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessAndMobility) Reset() {
+	*x = AccessAndMobility{}
+	mi := &file_nfconfig_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessAndMobility) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessAndMobility) ProtoMessage() {}
+
+func (x *AccessAndMobility) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessAndMobility.ProtoReflect.Descriptor instead.
+func (*AccessAndMobility) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AccessAndMobility) GetPlmnId() *PlmnId {
+	if x != nil {
+		return x.PlmnId
+	}
+	return nil
+}
+
+func (x *AccessAndMobility) GetSnssai() *Snssai {
+	if x != nil {
+		return x.Snssai
+	}
+	return nil
+}
+
+func (x *AccessAndMobility) GetTacs() []string {
+	if x != nil {
+		return x.Tacs
+	}
+	return nil
+}
+
+type AccessMobilityConfig struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AccessMobility []*AccessAndMobility   `protobuf:"bytes,1,rep,name=access_mobility,json=accessMobility,proto3" json:"access_mobility,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AccessMobilityConfig) Reset() {
+	*x = AccessMobilityConfig{}
+	mi := &file_nfconfig_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessMobilityConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessMobilityConfig) ProtoMessage() {}
+
+func (x *AccessMobilityConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessMobilityConfig.ProtoReflect.Descriptor instead.
+func (*AccessMobilityConfig) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AccessMobilityConfig) GetAccessMobility() []*AccessAndMobility {
+	if x != nil {
+		return x.AccessMobility
+	}
+	return nil
+}
+
+type IpDomain struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DnnName       string                 `protobuf:"bytes,1,opt,name=dnn_name,json=dnnName,proto3" json:"dnn_name,omitempty"`
+	DnsIpv4       string                 `protobuf:"bytes,2,opt,name=dns_ipv4,json=dnsIpv4,proto3" json:"dns_ipv4,omitempty"`
+	UeSubnet      string                 `protobuf:"bytes,3,opt,name=ue_subnet,json=ueSubnet,proto3" json:"ue_subnet,omitempty"`
+	Mtu           int32                  `protobuf:"varint,4,opt,name=mtu,proto3" json:"mtu,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IpDomain) Reset() {
+	*x = IpDomain{}
+	mi := &file_nfconfig_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IpDomain) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IpDomain) ProtoMessage() {}
+
+func (x *IpDomain) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IpDomain.ProtoReflect.Descriptor instead.
+func (*IpDomain) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *IpDomain) GetDnnName() string {
+	if x != nil {
+		return x.DnnName
+	}
+	return ""
+}
+
+func (x *IpDomain) GetDnsIpv4() string {
+	if x != nil {
+		return x.DnsIpv4
+	}
+	return ""
+}
+
+func (x *IpDomain) GetUeSubnet() string {
+	if x != nil {
+		return x.UeSubnet
+	}
+	return ""
+}
+
+func (x *IpDomain) GetMtu() int32 {
+	if x != nil {
+		return x.Mtu
+	}
+	return 0
+}
+
+type Upf struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Port          int32                  `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Upf) Reset() {
+	*x = Upf{}
+	mi := &file_nfconfig_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Upf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Upf) ProtoMessage() {}
+
+func (x *Upf) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Upf.ProtoReflect.Descriptor instead.
+func (*Upf) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Upf) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *Upf) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+type SessionManagement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SliceName     string                 `protobuf:"bytes,1,opt,name=slice_name,json=sliceName,proto3" json:"slice_name,omitempty"`
+	PlmnId        *PlmnId                `protobuf:"bytes,2,opt,name=plmn_id,json=plmnId,proto3" json:"plmn_id,omitempty"`
+	Snssai        *Snssai                `protobuf:"bytes,3,opt,name=snssai,proto3" json:"snssai,omitempty"`
+	IpDomain      []*IpDomain            `protobuf:"bytes,4,rep,name=ip_domain,json=ipDomain,proto3" json:"ip_domain,omitempty"`
+	Upf           *Upf                   `protobuf:"bytes,5,opt,name=upf,proto3" json:"upf,omitempty"`
+	GnbNames      []string               `protobuf:"bytes,6,rep,name=gnb_names,json=gnbNames,proto3" json:"gnb_names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionManagement) Reset() {
+	*x = SessionManagement{}
+	mi := &file_nfconfig_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionManagement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionManagement) ProtoMessage() {}
+
+func (x *SessionManagement) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionManagement.ProtoReflect.Descriptor instead.
+func (*SessionManagement) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SessionManagement) GetSliceName() string {
+	if x != nil {
+		return x.SliceName
+	}
+	return ""
+}
+
+func (x *SessionManagement) GetPlmnId() *PlmnId {
+	if x != nil {
+		return x.PlmnId
+	}
+	return nil
+}
+
+func (x *SessionManagement) GetSnssai() *Snssai {
+	if x != nil {
+		return x.Snssai
+	}
+	return nil
+}
+
+func (x *SessionManagement) GetIpDomain() []*IpDomain {
+	if x != nil {
+		return x.IpDomain
+	}
+	return nil
+}
+
+func (x *SessionManagement) GetUpf() *Upf {
+	if x != nil {
+		return x.Upf
+	}
+	return nil
+}
+
+func (x *SessionManagement) GetGnbNames() []string {
+	if x != nil {
+		return x.GnbNames
+	}
+	return nil
+}
+
+type SessionManagementConfig struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	SessionManagement []*SessionManagement   `protobuf:"bytes,1,rep,name=session_management,json=sessionManagement,proto3" json:"session_management,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SessionManagementConfig) Reset() {
+	*x = SessionManagementConfig{}
+	mi := &file_nfconfig_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionManagementConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionManagementConfig) ProtoMessage() {}
+
+func (x *SessionManagementConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionManagementConfig.ProtoReflect.Descriptor instead.
+func (*SessionManagementConfig) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SessionManagementConfig) GetSessionManagement() []*SessionManagement {
+	if x != nil {
+		return x.SessionManagement
+	}
+	return nil
+}
+
+type PccFlow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Description   string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Direction     string                 `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PccFlow) Reset() {
+	*x = PccFlow{}
+	mi := &file_nfconfig_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PccFlow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PccFlow) ProtoMessage() {}
+
+func (x *PccFlow) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PccFlow.ProtoReflect.Descriptor instead.
+func (*PccFlow) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PccFlow) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PccFlow) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *PccFlow) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type Arp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PriorityLevel int32                  `protobuf:"varint,1,opt,name=priority_level,json=priorityLevel,proto3" json:"priority_level,omitempty"`
+	PreemptCap    string                 `protobuf:"bytes,2,opt,name=preempt_cap,json=preemptCap,proto3" json:"preempt_cap,omitempty"`
+	PreemptVuln   string                 `protobuf:"bytes,3,opt,name=preempt_vuln,json=preemptVuln,proto3" json:"preempt_vuln,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Arp) Reset() {
+	*x = Arp{}
+	mi := &file_nfconfig_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Arp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Arp) ProtoMessage() {}
+
+func (x *Arp) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Arp.ProtoReflect.Descriptor instead.
+func (*Arp) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Arp) GetPriorityLevel() int32 {
+	if x != nil {
+		return x.PriorityLevel
+	}
+	return 0
+}
+
+func (x *Arp) GetPreemptCap() string {
+	if x != nil {
+		return x.PreemptCap
+	}
+	return ""
+}
+
+func (x *Arp) GetPreemptVuln() string {
+	if x != nil {
+		return x.PreemptVuln
+	}
+	return ""
+}
+
+type PccQos struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FiveQi        int32                  `protobuf:"varint,1,opt,name=five_qi,json=fiveQi,proto3" json:"five_qi,omitempty"`
+	MaxBrUl       string                 `protobuf:"bytes,2,opt,name=max_br_ul,json=maxBrUl,proto3" json:"max_br_ul,omitempty"`
+	MaxBrDl       string                 `protobuf:"bytes,3,opt,name=max_br_dl,json=maxBrDl,proto3" json:"max_br_dl,omitempty"`
+	Arp           *Arp                   `protobuf:"bytes,4,opt,name=arp,proto3" json:"arp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PccQos) Reset() {
+	*x = PccQos{}
+	mi := &file_nfconfig_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PccQos) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PccQos) ProtoMessage() {}
+
+func (x *PccQos) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PccQos.ProtoReflect.Descriptor instead.
+func (*PccQos) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PccQos) GetFiveQi() int32 {
+	if x != nil {
+		return x.FiveQi
+	}
+	return 0
+}
+
+func (x *PccQos) GetMaxBrUl() string {
+	if x != nil {
+		return x.MaxBrUl
+	}
+	return ""
+}
+
+func (x *PccQos) GetMaxBrDl() string {
+	if x != nil {
+		return x.MaxBrDl
+	}
+	return ""
+}
+
+func (x *PccQos) GetArp() *Arp {
+	if x != nil {
+		return x.Arp
+	}
+	return nil
+}
+
+type PccRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleId        string                 `protobuf:"bytes,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	Flows         []*PccFlow             `protobuf:"bytes,2,rep,name=flows,proto3" json:"flows,omitempty"`
+	Qos           *PccQos                `protobuf:"bytes,3,opt,name=qos,proto3" json:"qos,omitempty"`
+	Precedence    int32                  `protobuf:"varint,4,opt,name=precedence,proto3" json:"precedence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PccRule) Reset() {
+	*x = PccRule{}
+	mi := &file_nfconfig_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PccRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PccRule) ProtoMessage() {}
+
+func (x *PccRule) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PccRule.ProtoReflect.Descriptor instead.
+func (*PccRule) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PccRule) GetRuleId() string {
+	if x != nil {
+		return x.RuleId
+	}
+	return ""
+}
+
+func (x *PccRule) GetFlows() []*PccFlow {
+	if x != nil {
+		return x.Flows
+	}
+	return nil
+}
+
+func (x *PccRule) GetQos() *PccQos {
+	if x != nil {
+		return x.Qos
+	}
+	return nil
+}
+
+func (x *PccRule) GetPrecedence() int32 {
+	if x != nil {
+		return x.Precedence
+	}
+	return 0
+}
+
+type PolicyControl struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlmnId        *PlmnId                `protobuf:"bytes,1,opt,name=plmn_id,json=plmnId,proto3" json:"plmn_id,omitempty"`
+	Snssai        *Snssai                `protobuf:"bytes,2,opt,name=snssai,proto3" json:"snssai,omitempty"`
+	Dnns          []string               `protobuf:"bytes,3,rep,name=dnns,proto3" json:"dnns,omitempty"`
+	PccRules      []*PccRule             `protobuf:"bytes,4,rep,name=pcc_rules,json=pccRules,proto3" json:"pcc_rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyControl) Reset() {
+	*x = PolicyControl{}
+	mi := &file_nfconfig_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyControl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyControl) ProtoMessage() {}
+
+func (x *PolicyControl) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyControl.ProtoReflect.Descriptor instead.
+func (*PolicyControl) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PolicyControl) GetPlmnId() *PlmnId {
+	if x != nil {
+		return x.PlmnId
+	}
+	return nil
+}
+
+func (x *PolicyControl) GetSnssai() *Snssai {
+	if x != nil {
+		return x.Snssai
+	}
+	return nil
+}
+
+func (x *PolicyControl) GetDnns() []string {
+	if x != nil {
+		return x.Dnns
+	}
+	return nil
+}
+
+func (x *PolicyControl) GetPccRules() []*PccRule {
+	if x != nil {
+		return x.PccRules
+	}
+	return nil
+}
+
+type PolicyControlConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PolicyControl []*PolicyControl       `protobuf:"bytes,1,rep,name=policy_control,json=policyControl,proto3" json:"policy_control,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyControlConfig) Reset() {
+	*x = PolicyControlConfig{}
+	mi := &file_nfconfig_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyControlConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyControlConfig) ProtoMessage() {}
+
+func (x *PolicyControlConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyControlConfig.ProtoReflect.Descriptor instead.
+func (*PolicyControlConfig) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PolicyControlConfig) GetPolicyControl() []*PolicyControl {
+	if x != nil {
+		return x.PolicyControl
+	}
+	return nil
+}
+
+type ImsiRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         string                 `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	Prefix        string                 `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Count         uint64                 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImsiRange) Reset() {
+	*x = ImsiRange{}
+	mi := &file_nfconfig_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImsiRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImsiRange) ProtoMessage() {}
+
+func (x *ImsiRange) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImsiRange.ProtoReflect.Descriptor instead.
+func (*ImsiRange) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ImsiRange) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *ImsiRange) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *ImsiRange) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ImsiRange) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type ImsiQos struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	MbrUplink        string                 `protobuf:"bytes,1,opt,name=mbr_uplink,json=mbrUplink,proto3" json:"mbr_uplink,omitempty"`
+	MbrDownlink      string                 `protobuf:"bytes,2,opt,name=mbr_downlink,json=mbrDownlink,proto3" json:"mbr_downlink,omitempty"`
+	FiveQi           int32                  `protobuf:"varint,3,opt,name=five_qi,json=fiveQi,proto3" json:"five_qi,omitempty"`
+	ArpPriorityLevel int32                  `protobuf:"varint,4,opt,name=arp_priority_level,json=arpPriorityLevel,proto3" json:"arp_priority_level,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ImsiQos) Reset() {
+	*x = ImsiQos{}
+	mi := &file_nfconfig_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImsiQos) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImsiQos) ProtoMessage() {}
+
+func (x *ImsiQos) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImsiQos.ProtoReflect.Descriptor instead.
+func (*ImsiQos) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ImsiQos) GetMbrUplink() string {
+	if x != nil {
+		return x.MbrUplink
+	}
+	return ""
+}
+
+func (x *ImsiQos) GetMbrDownlink() string {
+	if x != nil {
+		return x.MbrDownlink
+	}
+	return ""
+}
+
+func (x *ImsiQos) GetFiveQi() int32 {
+	if x != nil {
+		return x.FiveQi
+	}
+	return 0
+}
+
+func (x *ImsiQos) GetArpPriorityLevel() int32 {
+	if x != nil {
+		return x.ArpPriorityLevel
+	}
+	return 0
+}
+
+type ImsiQosEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Imsis         []string               `protobuf:"bytes,1,rep,name=imsis,proto3" json:"imsis,omitempty"`
+	ImsiRanges    []*ImsiRange           `protobuf:"bytes,2,rep,name=imsi_ranges,json=imsi-ranges,proto3" json:"imsi_ranges,omitempty"`
+	Dnn           string                 `protobuf:"bytes,3,opt,name=dnn,proto3" json:"dnn,omitempty"`
+	Qos           []*ImsiQos             `protobuf:"bytes,4,rep,name=qos,proto3" json:"qos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImsiQosEntry) Reset() {
+	*x = ImsiQosEntry{}
+	mi := &file_nfconfig_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImsiQosEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImsiQosEntry) ProtoMessage() {}
+
+func (x *ImsiQosEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImsiQosEntry.ProtoReflect.Descriptor instead.
+func (*ImsiQosEntry) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ImsiQosEntry) GetImsis() []string {
+	if x != nil {
+		return x.Imsis
+	}
+	return nil
+}
+
+func (x *ImsiQosEntry) GetImsiRanges() []*ImsiRange {
+	if x != nil {
+		return x.ImsiRanges
+	}
+	return nil
+}
+
+func (x *ImsiQosEntry) GetDnn() string {
+	if x != nil {
+		return x.Dnn
+	}
+	return ""
+}
+
+func (x *ImsiQosEntry) GetQos() []*ImsiQos {
+	if x != nil {
+		return x.Qos
+	}
+	return nil
+}
+
+type ImsiQosConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ImsiQos       []*ImsiQosEntry        `protobuf:"bytes,1,rep,name=imsi_qos,json=imsiQos,proto3" json:"imsi_qos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImsiQosConfig) Reset() {
+	*x = ImsiQosConfig{}
+	mi := &file_nfconfig_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImsiQosConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImsiQosConfig) ProtoMessage() {}
+
+func (x *ImsiQosConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_nfconfig_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImsiQosConfig.ProtoReflect.Descriptor instead.
+func (*ImsiQosConfig) Descriptor() ([]byte, []int) {
+	return file_nfconfig_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ImsiQosConfig) GetImsiQos() []*ImsiQosEntry {
+	if x != nil {
+		return x.ImsiQos
+	}
+	return nil
+}
+
+var File_nfconfig_proto protoreflect.FileDescriptor
+
+const file_nfconfig_proto_rawDesc = "" +
+	"\n" +
+	"\x0enfconfig.proto\x12\vnfconfig.v1\"\xad\x01\n" +
+	"\x10SubscribeRequest\x12,\n" +
+	"\anf_type\x18\x01 \x01(\x0e2\x13.nfconfig.v1.NfTypeR\x06nfType\x12\x13\n" +
+	"\x05nf_id\x18\x02 \x01(\tR\x04nfId\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x04R\brevision\x12:\n" +
+	"\fconfig_types\x18\x04 \x03(\x0e2\x17.nfconfig.v1.ConfigTypeR\vconfigTypes\"\x9c\x04\n" +
+	"\fConfigUpdate\x128\n" +
+	"\vconfig_type\x18\x01 \x01(\x0e2\x17.nfconfig.v1.ConfigTypeR\n" +
+	"configType\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\x12\x12\n" +
+	"\x04hash\x18\x03 \x01(\tR\x04hash\x12-\n" +
+	"\x04plmn\x18\n" +
+	" \x01(\v2\x17.nfconfig.v1.PlmnConfigH\x00R\x04plmn\x12@\n" +
+	"\vplmn_snssai\x18\v \x01(\v2\x1d.nfconfig.v1.PlmnSnssaiConfigH\x00R\n" +
+	"plmnSnssai\x12L\n" +
+	"\x0faccess_mobility\x18\f \x01(\v2!.nfconfig.v1.AccessMobilityConfigH\x00R\x0eaccessMobility\x12U\n" +
+	"\x12session_management\x18\r \x01(\v2$.nfconfig.v1.SessionManagementConfigH\x00R\x11sessionManagement\x12I\n" +
+	"\x0epolicy_control\x18\x0e \x01(\v2 .nfconfig.v1.PolicyControlConfigH\x00R\rpolicyControl\x127\n" +
+	"\bimsi_qos\x18\x0f \x01(\v2\x1a.nfconfig.v1.ImsiQosConfigH\x00R\aimsiQosB\b\n" +
+	"\x06config\",\n" +
+	"\x06PlmnId\x12\x10\n" +
+	"\x03mcc\x18\x01 \x01(\tR\x03mcc\x12\x10\n" +
+	"\x03mnc\x18\x02 \x01(\tR\x03mnc\"*\n" +
+	"\x06Snssai\x12\x10\n" +
+	"\x03sst\x18\x01 \x01(\x05R\x03sst\x12\x0e\n" +
+	"\x02sd\x18\x02 \x01(\tR\x02sd\"5\n" +
+	"\n" +
+	"PlmnConfig\x12'\n" +
+	"\x04plmn\x18\x01 \x03(\v2\x13.nfconfig.v1.PlmnIdR\x04plmn\"q\n" +
+	"\n" +
+	"PlmnSnssai\x12,\n" +
+	"\aplmn_id\x18\x01 \x01(\v2\x13.nfconfig.v1.PlmnIdR\x06plmnId\x125\n" +
+	"\fs_nssai_list\x18\x02 \x03(\v2\x13.nfconfig.v1.SnssaiR\n" +
+	"sNssaiList\"L\n" +
+	"\x10PlmnSnssaiConfig\x128\n" +
+	"\vplmn_snssai\x18\x01 \x03(\v2\x17.nfconfig.v1.PlmnSnssaiR\n" +
+	"plmnSnssai\"\x82\x01\n" +
+	"\x11AccessAndMobility\x12,\n" +
+	"\aplmn_id\x18\x01 \x01(\v2\x13.nfconfig.v1.PlmnIdR\x06plmnId\x12+\n" +
+	"\x06snssai\x18\x02 \x01(\v2\x13.nfconfig.v1.SnssaiR\x06snssai\x12\x12\n" +
+	"\x04tacs\x18\x03 \x03(\tR\x04tacs\"_\n" +
+	"\x14AccessMobilityConfig\x12G\n" +
+	"\x0faccess_mobility\x18\x01 \x03(\v2\x1e.nfconfig.v1.AccessAndMobilityR\x0eaccessMobility\"o\n" +
+	"\bIpDomain\x12\x19\n" +
+	"\bdnn_name\x18\x01 \x01(\tR\adnnName\x12\x19\n" +
+	"\bdns_ipv4\x18\x02 \x01(\tR\adnsIpv4\x12\x1b\n" +
+	"\tue_subnet\x18\x03 \x01(\tR\bueSubnet\x12\x10\n" +
+	"\x03mtu\x18\x04 \x01(\x05R\x03mtu\"5\n" +
+	"\x03Upf\x12\x1a\n" +
+	"\bhostname\x18\x01 \x01(\tR\bhostname\x12\x12\n" +
+	"\x04port\x18\x02 \x01(\x05R\x04port\"\x82\x02\n" +
+	"\x11SessionManagement\x12\x1d\n" +
+	"\n" +
+	"slice_name\x18\x01 \x01(\tR\tsliceName\x12,\n" +
+	"\aplmn_id\x18\x02 \x01(\v2\x13.nfconfig.v1.PlmnIdR\x06plmnId\x12+\n" +
+	"\x06snssai\x18\x03 \x01(\v2\x13.nfconfig.v1.SnssaiR\x06snssai\x122\n" +
+	"\tip_domain\x18\x04 \x03(\v2\x15.nfconfig.v1.IpDomainR\bipDomain\x12\"\n" +
+	"\x03upf\x18\x05 \x01(\v2\x10.nfconfig.v1.UpfR\x03upf\x12\x1b\n" +
+	"\tgnb_names\x18\x06 \x03(\tR\bgnbNames\"h\n" +
+	"\x17SessionManagementConfig\x12M\n" +
+	"\x12session_management\x18\x01 \x03(\v2\x1e.nfconfig.v1.SessionManagementR\x11sessionManagement\"a\n" +
+	"\aPccFlow\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x1c\n" +
+	"\tdirection\x18\x02 \x01(\tR\tdirection\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\"p\n" +
+	"\x03Arp\x12%\n" +
+	"\x0epriority_level\x18\x01 \x01(\x05R\rpriorityLevel\x12\x1f\n" +
+	"\vpreempt_cap\x18\x02 \x01(\tR\n" +
+	"preemptCap\x12!\n" +
+	"\fpreempt_vuln\x18\x03 \x01(\tR\vpreemptVuln\"}\n" +
+	"\x06PccQos\x12\x17\n" +
+	"\afive_qi\x18\x01 \x01(\x05R\x06fiveQi\x12\x1a\n" +
+	"\tmax_br_ul\x18\x02 \x01(\tR\amaxBrUl\x12\x1a\n" +
+	"\tmax_br_dl\x18\x03 \x01(\tR\amaxBrDl\x12\"\n" +
+	"\x03arp\x18\x04 \x01(\v2\x10.nfconfig.v1.ArpR\x03arp\"\x95\x01\n" +
+	"\aPccRule\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\tR\x06ruleId\x12*\n" +
+	"\x05flows\x18\x02 \x03(\v2\x14.nfconfig.v1.PccFlowR\x05flows\x12%\n" +
+	"\x03qos\x18\x03 \x01(\v2\x13.nfconfig.v1.PccQosR\x03qos\x12\x1e\n" +
+	"\n" +
+	"precedence\x18\x04 \x01(\x05R\n" +
+	"precedence\"\xb1\x01\n" +
+	"\rPolicyControl\x12,\n" +
+	"\aplmn_id\x18\x01 \x01(\v2\x13.nfconfig.v1.PlmnIdR\x06plmnId\x12+\n" +
+	"\x06snssai\x18\x02 \x01(\v2\x13.nfconfig.v1.SnssaiR\x06snssai\x12\x12\n" +
+	"\x04dnns\x18\x03 \x03(\tR\x04dnns\x121\n" +
+	"\tpcc_rules\x18\x04 \x03(\v2\x14.nfconfig.v1.PccRuleR\bpccRules\"X\n" +
+	"\x13PolicyControlConfig\x12A\n" +
+	"\x0epolicy_control\x18\x01 \x03(\v2\x1a.nfconfig.v1.PolicyControlR\rpolicyControl\"a\n" +
+	"\tImsiRange\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\tR\x03end\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\tR\x06prefix\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x04R\x05count\"\x92\x01\n" +
+	"\aImsiQos\x12\x1d\n" +
+	"\n" +
+	"mbr_uplink\x18\x01 \x01(\tR\tmbrUplink\x12!\n" +
+	"\fmbr_downlink\x18\x02 \x01(\tR\vmbrDownlink\x12\x17\n" +
+	"\afive_qi\x18\x03 \x01(\x05R\x06fiveQi\x12,\n" +
+	"\x12arp_priority_level\x18\x04 \x01(\x05R\x10arpPriorityLevel\"\x98\x01\n" +
+	"\fImsiQosEntry\x12\x14\n" +
+	"\x05imsis\x18\x01 \x03(\tR\x05imsis\x128\n" +
+	"\vimsi_ranges\x18\x02 \x03(\v2\x16.nfconfig.v1.ImsiRangeR\vimsi-ranges\x12\x10\n" +
+	"\x03dnn\x18\x03 \x01(\tR\x03dnn\x12&\n" +
+	"\x03qos\x18\x04 \x03(\v2\x14.nfconfig.v1.ImsiQosR\x03qos\"E\n" +
+	"\rImsiQosConfig\x124\n" +
+	"\bimsi_qos\x18\x01 \x03(\v2\x19.nfconfig.v1.ImsiQosEntryR\aimsiQos*\xab\x01\n" +
+	"\x06NfType\x12\x17\n" +
+	"\x13NF_TYPE_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vNF_TYPE_AMF\x10\x01\x12\x0f\n" +
+	"\vNF_TYPE_SMF\x10\x02\x12\x0f\n" +
+	"\vNF_TYPE_PCF\x10\x03\x12\x10\n" +
+	"\fNF_TYPE_NSSF\x10\x04\x12\x10\n" +
+	"\fNF_TYPE_AUSF\x10\x05\x12\x0f\n" +
+	"\vNF_TYPE_UDM\x10\x06\x12\x0f\n" +
+	"\vNF_TYPE_UDR\x10\a\x12\x0f\n" +
+	"\vNF_TYPE_NRF\x10\b*\xdb\x01\n" +
+	"\n" +
+	"ConfigType\x12\x1b\n" +
+	"\x17CONFIG_TYPE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10CONFIG_TYPE_PLMN\x10\x01\x12\x1b\n" +
+	"\x17CONFIG_TYPE_PLMN_SNSSAI\x10\x02\x12\x1f\n" +
+	"\x1bCONFIG_TYPE_ACCESS_MOBILITY\x10\x03\x12\"\n" +
+	"\x1eCONFIG_TYPE_SESSION_MANAGEMENT\x10\x04\x12\x1e\n" +
+	"\x1aCONFIG_TYPE_POLICY_CONTROL\x10\x05\x12\x18\n" +
+	"\x14CONFIG_TYPE_IMSI_QOS\x10\x062S\n" +
+	"\bNfConfig\x12G\n" +
+	"\tSubscribe\x12\x1d.nfconfig.v1.SubscribeRequest\x1a\x19.nfconfig.v1.ConfigUpdate0\x01B@Z>github.com/omec-project/webconsole/backend/nfconfig/nfconfigpbb\x06proto3"
+
+var (
+	file_nfconfig_proto_rawDescOnce sync.Once
+	file_nfconfig_proto_rawDescData []byte
+)
+
+func file_nfconfig_proto_rawDescGZIP() []byte {
+	file_nfconfig_proto_rawDescOnce.Do(func() {
+		file_nfconfig_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nfconfig_proto_rawDesc), len(file_nfconfig_proto_rawDesc)))
+	})
+	return file_nfconfig_proto_rawDescData
+}
+
+var file_nfconfig_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_nfconfig_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_nfconfig_proto_goTypes = []any{
+	(NfType)(0),                     // 0: nfconfig.v1.NfType
+	(ConfigType)(0),                 // 1: nfconfig.v1.ConfigType
+	(*SubscribeRequest)(nil),        // 2: nfconfig.v1.SubscribeRequest
+	(*ConfigUpdate)(nil),            // 3: nfconfig.v1.ConfigUpdate
+	(*PlmnId)(nil),                  // 4: nfconfig.v1.PlmnId
+	(*Snssai)(nil),                  // 5: nfconfig.v1.Snssai
+	(*PlmnConfig)(nil),              // 6: nfconfig.v1.PlmnConfig
+	(*PlmnSnssai)(nil),              // 7: nfconfig.v1.PlmnSnssai
+	(*PlmnSnssaiConfig)(nil),        // 8: nfconfig.v1.PlmnSnssaiConfig
+	(*AccessAndMobility)(nil),       // 9: nfconfig.v1.AccessAndMobility
+	(*AccessMobilityConfig)(nil),    // 10: nfconfig.v1.AccessMobilityConfig
+	(*IpDomain)(nil),                // 11: nfconfig.v1.IpDomain
+	(*Upf)(nil),                     // 12: nfconfig.v1.Upf
+	(*SessionManagement)(nil),       // 13: nfconfig.v1.SessionManagement
+	(*SessionManagementConfig)(nil), // 14: nfconfig.v1.SessionManagementConfig
+	(*PccFlow)(nil),                 // 15: nfconfig.v1.PccFlow
+	(*Arp)(nil),                     // 16: nfconfig.v1.Arp
+	(*PccQos)(nil),                  // 17: nfconfig.v1.PccQos
+	(*PccRule)(nil),                 // 18: nfconfig.v1.PccRule
+	(*PolicyControl)(nil),           // 19: nfconfig.v1.PolicyControl
+	(*PolicyControlConfig)(nil),     // 20: nfconfig.v1.PolicyControlConfig
+	(*ImsiRange)(nil),               // 21: nfconfig.v1.ImsiRange
+	(*ImsiQos)(nil),                 // 22: nfconfig.v1.ImsiQos
+	(*ImsiQosEntry)(nil),            // 23: nfconfig.v1.ImsiQosEntry
+	(*ImsiQosConfig)(nil),           // 24: nfconfig.v1.ImsiQosConfig
+}
+var file_nfconfig_proto_depIdxs = []int32{
+	0,  // 0: nfconfig.v1.SubscribeRequest.nf_type:type_name -> nfconfig.v1.NfType
+	1,  // 1: nfconfig.v1.SubscribeRequest.config_types:type_name -> nfconfig.v1.ConfigType
+	1,  // 2: nfconfig.v1.ConfigUpdate.config_type:type_name -> nfconfig.v1.ConfigType
+	6,  // 3: nfconfig.v1.ConfigUpdate.plmn:type_name -> nfconfig.v1.PlmnConfig
+	8,  // 4: nfconfig.v1.ConfigUpdate.plmn_snssai:type_name -> nfconfig.v1.PlmnSnssaiConfig
+	10, // 5: nfconfig.v1.ConfigUpdate.access_mobility:type_name -> nfconfig.v1.AccessMobilityConfig
+	14, // 6: nfconfig.v1.ConfigUpdate.session_management:type_name -> nfconfig.v1.SessionManagementConfig
+	20, // 7: nfconfig.v1.ConfigUpdate.policy_control:type_name -> nfconfig.v1.PolicyControlConfig
+	24, // 8: nfconfig.v1.ConfigUpdate.imsi_qos:type_name -> nfconfig.v1.ImsiQosConfig
+	4,  // 9: nfconfig.v1.PlmnConfig.plmn:type_name -> nfconfig.v1.PlmnId
+	4,  // 10: nfconfig.v1.PlmnSnssai.plmn_id:type_name -> nfconfig.v1.PlmnId
+	5,  // 11: nfconfig.v1.PlmnSnssai.s_nssai_list:type_name -> nfconfig.v1.Snssai
+	7,  // 12: nfconfig.v1.PlmnSnssaiConfig.plmn_snssai:type_name -> nfconfig.v1.PlmnSnssai
+	4,  // 13: nfconfig.v1.AccessAndMobility.plmn_id:type_name -> nfconfig.v1.PlmnId
+	5,  // 14: nfconfig.v1.AccessAndMobility.snssai:type_name -> nfconfig.v1.Snssai
+	9,  // 15: nfconfig.v1.AccessMobilityConfig.access_mobility:type_name -> nfconfig.v1.AccessAndMobility
+	4,  // 16: nfconfig.v1.SessionManagement.plmn_id:type_name -> nfconfig.v1.PlmnId
+	5,  // 17: nfconfig.v1.SessionManagement.snssai:type_name -> nfconfig.v1.Snssai
+	11, // 18: nfconfig.v1.SessionManagement.ip_domain:type_name -> nfconfig.v1.IpDomain
+	12, // 19: nfconfig.v1.SessionManagement.upf:type_name -> nfconfig.v1.Upf
+	13, // 20: nfconfig.v1.SessionManagementConfig.session_management:type_name -> nfconfig.v1.SessionManagement
+	16, // 21: nfconfig.v1.PccQos.arp:type_name -> nfconfig.v1.Arp
+	15, // 22: nfconfig.v1.PccRule.flows:type_name -> nfconfig.v1.PccFlow
+	17, // 23: nfconfig.v1.PccRule.qos:type_name -> nfconfig.v1.PccQos
+	4,  // 24: nfconfig.v1.PolicyControl.plmn_id:type_name -> nfconfig.v1.PlmnId
+	5,  // 25: nfconfig.v1.PolicyControl.snssai:type_name -> nfconfig.v1.Snssai
+	18, // 26: nfconfig.v1.PolicyControl.pcc_rules:type_name -> nfconfig.v1.PccRule
+	19, // 27: nfconfig.v1.PolicyControlConfig.policy_control:type_name -> nfconfig.v1.PolicyControl
+	21, // 28: nfconfig.v1.ImsiQosEntry.imsi_ranges:type_name -> nfconfig.v1.ImsiRange
+	22, // 29: nfconfig.v1.ImsiQosEntry.qos:type_name -> nfconfig.v1.ImsiQos
+	23, // 30: nfconfig.v1.ImsiQosConfig.imsi_qos:type_name -> nfconfig.v1.ImsiQosEntry
+	2,  // 31: nfconfig.v1.NfConfig.Subscribe:input_type -> nfconfig.v1.SubscribeRequest
+	3,  // 32: nfconfig.v1.NfConfig.Subscribe:output_type -> nfconfig.v1.ConfigUpdate
+	32, // [32:33] is the sub-list for method output_type
+	31, // [31:32] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
+}
+
+func init() { file_nfconfig_proto_init() }
+func file_nfconfig_proto_init() {
+	if File_nfconfig_proto != nil {
+		return
+	}
+	file_nfconfig_proto_msgTypes[1].OneofWrappers = []any{
+		(*ConfigUpdate_Plmn)(nil),
+		(*ConfigUpdate_PlmnSnssai)(nil),
+		(*ConfigUpdate_AccessMobility)(nil),
+		(*ConfigUpdate_SessionManagement)(nil),
+		(*ConfigUpdate_PolicyControl)(nil),
+		(*ConfigUpdate_ImsiQos)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nfconfig_proto_rawDesc), len(file_nfconfig_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   23,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_nfconfig_proto_goTypes,
+		DependencyIndexes: file_nfconfig_proto_depIdxs,
+		EnumInfos:         file_nfconfig_proto_enumTypes,
+		MessageInfos:      file_nfconfig_proto_msgTypes,
+	}.Build()
+	File_nfconfig_proto = out.File
+	file_nfconfig_proto_goTypes = nil
+	file_nfconfig_proto_depIdxs = nil
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: nfconfig.proto
+
+package nfconfigpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NfConfig_Subscribe_FullMethodName = "/nfconfig.v1.NfConfig/Subscribe"
+)
+
+// NfConfigClient is the client API for NfConfig service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NfConfig serves the configuration of the REST NFConfig API to network
+// functions over gRPC.
+type NfConfigClient interface {
+	// Subscribe streams the requested configuration types, then an update each
+	// time one of them changes.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ConfigUpdate], error)
+}
+
+type nfConfigClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNfConfigClient(cc grpc.ClientConnInterface) NfConfigClient {
+	return &nfConfigClient{cc}
+}
+
+func (c *nfConfigClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ConfigUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NfConfig_ServiceDesc.Streams[0], NfConfig_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, ConfigUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NfConfig_SubscribeClient = grpc.ServerStreamingClient[ConfigUpdate]
+
+// NfConfigServer is the server API for NfConfig service.
+// All implementations must embed UnimplementedNfConfigServer
+// for forward compatibility.
+//
+// NfConfig serves the configuration of the REST NFConfig API to network
+// functions over gRPC.
+type NfConfigServer interface {
+	// Subscribe streams the requested configuration types, then an update each
+	// time one of them changes.
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[ConfigUpdate]) error
+	mustEmbedUnimplementedNfConfigServer()
+}
+
+// UnimplementedNfConfigServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNfConfigServer struct{}
+
+func (UnimplementedNfConfigServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[ConfigUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedNfConfigServer) mustEmbedUnimplementedNfConfigServer() {}
+func (UnimplementedNfConfigServer) testEmbeddedByValue()                  {}
+
+// UnsafeNfConfigServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NfConfigServer will
+// result in compilation errors.
+type UnsafeNfConfigServer interface {
+	mustEmbedUnimplementedNfConfigServer()
+}
+
+func RegisterNfConfigServer(s grpc.ServiceRegistrar, srv NfConfigServer) {
+	// If the following call pancis, it indicates UnimplementedNfConfigServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NfConfig_ServiceDesc, srv)
+}
+
+func _NfConfig_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NfConfigServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, ConfigUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NfConfig_SubscribeServer = grpc.ServerStreamingServer[ConfigUpdate]
+
+// NfConfig_ServiceDesc is the grpc.ServiceDesc for NfConfig service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NfConfig_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nfconfig.v1.NfConfig",
+	HandlerType: (*NfConfigServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _NfConfig_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "nfconfig.proto",
+}
@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package nfconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configapi"
+	"github.com/omec-project/webconsole/configmodels"
+)
+
+// configPreviewRequest holds candidate network slices and device groups. Each
+// one replaces the current one of the same name, or is added if there is none.
+type configPreviewRequest struct {
+	Slices       []configmodels.Slice        `json:"slices"`
+	DeviceGroups []configmodels.DeviceGroups `json:"deviceGroups"`
+}
+
+// configTypePreview is the rendering of a configuration type with the
+// candidates, along with the entries it adds to or removes from the one
+// served now.
+type configTypePreview struct {
+	Changed bool              `json:"changed"`
+	Config  json.RawMessage   `json:"config"`
+	Added   []json.RawMessage `json:"added"`
+	Removed []json.RawMessage `json:"removed"`
+}
+
+// configPreview is the response of POST /nfconfig/preview. Revision is the
+// revision of the configuration the candidates are compared to.
+type configPreview struct {
+	Revision uint64                       `json:"revision"`
+	Configs  map[string]configTypePreview `json:"configs"`
+}
+
+// validate checks the candidates as the network slice and device group APIs
+// do, and fills in the same defaults, so that the builders get the
+// configuration they would read from the database.
+func (r *configPreviewRequest) validate() error {
+	if len(r.Slices) == 0 && len(r.DeviceGroups) == 0 {
+		return fmt.Errorf("at least one slice or device group is required")
+	}
+	for i := range r.Slices {
+		slice := &r.Slices[i]
+		if slice.SliceName == "" {
+			return fmt.Errorf("slice-name is required")
+		}
+		if err := configapi.ValidateSlice(slice); err != nil {
+			return fmt.Errorf("slice %s: %w", slice.SliceName, err)
+		}
+	}
+	for i := range r.DeviceGroups {
+		deviceGroup := &r.DeviceGroups[i]
+		if deviceGroup.DeviceGroupName == "" {
+			return fmt.Errorf("group-name is required")
+		}
+		if err := configapi.ValidateDeviceGroup(deviceGroup); err != nil {
+			return fmt.Errorf("device group %s: %w", deviceGroup.DeviceGroupName, err)
+		}
+	}
+	return nil
+}
+
+// withCandidates builds the configuration the snapshot would become with the
+// candidates, without publishing it.
+func (c *inMemoryConfig) withCandidates(r configPreviewRequest) *inMemoryConfig {
+	networkSlices := slices.Clone(c.networkSlices)
+	for _, candidate := range r.Slices {
+		i := slices.IndexFunc(networkSlices, func(s configmodels.Slice) bool { return s.SliceName == candidate.SliceName })
+		if i < 0 {
+			networkSlices = append(networkSlices, candidate)
+		} else {
+			networkSlices[i] = candidate
+		}
+	}
+	deviceGroups := maps.Clone(c.deviceGroups)
+	if deviceGroups == nil {
+		deviceGroups = map[string]configmodels.DeviceGroups{}
+	}
+	for _, candidate := range r.DeviceGroups {
+		deviceGroups[candidate.DeviceGroupName] = candidate
+	}
+	return newInMemoryConfig(networkSlices, deviceGroups)
+}
+
+// diffEntries returns the entries of the candidate list missing from the
+// current one, and those of the current list missing from the candidate.
+func diffEntries(current, candidate []byte) ([]json.RawMessage, []json.RawMessage, error) {
+	var currentEntries, candidateEntries []json.RawMessage
+	if err := json.Unmarshal(current, &currentEntries); err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(candidate, &candidateEntries); err != nil {
+		return nil, nil, err
+	}
+	missingFrom := func(entries, other []json.RawMessage) []json.RawMessage {
+		seen := map[string]bool{}
+		for _, entry := range other {
+			seen[string(entry)] = true
+		}
+		missing := []json.RawMessage{}
+		for _, entry := range entries {
+			if !seen[string(entry)] {
+				missing = append(missing, entry)
+			}
+		}
+		return missing
+	}
+	return missingFrom(candidateEntries, currentEntries), missingFrom(currentEntries, candidateEntries), nil
+}
+
+func renderPreview(current, candidate *inMemoryConfig) (configPreview, error) {
+	preview := configPreview{
+		Revision: current.revision,
+		Configs:  map[string]configTypePreview{},
+	}
+	currentConfigs := current.configs()
+	for configType, config := range candidate.configs() {
+		candidatePayload, err := json.Marshal(config)
+		if err != nil {
+			return configPreview{}, fmt.Errorf("failed to marshal %s configuration: %w", configType, err)
+		}
+		currentPayload, err := json.Marshal(currentConfigs[configType])
+		if err != nil {
+			return configPreview{}, fmt.Errorf("failed to marshal %s configuration: %w", configType, err)
+		}
+		if string(currentPayload) == "null" {
+			currentPayload = []byte("[]")
+		}
+		added, removed, err := diffEntries(currentPayload, candidatePayload)
+		if err != nil {
+			return configPreview{}, fmt.Errorf("failed to compare %s configuration: %w", configType, err)
+		}
+		preview.Configs[configType] = configTypePreview{
+			Changed: string(currentPayload) != string(candidatePayload),
+			Config:  candidatePayload,
+			Added:   added,
+			Removed: removed,
+		}
+	}
+	return preview, nil
+}
+
+// PreviewConfig renders the configuration the network functions would receive
+// with candidate network slices and device groups, and compares it to the one
+// served now. Nothing is persisted or published. The filters of the GET
+// endpoints apply to both configurations.
+func (n *NFConfigServer) PreviewConfig(c *gin.Context) {
+	var request configPreviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.NfConfigLog.Warnf("Invalid NF configuration preview request: %+v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid preview request body"})
+		return
+	}
+	if err := request.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := parseConfigFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	logger.NfConfigLog.Infof("Previewing NF configuration with %d slices and %d device groups", len(request.Slices), len(request.DeviceGroups))
+	current := n.snapshot()
+	candidate := current.withCandidates(request)
+	preview, err := renderPreview(current.filtered(filter), candidate.filtered(filter))
+	if err != nil {
+		logger.NfConfigLog.Errorf("Failed to render NF configuration preview: %+v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render configuration"})
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
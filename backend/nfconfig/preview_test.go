@@ -0,0 +1,231 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+package nfconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/openapi/nfConfigApi"
+	"github.com/omec-project/webconsole/configmodels"
+)
+
+var previewTestQos = &configmodels.DeviceGroupsIpDomainExpandedUeDnnQos{
+	DnnMbrUplink:   1000000,
+	DnnMbrDownlink: 2000000,
+	TrafficClass:   &configmodels.TrafficClassInfo{Qci: 9, Arp: 1},
+}
+
+// makePreviewSlice builds a network slice passing the network slice API
+// validation.
+func makePreviewSlice(mnc, sst, sd string, tacs []int32) configmodels.Slice {
+	slice := makeNetworkSlice("001", mnc, sst, sd, tacs)
+	slice.SiteDeviceGroup = []string{"dg1"}
+	slice.SiteInfo.Upf = map[string]any{"upf-name": "upf", "upf-port": "8805"}
+	return slice
+}
+
+// makePreviewDeviceGroup builds a device group passing the device group API
+// validation.
+func makePreviewDeviceGroup(name, dnsPrimary string) configmodels.DeviceGroups {
+	_, deviceGroup := makeDeviceGroup(deviceGroupParams{
+		imsis:      []string{"001010000000001"},
+		dnn:        "internet",
+		dnsPrimary: dnsPrimary,
+		ueIpPool:   "10.0.0.0/16",
+		mtu:        1400,
+		qos:        previewTestQos,
+	})
+	deviceGroup.DeviceGroupName = name
+	deviceGroup.SiteInfo = "test"
+	deviceGroup.IpDomainName = "pool1"
+	return deviceGroup
+}
+
+func newPreviewTestServer() *NFConfigServer {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(enforceAcceptJSON())
+	n := &NFConfigServer{Router: router}
+	n.setupRoutes()
+	name, deviceGroup := makeDeviceGroup(deviceGroupParams{
+		name:       "dg1",
+		imsis:      []string{"001010000000001"},
+		dnn:        "internet",
+		dnsPrimary: "8.8.8.8",
+		ueIpPool:   "10.0.0.0/16",
+		mtu:        1400,
+		qos:        previewTestQos,
+	})
+	slice := makeNetworkSlice("001", "01", "1", "010203", []int32{1})
+	slice.SiteDeviceGroup = []string{name}
+	n.publish(newInMemoryConfig([]configmodels.Slice{slice}, map[string]configmodels.DeviceGroups{name: deviceGroup}))
+	return n
+}
+
+func postPreview(t *testing.T, n *NFConfigServer, query string, body any) (int, configPreview) {
+	t.Helper()
+	payload, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/nfconfig/preview"+query, bytes.NewReader(payload))
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	n.Router.ServeHTTP(w, req)
+	var preview configPreview
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &preview); err != nil {
+			t.Fatalf("failed to unmarshal preview: %v", err)
+		}
+	}
+	return w.Code, preview
+}
+
+func rawEntries(t *testing.T, values ...any) []json.RawMessage {
+	t.Helper()
+	entries := []json.RawMessage{}
+	for _, value := range values {
+		entry, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("failed to marshal entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestPreviewConfig(t *testing.T) {
+	n := newPreviewTestServer()
+	before := n.snapshot()
+
+	updated := makePreviewSlice("01", "1", "010203", []int32{1, 2})
+	added := makePreviewSlice("02", "2", "040506", []int32{3})
+	deviceGroup := makePreviewDeviceGroup("dg1", "1.1.1.1")
+	code, preview := postPreview(t, n, "", configPreviewRequest{
+		Slices:       []configmodels.Slice{updated, added},
+		DeviceGroups: []configmodels.DeviceGroups{deviceGroup},
+	})
+	if code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, code)
+	}
+	if preview.Revision != 1 {
+		t.Errorf("expected the preview to compare to revision 1, got %d", preview.Revision)
+	}
+
+	plmn := preview.Configs[plmnConfig]
+	if !plmn.Changed || !reflect.DeepEqual(plmn.Added, rawEntries(t, nfConfigApi.NewPlmnId("001", "02"))) || len(plmn.Removed) != 0 {
+		t.Errorf("expected PLMN 001-02 to be added, got %+v", plmn)
+	}
+	accessMobility := preview.Configs[accessMobilityConfig]
+	expectedAdded := rawEntries(t,
+		nfConfigApi.AccessAndMobility{PlmnId: *nfConfigApi.NewPlmnId("001", "01"), Snssai: makeSnssaiWithSd(1, "010203"), Tacs: []string{"1", "2"}},
+		nfConfigApi.AccessAndMobility{PlmnId: *nfConfigApi.NewPlmnId("001", "02"), Snssai: makeSnssaiWithSd(2, "040506"), Tacs: []string{"3"}},
+	)
+	expectedRemoved := rawEntries(t,
+		nfConfigApi.AccessAndMobility{PlmnId: *nfConfigApi.NewPlmnId("001", "01"), Snssai: makeSnssaiWithSd(1, "010203"), Tacs: []string{"1"}},
+	)
+	if !reflect.DeepEqual(accessMobility.Added, expectedAdded) || !reflect.DeepEqual(accessMobility.Removed, expectedRemoved) {
+		t.Errorf("expected access mobility diff +%s -%s, got +%s -%s", expectedAdded, expectedRemoved, accessMobility.Added, accessMobility.Removed)
+	}
+	var sessionManagement []nfConfigApi.SessionManagement
+	if err := json.Unmarshal(preview.Configs[sessionManagementConfig].Config, &sessionManagement); err != nil {
+		t.Fatalf("failed to unmarshal session management: %v", err)
+	}
+	if len(sessionManagement) == 0 || len(sessionManagement[0].IpDomain) != 1 || sessionManagement[0].IpDomain[0].DnsIpv4 != "1.1.1.1" {
+		t.Errorf("expected the candidate device group in the session management, got %+v", sessionManagement)
+	}
+	if qos := preview.Configs[imsiQosConfigType]; qos.Changed || len(qos.Added) != 0 || len(qos.Removed) != 0 {
+		t.Errorf("expected IMSI QoS to be unchanged, got %+v", qos)
+	}
+
+	if after := n.snapshot(); after != before {
+		t.Errorf("expected the preview not to publish a configuration")
+	}
+}
+
+func TestPreviewConfig_Filtered(t *testing.T) {
+	n := newPreviewTestServer()
+	code, preview := postPreview(t, n, "?plmn=001-01", configPreviewRequest{
+		Slices: []configmodels.Slice{makePreviewSlice("02", "2", "040506", []int32{3})},
+	})
+	if code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, code)
+	}
+	for configType, config := range preview.Configs {
+		if config.Changed {
+			t.Errorf("expected %s to be unchanged for PLMN 001-01, got %+v", configType, config)
+		}
+	}
+}
+
+func TestPreviewConfig_InvalidRequest(t *testing.T) {
+	n := newPreviewTestServer()
+	testCases := []struct {
+		name string
+		body any
+	}{
+		{"no candidates", configPreviewRequest{}},
+		{"slice without name", configPreviewRequest{Slices: []configmodels.Slice{{}}}},
+		{"device group without name", configPreviewRequest{DeviceGroups: []configmodels.DeviceGroups{{}}}},
+		{"device group without QoS", configPreviewRequest{DeviceGroups: []configmodels.DeviceGroups{{
+			DeviceGroupName:  "dg2",
+			IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{Dnn: "internet"},
+		}}}},
+		{"invalid IMSI range", configPreviewRequest{DeviceGroups: []configmodels.DeviceGroups{{
+			DeviceGroupName: "dg2",
+			ImsiRanges:      []configmodels.DeviceGroupsImsiRange{{Start: "2", End: "1"}},
+		}}}},
+		{"slice without SD", configPreviewRequest{Slices: []configmodels.Slice{makePreviewSlice("02", "2", "", []int32{3})}}},
+		{"rule without traffic class", configPreviewRequest{Slices: []configmodels.Slice{func() configmodels.Slice {
+			slice := makePreviewSlice("02", "2", "040506", []int32{3})
+			slice.ApplicationFilteringRules = []configmodels.SliceApplicationFilteringRules{{
+				RuleName: "rule1", Action: "permit", Endpoint: "any", EndPort: 80, BitrateUnit: "bps",
+			}}
+			return slice
+		}()}}},
+		{"device group with invalid name", configPreviewRequest{DeviceGroups: []configmodels.DeviceGroups{makePreviewDeviceGroup("dg 2", "1.1.1.1")}}},
+		{"invalid body", []string{"slice"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if code, _ := postPreview(t, n, "", tc.body); code != http.StatusBadRequest {
+				t.Errorf("expected %d, got %d", http.StatusBadRequest, code)
+			}
+		})
+	}
+}
+
+func TestPreviewConfig_CandidateDefaults(t *testing.T) {
+	n := newPreviewTestServer()
+	deviceGroup := makePreviewDeviceGroup("dg1", "8.8.8.8")
+	deviceGroup.IpDomainExpanded.UeDnnQos = &configmodels.DeviceGroupsIpDomainExpandedUeDnnQos{
+		DnnMbrUplink:   5,
+		DnnMbrDownlink: 10,
+		BitrateUnit:    "Mbps",
+	}
+	code, preview := postPreview(t, n, "", configPreviewRequest{
+		DeviceGroups: []configmodels.DeviceGroups{deviceGroup},
+	})
+	if code != http.StatusOK {
+		t.Fatalf("expected %d for a device group without traffic class, got %d", http.StatusOK, code)
+	}
+	var sessionManagement []nfConfigApi.SessionManagement
+	if err := json.Unmarshal(preview.Configs[sessionManagementConfig].Config, &sessionManagement); err != nil {
+		t.Fatalf("failed to unmarshal session management: %v", err)
+	}
+	if len(sessionManagement) == 0 || len(sessionManagement[0].IpDomain) != 1 {
+		t.Fatalf("expected the candidate device group in the session management, got %+v", sessionManagement)
+	}
+	if qos := preview.Configs[imsiQosConfigType]; !qos.Changed || !strings.Contains(string(qos.Config), `"5 Mbps"`) {
+		t.Errorf("expected the IMSI QoS of the candidate in bps with the default traffic class, got %+v", qos)
+	}
+}
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package nfconfig
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/omec-project/openapi/nfConfigApi"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+)
+
+type imsiQosKey struct {
+	dnn  string
+	imsi string
+}
+
+// imsiRangeKey groups the ranges of a DNN by IMSI length, as only IMSIs of
+// the same length as the bounds of a range belong to it.
+type imsiRangeKey struct {
+	dnn    string
+	length int
+}
+
+// imsiQosMatch is the QoS of an IMSI along with the position of its entry in
+// the snapshot. When several entries hold the IMSI, the first one wins.
+type imsiQosMatch struct {
+	priority int
+	qos      []nfConfigApi.ImsiQos
+}
+
+type imsiQosSegment struct {
+	start uint64
+	end   uint64
+	imsiQosMatch
+}
+
+// imsiQosIndex resolves the QoS of a (DNN, IMSI) pair without scanning the
+// IMSIs of every device group. Enumerated IMSIs are kept in a map and IMSI
+// ranges, declared with start/end or prefix/count, are flattened into sorted,
+// disjoint segments searched in logarithmic time.
+type imsiQosIndex struct {
+	imsis  map[imsiQosKey]imsiQosMatch
+	ranges map[imsiRangeKey][]imsiQosSegment
+}
+
+func newImsiQosIndex(imsiQosConfigs []imsiQosConfig) *imsiQosIndex {
+	index := &imsiQosIndex{
+		imsis:  map[imsiQosKey]imsiQosMatch{},
+		ranges: map[imsiRangeKey][]imsiQosSegment{},
+	}
+	rangesByKey := map[imsiRangeKey][]imsiQosSegment{}
+	for priority, config := range imsiQosConfigs {
+		match := imsiQosMatch{priority: priority, qos: config.qos}
+		for _, imsi := range config.imsis {
+			key := imsiQosKey{dnn: config.dnn, imsi: imsi}
+			if _, ok := index.imsis[key]; !ok {
+				index.imsis[key] = match
+			}
+		}
+		for _, imsiRange := range config.imsiRanges {
+			start, end, err := imsiRange.Bounds()
+			if err != nil {
+				logger.NfConfigLog.Warnf("Ignoring IMSI range %+v of DNN %s in QoS index: %+v", imsiRange, config.dnn, err)
+				continue
+			}
+			first, _ := strconv.ParseUint(start, 10, 64)
+			last, _ := strconv.ParseUint(end, 10, 64)
+			key := imsiRangeKey{dnn: config.dnn, length: len(start)}
+			rangesByKey[key] = append(rangesByKey[key], imsiQosSegment{start: first, end: last, imsiQosMatch: match})
+		}
+	}
+	for key, ranges := range rangesByKey {
+		index.ranges[key] = disjointSegments(ranges)
+	}
+	return index
+}
+
+// disjointSegments splits possibly overlapping ranges, given by increasing
+// priority, into sorted segments each owned by the first range covering it.
+func disjointSegments(ranges []imsiQosSegment) []imsiQosSegment {
+	bounds := make([]uint64, 0, 2*len(ranges))
+	for _, r := range ranges {
+		bounds = append(bounds, r.start, r.end+1)
+	}
+	slices.Sort(bounds)
+	bounds = slices.Compact(bounds)
+
+	// elementary segment i spans [bounds[i], bounds[i+1]). next skips the
+	// segments already owned by a range of higher priority.
+	owners := make([]*imsiQosSegment, len(bounds)-1)
+	next := make([]int, len(bounds))
+	for i := range next {
+		next[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if next[i] != i {
+			next[i] = find(next[i])
+		}
+		return next[i]
+	}
+	for i := range ranges {
+		first, _ := slices.BinarySearch(bounds, ranges[i].start)
+		last, _ := slices.BinarySearch(bounds, ranges[i].end+1)
+		for j := find(first); j < last; j = find(j) {
+			owners[j] = &ranges[i]
+			next[j] = j + 1
+		}
+	}
+
+	segments := []imsiQosSegment{}
+	for i, owner := range owners {
+		if owner == nil {
+			continue
+		}
+		if n := len(segments); n > 0 && segments[n-1].priority == owner.priority && segments[n-1].end+1 == bounds[i] {
+			segments[n-1].end = bounds[i+1] - 1
+			continue
+		}
+		segments = append(segments, imsiQosSegment{start: bounds[i], end: bounds[i+1] - 1, imsiQosMatch: owner.imsiQosMatch})
+	}
+	return segments
+}
+
+// lookup returns the QoS of the IMSI (without the `imsi-` prefix) on the DNN.
+func (x *imsiQosIndex) lookup(dnn, imsi string) ([]nfConfigApi.ImsiQos, bool) {
+	if x == nil {
+		return nil, false
+	}
+	match, found := x.imsis[imsiQosKey{dnn: dnn, imsi: imsi}]
+	if configmodels.IsValidImsi(imsi) {
+		segments := x.ranges[imsiRangeKey{dnn: dnn, length: len(imsi)}]
+		value, _ := strconv.ParseUint(imsi, 10, 64)
+		i := sort.Search(len(segments), func(i int) bool { return segments[i].end >= value })
+		if i < len(segments) && segments[i].start <= value && (!found || segments[i].priority < match.priority) {
+			match, found = segments[i].imsiQosMatch, true
+		}
+	}
+	return match.qos, found
+}
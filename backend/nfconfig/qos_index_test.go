@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+package nfconfig
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/openapi/nfConfigApi"
+	"github.com/omec-project/webconsole/configmodels"
+)
+
+func qosWithFiveQi(fiveQi int32) []nfConfigApi.ImsiQos {
+	return []nfConfigApi.ImsiQos{*nfConfigApi.NewImsiQos("1 Mbps", "1 Mbps", fiveQi, 1)}
+}
+
+func TestImsiQosIndex_Lookup(t *testing.T) {
+	index := newImsiQosIndex([]imsiQosConfig{
+		{
+			dnn:        "internet",
+			imsis:      []string{"001010000000001"},
+			imsiRanges: []configmodels.DeviceGroupsImsiRange{{Start: "001010000000100", End: "001010000000199"}},
+			qos:        qosWithFiveQi(1),
+		},
+		{
+			dnn:        "internet",
+			imsiRanges: []configmodels.DeviceGroupsImsiRange{{Prefix: "001010000000", Count: 300}},
+			qos:        qosWithFiveQi(2),
+		},
+		{
+			dnn:        "internet",
+			imsis:      []string{"001010000000150", "001010000000300"},
+			imsiRanges: []configmodels.DeviceGroupsImsiRange{{Start: "00101001", End: "00101009"}},
+			qos:        qosWithFiveQi(3),
+		},
+		{
+			dnn:        "ims",
+			imsiRanges: []configmodels.DeviceGroupsImsiRange{{Prefix: "00101", Count: 1000000}},
+			qos:        qosWithFiveQi(4),
+		},
+		{
+			dnn:        "internet",
+			imsiRanges: []configmodels.DeviceGroupsImsiRange{{Start: "9", End: "1"}},
+			qos:        qosWithFiveQi(5),
+		},
+	})
+
+	testCases := []struct {
+		name           string
+		dnn            string
+		imsi           string
+		expectedFiveQi int32
+	}{
+		{"enumerated IMSI", "internet", "001010000000001", 1},
+		{"start/end range", "internet", "001010000000100", 1},
+		{"first range wins over overlapping one", "internet", "001010000000199", 1},
+		{"prefix range past the overlap", "internet", "001010000000250", 2},
+		{"first range wins over later enumerated IMSI", "internet", "001010000000150", 1},
+		{"enumerated IMSI outside earlier ranges", "internet", "001010000000300", 3},
+		{"shorter IMSI range", "internet", "00101005", 3},
+		{"IMSI of another length", "internet", "0010100005", 0},
+		{"other DNN", "ims", "001010000123456", 4},
+		{"IMSI outside the ranges of the DNN", "ims", "001020000000001", 0},
+		{"unknown IMSI", "internet", "001019999999999", 0},
+		{"invalid IMSI", "internet", "abc", 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			qos, found := index.lookup(tc.dnn, tc.imsi)
+			if tc.expectedFiveQi == 0 {
+				if found {
+					t.Errorf("expected no QoS, got %+v", qos)
+				}
+				return
+			}
+			if !found || len(qos) != 1 || qos[0].FiveQi != tc.expectedFiveQi {
+				t.Errorf("expected 5QI %d, got %+v (found: %v)", tc.expectedFiveQi, qos, found)
+			}
+		})
+	}
+}
+
+func TestImsiQosIndex_MatchesScan(t *testing.T) {
+	configs := []imsiQosConfig{}
+	for i := range 20 {
+		configs = append(configs, imsiQosConfig{
+			dnn:        "internet",
+			imsis:      []string{configmodels.FormatImsi(uint64(1000+i*37), 15)},
+			imsiRanges: []configmodels.DeviceGroupsImsiRange{{Start: configmodels.FormatImsi(uint64(i*50), 15), End: configmodels.FormatImsi(uint64(i*50+120), 15)}},
+			qos:        qosWithFiveQi(int32(i + 1)),
+		})
+	}
+	index := newImsiQosIndex(configs)
+	for value := range uint64(1200) {
+		imsi := configmodels.FormatImsi(value, 15)
+		var expected []nfConfigApi.ImsiQos
+		for _, config := range configs {
+			if (&configmodels.DeviceGroups{Imsis: config.imsis, ImsiRanges: config.imsiRanges}).ContainsImsi(imsi) {
+				expected = config.qos
+				break
+			}
+		}
+		qos, _ := index.lookup("internet", imsi)
+		if len(qos) != len(expected) || (len(qos) > 0 && qos[0].FiveQi != expected[0].FiveQi) {
+			t.Fatalf("IMSI %s: expected %+v, got %+v", imsi, expected, qos)
+		}
+	}
+}
+
+func TestPostImsiQosBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	configs := []imsiQosConfig{
+		{
+			dnn:        "internet",
+			imsiRanges: []configmodels.DeviceGroupsImsiRange{{Prefix: "00101", Count: 100}},
+			qos:        qosWithFiveQi(9),
+		},
+	}
+	n := &NFConfigServer{Router: gin.New()}
+	n.setupRoutes()
+	n.publish(&inMemoryConfig{imsiQos: configs, imsiQosIndex: newImsiQosIndex(configs)})
+
+	testCases := []struct {
+		name         string
+		path         string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "lookups in order",
+			path:         "/nfconfig/qos:batch",
+			body:         `[{"dnn":"internet","imsi":"imsi-001010000000042"},{"dnn":"internet","imsi":"001019999999999"}]`,
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"dnn":"internet","imsi":"imsi-001010000000042","qos":[{"mbrUplink":"1 Mbps","mbrDownlink":"1 Mbps","fiveQi":9,"arpPriorityLevel":1}]},` +
+				`{"dnn":"internet","imsi":"001019999999999","qos":[]}]`,
+		},
+		{
+			name:         "empty batch",
+			path:         "/nfconfig/qos:batch",
+			body:         `[]`,
+			expectedCode: http.StatusOK,
+			expectedBody: `[]`,
+		},
+		{
+			name:         "invalid body",
+			path:         "/nfconfig/qos:batch",
+			body:         `{"dnn":"internet"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "too many lookups",
+			path:         "/nfconfig/qos:batch",
+			body:         "[" + strings.Repeat(`{"dnn":"internet","imsi":"001010000000001"},`, maxQosBatchSize) + `{"dnn":"internet","imsi":"001010000000001"}]`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown action",
+			path:         "/nfconfig/qos:resolve",
+			body:         `[]`,
+			expectedCode: http.StatusNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			n.Router.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedCode {
+				t.Fatalf("expected %d, got %d (body: %s)", tc.expectedCode, w.Code, w.Body.String())
+			}
+			if tc.expectedBody != "" && w.Body.String() != tc.expectedBody {
+				t.Errorf("expected body %s, got %s", tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package nfconfig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/logger"
+)
+
+type configTypeRevision struct {
+	Revision uint64 `json:"revision"`
+	Hash     string `json:"hash"`
+}
+
+// configRevisions is the response of GET /nfconfig/revision: the revision and
+// hash of the last sync that changed the configuration, and those of each
+// configuration type.
+type configRevisions struct {
+	Revision uint64                        `json:"revision"`
+	Hash     string                        `json:"hash"`
+	Configs  map[string]configTypeRevision `json:"configs"`
+}
+
+func contentHash(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// snapshotHash combines the hashes of all the configuration types.
+func snapshotHash(configs map[string]configRevision) string {
+	h := sha256.New()
+	for _, configType := range slices.Sorted(maps.Keys(configs)) {
+		h.Write([]byte(configType + ":" + configs[configType].hash + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *inMemoryConfig) configs() map[string]any {
+	return map[string]any{
+		accessMobilityConfig:    c.accessAndMobility,
+		plmnConfig:              c.plmn,
+		plmnSnssaiConfig:        c.plmnSnssai,
+		policyControlConfig:     c.policyControl,
+		sessionManagementConfig: c.sessionManagement,
+		imsiQosConfigType:       c.imsiQos,
+	}
+}
+
+// setRevisions renders the configuration types of a new snapshot and gives it
+// the revision following the previous one if any of them changed. Unchanged
+// types keep the revision of the sync that last changed them. It reports
+// whether the configuration changed.
+func (c *inMemoryConfig) setRevisions(previous *inMemoryConfig) bool {
+	c.revision = previous.revision
+	c.hash = previous.hash
+	c.revisions = make(map[string]configRevision, len(previous.revisions))
+	var updated []string
+	for configType, config := range c.configs() {
+		payload, err := json.Marshal(config)
+		if err != nil {
+			logger.NfConfigLog.Errorf("Failed to marshal %s configuration: %+v", configType, err)
+			payload = nil
+		}
+		if current, ok := previous.revisions[configType]; ok && bytes.Equal(current.payload, payload) {
+			c.revisions[configType] = current
+			continue
+		}
+		c.revisions[configType] = configRevision{hash: contentHash(payload), payload: payload}
+		updated = append(updated, configType)
+	}
+	if len(updated) == 0 {
+		return false
+	}
+	c.revision++
+	for _, configType := range updated {
+		config := c.revisions[configType]
+		config.revision = c.revision
+		c.revisions[configType] = config
+	}
+	c.hash = snapshotHash(c.revisions)
+	slices.Sort(updated)
+	logger.NfConfigLog.Infof("NF configuration revision %d changed %v", c.revision, updated)
+	return true
+}
+
+func (c *inMemoryConfig) revisionSummary() configRevisions {
+	revisions := configRevisions{
+		Revision: c.revision,
+		Hash:     c.hash,
+		Configs:  make(map[string]configTypeRevision, len(c.revisions)),
+	}
+	for configType, config := range c.revisions {
+		revisions.Configs[configType] = configTypeRevision{Revision: config.revision, Hash: config.hash}
+	}
+	return revisions
+}
+
+func setRevisionHeaders(c *gin.Context, config configRevision) {
+	c.Header("ETag", strconv.Quote(config.hash))
+	c.Header(revisionHeader, strconv.FormatUint(config.revision, 10))
+}
+
+// etagMatches reports whether an If-None-Match header matches the entity tag,
+// using the weak comparison required for GET requests.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// respondWithConfig writes the configuration with its ETag and revision, or
+// 304 Not Modified if the client already has it.
+func respondWithConfig(c *gin.Context, snapshot *inMemoryConfig, configType string, config any) {
+	payload, err := json.Marshal(config)
+	if err != nil {
+		logger.NfConfigLog.Errorf("Failed to marshal %s configuration: %+v", configType, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render configuration"})
+		return
+	}
+	setRevisionHeaders(c, configRevision{revision: snapshot.revisions[configType].revision, hash: contentHash(payload)})
+	if etagMatches(c.GetHeader("If-None-Match"), c.Writer.Header().Get("ETag")) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", payload)
+}
+
+func (n *NFConfigServer) GetConfigRevision(c *gin.Context) {
+	revisions := n.snapshot().revisionSummary()
+	logger.NfConfigLog.Debugf("Handling GET request for config revision %d", revisions.Revision)
+	c.JSON(http.StatusOK, revisions)
+}
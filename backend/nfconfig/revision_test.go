@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+package nfconfig
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/omec-project/openapi/nfConfigApi"
+)
+
+func TestEtagMatches(t *testing.T) {
+	testCases := []struct {
+		ifNoneMatch string
+		expected    bool
+	}{
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", "abc"`, true},
+		{`*`, true},
+		{`"xyz"`, false},
+		{`abc`, false},
+		{``, false},
+	}
+	for _, tc := range testCases {
+		if matches := etagMatches(tc.ifNoneMatch, `"abc"`); matches != tc.expected {
+			t.Errorf("If-None-Match %q: expected %v, got %v", tc.ifNoneMatch, tc.expected, matches)
+		}
+	}
+}
+
+func TestGetConfig_ConditionalGet(t *testing.T) {
+	n := newWatchTestServer([]nfConfigApi.PlmnId{{Mcc: "001", Mnc: "01"}})
+	imsiQos := []imsiQosConfig{
+		{dnn: "internet", imsis: []string{"001010000000001"}, qos: []nfConfigApi.ImsiQos{*nfConfigApi.NewImsiQos("1 Mbps", "2 Mbps", 9, 1)}},
+	}
+	n.publish(&inMemoryConfig{
+		plmn:         []nfConfigApi.PlmnId{{Mcc: "001", Mnc: "01"}},
+		imsiQos:      imsiQos,
+		imsiQosIndex: newImsiQosIndex(imsiQos),
+	})
+
+	for _, path := range []string{"/nfconfig/plmn", "/nfconfig/qos/internet/imsi-001010000000001"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Accept", "application/json")
+			w := httptest.NewRecorder()
+			n.Router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected %d, got %d", http.StatusOK, w.Code)
+			}
+			etag := w.Header().Get("ETag")
+			if etag != `"`+contentHash(w.Body.Bytes())+`"` {
+				t.Errorf("expected ETag to be the hash of the body, got %s", etag)
+			}
+			revision := w.Header().Get(revisionHeader)
+			if revision == "" {
+				t.Errorf("expected %s header", revisionHeader)
+			}
+
+			req = httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Accept", "application/json")
+			req.Header.Set("If-None-Match", etag)
+			w = httptest.NewRecorder()
+			n.Router.ServeHTTP(w, req)
+			if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+				t.Errorf("expected %d with an empty body, got %d (body: %s)", http.StatusNotModified, w.Code, w.Body.String())
+			}
+			if w.Header().Get("ETag") != etag || w.Header().Get(revisionHeader) != revision {
+				t.Errorf("expected 304 to repeat ETag %s and revision %s", etag, revision)
+			}
+
+			req = httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Accept", "application/json")
+			req.Header.Set("If-None-Match", `"stale"`)
+			w = httptest.NewRecorder()
+			n.Router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Errorf("expected %d for a stale ETag, got %d", http.StatusOK, w.Code)
+			}
+		})
+	}
+}
+
+func TestGetConfigRevision(t *testing.T) {
+	n := newWatchTestServer([]nfConfigApi.PlmnId{{Mcc: "001", Mnc: "01"}})
+	getRevisions := func() configRevisions {
+		req := httptest.NewRequest(http.MethodGet, "/nfconfig/revision", nil)
+		req.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		n.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, w.Code)
+		}
+		var revisions configRevisions
+		if err := json.Unmarshal(w.Body.Bytes(), &revisions); err != nil {
+			t.Fatalf("failed to unmarshal body: %v", err)
+		}
+		return revisions
+	}
+
+	initial := getRevisions()
+	expectedTypes := []string{accessMobilityConfig, plmnConfig, plmnSnssaiConfig, policyControlConfig, imsiQosConfigType, sessionManagementConfig}
+	if types := slices.Sorted(maps.Keys(initial.Configs)); !slices.Equal(types, slices.Sorted(slices.Values(expectedTypes))) {
+		t.Fatalf("expected config types %v, got %v", expectedTypes, types)
+	}
+	if initial.Revision != 1 || initial.Hash == "" || initial.Configs[plmnConfig].Revision != 1 {
+		t.Fatalf("unexpected initial revisions: %+v", initial)
+	}
+
+	n.publish(&inMemoryConfig{plmn: []nfConfigApi.PlmnId{{Mcc: "001", Mnc: "02"}}})
+	updated := getRevisions()
+	if updated.Revision != 2 || updated.Hash == initial.Hash {
+		t.Errorf("expected a new revision and hash, got %+v", updated)
+	}
+	if updated.Configs[plmnConfig].Revision != 2 || updated.Configs[plmnConfig].Hash == initial.Configs[plmnConfig].Hash {
+		t.Errorf("expected plmn to change at revision 2, got %+v", updated.Configs[plmnConfig])
+	}
+	if updated.Configs[policyControlConfig] != initial.Configs[policyControlConfig] {
+		t.Errorf("expected policy control to be unchanged, got %+v", updated.Configs[policyControlConfig])
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -25,8 +27,17 @@ import (
 type NFConfigServer struct {
 	config         *factory.Configuration
 	Router         *gin.Engine
-	inMemoryConfig inMemoryConfig
+	inMemoryConfig atomic.Pointer[inMemoryConfig]
 	syncMutex      sync.Mutex
+	publishMutex   sync.Mutex
+	watcher        configWatcher
+	syncState      syncState
+	// bootRevision is the revision before the first sync: the start time in
+	// Unix microseconds, so that revisions keep increasing across restarts
+	bootRevision uint64
+	// waitForDatabase holds the syncs of a server started from the cache
+	// until the first sync trigger, sent once the database is initialized
+	waitForDatabase bool
 }
 
 const (
@@ -35,6 +46,7 @@ const (
 )
 
 type Route struct {
+	Method      string
 	Pattern     string
 	HandlerFunc gin.HandlerFunc
 }
@@ -48,6 +60,37 @@ func (n *NFConfigServer) router() *gin.Engine {
 }
 
 func NewNFConfigServer(config *factory.Config) (NFConfigInterface, error) {
+	nfconfigServer, err := newServer(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = nfconfigServer.syncInMemoryConfig(); err != nil {
+		if cacheErr := nfconfigServer.loadSnapshot(); cacheErr != nil {
+			return nil, fmt.Errorf("failed to sync NF configuration data: %w (%w)", err, cacheErr)
+		}
+		logger.NfConfigLog.Warnf("Failed to sync NF configuration data, starting from cache: %+v", err)
+	}
+	return nfconfigServer, nil
+}
+
+// NewCachedNFConfigServer builds the server from the cache alone, for a start
+// with the database unreachable. It does not access the database until the
+// first trigger on the sync channel, which is to be sent once the database is
+// initialized.
+func NewCachedNFConfigServer(config *factory.Config) (NFConfigInterface, error) {
+	nfconfigServer, err := newServer(config)
+	if err != nil {
+		return nil, err
+	}
+	if err = nfconfigServer.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	nfconfigServer.waitForDatabase = true
+	return nfconfigServer, nil
+}
+
+func newServer(config *factory.Config) (*NFConfigServer, error) {
 	if config == nil {
 		return nil, fmt.Errorf("configuration cannot be nil")
 	}
@@ -60,12 +103,9 @@ func NewNFConfigServer(config *factory.Config) (NFConfigInterface, error) {
 	router.Use(enforceAcceptJSON())
 
 	nfconfigServer := &NFConfigServer{
-		config: config.Configuration,
-		Router: router,
-	}
-
-	if err := nfconfigServer.syncInMemoryConfig(); err != nil {
-		return nil, fmt.Errorf("failed to sync NF configuration data: %w", err)
+		config:       config.Configuration,
+		Router:       router,
+		bootRevision: uint64(time.Now().UnixMicro()),
 	}
 
 	logger.InitLog.Infoln("Setting up NFConfig routes")
@@ -80,7 +120,12 @@ func (n *NFConfigServer) Start(ctx context.Context, syncChan <-chan struct{}) er
 		Addr:    addr,
 		Handler: n.Router,
 	}
-	serverErrChan := make(chan error, 1)
+	srv.RegisterOnShutdown(n.watcher.close)
+	serverErrChan := make(chan error, 2)
+	grpcServer, err := n.startGrpcServer(serverErrChan)
+	if err != nil {
+		return err
+	}
 	go func() {
 		if n.config.NfConfigTLS != nil && n.config.NfConfigTLS.Key != "" && n.config.NfConfigTLS.PEM != "" {
 			logger.NfConfigLog.Infoln("Starting HTTPS server on", addr)
@@ -95,9 +140,16 @@ func (n *NFConfigServer) Start(ctx context.Context, syncChan <-chan struct{}) er
 		logger.NfConfigLog.Infoln("NFConfig context cancelled, shutting down server.")
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
+		if grpcServer != nil {
+			n.watcher.close()
+			grpcServer.GracefulStop()
+		}
 		return srv.Shutdown(shutdownCtx)
 
 	case err := <-serverErrChan:
+		if grpcServer != nil {
+			grpcServer.Stop()
+		}
 		return err
 	}
 }
@@ -105,6 +157,12 @@ func (n *NFConfigServer) Start(ctx context.Context, syncChan <-chan struct{}) er
 func (n *NFConfigServer) startSyncWorker(ctx context.Context, syncChan <-chan struct{}) {
 	go func() {
 		var currentCancel context.CancelFunc
+		if n.snapshot().source == cachedConfig && !n.waitForDatabase {
+			// keep trying to replace the cached configuration with live data
+			var syncCtx context.Context
+			syncCtx, currentCancel = context.WithCancel(context.Background())
+			go n.syncWithRetry(syncCtx)
+		}
 
 		for {
 			select {
@@ -154,7 +212,8 @@ var syncInMemoryConfigFunc = func(n *NFConfigServer) error {
 	return n.syncInMemoryConfig()
 }
 
-func (n *NFConfigServer) syncInMemoryConfig() error {
+func (n *NFConfigServer) syncInMemoryConfig() (err error) {
+	defer func() { n.syncState.record(err) }()
 	rawSlices, err := dbadapter.CommonDBClient.RestfulAPIGetMany(sliceDataColl, bson.M{})
 	if err != nil {
 		return err
@@ -191,55 +250,134 @@ func (n *NFConfigServer) syncInMemoryConfig() error {
 	}
 	logger.NfConfigLog.Debugf("Parsed %d device groups", len(deviceGroups))
 
-	n.inMemoryConfig.syncPlmn(slices)
-	n.inMemoryConfig.syncPlmnSnssai(slices)
-	n.inMemoryConfig.syncAccessAndMobility(slices)
-	n.inMemoryConfig.syncSessionManagement(slices, deviceGroups)
-	n.inMemoryConfig.syncPolicyControl(slices, deviceGroups)
-	n.inMemoryConfig.syncImsiQos(deviceGroups)
+	config := newInMemoryConfig(slices, deviceGroups)
+	config.source = liveConfig
+	config.syncedAt = time.Now()
+	n.publish(config)
+	if err := n.persistSnapshot(config); err != nil {
+		logger.NfConfigLog.Warnf("Failed to persist NF configuration snapshot: %+v", err)
+	}
 	logger.NfConfigLog.Infoln("Updated NF in-memory configuration")
 	return nil
 }
 
+// publish swaps in a new snapshot of the configuration so that handlers never
+// see parts of two different syncs, and wakes up the watchers if it changed.
+func (n *NFConfigServer) publish(config *inMemoryConfig) {
+	n.publishMutex.Lock()
+	defer n.publishMutex.Unlock()
+	changed := config.setRevisions(n.snapshot())
+	n.inMemoryConfig.Store(config)
+	if changed {
+		n.watcher.notify()
+	}
+}
+
+// snapshot returns the current configuration, empty until the first sync.
+func (n *NFConfigServer) snapshot() *inMemoryConfig {
+	if config := n.inMemoryConfig.Load(); config != nil {
+		return config
+	}
+	return &inMemoryConfig{revision: n.bootRevision}
+}
+
 func (n *NFConfigServer) setupRoutes() {
 	api := n.Router.Group("/nfconfig")
 	for _, route := range n.getRoutes() {
-		api.GET(route.Pattern, route.HandlerFunc)
+		api.Handle(route.Method, route.Pattern, route.HandlerFunc)
 	}
 }
 
 func (n *NFConfigServer) getRoutes() []Route {
 	return []Route{
 		{
+			Method:      http.MethodGet,
 			Pattern:     "/access-mobility",
 			HandlerFunc: n.GetAccessMobilityConfig,
 		},
 		{
+			Method:      http.MethodGet,
 			Pattern:     "/plmn",
 			HandlerFunc: n.GetPlmnConfig,
 		},
 		{
+			Method:      http.MethodGet,
 			Pattern:     "/plmn-snssai",
 			HandlerFunc: n.GetPlmnSnssaiConfig,
 		},
 		{
+			Method:      http.MethodGet,
 			Pattern:     "/policy-control",
 			HandlerFunc: n.GetPolicyControlConfig,
 		},
 		{
+			Method:      http.MethodGet,
 			Pattern:     "/session-management",
 			HandlerFunc: n.GetSessionManagementConfig,
 		},
 		{
+			Method:      http.MethodGet,
 			Pattern:     "/qos/:dnn/:imsi",
 			HandlerFunc: n.GetImsiQosConfig,
 		},
+		{
+			// gin has no literal colons in patterns, the action is checked
+			// by the handler
+			Method:      http.MethodPost,
+			Pattern:     "/qos:action",
+			HandlerFunc: n.PostImsiQosBatch,
+		},
+		{
+			Method:      http.MethodPost,
+			Pattern:     "/preview",
+			HandlerFunc: n.PreviewConfig,
+		},
+		{
+			Method:      http.MethodGet,
+			Pattern:     "/status",
+			HandlerFunc: n.GetConfigStatus,
+		},
+		{
+			Method:      http.MethodGet,
+			Pattern:     "/revision",
+			HandlerFunc: n.GetConfigRevision,
+		},
+		{
+			Method:      http.MethodGet,
+			Pattern:     "/access-mobility/watch",
+			HandlerFunc: n.WatchConfig(accessMobilityConfig),
+		},
+		{
+			Method:      http.MethodGet,
+			Pattern:     "/plmn/watch",
+			HandlerFunc: n.WatchConfig(plmnConfig),
+		},
+		{
+			Method:      http.MethodGet,
+			Pattern:     "/plmn-snssai/watch",
+			HandlerFunc: n.WatchConfig(plmnSnssaiConfig),
+		},
+		{
+			Method:      http.MethodGet,
+			Pattern:     "/policy-control/watch",
+			HandlerFunc: n.WatchConfig(policyControlConfig),
+		},
+		{
+			Method:      http.MethodGet,
+			Pattern:     "/session-management/watch",
+			HandlerFunc: n.WatchConfig(sessionManagementConfig),
+		},
 	}
 }
 
 func enforceAcceptJSON() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		acceptHeader := c.GetHeader("Accept")
+		// watch endpoints may also stream Server-Sent Events
+		if acceptHeader == eventStreamContentType && strings.HasSuffix(c.FullPath(), "/watch") {
+			c.Next()
+			return
+		}
 		if acceptHeader != "application/json" {
 			logger.NfConfigLog.Warnf("Invalid Accept header value: '%s'. Expected 'application/json'", acceptHeader)
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
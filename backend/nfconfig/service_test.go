@@ -271,6 +271,18 @@ func TestNFConfigRoutes(t *testing.T) {
 			acceptHeader: "application/json",
 			wantStatus:   http.StatusNotFound,
 		},
+		{
+			name:         "revision endpoint status OK",
+			path:         "/nfconfig/revision",
+			acceptHeader: "application/json",
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "status endpoint status OK",
+			path:         "/nfconfig/status",
+			acceptHeader: "application/json",
+			wantStatus:   http.StatusOK,
+		},
 		{
 			name:         "access mobility endpoint invalid accept header",
 			path:         "/nfconfig/access-mobility",
@@ -340,6 +352,16 @@ func TestNFConfigStart(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "HTTP and gRPC servers start and graceful shutdown",
+			config: &factory.Configuration{
+				NfConfigGrpc: &factory.NfConfigGrpc{
+					Enable: true,
+					Port:   19876,
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "HTTPS server start and graceful shutdown",
 			config: &factory.Configuration{
@@ -576,28 +598,27 @@ func TestSyncInMemoryConfig_UpdateAllConfigs(t *testing.T) {
 			originalDBClient := dbadapter.CommonDBClient
 			defer func() { dbadapter.CommonDBClient = originalDBClient }()
 			dbadapter.CommonDBClient = mockDB
-			n := &NFConfigServer{
-				inMemoryConfig: inMemoryConfig{},
-			}
+			n := &NFConfigServer{}
 
 			err := n.syncInMemoryConfig()
 			if err != nil {
 				t.Errorf("expected no error. Got %s", err)
 			}
-			if !reflect.DeepEqual(tc.expectedPlmn, n.inMemoryConfig.plmn) {
-				t.Errorf("expected PLMN %+v, got %+v", tc.expectedPlmn, n.inMemoryConfig.plmn)
+			config := n.snapshot()
+			if !reflect.DeepEqual(tc.expectedPlmn, config.plmn) {
+				t.Errorf("expected PLMN %+v, got %+v", tc.expectedPlmn, config.plmn)
 			}
-			if !reflect.DeepEqual(tc.expectedPlmnSnssai, n.inMemoryConfig.plmnSnssai) {
-				t.Errorf("expected PLMN-SNSSAI %+v, got %+v", tc.expectedPlmnSnssai, n.inMemoryConfig.plmnSnssai)
+			if !reflect.DeepEqual(tc.expectedPlmnSnssai, config.plmnSnssai) {
+				t.Errorf("expected PLMN-SNSSAI %+v, got %+v", tc.expectedPlmnSnssai, config.plmnSnssai)
 			}
-			if !reflect.DeepEqual(tc.expectedAccessAndMobility, n.inMemoryConfig.accessAndMobility) {
-				t.Errorf("expected Access and Mobility %+v, got %+v", tc.expectedAccessAndMobility, n.inMemoryConfig.accessAndMobility)
+			if !reflect.DeepEqual(tc.expectedAccessAndMobility, config.accessAndMobility) {
+				t.Errorf("expected Access and Mobility %+v, got %+v", tc.expectedAccessAndMobility, config.accessAndMobility)
 			}
-			if !reflect.DeepEqual(tc.expectedSessionManagement, n.inMemoryConfig.sessionManagement) {
-				t.Errorf("expected Session Management %+v, got %+v", tc.expectedSessionManagement, n.inMemoryConfig.sessionManagement)
+			if !reflect.DeepEqual(tc.expectedSessionManagement, config.sessionManagement) {
+				t.Errorf("expected Session Management %+v, got %+v", tc.expectedSessionManagement, config.sessionManagement)
 			}
-			if !reflect.DeepEqual(tc.expectedPolicyControl, n.inMemoryConfig.policyControl) {
-				t.Errorf("expected Policy Control %+v, got %+v", tc.expectedPolicyControl, n.inMemoryConfig.policyControl)
+			if !reflect.DeepEqual(tc.expectedPolicyControl, config.policyControl) {
+				t.Errorf("expected Policy Control %+v, got %+v", tc.expectedPolicyControl, config.policyControl)
 			}
 		})
 	}
@@ -669,36 +690,73 @@ func TestSyncInMemoryConfig_DBError_KeepsPreviousConfig(t *testing.T) {
 			originalDBClient := dbadapter.CommonDBClient
 			defer func() { dbadapter.CommonDBClient = originalDBClient }()
 			dbadapter.CommonDBClient = mockDB
-			n := &NFConfigServer{
-				inMemoryConfig: inMemoryConfig{
-					plmn:              tc.expectedPlmn,
-					plmnSnssai:        tc.expectedPlmnSnssai,
-					accessAndMobility: tc.expectedAccessAndMobility,
-					sessionManagement: tc.expectedSessionManagement,
-					policyControl:     tc.expectedPolicyControl,
-				},
-			}
+			n := &NFConfigServer{}
+			n.publish(&inMemoryConfig{
+				plmn:              tc.expectedPlmn,
+				plmnSnssai:        tc.expectedPlmnSnssai,
+				accessAndMobility: tc.expectedAccessAndMobility,
+				sessionManagement: tc.expectedSessionManagement,
+				policyControl:     tc.expectedPolicyControl,
+			})
 
 			err := n.syncInMemoryConfig()
+			config := n.snapshot()
 
 			if err == nil {
 				t.Errorf("expected error. Got nil")
 			}
-			if !reflect.DeepEqual(tc.expectedPlmn, n.inMemoryConfig.plmn) {
-				t.Errorf("expected PLMN %v, got %v", tc.expectedPlmn, n.inMemoryConfig.plmn)
+			if !reflect.DeepEqual(tc.expectedPlmn, config.plmn) {
+				t.Errorf("expected PLMN %v, got %v", tc.expectedPlmn, config.plmn)
 			}
-			if !reflect.DeepEqual(tc.expectedPlmnSnssai, n.inMemoryConfig.plmnSnssai) {
-				t.Errorf("expected PLMN-SNSSAI %v, got %v", tc.expectedPlmnSnssai, n.inMemoryConfig.plmnSnssai)
+			if !reflect.DeepEqual(tc.expectedPlmnSnssai, config.plmnSnssai) {
+				t.Errorf("expected PLMN-SNSSAI %v, got %v", tc.expectedPlmnSnssai, config.plmnSnssai)
 			}
-			if !reflect.DeepEqual(tc.expectedAccessAndMobility, n.inMemoryConfig.accessAndMobility) {
-				t.Errorf("expected Access and Mobility %v, got %v", tc.expectedAccessAndMobility, n.inMemoryConfig.accessAndMobility)
+			if !reflect.DeepEqual(tc.expectedAccessAndMobility, config.accessAndMobility) {
+				t.Errorf("expected Access and Mobility %v, got %v", tc.expectedAccessAndMobility, config.accessAndMobility)
 			}
-			if !reflect.DeepEqual(tc.expectedSessionManagement, n.inMemoryConfig.sessionManagement) {
-				t.Errorf("expected Session Management %+v, got %+v", tc.expectedSessionManagement, n.inMemoryConfig.sessionManagement)
+			if !reflect.DeepEqual(tc.expectedSessionManagement, config.sessionManagement) {
+				t.Errorf("expected Session Management %+v, got %+v", tc.expectedSessionManagement, config.sessionManagement)
 			}
-			if !reflect.DeepEqual(tc.expectedPolicyControl, n.inMemoryConfig.policyControl) {
-				t.Errorf("expected Policy Control %+v, got %+v", tc.expectedPolicyControl, n.inMemoryConfig.policyControl)
+			if !reflect.DeepEqual(tc.expectedPolicyControl, config.policyControl) {
+				t.Errorf("expected Policy Control %+v, got %+v", tc.expectedPolicyControl, config.policyControl)
 			}
 		})
 	}
 }
+
+func TestPublish_HandlersReadConsistentSnapshot(t *testing.T) {
+	n := newWatchTestServer(nil)
+	generation := func(mnc string) *inMemoryConfig {
+		plmn := *nfConfigApi.NewPlmnId("001", mnc)
+		return &inMemoryConfig{
+			plmn:              []nfConfigApi.PlmnId{plmn},
+			sessionManagement: []nfConfigApi.SessionManagement{{SliceName: "slice" + mnc, PlmnId: plmn}},
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ctx.Err() == nil; i++ {
+			n.publish(generation(fmt.Sprintf("%02d", i%2)))
+		}
+	}()
+
+	for range 200 {
+		config := n.snapshot()
+		if len(config.plmn) == 1 && config.sessionManagement[0].PlmnId != config.plmn[0] {
+			t.Fatalf("snapshot mixes two syncs: %+v and %+v", config.plmn, config.sessionManagement)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/nfconfig/session-management", nil)
+		req.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		n.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, w.Code)
+		}
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package nfconfig
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/logger"
+)
+
+const (
+	accessMobilityConfig    = "access-mobility"
+	plmnConfig              = "plmn"
+	plmnSnssaiConfig        = "plmn-snssai"
+	policyControlConfig     = "policy-control"
+	sessionManagementConfig = "session-management"
+	imsiQosConfigType       = "qos"
+)
+
+const (
+	revisionHeader         = "X-Config-Revision"
+	eventStreamContentType = "text/event-stream"
+	defaultWatchTimeout    = 30 * time.Second
+	maxWatchTimeout        = 5 * time.Minute
+	watchKeepAliveInterval = 15 * time.Second
+)
+
+// configRevision is the rendered payload of an NF configuration type together
+// with its content hash and the revision of the sync that last changed it.
+type configRevision struct {
+	revision uint64
+	hash     string
+	payload  []byte
+}
+
+// configWatcher wakes up the watchers whenever a published snapshot changes
+// at least one configuration type.
+type configWatcher struct {
+	mu       sync.Mutex
+	changed  chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (w *configWatcher) init() {
+	if w.changed == nil {
+		w.changed = make(chan struct{})
+	}
+	if w.stop == nil {
+		w.stop = make(chan struct{})
+	}
+}
+
+// changes returns a channel closed on the next change.
+func (w *configWatcher) changes() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.init()
+	return w.changed
+}
+
+func (w *configWatcher) notify() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.init()
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+func (w *configWatcher) stopped() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.init()
+	return w.stop
+}
+
+// close ends the open event streams so that the server can shut down.
+func (w *configWatcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.init()
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+// changedSince returns the payload of the configuration type if it changed
+// after the given revision. Revisions start from the boot time, so a revision
+// from before a restart is older than every type; a newer one than the current
+// revision comes from a clock set back and is treated as stale too.
+func (c *inMemoryConfig) changedSince(configType string, since uint64) (configRevision, bool) {
+	config, ok := c.revisions[configType]
+	return config, ok && (config.revision > since || since > c.revision)
+}
+
+// nextConfig returns the payload of the configuration type if it changed
+// after the given revision, along with a channel closed on the next change.
+func (n *NFConfigServer) nextConfig(configType string, since uint64, filter configFilter) (configRevision, bool, <-chan struct{}) {
+	// the channel is taken before the snapshot so that a publish in between
+	// is not missed
+	changed := n.watcher.changes()
+	snapshot := n.snapshot()
+	config, ok := snapshot.changedSince(configType, since)
+	if ok {
+		config = snapshot.filteredRevision(configType, config, filter)
+	}
+	return config, ok, changed
+}
+
+// WatchConfig returns the handler watching a configuration type. Clients
+// accepting text/event-stream get a Server-Sent Events stream with one event
+// per change, the others a long-poll answered as soon as the configuration
+// changes after the revision they saw, or 204 No Content on timeout. The last
+// seen revision is taken from the `revision` query parameter or from the
+// Last-Event-ID header sent by reconnecting SSE clients. The filters of the
+// GET endpoints apply to the payloads sent, and a change that leaves the
+// filtered payload as last sent, or as the If-None-Match header of a long-poll,
+// is not reported.
+func (n *NFConfigServer) WatchConfig(configType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		since, err := watchRevision(c)
+		if err != nil {
+			logger.NfConfigLog.Warnf("Invalid %s watch request: %+v", configType, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter, err := parseConfigFilter(c)
+		if err != nil {
+			logger.NfConfigLog.Warnf("Invalid %s watch request: %+v", configType, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if c.GetHeader("Accept") == eventStreamContentType {
+			n.streamConfig(c, configType, since, filter)
+			return
+		}
+		timeout, err := watchTimeout(c)
+		if err != nil {
+			logger.NfConfigLog.Warnf("Invalid %s watch request: %+v", configType, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		n.longPollConfig(c, configType, since, filter, timeout)
+	}
+}
+
+func watchRevision(c *gin.Context) (uint64, error) {
+	value := c.Query("revision")
+	if value == "" {
+		value = c.GetHeader("Last-Event-ID")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	revision, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid revision %q", value)
+	}
+	return revision, nil
+}
+
+func watchTimeout(c *gin.Context) (time.Duration, error) {
+	value := c.Query("timeout")
+	if value == "" {
+		return defaultWatchTimeout, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q", value)
+	}
+	return min(timeout, maxWatchTimeout), nil
+}
+
+func (n *NFConfigServer) longPollConfig(c *gin.Context, configType string, since uint64, filter configFilter, timeout time.Duration) {
+	logger.NfConfigLog.Debugf("Handling long-poll watch for %s config after revision %d", configType, since)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ifNoneMatch := c.GetHeader("If-None-Match")
+	for {
+		config, ok, changed := n.nextConfig(configType, since, filter)
+		if ok && ifNoneMatch != "" && etagMatches(ifNoneMatch, strconv.Quote(config.hash)) {
+			// a change outside of the filter leaves the payload as the
+			// client already has it
+			since = config.revision
+			continue
+		}
+		if ok {
+			setRevisionHeaders(c, config)
+			c.Data(http.StatusOK, "application/json", config.payload)
+			return
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			c.Header(revisionHeader, strconv.FormatUint(n.snapshot().revision, 10))
+			c.Status(http.StatusNoContent)
+			return
+		case <-n.watcher.stopped():
+			c.Status(http.StatusServiceUnavailable)
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+func (n *NFConfigServer) streamConfig(c *gin.Context, configType string, since uint64, filter configFilter) {
+	logger.NfConfigLog.Infof("Starting %s config event stream after revision %d", configType, since)
+	c.Header("Content-Type", eventStreamContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(watchKeepAliveInterval)
+	defer keepAlive.Stop()
+	var sent string
+	for {
+		config, ok, changed := n.nextConfig(configType, since, filter)
+		if ok && config.hash == sent {
+			// a change outside of the filter leaves the payload as sent
+			since = config.revision
+			continue
+		}
+		if ok {
+			if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", config.revision, configType, config.payload); err != nil {
+				logger.NfConfigLog.Warnf("Failed to write %s config event: %+v", configType, err)
+				return
+			}
+			c.Writer.Flush()
+			since = config.revision
+			sent = config.hash
+			continue
+		}
+		select {
+		case <-changed:
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(c.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-n.watcher.stopped():
+			return
+		case <-c.Request.Context().Done():
+			logger.NfConfigLog.Infof("Client closed %s config event stream", configType)
+			return
+		}
+	}
+}
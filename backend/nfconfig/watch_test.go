@@ -0,0 +1,201 @@
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+package nfconfig
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/openapi/nfConfigApi"
+)
+
+func newWatchTestServer(plmn []nfConfigApi.PlmnId) *NFConfigServer {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(enforceAcceptJSON())
+	n := &NFConfigServer{
+		Router: router,
+	}
+	n.setupRoutes()
+	n.publish(&inMemoryConfig{plmn: plmn})
+	return n
+}
+
+func TestPublish_Revisions(t *testing.T) {
+	n := &NFConfigServer{}
+	n.publish(&inMemoryConfig{plmn: []nfConfigApi.PlmnId{{Mcc: "001", Mnc: "01"}}})
+	n.publish(&inMemoryConfig{plmn: []nfConfigApi.PlmnId{{Mcc: "001", Mnc: "01"}}})
+	if revision := n.snapshot().revision; revision != 1 {
+		t.Fatalf("expected an unchanged sync to keep revision 1, got %d", revision)
+	}
+
+	_, _, changed := n.nextConfig(plmnConfig, 1, configFilter{})
+	n.publish(&inMemoryConfig{plmn: []nfConfigApi.PlmnId{{Mcc: "001", Mnc: "02"}}})
+	select {
+	case <-changed:
+	default:
+		t.Fatalf("expected watchers to be notified of the change")
+	}
+	if config, ok, _ := n.nextConfig(plmnConfig, 1, configFilter{}); !ok || config.revision != 2 || string(config.payload) != `[{"mcc":"001","mnc":"02"}]` {
+		t.Errorf("expected plmn to change at revision 2, got %d %s (changed: %v)", config.revision, config.payload, ok)
+	}
+	if config, ok, _ := n.nextConfig(policyControlConfig, 1, configFilter{}); ok || config.revision != 1 {
+		t.Errorf("expected policy control to stay at revision 1, got %d (changed: %v)", config.revision, ok)
+	}
+	if _, ok, _ := n.nextConfig(policyControlConfig, 42, configFilter{}); !ok {
+		t.Errorf("expected a revision from before a restart to be treated as stale")
+	}
+}
+
+func TestPublish_RevisionsAfterRestart(t *testing.T) {
+	before := &NFConfigServer{bootRevision: 1000}
+	before.publish(&inMemoryConfig{plmn: []nfConfigApi.PlmnId{{Mcc: "001", Mnc: "01"}}})
+	before.publish(&inMemoryConfig{plmn: []nfConfigApi.PlmnId{{Mcc: "001", Mnc: "02"}}})
+	seen := before.snapshot().revision
+
+	after := &NFConfigServer{bootRevision: 2000}
+	after.publish(&inMemoryConfig{plmn: []nfConfigApi.PlmnId{{Mcc: "001", Mnc: "02"}}})
+	if revision := after.snapshot().revision; revision <= seen {
+		t.Fatalf("expected the revision after a restart to be above %d, got %d", seen, revision)
+	}
+	for _, configType := range []string{plmnConfig, policyControlConfig} {
+		if _, ok, _ := after.nextConfig(configType, seen, configFilter{}); !ok {
+			t.Errorf("expected %s to be sent again to a client of the previous process", configType)
+		}
+	}
+}
+
+func TestWatchConfig_LongPoll(t *testing.T) {
+	n := newWatchTestServer([]nfConfigApi.PlmnId{{Mcc: "001", Mnc: "01"}})
+
+	testCases := []struct {
+		name             string
+		query            string
+		update           []nfConfigApi.PlmnId
+		expectedCode     int
+		expectedRevision string
+		expectedBody     string
+	}{
+		{
+			name:             "client behind current revision",
+			query:            "revision=0",
+			expectedCode:     http.StatusOK,
+			expectedRevision: "1",
+			expectedBody:     `[{"mcc":"001","mnc":"01"}]`,
+		},
+		{
+			name:             "no change before timeout",
+			query:            "revision=1&timeout=50ms",
+			expectedCode:     http.StatusNoContent,
+			expectedRevision: "1",
+		},
+		{
+			name:             "change while waiting",
+			query:            "revision=1",
+			update:           []nfConfigApi.PlmnId{{Mcc: "001", Mnc: "02"}},
+			expectedCode:     http.StatusOK,
+			expectedRevision: "2",
+			expectedBody:     `[{"mcc":"001","mnc":"02"}]`,
+		},
+		{
+			name:         "invalid revision",
+			query:        "revision=abc",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid timeout",
+			query:        "timeout=-1s",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.update != nil {
+				go func() {
+					time.Sleep(50 * time.Millisecond)
+					n.publish(&inMemoryConfig{plmn: tc.update})
+				}()
+			}
+			req := httptest.NewRequest(http.MethodGet, "/nfconfig/plmn/watch?"+tc.query, nil)
+			req.Header.Set("Accept", "application/json")
+			w := httptest.NewRecorder()
+			n.Router.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedCode {
+				t.Fatalf("expected %d, got %d (body: %s)", tc.expectedCode, w.Code, w.Body.String())
+			}
+			if revision := w.Header().Get(revisionHeader); revision != tc.expectedRevision {
+				t.Errorf("expected revision %q, got %q", tc.expectedRevision, revision)
+			}
+			if tc.expectedBody != "" && w.Body.String() != tc.expectedBody {
+				t.Errorf("expected body %s, got %s", tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWatchConfig_ServerSentEvents(t *testing.T) {
+	n := newWatchTestServer([]nfConfigApi.PlmnId{{Mcc: "001", Mnc: "01"}})
+	server := httptest.NewServer(n.Router)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/nfconfig/plmn/watch", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Accept", eventStreamContentType)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to open event stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != eventStreamContentType {
+		t.Fatalf("unexpected response: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() string {
+		var lines []string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("failed to read event: %v", err)
+			}
+			if line == "\n" {
+				return strings.Join(lines, "")
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	expected := "id: 1\nevent: plmn\ndata: [{\"mcc\":\"001\",\"mnc\":\"01\"}]\n"
+	if event := readEvent(); event != expected {
+		t.Errorf("expected event %q, got %q", expected, event)
+	}
+	n.publish(&inMemoryConfig{plmn: []nfConfigApi.PlmnId{{Mcc: "001", Mnc: "02"}}})
+	expected = "id: 2\nevent: plmn\ndata: [{\"mcc\":\"001\",\"mnc\":\"02\"}]\n"
+	if event := readEvent(); event != expected {
+		t.Errorf("expected event %q, got %q", expected, event)
+	}
+}
+
+func TestWatchConfig_EventStreamOnlyOnWatchEndpoints(t *testing.T) {
+	n := newWatchTestServer(nil)
+	req := httptest.NewRequest(http.MethodGet, "/nfconfig/plmn", nil)
+	req.Header.Set("Accept", eventStreamContentType)
+	w := httptest.NewRecorder()
+	n.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
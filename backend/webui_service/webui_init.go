@@ -39,47 +39,81 @@ type WebUIInterface interface {
 	Start(ctx context.Context, syncChan chan<- struct{})
 }
 
-func setupAuthenticationFeature(subconfig_router *gin.Engine, nfSyncMiddelware gin.HandlerFunc) {
-	jwtSecret, err := auth.GenerateJWTSecret()
+// setupAuthenticationFeature adds the authenticated routes. It fails on an
+// invalid authentication configuration, which must stop the start rather
+// than leave the routes out.
+func setupAuthenticationFeature(subconfig_router *gin.Engine, nfSyncMiddelware gin.HandlerFunc, auditMiddleware gin.HandlerFunc) error {
+	signingKeys, err := auth.LoadSigningKeys(factory.WebUIConfig.Configuration.JwtKeys)
 	if err != nil {
-		logger.InitLog.Error(err)
-		return
+		return err
+	}
+	if err = auth.SeedBuiltinRoles(); err != nil {
+		return err
+	}
+	var ldapAuthenticator *auth.LDAPAuthenticator
+	if ldapConfig := factory.WebUIConfig.Configuration.Ldap; ldapConfig != nil {
+		if ldapAuthenticator, err = auth.NewLDAPAuthenticator(ldapConfig); err != nil {
+			return err
+		}
+	}
+	configapi.AddUserAccountService(subconfig_router, signingKeys, auditMiddleware)
+	loginThrottle := auth.NewLoginThrottle(factory.WebUIConfig.Configuration.LoginThrottling)
+	auth.AddAuthenticationService(subconfig_router, signingKeys, ldapAuthenticator, loginThrottle)
+	if oidcConfig := factory.WebUIConfig.Configuration.Oidc; oidcConfig != nil {
+		var oidcProvider *auth.OIDCProvider
+		if oidcProvider, err = auth.NewOIDCProvider(oidcConfig); err != nil {
+			return err
+		}
+		auth.AddOIDCService(subconfig_router, signingKeys, oidcProvider)
 	}
-	configapi.AddUserAccountService(subconfig_router, jwtSecret)
-	auth.AddAuthenticationService(subconfig_router, jwtSecret)
-	authMiddleware := auth.AdminOrUserAuthMiddleware(jwtSecret)
-	configapi.AddApiService(subconfig_router, authMiddleware)
-	configapi.AddConfigV1Service(subconfig_router, nfSyncMiddelware, authMiddleware)
+	authMiddleware := auth.AdminOrUserAuthMiddleware(signingKeys)
+	configapi.AddApiService(subconfig_router, auditMiddleware, authMiddleware)
+	configapi.AddConfigV1Service(subconfig_router, auditMiddleware, nfSyncMiddelware, authMiddleware)
+	return nil
 }
 
 func (webui *WEBUI) Start(ctx context.Context, syncChan chan<- struct{}) {
 	subconfig_router := utilLogger.NewGinWithZap(logger.GinLog)
+	// the client IPs throttling the log ins and recorded in the audit log must
+	// not be taken from headers set by the clients
+	if err := subconfig_router.SetTrustedProxies(factory.WebUIConfig.Configuration.TrustedProxies); err != nil {
+		logger.InitLog.Errorf("invalid trusted-proxies: %v", err)
+		os.Exit(1)
+	}
 	nFConfigSyncMiddleware := triggerNFConfigSyncMiddleware(syncChan)
+	auditMiddleware := configapi.AuditMiddleware()
 	if factory.WebUIConfig.Configuration.EnableAuthentication {
-		setupAuthenticationFeature(subconfig_router, nFConfigSyncMiddleware)
+		if err := setupAuthenticationFeature(subconfig_router, nFConfigSyncMiddleware, auditMiddleware); err != nil {
+			logger.InitLog.Errorf("authentication setup failed: %v", err)
+			os.Exit(1)
+		}
 	} else {
-		configapi.AddApiService(subconfig_router)
-		configapi.AddConfigV1Service(subconfig_router, nFConfigSyncMiddleware)
+		configapi.AddApiService(subconfig_router, auditMiddleware)
+		configapi.AddConfigV1Service(subconfig_router, auditMiddleware, nFConfigSyncMiddleware)
 	}
 	if factory.WebUIConfig.Configuration.SSM.SsmSync.Enable {
 		logger.AppLog.Debug("exec ssmsync.AddSyncSSMService(subconfig_router)")
-		ssmsync.AddSyncSSMService(subconfig_router)
+		ssmsync.AddSyncSSMService(subconfig_router, auditMiddleware)
 	} else if factory.WebUIConfig.Configuration.Vault.SsmSync.Enable {
 		logger.AppLog.Debug("exec vaultsync.AddSyncSSMService(subconfig_router)")
-		vaultsync.AddSyncVaultService(subconfig_router)
+		vaultsync.AddSyncVaultService(subconfig_router, auditMiddleware)
 	}
 	AddSwaggerUiService(subconfig_router)
 	AddUiService(subconfig_router)
 
+	if err := configapi.ResumeSyncJobs(); err != nil {
+		logger.AppLog.Errorf("failed to resume sync jobs: %v", err)
+	}
+
 	go metrics.InitMetrics()
 
 	subconfig_router.Use(cors.New(cors.Config{
 		AllowMethods: []string{"GET", "POST", "OPTIONS", "PUT", "PATCH", "DELETE"},
 		AllowHeaders: []string{
 			"Origin", "Content-Length", "Content-Type", "User-Agent",
-			"Referrer", "Host", "Token", "X-Requested-With",
+			"Referrer", "Host", "Token", "X-Requested-With", "X-Request-ID",
 		},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID"},
 		AllowCredentials: true,
 		AllowAllOrigins:  true,
 		MaxAge:           86400,
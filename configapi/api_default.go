@@ -197,8 +197,18 @@ func DeviceGroupGroupNamePut(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "request_id": requestID})
 		return
 	}
+	// the checks and the write are done under rwLock, so that concurrent
+	// writes cannot both pass the overlap checks
+	rwLock.Lock()
+	defer rwLock.Unlock()
+	if statusCode, err := validateDeviceGroupIpPool(&requestDeviceGroup); err != nil {
+		logger.ConfigLog.Errorln(err)
+		c.JSON(statusCode, gin.H{"error": err.Error(), "request_id": requestID})
+		return
+	}
 
-	if statusCode, err := deviceGroupPostHelper(requestDeviceGroup, groupName); err != nil {
+	job, statusCode, err := deviceGroupPostHelper(requestDeviceGroup, groupName)
+	if err != nil {
 		logger.WebUILog.Errorf("Device group update failed: %+v", err)
 		c.JSON(statusCode, gin.H{
 			"error":      fmt.Sprintf("Failed to update device group %s with error: %+v.", groupName, err),
@@ -207,7 +217,8 @@ func DeviceGroupGroupNamePut(c *gin.Context) {
 		})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{})
+	c.Header("Location", "/config/v1/jobs/"+job.JobId)
+	c.JSON(http.StatusAccepted, job)
 }
 
 // DeviceGroupGroupNamePost godoc
@@ -217,8 +228,9 @@ func DeviceGroupGroupNamePut(c *gin.Context) {
 // @Param        deviceGroupName    path    string                       true    " "
 // @Param        content            body    configmodels.DeviceGroups    true    " "
 // @Security     BearerAuth
-// @Success      200  {object}  nil  "Device group created"
+// @Success      202  {object}  configmodels.SyncJob  "Device group created, subscriber sync queued"
 // @Failure      400  {object}  nil  "Invalid device group content"
+// @Failure      409  {object}  nil  "UE IP pool overlaps another device group"
 // @Failure      401  {object}  nil  "Authorization failed"
 // @Failure      403  {object}  nil  "Forbidden"
 // @Failure      500  {object}  nil  "Error creating device group"
@@ -278,8 +290,18 @@ func DeviceGroupGroupNamePost(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "request_id": requestID})
 		return
 	}
+	// the checks and the write are done under rwLock, so that concurrent
+	// writes cannot both pass the overlap checks
+	rwLock.Lock()
+	defer rwLock.Unlock()
+	if statusCode, err := validateDeviceGroupIpPool(&requestDeviceGroup); err != nil {
+		logger.ConfigLog.Errorln(err)
+		c.JSON(statusCode, gin.H{"error": err.Error(), "request_id": requestID})
+		return
+	}
 
-	if statusCode, err := deviceGroupPostHelper(requestDeviceGroup, groupName); err != nil {
+	job, statusCode, err := deviceGroupPostHelper(requestDeviceGroup, groupName)
+	if err != nil {
 		logger.WebUILog.Errorf("Device group create failed: %+v", err)
 		c.JSON(statusCode, gin.H{
 			"error":      fmt.Sprintf("Failed to create device group %s with error: %+v.", groupName, err),
@@ -288,7 +310,8 @@ func DeviceGroupGroupNamePost(c *gin.Context) {
 		})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{})
+	c.Header("Location", "/config/v1/jobs/"+job.JobId)
+	c.JSON(http.StatusAccepted, job)
 }
 
 // GetNetworkSlices godoc
@@ -417,8 +440,9 @@ func NetworkSliceSliceNameDelete(c *gin.Context) {
 // @Param        sliceName    path    string                true    " "
 // @Param        content      body    configmodels.Slice    true    " "
 // @Security     BearerAuth
-// @Success      200  {object}  nil  "Network slice created"
+// @Success      202  {object}  configmodels.SyncJob  "Network slice created, subscriber sync queued"
 // @Failure      400  {object}  nil  "Invalid network slice content"
+// @Failure      409  {object}  nil  "UE IP pools of the device groups overlap"
 // @Failure      401  {object}  nil  "Authorization failed"
 // @Failure      403  {object}  nil  "Forbidden"
 // @Failure      500  {object}  nil  "Error creating network slice"
@@ -447,7 +471,7 @@ func NetworkSliceSliceNamePost(c *gin.Context) {
 		})
 		return
 	}
-	statusCode, err := networkSlicePostHelper(c, sliceName)
+	job, statusCode, err := networkSlicePostHelper(c, sliceName)
 	if err != nil {
 		c.JSON(statusCode, gin.H{
 			"error":      fmt.Sprintf("Failed to create network slice %s with error: %+v", sliceName, err),
@@ -456,7 +480,8 @@ func NetworkSliceSliceNamePost(c *gin.Context) {
 		})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{})
+	c.Header("Location", "/config/v1/jobs/"+job.JobId)
+	c.JSON(http.StatusAccepted, job)
 }
 
 // NetworkSliceSliceNamePut -
@@ -483,7 +508,7 @@ func NetworkSliceSliceNamePut(c *gin.Context) {
 		})
 		return
 	}
-	statusCode, err := networkSlicePostHelper(c, sliceName)
+	job, statusCode, err := networkSlicePostHelper(c, sliceName)
 	if err != nil {
 		c.JSON(statusCode, gin.H{
 			"error":      fmt.Sprintf("Failed to update network slice %s with error: %+v.", sliceName, err),
@@ -492,5 +517,6 @@ func NetworkSliceSliceNamePut(c *gin.Context) {
 		})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{})
+	c.Header("Location", "/config/v1/jobs/"+job.JobId)
+	c.JSON(http.StatusAccepted, job)
 }
@@ -0,0 +1,298 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omec-project/openapi/models"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const deviceGroupImsisMaxRequest = imsiBatchSize
+
+var (
+	errImsiCoveredByRange     = errors.New("IMSI is covered by an IMSI range of the device group")
+	errImsiInOtherDeviceGroup = errors.New("IMSI is already a member of another device group")
+)
+
+// PostDeviceGroupImsis godoc
+//
+// @Description  Add IMSIs to an existing device group and queue the sync of only the added ones
+// @Tags         Device Groups
+// @Param        deviceGroupName    path    string                           true    " "
+// @Param        content            body    configmodels.DeviceGroupImsis    true    " "
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  configmodels.DeviceGroupImsisResult  "All IMSIs already in the device group"
+// @Success      202  {object}  configmodels.DeviceGroupImsisResult  "IMSIs added, subscriber sync queued"
+// @Failure      400  {object}  nil                                  "Invalid IMSI list or UE IP pool too small"
+// @Failure      401  {object}  nil                                  "Authorization failed"
+// @Failure      403  {object}  nil                                  "Forbidden"
+// @Failure      404  {object}  nil                                  "Device group not found"
+// @Failure      409  {object}  nil                                  "IMSI in another device group or UE IP pool overlap"
+// @Failure      500  {object}  nil                                  "Error updating device group"
+// @Router       /config/v1/device-group/{deviceGroupName}/imsis  [post]
+func PostDeviceGroupImsis(c *gin.Context) {
+	handleDeviceGroupImsis(c, true)
+}
+
+// DeleteDeviceGroupImsis godoc
+//
+// @Description  Remove IMSIs from an existing device group and queue the clean up of only the removed ones
+// @Tags         Device Groups
+// @Param        deviceGroupName    path    string                           true    " "
+// @Param        content            body    configmodels.DeviceGroupImsis    true    " "
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  configmodels.DeviceGroupImsisResult  "No IMSI was in the device group"
+// @Success      202  {object}  configmodels.DeviceGroupImsisResult  "IMSIs removed, subscriber clean up queued"
+// @Failure      400  {object}  nil                                  "Invalid IMSI list"
+// @Failure      401  {object}  nil                                  "Authorization failed"
+// @Failure      403  {object}  nil                                  "Forbidden"
+// @Failure      404  {object}  nil                                  "Device group not found"
+// @Failure      500  {object}  nil                                  "Error updating device group"
+// @Router       /config/v1/device-group/{deviceGroupName}/imsis  [delete]
+func DeleteDeviceGroupImsis(c *gin.Context) {
+	handleDeviceGroupImsis(c, false)
+}
+
+func handleDeviceGroupImsis(c *gin.Context, add bool) {
+	setCorsHeader(c)
+	requestID := uuid.New().String()
+	groupName := c.Param("group-name")
+	if !isValidName(groupName) {
+		logger.ConfigLog.Errorf("invalid Device Group name %s. Name needs to match regular expression: %s", groupName, NAME_PATTERN)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      fmt.Sprintf("Invalid Device Group name %s. Name needs to match regular expression: %s", groupName, NAME_PATTERN),
+			"request_id": requestID,
+		})
+		return
+	}
+	if ct := strings.Split(c.GetHeader("Content-Type"), ";")[0]; ct != "application/json" {
+		err := fmt.Sprintf("unsupported content-type: %s", ct)
+		logger.ConfigLog.Errorln(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err, "request_id": requestID})
+		return
+	}
+	var request configmodels.DeviceGroupImsis
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.ConfigLog.Errorf("JSON bind error: %+v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("JSON bind error: %+v", err), "request_id": requestID})
+		return
+	}
+	imsis, err := normalizeDeviceGroupImsis(request.Imsis)
+	if err != nil {
+		logger.ConfigLog.Errorln(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "request_id": requestID})
+		return
+	}
+
+	result, statusCode, err := updateDeviceGroupImsis(groupName, imsis, add)
+	if err != nil {
+		logger.ConfigLog.Errorf("request ID: %s failed to update IMSIs of device group %s: %+v", requestID, groupName, err)
+		c.JSON(statusCode, gin.H{
+			"error":      fmt.Sprintf("Failed to update IMSIs of device group %s with error: %+v.", groupName, err),
+			"request_id": requestID,
+			"message":    "Please refer to the log with the provided Request ID for details",
+		})
+		return
+	}
+	if result.Job != nil {
+		c.Header("Location", "/config/v1/jobs/"+result.Job.JobId)
+	}
+	c.JSON(statusCode, result)
+}
+
+func normalizeDeviceGroupImsis(imsis []string) ([]string, error) {
+	if len(imsis) == 0 {
+		return nil, errors.New("imsis must not be empty")
+	}
+	if len(imsis) > deviceGroupImsisMaxRequest {
+		return nil, fmt.Errorf("too many IMSIs: %d (maximum %d)", len(imsis), deviceGroupImsisMaxRequest)
+	}
+	normalized := make([]string, 0, len(imsis))
+	for _, imsi := range imsis {
+		imsi = strings.TrimPrefix(strings.TrimSpace(imsi), "imsi-")
+		if !configmodels.IsValidImsi(imsi) {
+			return nil, fmt.Errorf("invalid IMSI %q", imsi)
+		}
+		if !slices.Contains(normalized, imsi) {
+			normalized = append(normalized, imsi)
+		}
+	}
+	return normalized, nil
+}
+
+// updateDeviceGroupImsis adds or removes the IMSIs with a single $addToSet or
+// $pull on the device group and then queues the sync of only the IMSIs whose
+// membership changed.
+func updateDeviceGroupImsis(groupName string, imsis []string, add bool) (*configmodels.DeviceGroupImsisResult, int, error) {
+	rwLock.Lock()
+	defer rwLock.Unlock()
+	devGroup := getDeviceGroupByName(groupName)
+	if devGroup == nil || devGroup.DeviceGroupName == "" {
+		return nil, http.StatusNotFound, fmt.Errorf("device group %s not found", groupName)
+	}
+
+	result := &configmodels.DeviceGroupImsisResult{DeviceGroupName: groupName, Changed: []string{}, Unchanged: []string{}}
+	for _, imsi := range imsis {
+		switch {
+		case add && devGroup.ContainsImsi(imsi):
+			result.Unchanged = append(result.Unchanged, imsi)
+		case add:
+			result.Changed = append(result.Changed, imsi)
+		case slices.Contains(devGroup.Imsis, imsi):
+			result.Changed = append(result.Changed, imsi)
+		case devGroup.ContainsImsi(imsi):
+			return nil, http.StatusBadRequest, fmt.Errorf("%w: %s", errImsiCoveredByRange, imsi)
+		default:
+			result.Unchanged = append(result.Unchanged, imsi)
+		}
+	}
+	if len(result.Changed) == 0 {
+		return result, http.StatusOK, nil
+	}
+
+	filter := bson.M{"group-name": groupName}
+	var added, removed []string
+	if add {
+		if statusCode, err := validateDeviceGroupImsisAdd(devGroup, result.Changed); err != nil {
+			return nil, statusCode, err
+		}
+		err := dbadapter.CommonDBClient.RestfulAPIAddToSetOne(devGroupDataColl, filter, bson.M{"imsis": bson.M{"$each": result.Changed}})
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		added = result.Changed
+	} else {
+		err := dbadapter.CommonDBClient.RestfulAPIPullOne(devGroupDataColl, filter, bson.M{"imsis": bson.M{"$in": result.Changed}})
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		removed = result.Changed
+	}
+	logger.ConfigLog.Infof("device group %s: changed IMSIs %v (add=%t)", groupName, result.Changed, add)
+
+	job, err := enqueueDeviceGroupImsisSyncJob(groupName, added, removed)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	result.Job = job
+	return result, http.StatusAccepted, nil
+}
+
+// validateDeviceGroupImsisAdd applies to the IMSIs being added the checks of a
+// device group update: they must not belong to another device group and the UE
+// IP pools must still be large enough.
+func validateDeviceGroupImsisAdd(devGroup *configmodels.DeviceGroups, imsis []string) (int, error) {
+	filter := bson.M{
+		"group-name": bson.M{"$ne": devGroup.DeviceGroupName},
+		"$or": []bson.M{
+			{"imsis": bson.M{"$in": imsis}},
+			{"imsi-ranges.0": bson.M{"$exists": true}},
+		},
+	}
+	rawDeviceGroups, err := dbadapter.CommonDBClient.RestfulAPIGetMany(devGroupDataColl, filter)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, rawDeviceGroup := range rawDeviceGroups {
+		var other configmodels.DeviceGroups
+		if err = json.Unmarshal(configmodels.MapToByte(rawDeviceGroup), &other); err != nil {
+			return http.StatusInternalServerError, err
+		}
+		for _, imsi := range imsis {
+			if other.ContainsImsi(imsi) {
+				return http.StatusConflict, fmt.Errorf("%w: %s belongs to device group %s", errImsiInOtherDeviceGroup, imsi, other.DeviceGroupName)
+			}
+		}
+	}
+	updated := *devGroup
+	updated.Imsis = append(slices.Clone(devGroup.Imsis), imsis...)
+	return validateDeviceGroupIpPool(&updated)
+}
+
+// syncDeviceGroupImsis syncs the IMSIs added to or removed from a device
+// group. The device group is read when the job runs, so an IMSI whose
+// membership changed again since the job was queued is left to the later job.
+func syncDeviceGroupImsis(groupName string, added, removed []string, progress *syncJobProgress) error {
+	rwLock.Lock()
+	defer rwLock.Unlock()
+	devGroup := getDeviceGroupByName(groupName)
+	if devGroup == nil {
+		return fmt.Errorf("could not read device group %s", groupName)
+	}
+	if devGroup.DeviceGroupName == "" {
+		logger.WebUILog.Infof("Device group %s no longer exists — skipping sync", groupName)
+		return nil
+	}
+	added = slices.DeleteFunc(slices.Clone(added), func(imsi string) bool {
+		return !devGroup.ContainsImsi(imsi)
+	})
+	removed = slices.DeleteFunc(slices.Clone(removed), devGroup.ContainsImsi)
+	var errs []error
+	if len(added) > 0 {
+		if err := syncChangedDeviceGroupImsis(devGroup, added, true, progress); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(removed) > 0 {
+		if err := syncChangedDeviceGroupImsis(devGroup, removed, false, progress); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func syncChangedDeviceGroupImsis(devGroup *configmodels.DeviceGroups, imsis []string, add bool, progress *syncJobProgress) error {
+	slice := findSliceByDeviceGroup(devGroup.DeviceGroupName)
+	if slice == nil {
+		logger.WebUILog.Infof("Device group %s not associated with any slice — skipping sync", devGroup.DeviceGroupName)
+		return nil
+	}
+	mcc, mnc := slice.SiteInfo.Plmn.Mcc, slice.SiteInfo.Plmn.Mnc
+	if !add {
+		if err := removeSubscribersRelatedToDeviceGroups(mcc, mnc, imsis, nil); err != nil {
+			progress.fail(imsis...)
+			return err
+		}
+		progress.done(len(imsis))
+		return nil
+	}
+	sVal, err := strconv.ParseUint(slice.SliceId.Sst, 10, 32)
+	if err != nil {
+		return fmt.Errorf("could not parse SST %s of slice %s: %w", slice.SliceId.Sst, slice.SliceName, err)
+	}
+	snssai := &models.Snssai{
+		Sd:  slice.SliceId.Sd,
+		Sst: int32(sVal),
+	}
+	existing, err := filterExistingIMSIsFromAuthDB(imsis)
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+	if err = updatePolicyAndProvisionedDataBatch(existing, mcc, mnc, snssai, devGroup.GetIpDomains()); err != nil {
+		progress.fail(existing...)
+		return err
+	}
+	progress.done(len(existing))
+	return nil
+}
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type deviceGroupImsisMock struct {
+	mu          sync.Mutex
+	mockDB      *dbadapter.MockDBClient
+	addToSet    []bson.M
+	pulled      []bson.M
+	deletedUeId []string
+	jobs        map[string]map[string]any
+}
+
+func newDeviceGroupImsisMock(devGroup *configmodels.DeviceGroups, slice *configmodels.Slice, otherDevGroups ...*configmodels.DeviceGroups) *deviceGroupImsisMock {
+	m := &deviceGroupImsisMock{jobs: map[string]map[string]any{}}
+	m.mockDB = &dbadapter.MockDBClient{
+		GetOneFn: func(collName string, filter bson.M) (map[string]any, error) {
+			if collName == configmodels.SyncJobsColl {
+				m.mu.Lock()
+				defer m.mu.Unlock()
+				return m.jobs[filter["job-id"].(string)], nil
+			}
+			if collName == devGroupDataColl && devGroup != nil {
+				m.mu.Lock()
+				defer m.mu.Unlock()
+				return configmodels.ToBsonM(devGroup), nil
+			}
+			return nil, nil
+		},
+		GetManyFn: func(collName string, filter bson.M) ([]map[string]any, error) {
+			var docs []map[string]any
+			switch collName {
+			case sliceDataColl:
+				if slice != nil {
+					docs = append(docs, configmodels.ToBsonM(slice))
+				}
+			case devGroupDataColl:
+				if _, others := filter["group-name"]; !others && devGroup != nil {
+					m.mu.Lock()
+					docs = append(docs, configmodels.ToBsonM(devGroup))
+					m.mu.Unlock()
+				}
+				for _, other := range otherDevGroups {
+					docs = append(docs, configmodels.ToBsonM(other))
+				}
+			}
+			return docs, nil
+		},
+		PostFn: func(collName string, filter bson.M, postData map[string]any) (bool, error) {
+			if collName == configmodels.SyncJobsColl {
+				m.mu.Lock()
+				m.jobs[filter["job-id"].(string)] = postData
+				m.mu.Unlock()
+			}
+			return true, nil
+		},
+		AddToSetOneFn: func(collName string, filter bson.M, addData map[string]any) error {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.addToSet = append(m.addToSet, addData)
+			devGroup.Imsis = append(devGroup.Imsis, addData["imsis"].(bson.M)["$each"].([]string)...)
+			return nil
+		},
+		PullOneFn: func(collName string, filter bson.M, putData map[string]any) error {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.pulled = append(m.pulled, putData)
+			pulled := putData["imsis"].(bson.M)["$in"].([]string)
+			devGroup.Imsis = slices.DeleteFunc(devGroup.Imsis, func(imsi string) bool {
+				return slices.Contains(pulled, imsi)
+			})
+			return nil
+		},
+		DeleteOneWithContextFn: func(ctx context.Context, collName string, filter bson.M) error {
+			if collName == AmDataColl {
+				m.mu.Lock()
+				m.deletedUeId = append(m.deletedUeId, filter["ueId"].(string))
+				m.mu.Unlock()
+			}
+			return nil
+		},
+		StartSessionFn: func() (mongo.Session, error) {
+			return &MockSession{}, nil
+		},
+	}
+	return m
+}
+
+func performDeviceGroupImsisRequest(t *testing.T, db dbadapter.DBInterface, method, body string) (*httptest.ResponseRecorder, configmodels.DeviceGroupImsisResult) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	AddConfigV1Service(router)
+
+	origDBClient := dbadapter.CommonDBClient
+	origAuthDBClient := dbadapter.AuthDBClient
+	originalConfig := factory.WebUIConfig
+	t.Cleanup(func() {
+		dbadapter.CommonDBClient = origDBClient
+		dbadapter.AuthDBClient = origAuthDBClient
+		factory.WebUIConfig = originalConfig
+	})
+	factory.WebUIConfig = &factory.Config{Configuration: &factory.Configuration{Mongodb: &factory.Mongodb{ConcurrencyOps: 5}}}
+	dbadapter.CommonDBClient = db
+	dbadapter.AuthDBClient = nil
+
+	req := httptest.NewRequest(method, "/config/v1/device-group/group1/imsis", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var result configmodels.DeviceGroupImsisResult
+	_ = json.Unmarshal(w.Body.Bytes(), &result)
+	return w, result
+}
+
+func deviceGroupImsisTestGroup() *configmodels.DeviceGroups {
+	return &configmodels.DeviceGroups{
+		DeviceGroupName: "group1",
+		Imsis:           []string{"001010000000001", "001010000000002"},
+		ImsiRanges:      []configmodels.DeviceGroupsImsiRange{{Start: "001010000000100", End: "001010000000199"}},
+	}
+}
+
+func TestPostDeviceGroupImsis_AddsOnlyNewImsis(t *testing.T) {
+	m := newDeviceGroupImsisMock(deviceGroupImsisTestGroup(), nil)
+	body := `{"imsis": ["001010000000001", "imsi-001010000000003", "001010000000150", "001010000000003"]}`
+	w, result := performDeviceGroupImsisRequest(t, m.mockDB, http.MethodPost, body)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	if result.Job == nil || w.Header().Get("Location") != "/config/v1/jobs/"+result.Job.JobId {
+		t.Errorf("expected a queued sync job, got %+v (Location %q)", result.Job, w.Header().Get("Location"))
+	}
+	if !reflect.DeepEqual(result.Changed, []string{"001010000000003"}) {
+		t.Errorf("unexpected changed IMSIs: %v", result.Changed)
+	}
+	if !reflect.DeepEqual(result.Unchanged, []string{"001010000000001", "001010000000150"}) {
+		t.Errorf("unexpected unchanged IMSIs: %v", result.Unchanged)
+	}
+	expected := []bson.M{{"imsis": bson.M{"$each": []string{"001010000000003"}}}}
+	if !reflect.DeepEqual(m.addToSet, expected) {
+		t.Errorf("expected $addToSet %v, got %v", expected, m.addToSet)
+	}
+	if result.Job != nil {
+		if job := waitForSyncJob(t, result.Job.JobId); job.Status != configmodels.SyncJobStatusSucceeded {
+			t.Errorf("unexpected finished job: %+v", job)
+		}
+	}
+}
+
+func TestDeleteDeviceGroupImsis_RemovesAndCleansUpOnlyChangedImsis(t *testing.T) {
+	slice := &configmodels.Slice{
+		SliceName:       "slice1",
+		SliceId:         configmodels.SliceSliceId{Sst: "1", Sd: "010203"},
+		SiteDeviceGroup: []string{"group1"},
+		SiteInfo:        configmodels.SliceSiteInfo{Plmn: configmodels.SliceSiteInfoPlmn{Mcc: "001", Mnc: "01"}},
+	}
+	m := newDeviceGroupImsisMock(deviceGroupImsisTestGroup(), slice)
+	w, result := performDeviceGroupImsisRequest(t, m.mockDB, http.MethodDelete, `{"imsis": ["001010000000002", "001010000000009"]}`)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	if !reflect.DeepEqual(result.Changed, []string{"001010000000002"}) || !reflect.DeepEqual(result.Unchanged, []string{"001010000000009"}) {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	expected := []bson.M{{"imsis": bson.M{"$in": []string{"001010000000002"}}}}
+	if !reflect.DeepEqual(m.pulled, expected) {
+		t.Errorf("expected $pull %v, got %v", expected, m.pulled)
+	}
+	job := waitForSyncJob(t, result.Job.JobId)
+	if job.Status != configmodels.SyncJobStatusSucceeded || job.Processed != 1 {
+		t.Errorf("unexpected finished job: %+v", job)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !slices.Equal(m.deletedUeId, []string{"imsi-001010000000002"}) {
+		t.Errorf("expected only the removed IMSI to be cleaned up, got %v", m.deletedUeId)
+	}
+}
+
+func TestDeleteDeviceGroupImsis_RangeMemberRejected(t *testing.T) {
+	m := newDeviceGroupImsisMock(deviceGroupImsisTestGroup(), nil)
+	w, _ := performDeviceGroupImsisRequest(t, m.mockDB, http.MethodDelete, `{"imsis": ["001010000000150"]}`)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+	if len(m.pulled) != 0 {
+		t.Errorf("expected no $pull, got %v", m.pulled)
+	}
+}
+
+func TestPostDeviceGroupImsis_InvalidRequests(t *testing.T) {
+	testCases := []struct {
+		name     string
+		devGroup *configmodels.DeviceGroups
+		body     string
+		expected int
+	}{
+		{"group not found", nil, `{"imsis": ["001010000000003"]}`, http.StatusNotFound},
+		{"empty list", deviceGroupImsisTestGroup(), `{"imsis": []}`, http.StatusBadRequest},
+		{"invalid IMSI", deviceGroupImsisTestGroup(), `{"imsis": ["00101abc"]}`, http.StatusBadRequest},
+		{"invalid JSON", deviceGroupImsisTestGroup(), `{"imsis": "001010000000003"}`, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newDeviceGroupImsisMock(tc.devGroup, nil)
+			w, _ := performDeviceGroupImsisRequest(t, m.mockDB, http.MethodPost, tc.body)
+			if w.Code != tc.expected {
+				t.Errorf("expected %d, got %d (body: %s)", tc.expected, w.Code, w.Body.String())
+			}
+			if len(m.addToSet) != 0 {
+				t.Errorf("expected no $addToSet, got %v", m.addToSet)
+			}
+		})
+	}
+}
+
+func TestPostDeviceGroupImsis_ImsiInOtherDeviceGroupRejected(t *testing.T) {
+	other := &configmodels.DeviceGroups{
+		DeviceGroupName: "group2",
+		ImsiRanges:      []configmodels.DeviceGroupsImsiRange{{Start: "001010000000200", End: "001010000000299"}},
+	}
+	m := newDeviceGroupImsisMock(deviceGroupImsisTestGroup(), nil, other)
+	w, _ := performDeviceGroupImsisRequest(t, m.mockDB, http.MethodPost, `{"imsis": ["001010000000003", "001010000000250"]}`)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusConflict, w.Code, w.Body.String())
+	}
+	if len(m.addToSet) != 0 || len(m.jobs) != 0 {
+		t.Errorf("expected no $addToSet and no job, got %v and %d jobs", m.addToSet, len(m.jobs))
+	}
+}
+
+func TestPostDeviceGroupImsis_UeIpPoolTooSmallRejected(t *testing.T) {
+	group := &configmodels.DeviceGroups{
+		DeviceGroupName: "group1",
+		Imsis:           []string{"001010000000001", "001010000000002"},
+		IpDomainName:    "pool1",
+		IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{
+			Dnn:      "internet",
+			UeIpPool: "10.0.0.0/31",
+		},
+	}
+	m := newDeviceGroupImsisMock(group, nil)
+	w, _ := performDeviceGroupImsisRequest(t, m.mockDB, http.MethodPost, `{"imsis": ["001010000000003"]}`)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+	if len(m.addToSet) != 0 {
+		t.Errorf("expected no $addToSet, got %v", m.addToSet)
+	}
+}
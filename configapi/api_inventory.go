@@ -704,7 +704,7 @@ func updateInventoryInNetworkSlices(filter bson.M, updateFunc func(*configmodels
 		}
 		prevSlice := getSliceByName(networkSlice.SliceName)
 		updateFunc(&networkSlice)
-		if statusCode, err := updateNS(networkSlice, *prevSlice); err != nil {
+		if _, statusCode, err := updateNS(networkSlice, *prevSlice); err != nil {
 			logger.ConfigLog.Errorf("error updating slice %s: %+v", networkSlice.SliceName, err)
 			return statusCode, err
 		}
@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/logger"
+)
+
+// GetIpPools godoc
+//
+// @Description  Return the UE IP pool of every device group with its size, assigned IMSIs, headroom and overlapping pools
+// @Tags         Device Groups
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   configmodels.IpPool  "UE IP pools"
+// @Failure      401  {object}  nil  "Authorization failed"
+// @Failure      403  {object}  nil  "Forbidden"
+// @Failure      500  {object}  nil  "Error retrieving UE IP pools"
+// @Router       /config/v1/ip-pools  [get]
+func GetIpPools(c *gin.Context) {
+	setCorsHeader(c)
+	logger.WebUILog.Infoln("Get all UE IP pools")
+	ipPools, err := getIpPools()
+	if err != nil {
+		logger.AppLog.Errorln(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch UE IP pools"})
+		return
+	}
+	c.JSON(http.StatusOK, ipPools)
+}
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func ipPoolDeviceGroup(name, dnn, pool string, imsis ...string) configmodels.DeviceGroups {
+	devGroup := deviceGroup(name)
+	devGroup.Imsis = imsis
+	devGroup.IpDomainExpanded.Dnn = dnn
+	devGroup.IpDomainExpanded.UeIpPool = pool
+	return devGroup
+}
+
+func ipPoolSlice(name, upf string, devGroups ...string) configmodels.Slice {
+	slice := networkSlice(name)
+	slice.SiteDeviceGroup = devGroups
+	slice.SiteInfo.Upf = map[string]any{"upf-name": upf, "upf-port": "8805"}
+	return slice
+}
+
+func useIpPoolsMock(t *testing.T, devGroups []configmodels.DeviceGroups, networkSlices []configmodels.Slice) {
+	t.Helper()
+	origDBClient := dbadapter.CommonDBClient
+	t.Cleanup(func() { dbadapter.CommonDBClient = origDBClient })
+	dbadapter.CommonDBClient = &dbadapter.MockDBClient{
+		GetManyFn: func(collName string, filter bson.M) ([]map[string]any, error) {
+			var results []map[string]any
+			switch collName {
+			case devGroupDataColl:
+				for _, devGroup := range devGroups {
+					results = append(results, configmodels.ToBsonM(devGroup))
+				}
+			case sliceDataColl:
+				for _, slice := range networkSlices {
+					results = append(results, configmodels.ToBsonM(slice))
+				}
+			}
+			return results, nil
+		},
+	}
+}
+
+func TestIpPoolSize(t *testing.T) {
+	testCases := []struct {
+		pool     string
+		expected uint64
+	}{
+		{"10.0.0.0/24", 256},
+		{"10.0.0.7/32", 1},
+		{"172.250.1.0/16", 65536},
+		{"2001:db8::/120", 256},
+		{"2001:db8::/32", math.MaxUint64},
+	}
+	for _, tc := range testCases {
+		prefix, err := parseUeIpPool(tc.pool)
+		if err != nil {
+			t.Fatalf("could not parse %s: %+v", tc.pool, err)
+		}
+		if size := ipPoolSize(prefix); size != tc.expected {
+			t.Errorf("expected size of %s to be %d, got %d", tc.pool, tc.expected, size)
+		}
+	}
+	if _, err := parseUeIpPool("10.0.0.0"); !errors.Is(err, errInvalidUeIpPool) {
+		t.Errorf("expected %v, got %v", errInvalidUeIpPool, err)
+	}
+}
+
+func TestValidateDeviceGroupIpPool(t *testing.T) {
+	existing := []configmodels.DeviceGroups{
+		ipPoolDeviceGroup("group1", "internet", "10.0.0.0/24"),
+		ipPoolDeviceGroup("group2", "enterprise", "10.1.0.0/24"),
+	}
+	networkSlices := []configmodels.Slice{
+		ipPoolSlice("slice1", "upf1", "group1", "group3"),
+		ipPoolSlice("slice2", "upf2", "group2", "group4"),
+	}
+	testCases := []struct {
+		name        string
+		devGroup    configmodels.DeviceGroups
+		expectedErr error
+		statusCode  int
+	}{
+		{"disjoint pool", ipPoolDeviceGroup("group3", "internet", "10.0.1.0/24"), nil, http.StatusOK},
+		{"update keeps own pool", ipPoolDeviceGroup("group1", "internet", "10.0.0.0/25"), nil, http.StatusOK},
+		{"overlap on shared slice", ipPoolDeviceGroup("group3", "iot", "10.0.0.128/25"), errUeIpPoolOverlap, http.StatusConflict},
+		{"overlap on shared DNN", ipPoolDeviceGroup("group5", "enterprise", "10.1.0.0/16"), errUeIpPoolOverlap, http.StatusConflict},
+		{"overlap without shared scope", ipPoolDeviceGroup("group5", "iot", "10.1.0.0/16"), nil, http.StatusOK},
+		{"invalid CIDR", ipPoolDeviceGroup("group3", "internet", "10.0.1.0/33"), errInvalidUeIpPool, http.StatusBadRequest},
+		{"pool too small", ipPoolDeviceGroup("group3", "internet", "10.0.1.0/31", "001010000000001", "001010000000002", "001010000000003"), errUeIpPoolTooSmall, http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			useIpPoolsMock(t, existing, networkSlices)
+			statusCode, err := validateDeviceGroupIpPool(&tc.devGroup)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+			}
+			if statusCode != tc.statusCode {
+				t.Errorf("expected status code %d, got %d", tc.statusCode, statusCode)
+			}
+		})
+	}
+}
+
+func TestValidateSliceIpPools(t *testing.T) {
+	existing := []configmodels.DeviceGroups{
+		ipPoolDeviceGroup("group1", "internet", "10.0.0.0/24"),
+		ipPoolDeviceGroup("group2", "enterprise", "10.0.0.0/16"),
+	}
+	networkSlices := []configmodels.Slice{ipPoolSlice("slice2", "upf2", "group2")}
+	testCases := []struct {
+		name        string
+		slice       configmodels.Slice
+		expectedErr error
+	}{
+		{"device groups on separate UPFs", ipPoolSlice("slice1", "upf1", "group1"), nil},
+		{"overlapping device groups on the slice", ipPoolSlice("slice1", "upf1", "group1", "group2"), errUeIpPoolOverlap},
+		{"overlapping device groups on the UPF", ipPoolSlice("slice1", "upf2", "group1"), errUeIpPoolOverlap},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			useIpPoolsMock(t, existing, networkSlices)
+			_, err := validateSliceIpPools(tc.slice)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestGetIpPools(t *testing.T) {
+	group3 := ipPoolDeviceGroup("group3", "internet", "10.0.0.0/30", "001010000000001")
+	group3.ImsiRanges = []configmodels.DeviceGroupsImsiRange{{Start: "001010000000100", End: "001010000000104"}}
+	devGroups := []configmodels.DeviceGroups{
+		ipPoolDeviceGroup("group2", "internet", "10.0.0.0/24", "001010000000002"),
+		ipPoolDeviceGroup("group1", "internet", "10.1.0.0/24"),
+		group3,
+		ipPoolDeviceGroup("group4", "internet", "bad-pool"),
+	}
+	useIpPoolsMock(t, devGroups, []configmodels.Slice{ipPoolSlice("slice1", "upf1", "group1", "group2")})
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	AddConfigV1Service(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/config/v1/ip-pools", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+	}
+	var ipPools []configmodels.IpPool
+	if err := json.Unmarshal(w.Body.Bytes(), &ipPools); err != nil {
+		t.Fatalf("could not unmarshal response: %+v", err)
+	}
+	for i := range ipPools {
+		ipPools[i].Error = ""
+	}
+	expected := []configmodels.IpPool{
+		{DeviceGroupName: "group1", Dnn: "internet", UeIpPool: "10.1.0.0/24", Slices: []string{"slice1"}, Upfs: []string{"upf1"}, Size: 256, Headroom: 256},
+		{DeviceGroupName: "group2", Dnn: "internet", UeIpPool: "10.0.0.0/24", Slices: []string{"slice1"}, Upfs: []string{"upf1"}, Size: 256, AssignedImsis: 1, Headroom: 255, Overlaps: []string{"group3"}},
+		{DeviceGroupName: "group3", Dnn: "internet", UeIpPool: "10.0.0.0/30", Size: 4, AssignedImsis: 6, Headroom: -2, Overlaps: []string{"group2"}},
+		{DeviceGroupName: "group4", Dnn: "internet", UeIpPool: "bad-pool"},
+	}
+	if !reflect.DeepEqual(ipPools, expected) {
+		t.Errorf("expected %+v, got %+v", expected, ipPools)
+	}
+}
+
+func TestIpPoolValidators_DatabaseError(t *testing.T) {
+	origDBClient := dbadapter.CommonDBClient
+	t.Cleanup(func() { dbadapter.CommonDBClient = origDBClient })
+	dbadapter.CommonDBClient = &dbadapter.MockDBClient{
+		GetManyFn: func(collName string, filter bson.M) ([]map[string]any, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	devGroup := ipPoolDeviceGroup("group1", "internet", "10.0.0.0/24")
+	if statusCode, err := validateDeviceGroupIpPool(&devGroup); err == nil || statusCode != http.StatusInternalServerError {
+		t.Errorf("expected the device group check to fail with %d, got %d (%v)", http.StatusInternalServerError, statusCode, err)
+	}
+	if statusCode, err := validateSliceIpPools(ipPoolSlice("slice1", "upf1", "group1")); err == nil || statusCode != http.StatusInternalServerError {
+		t.Errorf("expected the slice check to fail with %d, got %d (%v)", http.StatusInternalServerError, statusCode, err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	AddConfigV1Service(router)
+	req := httptest.NewRequest(http.MethodGet, "/config/v1/ip-pools", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected %d, got %d (body: %s)", http.StatusInternalServerError, w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,523 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omec-project/openapi/models"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	subscriberBulkMaxRows   = 10000
+	subscriberBulkBatchSize = 500
+
+	subscriberBulkModeBestEffort = "best-effort"
+	subscriberBulkModeAtomic     = "all-or-nothing"
+)
+
+// csv header aliases accepted in addition to the JSON field names
+var subscriberBulkCsvColumns = map[string]string{
+	"ueid":                "ueId",
+	"imsi":                "ueId",
+	"plmnid":              "plmnID",
+	"opc":                 "opc",
+	"key":                 "key",
+	"ki":                  "key",
+	"sequencenumber":      "sequenceNumber",
+	"sqn":                 "sequenceNumber",
+	"k4_sno":              "k4_sno",
+	"k4sno":               "k4_sno",
+	"encryptionalgorithm": "encryptionAlgorithm",
+}
+
+type subscriberBulkRow struct {
+	index        int
+	ueId         string
+	authSubsData *models.AuthenticationSubscription
+}
+
+// PostSubscribersBulk godoc
+//
+// @Description  Create many subscribers from a JSON array or a CSV file. Every row is validated before any write; the response reports each row as created, conflict, invalid or failed. Use mode=all-or-nothing to write nothing unless every row can be created
+// @Tags         Subscribers
+// @Accept       json,text/csv
+// @Produce      json
+// @Param        mode       query   string                             false   "best-effort (default) or all-or-nothing"
+// @Param        content    body    []configmodels.SubsBulkEntry       true    "Subscribers to create"
+// @Security     BearerAuth
+// @Success      200  {object}  configmodels.SubsBulkReport  "Per-row provisioning report"
+// @Failure      400  {object}  nil  "Invalid request body or mode"
+// @Failure      401  {object}  nil  "Authorization failed"
+// @Failure      403  {object}  nil  "Forbidden"
+// @Failure      409  {object}  configmodels.SubsBulkReport  "All-or-nothing request rejected"
+// @Failure      415  {object}  nil  "Unsupported media type"
+// @Failure      500  {object}  nil  "Error creating subscribers"
+// @Router       /api/subscriber:bulk  [post]
+func PostSubscribersBulk(c *gin.Context) {
+	setCorsHeader(c)
+	logger.WebUILog.Infoln("Post Subscribers Bulk")
+	requestID := uuid.New().String()
+
+	// registered as /subscriber:action since gin only matches a literal colon with a wildcard
+	if c.Param("action") != ":bulk" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found", "request_id": requestID})
+		return
+	}
+
+	mode := c.DefaultQuery("mode", subscriberBulkModeBestEffort)
+	if mode != subscriberBulkModeBestEffort && mode != subscriberBulkModeAtomic {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid mode %q: use %s or %s", mode, subscriberBulkModeBestEffort, subscriberBulkModeAtomic), "request_id": requestID})
+		return
+	}
+
+	var entries []configmodels.SubsBulkEntry
+	var err error
+	switch c.ContentType() {
+	case "application/json":
+		err = json.NewDecoder(c.Request.Body).Decode(&entries)
+	case "text/csv":
+		entries, err = parseSubscribersBulkCsv(c.Request.Body)
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be application/json or text/csv", "request_id": requestID})
+		return
+	}
+	if err != nil {
+		logger.WebUILog.Errorf("Post Subscribers Bulk - failed to parse body: %+v request ID: %s", err, requestID)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request body: %v", err), "request_id": requestID})
+		return
+	}
+	if len(entries) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: no subscribers provided", "request_id": requestID})
+		return
+	}
+	if len(entries) > subscriberBulkMaxRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("too many subscribers: at most %d rows per request", subscriberBulkMaxRows), "request_id": requestID})
+		return
+	}
+
+	report := configmodels.SubsBulkReport{
+		Mode:    mode,
+		Total:   len(entries),
+		Results: make([]configmodels.SubsBulkResult, len(entries)),
+	}
+	rows, err := validateSubscribersBulk(entries, report.Results)
+	if err != nil {
+		logger.WebUILog.Errorf("Post Subscribers Bulk - validation failed: %+v request ID: %s", err, requestID)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to validate subscribers",
+			"request_id": requestID,
+			"message":    "Please refer to the log with the provided Request ID for details",
+		})
+		return
+	}
+	rows, err = markExistingSubscribersBulk(rows, report.Results)
+	if err != nil {
+		logger.WebUILog.Errorf("Post Subscribers Bulk - existence check failed: %+v request ID: %s", err, requestID)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to check subscribers existence",
+			"request_id": requestID,
+			"message":    "Please refer to the log with the provided Request ID for details",
+		})
+		return
+	}
+
+	if mode == subscriberBulkModeAtomic {
+		if len(rows) != len(entries) {
+			for _, row := range rows {
+				report.Results[row.index].Status = configmodels.SubsBulkStatusNotCreated
+			}
+			summarizeSubscribersBulk(&report)
+			c.JSON(http.StatusConflict, report)
+			return
+		}
+		if err = writeSubscribersBulkAtomic(rows); err != nil {
+			logger.WebUILog.Errorf("Post Subscribers Bulk - atomic write failed: %+v request ID: %s", err, requestID)
+			for _, row := range rows {
+				report.Results[row.index].Status = configmodels.SubsBulkStatusFailed
+				report.Results[row.index].Error = "transaction aborted"
+			}
+		} else {
+			for _, row := range rows {
+				report.Results[row.index].Status = configmodels.SubsBulkStatusCreated
+			}
+		}
+	} else {
+		writeSubscribersBulk(rows, report.Results)
+	}
+
+	summarizeSubscribersBulk(&report)
+	logger.WebUILog.Infof("Post Subscribers Bulk - total: %d created: %d conflicts: %d invalid: %d failed: %d request ID: %s",
+		report.Total, report.Created, report.Conflicts, report.Invalid, report.Failed, requestID)
+	if report.Failed > 0 && report.Created == 0 {
+		c.JSON(http.StatusInternalServerError, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func parseSubscribersBulkCsv(body io.Reader) ([]configmodels.SubsBulkEntry, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		column, ok := subscriberBulkCsvColumns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		columns[i] = column
+	}
+
+	var entries []configmodels.SubsBulkEntry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		var entry configmodels.SubsBulkEntry
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			switch columns[i] {
+			case "ueId":
+				entry.UeId = value
+			case "plmnID":
+				entry.PlmnID = value
+			case "opc":
+				entry.OPc = value
+			case "key":
+				entry.Key = value
+			case "sequenceNumber":
+				entry.SequenceNumber = value
+			case "k4_sno":
+				sno, err := strconv.ParseUint(value, 10, 8)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid k4_sno %q", line, value)
+				}
+				k4Sno := byte(sno)
+				entry.K4Sno = &k4Sno
+			case "encryptionAlgorithm":
+				algorithm, err := strconv.ParseInt(value, 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid encryptionAlgorithm %q", line, value)
+				}
+				encryptionAlgorithm := int32(algorithm)
+				entry.EncryptionAlgorithm = &encryptionAlgorithm
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func normalizeSubscriberUeId(ueId string) string {
+	ueId = strings.TrimSpace(ueId)
+	if ueId != "" && !strings.HasPrefix(ueId, "imsi-") {
+		return "imsi-" + ueId
+	}
+	return ueId
+}
+
+func newAuthenticationSubscription(subsOverrideData *configmodels.SubsOverrideData) *models.AuthenticationSubscription {
+	authSubsData := &models.AuthenticationSubscription{
+		AuthenticationManagementField: "8000",
+		AuthenticationMethod:          "5G_AKA",
+		Milenage: &models.Milenage{
+			Op: &models.Op{
+				EncryptionAlgorithm: 0,
+				EncryptionKey:       0,
+				OpValue:             "",
+			},
+		},
+		Opc: &models.Opc{
+			OpcValue:            subsOverrideData.OPc,
+			EncryptionAlgorithm: 0,
+			EncryptionKey:       0,
+		},
+		PermanentKey: &models.PermanentKey{
+			PermanentKeyValue: subsOverrideData.Key,
+			EncryptionKey:     "",
+		},
+		SequenceNumber: subsOverrideData.SequenceNumber,
+	}
+	if subsOverrideData.EncryptionAlgorithm != nil {
+		authSubsData.PermanentKey.EncryptionAlgorithm = *subsOverrideData.EncryptionAlgorithm
+	}
+	if subsOverrideData.K4Sno != nil {
+		authSubsData.K4_SNO = *subsOverrideData.K4Sno
+	}
+	return authSubsData
+}
+
+// validateSubscribersBulk checks every entry and builds the authentication
+// subscription for the valid ones. Invalid entries are recorded in results.
+func validateSubscribersBulk(entries []configmodels.SubsBulkEntry, results []configmodels.SubsBulkResult) ([]subscriberBulkRow, error) {
+	rows := make([]subscriberBulkRow, 0, len(entries))
+	seen := make(map[string]int, len(entries))
+	k4Cache := make(map[string]string)
+	for i := range entries {
+		entry := &entries[i]
+		ueId := normalizeSubscriberUeId(entry.UeId)
+		results[i] = configmodels.SubsBulkResult{Row: i + 1, UeId: ueId}
+		invalid := func(msg string) {
+			results[i].Status = configmodels.SubsBulkStatusInvalid
+			results[i].Error = msg
+		}
+		if ueId == "" {
+			invalid("missing ueId")
+			continue
+		}
+		if _, err := strconv.ParseUint(strings.TrimPrefix(ueId, "imsi-"), 10, 64); err != nil {
+			invalid("ueId must be imsi- followed by digits")
+			continue
+		}
+		if first, ok := seen[ueId]; ok {
+			invalid(fmt.Sprintf("duplicate of row %d", first))
+			continue
+		}
+		seen[ueId] = i + 1
+		if entry.OPc == "" || entry.Key == "" || entry.SequenceNumber == "" {
+			invalid("missing required authentication data: OPc, Key and Sequence number must be provided")
+			continue
+		}
+		if entry.EncryptionAlgorithm != nil && (*entry.EncryptionAlgorithm < 0 || *entry.EncryptionAlgorithm > 8) {
+			invalid("encryption Algorithm is not valid: encryption Algorithm must be between 0 and 8")
+			continue
+		}
+
+		authSubsData := newAuthenticationSubscription(&entry.SubsOverrideData)
+		if entry.K4Sno != nil {
+			cacheKey := fmt.Sprintf("%d/%d", *entry.K4Sno, authSubsData.PermanentKey.EncryptionAlgorithm)
+			k4, ok := k4Cache[cacheKey]
+			if !ok {
+				if err := assingK4Key(entry.K4Sno, authSubsData); err != nil {
+					return nil, err
+				}
+				k4 = authSubsData.PermanentKey.EncryptionKey
+				k4Cache[cacheKey] = k4
+			}
+			authSubsData.PermanentKey.EncryptionKey = k4
+		}
+		rows = append(rows, subscriberBulkRow{index: i, ueId: ueId, authSubsData: authSubsData})
+	}
+	return rows, nil
+}
+
+// markExistingSubscribersBulk flags rows whose subscriber already exists and
+// returns the rows that can still be created.
+func markExistingSubscribersBulk(rows []subscriberBulkRow, results []configmodels.SubsBulkResult) ([]subscriberBulkRow, error) {
+	ueIds := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ueIds = append(ueIds, row.ueId)
+	}
+	existing := make(map[string]struct{})
+	for _, chunk := range chunkStrings(ueIds, imsiBatchSize) {
+		docs, err := dbadapter.CommonDBClient.RestfulAPIGetMany(AmDataColl, bson.M{"ueId": bson.M{"$in": chunk}})
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			if ueId, ok := doc["ueId"].(string); ok {
+				existing[ueId] = struct{}{}
+			}
+		}
+	}
+	remaining := make([]subscriberBulkRow, 0, len(rows))
+	for _, row := range rows {
+		if _, ok := existing[row.ueId]; ok {
+			results[row.index].Status = configmodels.SubsBulkStatusConflict
+			results[row.index].Error = fmt.Sprintf("subscriber %s already exists", row.ueId)
+			continue
+		}
+		remaining = append(remaining, row)
+	}
+	return remaining, nil
+}
+
+// subscriberBulkDocuments holds the documents of a batch of rows. Their IDs
+// are set before the insert, so that a rollback deletes only the documents
+// written by the request, and not the ones of the same subscribers created
+// concurrently by other requests.
+type subscriberBulkDocuments struct {
+	authDocs []any
+	amDocs   []any
+	authIDs  []primitive.ObjectID
+	amIDs    []primitive.ObjectID
+	ueIds    []string
+}
+
+func newSubscriberBulkDocuments(rows []subscriberBulkRow) *subscriberBulkDocuments {
+	docs := &subscriberBulkDocuments{}
+	for _, row := range rows {
+		authID, amID := primitive.NewObjectID(), primitive.NewObjectID()
+		authDataBsonA := configmodels.ToBsonM(row.authSubsData)
+		authDataBsonA["_id"] = authID
+		authDataBsonA["ueId"] = row.ueId
+		docs.authDocs = append(docs.authDocs, authDataBsonA)
+		docs.amDocs = append(docs.amDocs, bson.M{"_id": amID, "ueId": row.ueId})
+		docs.authIDs = append(docs.authIDs, authID)
+		docs.amIDs = append(docs.amIDs, amID)
+		docs.ueIds = append(docs.ueIds, row.ueId)
+	}
+	return docs
+}
+
+// rollback deletes the documents of the batch, those which were not written
+// are ignored.
+func (docs *subscriberBulkDocuments) rollback(amData bool) {
+	if amData {
+		if err := dbadapter.CommonDBClient.RestfulAPIDeleteMany(AmDataColl, bson.M{"_id": bson.M{"$in": docs.amIDs}}); err != nil {
+			logger.AppLog.Errorf("rollback failed for amData: %+v", err)
+		}
+	}
+	if err := dbadapter.AuthDBClient.RestfulAPIDeleteMany(AuthSubsDataColl, bson.M{"_id": bson.M{"$in": docs.authIDs}}); err != nil {
+		logger.AppLog.Errorf("rollback failed for authentication subscriptions: %+v", err)
+	}
+}
+
+func chunkSubscriberBulkRows(rows []subscriberBulkRow) [][]subscriberBulkRow {
+	var batches [][]subscriberBulkRow
+	for start := 0; start < len(rows); start += subscriberBulkBatchSize {
+		end := min(start+subscriberBulkBatchSize, len(rows))
+		batches = append(batches, rows[start:end])
+	}
+	return batches
+}
+
+// writeSubscribersBulk writes rows batch by batch. A batch whose amData write
+// fails is rolled back from the AuthDB so that both collections stay aligned.
+func writeSubscribersBulk(rows []subscriberBulkRow, results []configmodels.SubsBulkResult) {
+	for _, batch := range chunkSubscriberBulkRows(rows) {
+		docs := newSubscriberBulkDocuments(batch)
+		filter := bson.M{"ueId": bson.M{"$in": docs.ueIds}}
+		err := dbadapter.AuthDBClient.RestfulAPIPostMany(AuthSubsDataColl, filter, docs.authDocs)
+		if err != nil {
+			logger.AppLog.Errorf("failed to insert authentication subscription batch: %+v", err)
+			docs.rollback(false)
+		} else if err = dbadapter.CommonDBClient.RestfulAPIPostMany(AmDataColl, filter, docs.amDocs); err != nil {
+			logger.AppLog.Errorf("failed to insert amData batch: %+v", err)
+			docs.rollback(true)
+		}
+		for _, row := range batch {
+			if err != nil {
+				results[row.index].Status = configmodels.SubsBulkStatusFailed
+				results[row.index].Error = "failed to write subscriber batch"
+				continue
+			}
+			results[row.index].Status = configmodels.SubsBulkStatusCreated
+		}
+	}
+}
+
+// writeSubscribersBulkAtomic writes all rows or none. Each database uses a
+// transaction when Mongo supports them; otherwise the written documents are
+// removed again on failure.
+func writeSubscribersBulkAtomic(rows []subscriberBulkRow) error {
+	var batches []*subscriberBulkDocuments
+	for _, batch := range chunkSubscriberBulkRows(rows) {
+		batches = append(batches, newSubscriberBulkDocuments(batch))
+	}
+	writeAll := func(authCtx, commonCtx context.Context) error {
+		for _, docs := range batches {
+			filter := bson.M{"ueId": bson.M{"$in": docs.ueIds}}
+			if err := dbadapter.AuthDBClient.RestfulAPIPostManyWithContext(authCtx, AuthSubsDataColl, filter, docs.authDocs); err != nil {
+				return fmt.Errorf("failed to insert authentication subscriptions: %w", err)
+			}
+			if err := dbadapter.CommonDBClient.RestfulAPIPostManyWithContext(commonCtx, AmDataColl, filter, docs.amDocs); err != nil {
+				return fmt.Errorf("failed to insert amData: %w", err)
+			}
+		}
+		return nil
+	}
+
+	cleanup := func() {
+		for _, docs := range batches {
+			docs.rollback(true)
+		}
+	}
+
+	if !subscriberBulkTransactionsSupported() {
+		logger.AppLog.Warnln("MongoDB does not support transactions; all-or-nothing bulk write relies on compensating deletes")
+		if err := writeAll(context.TODO(), context.TODO()); err != nil {
+			cleanup()
+			return err
+		}
+		return nil
+	}
+
+	authRunner := dbadapter.GetSessionRunner(dbadapter.AuthDBClient)
+	commonRunner := dbadapter.GetSessionRunner(dbadapter.CommonDBClient)
+	commonCommitted := false
+	err := authRunner(context.TODO(), func(authSc mongo.SessionContext) error {
+		err := commonRunner(context.TODO(), func(commonSc mongo.SessionContext) error {
+			return writeAll(authSc, commonSc)
+		})
+		commonCommitted = err == nil
+		return err
+	})
+	if err != nil && commonCommitted {
+		// the AuthDB commit failed after the CommonDB transaction committed
+		cleanup()
+	}
+	return err
+}
+
+func subscriberBulkTransactionsSupported() bool {
+	for _, client := range []dbadapter.DBInterface{dbadapter.AuthDBClient, dbadapter.CommonDBClient} {
+		supported, err := client.SupportsTransactions()
+		if err != nil {
+			logger.AppLog.Warnf("could not verify transactions support: %+v", err)
+			return false
+		}
+		if !supported {
+			return false
+		}
+	}
+	return true
+}
+
+func summarizeSubscribersBulk(report *configmodels.SubsBulkReport) {
+	report.Created, report.Conflicts, report.Invalid, report.Failed = 0, 0, 0, 0
+	for _, result := range report.Results {
+		switch result.Status {
+		case configmodels.SubsBulkStatusCreated:
+			report.Created++
+		case configmodels.SubsBulkStatusConflict:
+			report.Conflicts++
+		case configmodels.SubsBulkStatusInvalid:
+			report.Invalid++
+		case configmodels.SubsBulkStatusFailed:
+			report.Failed++
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type BulkSubscriberMockDBClient struct {
+	dbadapter.DBInterface
+	existing             []string
+	supportsTransactions bool
+	postManyErr          error
+	postedDocs           map[string][]any
+	deletedFilters       map[string][]bson.M
+	sessionsStarted      int
+}
+
+func newBulkSubscriberMockDBClient(existing ...string) *BulkSubscriberMockDBClient {
+	return &BulkSubscriberMockDBClient{
+		existing:       existing,
+		postedDocs:     map[string][]any{},
+		deletedFilters: map[string][]bson.M{},
+	}
+}
+
+func (db *BulkSubscriberMockDBClient) RestfulAPIGetMany(collName string, filter bson.M) ([]map[string]any, error) {
+	var docs []map[string]any
+	in := filter["ueId"].(bson.M)["$in"].([]string)
+	for _, ueId := range in {
+		for _, existing := range db.existing {
+			if ueId == existing {
+				docs = append(docs, map[string]any{"ueId": ueId})
+			}
+		}
+	}
+	return docs, nil
+}
+
+func (db *BulkSubscriberMockDBClient) RestfulAPIPostMany(collName string, filter bson.M, postDataArray []any) error {
+	if db.postManyErr != nil && collName == AmDataColl {
+		return db.postManyErr
+	}
+	db.postedDocs[collName] = append(db.postedDocs[collName], postDataArray...)
+	return nil
+}
+
+func (db *BulkSubscriberMockDBClient) RestfulAPIPostManyWithContext(_ context.Context, collName string, filter bson.M, postDataArray []any) error {
+	return db.RestfulAPIPostMany(collName, filter, postDataArray)
+}
+
+func (db *BulkSubscriberMockDBClient) RestfulAPIDeleteMany(collName string, filter bson.M) error {
+	db.deletedFilters[collName] = append(db.deletedFilters[collName], filter)
+	return nil
+}
+
+func (db *BulkSubscriberMockDBClient) SupportsTransactions() (bool, error) {
+	return db.supportsTransactions, nil
+}
+
+func (db *BulkSubscriberMockDBClient) StartSession() (mongo.Session, error) {
+	db.sessionsStarted++
+	return &MockSession{}, nil
+}
+
+func performBulkRequest(t *testing.T, db dbadapter.DBInterface, query, contentType, body string) (*httptest.ResponseRecorder, configmodels.SubsBulkReport) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	AddApiService(router)
+
+	origDBClient := dbadapter.CommonDBClient
+	origAuthDBClient := dbadapter.AuthDBClient
+	t.Cleanup(func() {
+		dbadapter.CommonDBClient = origDBClient
+		dbadapter.AuthDBClient = origAuthDBClient
+	})
+	dbadapter.CommonDBClient = db
+	dbadapter.AuthDBClient = db
+
+	req, err := http.NewRequest(http.MethodPost, "/api/subscriber:bulk"+query, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var report configmodels.SubsBulkReport
+	_ = json.Unmarshal(w.Body.Bytes(), &report)
+	return w, report
+}
+
+const bulkSubscribersJSON = `[
+	{"ueId": "imsi-001010000000001", "opc": "8e27b6af0e692e750f32667a3b14605d", "key": "8baf473f2f8fd09487cccbd7097c6862", "sequenceNumber": "16f3b3f70fc2"},
+	{"ueId": "001010000000002", "opc": "8e27b6af0e692e750f32667a3b14605d", "key": "8baf473f2f8fd09487cccbd7097c6862", "sequenceNumber": "16f3b3f70fc2"},
+	{"ueId": "imsi-001010000000003", "opc": "8e27b6af0e692e750f32667a3b14605d", "sequenceNumber": "16f3b3f70fc2"},
+	{"ueId": "imsi-001010000000001", "opc": "8e27b6af0e692e750f32667a3b14605d", "key": "8baf473f2f8fd09487cccbd7097c6862", "sequenceNumber": "16f3b3f70fc2"},
+	{"ueId": "imsi-001010000000004", "opc": "8e27b6af0e692e750f32667a3b14605d", "key": "8baf473f2f8fd09487cccbd7097c6862", "sequenceNumber": "16f3b3f70fc2"}
+]`
+
+func TestPostSubscribersBulk_BestEffortReportsEachRow(t *testing.T) {
+	db := newBulkSubscriberMockDBClient("imsi-001010000000004")
+	w, report := performBulkRequest(t, db, "", "application/json", bulkSubscribersJSON)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+	}
+	expectedStatuses := []string{
+		configmodels.SubsBulkStatusCreated,
+		configmodels.SubsBulkStatusCreated,
+		configmodels.SubsBulkStatusInvalid,
+		configmodels.SubsBulkStatusInvalid,
+		configmodels.SubsBulkStatusConflict,
+	}
+	for i, expected := range expectedStatuses {
+		if report.Results[i].Status != expected {
+			t.Errorf("row %d: expected status %s, got %s (%s)", i+1, expected, report.Results[i].Status, report.Results[i].Error)
+		}
+	}
+	if report.Results[1].UeId != "imsi-001010000000002" {
+		t.Errorf("expected ueId to be normalized, got %s", report.Results[1].UeId)
+	}
+	if report.Created != 2 || report.Invalid != 2 || report.Conflicts != 1 {
+		t.Errorf("unexpected summary: %+v", report)
+	}
+	if len(db.postedDocs[AuthSubsDataColl]) != 2 || len(db.postedDocs[AmDataColl]) != 2 {
+		t.Errorf("expected 2 documents per collection, got %+v", db.postedDocs)
+	}
+}
+
+func TestPostSubscribersBulk_Csv(t *testing.T) {
+	body := "imsi,ki,opc,sqn,k4_sno\n" +
+		"001010000000001,8baf473f2f8fd09487cccbd7097c6862,8e27b6af0e692e750f32667a3b14605d,16f3b3f70fc2,\n" +
+		"001010000000002,8baf473f2f8fd09487cccbd7097c6862,8e27b6af0e692e750f32667a3b14605d,16f3b3f70fc2,\n"
+	db := newBulkSubscriberMockDBClient()
+	w, report := performBulkRequest(t, db, "", "text/csv", body)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+	}
+	if report.Created != 2 {
+		t.Errorf("expected 2 created subscribers, got %+v", report)
+	}
+	authDoc := db.postedDocs[AuthSubsDataColl][0].(bson.M)
+	if authDoc["ueId"] != "imsi-001010000000001" || authDoc["sequenceNumber"] != "16f3b3f70fc2" {
+		t.Errorf("unexpected authentication document: %+v", authDoc)
+	}
+}
+
+func TestPostSubscribersBulk_CsvUnknownColumn(t *testing.T) {
+	db := newBulkSubscriberMockDBClient()
+	w, _ := performBulkRequest(t, db, "", "text/csv", "imsi,colour\n001010000000001,blue\n")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestPostSubscribersBulk_AllOrNothingRejectsWhenAnyRowFails(t *testing.T) {
+	db := newBulkSubscriberMockDBClient("imsi-001010000000004")
+	w, report := performBulkRequest(t, db, "?mode=all-or-nothing", "application/json", bulkSubscribersJSON)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusConflict, w.Code, w.Body.String())
+	}
+	if report.Results[0].Status != configmodels.SubsBulkStatusNotCreated {
+		t.Errorf("expected valid rows to be reported as not created, got %s", report.Results[0].Status)
+	}
+	if len(db.postedDocs) != 0 {
+		t.Errorf("expected no writes, got %+v", db.postedDocs)
+	}
+}
+
+func TestPostSubscribersBulk_AllOrNothingUsesTransactions(t *testing.T) {
+	body := `[{"ueId": "imsi-001010000000001", "opc": "8e27b6af0e692e750f32667a3b14605d", "key": "8baf473f2f8fd09487cccbd7097c6862", "sequenceNumber": "16f3b3f70fc2"}]`
+	db := newBulkSubscriberMockDBClient()
+	db.supportsTransactions = true
+	w, report := performBulkRequest(t, db, "?mode=all-or-nothing", "application/json", body)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+	}
+	if report.Created != 1 {
+		t.Errorf("expected 1 created subscriber, got %+v", report)
+	}
+	if db.sessionsStarted != 2 {
+		t.Errorf("expected a session per database, got %d", db.sessionsStarted)
+	}
+}
+
+func TestPostSubscribersBulk_AllOrNothingCompensatesWithoutTransactions(t *testing.T) {
+	body := `[{"ueId": "imsi-001010000000001", "opc": "8e27b6af0e692e750f32667a3b14605d", "key": "8baf473f2f8fd09487cccbd7097c6862", "sequenceNumber": "16f3b3f70fc2"}]`
+	db := newBulkSubscriberMockDBClient()
+	db.postManyErr = errors.New("write failed")
+	w, report := performBulkRequest(t, db, "?mode=all-or-nothing", "application/json", body)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusInternalServerError, w.Code, w.Body.String())
+	}
+	if report.Failed != 1 {
+		t.Errorf("expected 1 failed row, got %+v", report)
+	}
+	if len(db.deletedFilters[AuthSubsDataColl]) != 1 {
+		t.Fatalf("expected authentication subscriptions to be rolled back, got %+v", db.deletedFilters)
+	}
+	expectInsertedDocumentsDeleted(t, db, AuthSubsDataColl)
+}
+
+// expectInsertedDocumentsDeleted checks that the rollback deletes the
+// documents written by the request by ID, and not every document of the same
+// subscribers.
+func expectInsertedDocumentsDeleted(t *testing.T, db *BulkSubscriberMockDBClient, collName string) {
+	t.Helper()
+	var insertedIDs []primitive.ObjectID
+	for _, doc := range db.postedDocs[collName] {
+		insertedIDs = append(insertedIDs, doc.(bson.M)["_id"].(primitive.ObjectID))
+	}
+	for _, filter := range db.deletedFilters[collName] {
+		if _, found := filter["ueId"]; found {
+			t.Errorf("expected the rollback not to delete by ueId, got %+v", filter)
+		}
+		deletedIDs, _ := filter["_id"].(bson.M)["$in"].([]primitive.ObjectID)
+		if len(deletedIDs) == 0 || !reflect.DeepEqual(deletedIDs, insertedIDs) {
+			t.Errorf("expected the inserted documents %v to be deleted, got %+v", insertedIDs, filter)
+		}
+	}
+}
+
+func TestPostSubscribersBulk_BestEffortRollsBackFailedBatch(t *testing.T) {
+	body := `[{"ueId": "imsi-001010000000001", "opc": "8e27b6af0e692e750f32667a3b14605d", "key": "8baf473f2f8fd09487cccbd7097c6862", "sequenceNumber": "16f3b3f70fc2"}]`
+	db := newBulkSubscriberMockDBClient()
+	db.postManyErr = errors.New("write failed")
+	w, report := performBulkRequest(t, db, "", "application/json", body)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusInternalServerError, w.Code, w.Body.String())
+	}
+	if report.Failed != 1 {
+		t.Errorf("expected 1 failed row, got %+v", report)
+	}
+	if len(db.deletedFilters[AuthSubsDataColl]) != 1 || len(db.deletedFilters[AmDataColl]) != 1 {
+		t.Fatalf("expected both collections to be rolled back, got %+v", db.deletedFilters)
+	}
+	expectInsertedDocumentsDeleted(t, db, AuthSubsDataColl)
+}
+
+func TestPostSubscribersBulk_UnknownActionNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	AddApiService(router)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/subscriber:import", strings.NewReader(`[]`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
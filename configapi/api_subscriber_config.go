@@ -9,15 +9,17 @@ package configapi
 
 import (
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
 	"net/http"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	ssm_constants "github.com/networkgcorefullcode/ssm/const"
@@ -30,12 +32,22 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
+const (
+	subscribersDefaultLimit = 50
+	subscribersMaxLimit     = 500
+)
+
 type subscribersPageResponse struct {
-	Items []configmodels.SubsListIE `json:"items"`
-	Page  int                       `json:"page"`
-	Limit int                       `json:"limit"`
-	Total int                       `json:"total"`
-	Pages int                       `json:"pages"`
+	Items      []configmodels.SubsListIE `json:"items"`
+	Page       int                       `json:"page"`
+	Limit      int                       `json:"limit"`
+	Total      int                       `json:"total"`
+	Pages      int                       `json:"pages"`
+	NextCursor string                    `json:"nextCursor,omitempty"`
+}
+
+type subscribersCursor struct {
+	UeId string `json:"ueId"`
 }
 
 func parsePositiveIntQuery(c *gin.Context, name string, defaultValue int) (int, error) {
@@ -84,7 +96,7 @@ func shouldReturnSubscribersMeta(c *gin.Context) bool {
 		return true
 	}
 	// Any query implies the client expects a structured response.
-	for _, key := range []string{"page", "limit", "plmnID", "ueId", "imsi", "q"} {
+	for _, key := range []string{"page", "limit", "plmnID", "ueId", "imsi", "q", "sort", "cursor"} {
 		if strings.TrimSpace(c.Query(key)) != "" {
 			return true
 		}
@@ -92,6 +104,51 @@ func shouldReturnSubscribersMeta(c *gin.Context) bool {
 	return false
 }
 
+// parseSubscribersSort reads the sort query parameter: ueId or plmnID,
+// prefixed with "-" for descending order. It returns the document field.
+func parseSubscribersSort(c *gin.Context) (string, bool, error) {
+	sortStr := strings.TrimSpace(c.Query("sort"))
+	descending := strings.HasPrefix(sortStr, "-")
+	switch strings.TrimPrefix(sortStr, "-") {
+	case "", "ueId":
+		return "ueId", descending, nil
+	case "plmnID":
+		return "servingPlmnId", descending, nil
+	default:
+		return "", false, fmt.Errorf("invalid sort")
+	}
+}
+
+func andSubscribersFilter(filter bson.M, extra bson.M) bson.M {
+	if len(filter) == 0 {
+		return extra
+	}
+	return bson.M{"$and": []bson.M{filter, extra}}
+}
+
+func encodeSubscribersCursor(ueId string) string {
+	data, err := json.Marshal(subscribersCursor{UeId: ueId})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSubscribersCursor(cursor string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", err
+	}
+	var decoded subscribersCursor
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		return "", err
+	}
+	if decoded.UeId == "" {
+		return "", fmt.Errorf("empty cursor")
+	}
+	return decoded.UeId, nil
+}
+
 var httpsClient *http.Client
 
 func init() {
@@ -320,11 +377,19 @@ func GetSampleJSON(c *gin.Context) {
 
 // GetSubscribers godoc
 //
-// @Description  Return the list of subscribers
+// @Description  Return the list of subscribers. Without query parameters the full list is returned as an array; otherwise a page is returned with its metadata
 // @Tags         Subscribers
 // @Produce      json
+// @Param        page      query   int       false   "Page number, starting at 1"
+// @Param        limit     query   int       false   "Page size (max 500)"
+// @Param        sort      query   string    false   "ueId or plmnID, prefixed with - for descending order"
+// @Param        cursor    query   string    false   "Opaque cursor from a previous nextCursor; only with ueId sort"
+// @Param        plmnID    query   string    false   "Filter by serving PLMN ID"
+// @Param        ueId      query   string    false   "Filter by exact UE ID"
+// @Param        q         query   string    false   "Case-insensitive UE ID search"
 // @Security     BearerAuth
 // @Success      200  {object}  configmodels.SubsListIE  "List of subscribers. Null if there are no subscribers"
+// @Failure      400  {object}  nil                      "Invalid query parameters"
 // @Failure      401  {object}  nil                      "Authorization failed"
 // @Failure      403  {object}  nil                      "Forbidden"
 // @Failure      500  {object}  nil                      "Error retrieving subscribers"
@@ -334,107 +399,140 @@ func GetSubscribers(c *gin.Context) {
 
 	logger.WebUILog.Infoln("Get All Subscribers List")
 
-	useMeta := shouldReturnSubscribersMeta(c)
 	filter := buildSubscribersFilter(c)
-
-	page := 1
-	limit := 50
-	if useMeta {
-		var err error
-		page, err = parsePositiveIntQuery(c, "page", 1)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		limit, err = parsePositiveIntQuery(c, "limit", 50)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		if limit > 500 {
-			limit = 500
-		}
+	if !shouldReturnSubscribersMeta(c) {
+		getSubscribersLegacyList(c, filter)
+		return
 	}
 
-	subsList := make([]configmodels.SubsListIE, 0)
-	amDataList, errGetMany := dbadapter.CommonDBClient.RestfulAPIGetMany(AmDataColl, filter)
-	if errGetMany != nil {
-		logger.AppLog.Errorf("failed to retrieve subscribers list with error: %+v", errGetMany)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve subscribers list"})
+	page, err := parsePositiveIntQuery(c, "page", 1)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	logger.AppLog.Infof("GetSubscribers: len: %d", len(amDataList))
-	if len(amDataList) == 0 {
-		if useMeta {
-			c.JSON(http.StatusOK, subscribersPageResponse{Items: subsList, Page: page, Limit: limit, Total: 0, Pages: 0})
-			return
-		}
-		c.JSON(http.StatusOK, subsList)
+	limit, err := parsePositiveIntQuery(c, "limit", subscribersDefaultLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	for _, amData := range amDataList {
-		var subsData configmodels.SubsListIE
-
-		err := json.Unmarshal(configmodels.MapToByte(amData), &subsData)
-		if err != nil {
-			logger.AppLog.Errorf("could not unmarshal subscriber %s", amData)
-		}
-
-		if servingPlmnId, plmnIdExists := amData["servingPlmnId"]; plmnIdExists {
-			subsData.PlmnID = servingPlmnId.(string)
-		}
-
-		subsList = append(subsList, subsData)
+	if limit > subscribersMaxLimit {
+		limit = subscribersMaxLimit
 	}
-
-	sort.SliceStable(subsList, func(i, j int) bool {
-		return subsList[i].UeId < subsList[j].UeId
-	})
-
-	if !useMeta {
-		c.JSON(http.StatusOK, subsList)
+	sortField, descending, err := parseSubscribersSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cursorStr := strings.TrimSpace(c.Query("cursor"))
+	if cursorStr != "" && sortField != "ueId" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cursor can only be used when sorting by ueId"})
 		return
 	}
 
-	total := len(subsList)
+	count, err := dbadapter.CommonDBClient.RestfulAPICount(AmDataColl, filter)
+	if err != nil {
+		logger.AppLog.Errorf("failed to count subscribers with error: %+v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve subscribers list"})
+		return
+	}
+	total := int(count)
+	logger.AppLog.Infof("GetSubscribers: total: %d", total)
 	if total == 0 {
 		c.JSON(http.StatusOK, subscribersPageResponse{Items: []configmodels.SubsListIE{}, Page: page, Limit: limit, Total: 0, Pages: 0})
 		return
 	}
-
-	pages := int(math.Ceil(float64(total) / float64(limit)))
-	if pages < 1 {
-		pages = 1
-	}
+	pages := (total + limit - 1) / limit
 	if page > pages {
 		page = pages
 	}
 
-	start := (page - 1) * limit
-	if start < 0 {
-		start = 0
+	order := 1
+	comparison := "$gt"
+	if descending {
+		order = -1
+		comparison = "$lt"
 	}
-	if start > total {
-		start = total
+	findFilter := filter
+	findOpts := dbadapter.FindOptions{
+		// one extra document tells whether there is a next page
+		Limit:      int64(limit) + 1,
+		Projection: bson.M{"_id": 0, "ueId": 1, "servingPlmnId": 1},
 	}
-	end := int(math.Min(float64(total), float64(start+limit)))
-	if end < start {
-		end = start
+	if sortField == "ueId" {
+		findOpts.Sort = bson.D{{Key: "ueId", Value: order}}
+	} else {
+		findOpts.Sort = bson.D{{Key: sortField, Value: order}, {Key: "ueId", Value: order}}
+	}
+	if cursorStr != "" {
+		lastUeId, err := decodeSubscribersCursor(cursorStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+		findFilter = andSubscribersFilter(filter, bson.M{"ueId": bson.M{comparison: lastUeId}})
+	} else {
+		findOpts.Skip = int64((page - 1) * limit)
 	}
 
-	items := subsList[start:end]
-	// Ensure JSON "items" is never null.
-	if items == nil {
-		items = []configmodels.SubsListIE{}
+	amDataList, err := dbadapter.CommonDBClient.RestfulAPIGetManyWithOptions(AmDataColl, findFilter, findOpts)
+	if err != nil {
+		logger.AppLog.Errorf("failed to retrieve subscribers list with error: %+v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve subscribers list"})
+		return
 	}
+	hasMore := len(amDataList) > limit
+	if hasMore {
+		amDataList = amDataList[:limit]
+	}
+	items := amDataToSubsList(amDataList)
 
-	c.JSON(http.StatusOK, subscribersPageResponse{
+	response := subscribersPageResponse{
 		Items: items,
 		Page:  page,
 		Limit: limit,
 		Total: total,
 		Pages: pages,
+	}
+	if hasMore && sortField == "ueId" && len(items) > 0 {
+		response.NextCursor = encodeSubscribersCursor(items[len(items)-1].UeId)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// getSubscribersLegacyList keeps the original contract of returning every
+// subscriber as a plain JSON array when no query parameter is given.
+func getSubscribersLegacyList(c *gin.Context, filter bson.M) {
+	amDataList, errGetMany := dbadapter.CommonDBClient.RestfulAPIGetMany(AmDataColl, filter)
+	if errGetMany != nil {
+		logger.AppLog.Errorf("failed to retrieve subscribers list with error: %+v", errGetMany)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve subscribers list"})
+		return
+	}
+	logger.AppLog.Infof("GetSubscribers: len: %d", len(amDataList))
+	subsList := amDataToSubsList(amDataList)
+	sort.SliceStable(subsList, func(i, j int) bool {
+		return subsList[i].UeId < subsList[j].UeId
 	})
+	c.JSON(http.StatusOK, subsList)
+}
+
+func amDataToSubsList(amDataList []map[string]any) []configmodels.SubsListIE {
+	subsList := make([]configmodels.SubsListIE, 0, len(amDataList))
+	for _, amData := range amDataList {
+		var subsData configmodels.SubsListIE
+
+		err := json.Unmarshal(configmodels.MapToByte(amData), &subsData)
+		if err != nil {
+			logger.AppLog.Errorf("could not unmarshal subscriber %s", amData)
+		}
+
+		if servingPlmnId, plmnIdExists := amData["servingPlmnId"].(string); plmnIdExists {
+			subsData.PlmnID = servingPlmnId
+		}
+
+		subsList = append(subsList, subsData)
+	}
+	return subsList
 }
 
 // GetSubscriberByID godoc
@@ -808,10 +906,139 @@ func PutSubscriberByID(c *gin.Context) {
 	c.JSON(http.StatusNoContent, gin.H{})
 }
 
-// Patch subscriber by IMSI(ueId) and PlmnID(servingPlmnId)
+// PatchSubscriberByID godoc
+//
+// @Description  Partially update subscriber authentication data by IMSI (UE ID). Accepts a JSON merge patch (RFC 7396) or a JSON patch (RFC 6902) applied over the subscriber override data
+// @Tags         Subscribers
+// @Accept       application/merge-patch+json,application/json-patch+json,json
+// @Param        imsi       path    string    true    "IMSI (UE ID)"
+// @Param        content    body    object    true    "Merge patch or JSON patch document"
+// @Security     BearerAuth
+// @Success      204  {object}  nil  "Subscriber updated successfully"
+// @Failure      400  {object}  nil  "Invalid patch document or patched subscriber content"
+// @Failure      401  {object}  nil  "Authorization failed"
+// @Failure      403  {object}  nil  "Forbidden"
+// @Failure      404  {object}  nil  "Subscriber not found"
+// @Failure      409  {object}  nil  "JSON patch test operation failed"
+// @Failure      415  {object}  nil  "Unsupported media type"
+// @Failure      500  {object}  nil  "Error updating subscriber"
+// @Router       /api/subscriber/{imsi}  [patch]
 func PatchSubscriberByID(c *gin.Context) {
 	setCorsHeader(c)
 	logger.WebUILog.Infoln("Patch One Subscriber Data")
+	requestID := uuid.New().String()
+
+	contentType := c.ContentType()
+	if contentType != mergePatchContentType && contentType != jsonPatchContentType && contentType != "application/json" {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error":      fmt.Sprintf("unsupported Content-Type %q: use %s or %s", contentType, mergePatchContentType, jsonPatchContentType),
+			"request_id": requestID,
+		})
+		return
+	}
+	patch, err := c.GetRawData()
+	if err != nil || len(patch) == 0 {
+		logger.WebUILog.Errorf("Patch One Subscriber Data - failed to read body: %+v request ID: %s", err, requestID)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: empty or unreadable patch document.", "request_id": requestID})
+		return
+	}
+
+	ueId := c.Param("ueId")
+	filter := bson.M{"ueId": ueId}
+	subscriber, err := dbadapter.CommonDBClient.RestfulAPIGetOne(AmDataColl, filter)
+	if err != nil {
+		logger.AppLog.Errorf("failed querying subscriber existence for IMSI: %s; Error: %+v", ueId, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to check subscriber: %s existence", ueId), "request_id": requestID})
+		return
+	}
+	authSubsDataInterface, err := dbadapter.AuthDBClient.RestfulAPIGetOne(AuthSubsDataColl, filter)
+	if err != nil {
+		logger.AppLog.Errorf("failed to fetch authentication subscription data from DB: %+v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to check subscriber: %s existence", ueId), "request_id": requestID})
+		return
+	}
+	if subscriber == nil || authSubsDataInterface == nil {
+		logger.WebUILog.Errorf("subscriber %s does not exist", ueId)
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("subscriber %s does not exist", ueId), "request_id": requestID})
+		return
+	}
+	var authSubsData models.AuthenticationSubscription
+	if err = json.Unmarshal(configmodels.MapToByte(authSubsDataInterface), &authSubsData); err != nil {
+		logger.WebUILog.Errorf("error unmarshalling authentication subscription data: %+v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      fmt.Sprintf("Failed to update subscriber %s", ueId),
+			"request_id": requestID,
+			"message":    "Please refer to the log with the provided Request ID for details",
+		})
+		return
+	}
+
+	current := subsOverrideDataFromAuthSubscription(&authSubsData)
+	patched, err := applySubscriberPatch(contentType, current, patch)
+	if err != nil {
+		logger.WebUILog.Errorf("failed to apply patch to subscriber %s: %+v request ID: %s", ueId, err, requestID)
+		statusCode := http.StatusBadRequest
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error(), "request_id": requestID})
+		return
+	}
+	if patched.OPc == "" || patched.Key == "" || patched.SequenceNumber == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required authentication data: OPc, Key and Sequence number must be provided", "request_id": requestID})
+		return
+	}
+	var ceroValue int32
+	if patched.EncryptionAlgorithm == nil {
+		patched.EncryptionAlgorithm = &ceroValue
+	}
+	if *patched.EncryptionAlgorithm < 0 || *patched.EncryptionAlgorithm > 8 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "encryption Algorithm is not valid: encryption Algorithm must be between 0 and 8", "request_id": requestID})
+		return
+	}
+
+	if authSubsData.Opc == nil {
+		authSubsData.Opc = &models.Opc{}
+	}
+	if authSubsData.PermanentKey == nil {
+		authSubsData.PermanentKey = &models.PermanentKey{}
+	}
+	authSubsData.Opc.OpcValue = patched.OPc
+	authSubsData.PermanentKey.PermanentKeyValue = patched.Key
+	authSubsData.SequenceNumber = patched.SequenceNumber
+
+	algorithmChanged := *patched.EncryptionAlgorithm != *current.EncryptionAlgorithm
+	k4SnoChanged := (patched.K4Sno == nil) != (current.K4Sno == nil) ||
+		(patched.K4Sno != nil && *patched.K4Sno != *current.K4Sno)
+	authSubsData.PermanentKey.EncryptionAlgorithm = *patched.EncryptionAlgorithm
+	if algorithmChanged || k4SnoChanged {
+		if patched.K4Sno == nil {
+			authSubsData.K4_SNO = 0
+			authSubsData.PermanentKey.EncryptionKey = ""
+		} else {
+			authSubsData.K4_SNO = *patched.K4Sno
+			if err = assingK4Key(patched.K4Sno, &authSubsData); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":      fmt.Sprintf("Failed to update subscriber %s", ueId),
+					"request_id": requestID,
+					"message":    "Please refer to the log with the provided Request ID for details, error assing the K4 Key",
+				})
+				return
+			}
+		}
+	}
+
+	err = SubscriberAuthenticationDataUpdate(ueId, &authSubsData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      fmt.Sprintf("Failed to update subscriber %s", ueId),
+			"request_id": requestID,
+			"message":    "Please refer to the log with the provided Request ID for details",
+		})
+		return
+	}
+	logger.WebUILog.Infof("Subscriber %s patched successfully", ueId)
+	c.JSON(http.StatusNoContent, gin.H{})
 }
 
 // DeleteSubscriberByID godoc
@@ -919,16 +1146,19 @@ func GetUEPDUSessionInfo(c *gin.Context) {
 	}
 }
 
+// k4KeyFilter selects the K4 key with the given SNO; when SSM is used the key
+// label must also match the encryption algorithm.
+func k4KeyFilter(k4Sno byte, encryptionAlgorithm int32) bson.M {
+	filter := bson.M{"k4_sno": int(k4Sno)}
+	if factory.WebUIConfig.Configuration.SSM.AllowSsm {
+		filter["key_label"] = ssm_constants.AlgorithmLabelMap[int(encryptionAlgorithm)]
+	}
+	return filter
+}
+
 func assingK4Key(k4Sno *byte, authSubsData *models.AuthenticationSubscription) error {
 	if k4Sno != nil {
-		snoIdint := int(*k4Sno)
-		filter := bson.M{"k4_sno": snoIdint}
-		if factory.WebUIConfig.Configuration.SSM.AllowSsm {
-			filter = bson.M{
-				"key_label": ssm_constants.AlgorithmLabelMap[int(authSubsData.PermanentKey.EncryptionAlgorithm)],
-				"k4_sno":    snoIdint,
-			}
-		}
+		filter := k4KeyFilter(*k4Sno, authSubsData.PermanentKey.EncryptionAlgorithm)
 
 		var k4Data configmodels.K4
 
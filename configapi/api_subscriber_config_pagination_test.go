@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"sort"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -16,7 +17,8 @@ import (
 
 type subscribersMockDB struct {
 	dbadapter.DBInterface
-	docs []map[string]any
+	docs         []map[string]any
+	lastFindOpts dbadapter.FindOptions
 }
 
 func (m *subscribersMockDB) RestfulAPIGetMany(coll string, filter bson.M) ([]map[string]any, error) {
@@ -29,6 +31,37 @@ func (m *subscribersMockDB) RestfulAPIGetMany(coll string, filter bson.M) ([]map
 	return results, nil
 }
 
+func (m *subscribersMockDB) RestfulAPICount(coll string, filter bson.M) (int64, error) {
+	docs, _ := m.RestfulAPIGetMany(coll, filter)
+	return int64(len(docs)), nil
+}
+
+func (m *subscribersMockDB) RestfulAPIGetManyWithOptions(coll string, filter bson.M, findOpts dbadapter.FindOptions) ([]map[string]any, error) {
+	m.lastFindOpts = findOpts
+	results, _ := m.RestfulAPIGetMany(coll, filter)
+	sort.SliceStable(results, func(i, j int) bool {
+		for _, key := range findOpts.Sort {
+			a, _ := results[i][key.Key].(string)
+			b, _ := results[j][key.Key].(string)
+			if a == b {
+				continue
+			}
+			if key.Value == -1 {
+				return a > b
+			}
+			return a < b
+		}
+		return false
+	})
+	if findOpts.Skip > 0 {
+		results = results[min(int(findOpts.Skip), len(results)):]
+	}
+	if findOpts.Limit > 0 && int(findOpts.Limit) < len(results) {
+		results = results[:findOpts.Limit]
+	}
+	return results, nil
+}
+
 func matchesSubscribersFilter(doc map[string]any, filter bson.M) bool {
 	if len(filter) == 0 {
 		return true
@@ -68,6 +101,18 @@ func matchesSubscribersFilter(doc map[string]any, filter bson.M) bool {
 					return false
 				}
 			case bson.M:
+				if gt, ok := v["$gt"].(string); ok {
+					if ue <= gt {
+						return false
+					}
+					continue
+				}
+				if lt, ok := v["$lt"].(string); ok {
+					if ue >= lt {
+						return false
+					}
+					continue
+				}
 				regexStr, _ := v["$regex"].(string)
 				optStr, _ := v["$options"].(string)
 				if regexStr == "" {
@@ -237,3 +282,126 @@ func TestGetSubscribers_FilterAndSearchAndExact(t *testing.T) {
 		}
 	}
 }
+
+func getSubscribersPage(t *testing.T, r *gin.Engine, url string) subscribersPageResponse {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body=%s", w.Code, w.Body.String())
+	}
+	var resp subscribersPageResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v; body=%s", err, w.Body.String())
+	}
+	return resp
+}
+
+func TestGetSubscribers_PagingIsPushedToDB(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	originalDB := dbadapter.CommonDBClient
+	defer func() { dbadapter.CommonDBClient = originalDB }()
+	mockDB := &subscribersMockDB{docs: []map[string]any{
+		{"ueId": "imsi-003", "servingPlmnId": "20893"},
+		{"ueId": "imsi-001", "servingPlmnId": "20893"},
+		{"ueId": "imsi-002", "servingPlmnId": "20895"},
+	}}
+	dbadapter.CommonDBClient = mockDB
+
+	r := gin.New()
+	r.GET("/api/subscriber", GetSubscribers)
+
+	resp := getSubscribersPage(t, r, "/api/subscriber?page=2&limit=2")
+	if mockDB.lastFindOpts.Skip != 2 || mockDB.lastFindOpts.Limit != 3 {
+		t.Fatalf("expected skip=2 limit=3, got %+v", mockDB.lastFindOpts)
+	}
+	if mockDB.lastFindOpts.Projection["ueId"] != 1 {
+		t.Fatalf("expected projection on ueId, got %+v", mockDB.lastFindOpts.Projection)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].UeId != "imsi-003" {
+		t.Fatalf("expected last item imsi-003, got %+v", resp.Items)
+	}
+	if resp.Total != 3 || resp.NextCursor != "" {
+		t.Fatalf("unexpected metadata: %+v", resp)
+	}
+}
+
+func TestGetSubscribers_SortDescendingByPlmn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	originalDB := dbadapter.CommonDBClient
+	defer func() { dbadapter.CommonDBClient = originalDB }()
+	dbadapter.CommonDBClient = &subscribersMockDB{docs: []map[string]any{
+		{"ueId": "imsi-001", "servingPlmnId": "20893"},
+		{"ueId": "imsi-002", "servingPlmnId": "20895"},
+		{"ueId": "imsi-003", "servingPlmnId": "20893"},
+	}}
+
+	r := gin.New()
+	r.GET("/api/subscriber", GetSubscribers)
+
+	resp := getSubscribersPage(t, r, "/api/subscriber?sort=-plmnID")
+	got := []string{resp.Items[0].UeId, resp.Items[1].UeId, resp.Items[2].UeId}
+	expected := []string{"imsi-002", "imsi-003", "imsi-001"}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected order %v, got %v", expected, got)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/subscriber?sort=key", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid sort, got %d", w.Code)
+	}
+}
+
+func TestGetSubscribers_CursorWalksAllPages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	originalDB := dbadapter.CommonDBClient
+	defer func() { dbadapter.CommonDBClient = originalDB }()
+	dbadapter.CommonDBClient = &subscribersMockDB{docs: []map[string]any{
+		{"ueId": "imsi-004", "servingPlmnId": "20893"},
+		{"ueId": "imsi-001", "servingPlmnId": "20893"},
+		{"ueId": "imsi-003", "servingPlmnId": "20893"},
+		{"ueId": "imsi-002", "servingPlmnId": "20893"},
+		{"ueId": "imsi-005", "servingPlmnId": "20893"},
+	}}
+
+	r := gin.New()
+	r.GET("/api/subscriber", GetSubscribers)
+
+	var seen []string
+	url := "/api/subscriber?limit=2"
+	for range 5 {
+		resp := getSubscribersPage(t, r, url)
+		if resp.Total != 5 {
+			t.Fatalf("expected total=5, got %d", resp.Total)
+		}
+		for _, item := range resp.Items {
+			seen = append(seen, item.UeId)
+		}
+		if resp.NextCursor == "" {
+			break
+		}
+		url = "/api/subscriber?limit=2&cursor=" + resp.NextCursor
+	}
+	expected := []string{"imsi-001", "imsi-002", "imsi-003", "imsi-004", "imsi-005"}
+	if len(seen) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, seen)
+	}
+	for i := range expected {
+		if seen[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, seen)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/subscriber?cursor=not-a-cursor", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid cursor, got %d", w.Code)
+	}
+}
@@ -0,0 +1,363 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omec-project/openapi/models"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	profileDocAuthSubscription = "authenticationSubscription"
+	profileDocAmData           = "amData"
+	profileDocSmData           = "smData"
+	profileDocSmfSelection     = "smfSelectionData"
+	profileDocAmPolicy         = "amPolicyData"
+	profileDocSmPolicy         = "smPolicyData"
+)
+
+type subscriberProfileDocs struct {
+	authSubs map[string]any
+	amData   map[string]any
+	smData   []map[string]any
+	smfSel   map[string]any
+	amPolicy map[string]any
+	smPolicy map[string]any
+}
+
+// GetSubscriberProfile godoc
+//
+// @Description  Return an aggregated view of a subscriber: device group and slice membership, K4 key metadata and the presence and consistency of every provisioned document
+// @Tags         Subscribers
+// @Param        imsi    path    string    true    "IMSI (UE ID)"    example(imsi-208930100007487)
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  configmodels.SubsProfile  "Subscriber profile"
+// @Failure      401  {object}  nil  "Authorization failed"
+// @Failure      403  {object}  nil  "Forbidden"
+// @Failure      404  {object}  nil  "Subscriber not found"
+// @Failure      500  {object}  nil  "Error retrieving subscriber profile"
+// @Router       /api/subscriber/{imsi}/profile  [get]
+func GetSubscriberProfile(c *gin.Context) {
+	setCorsHeader(c)
+	logger.WebUILog.Infoln("Get Subscriber Profile")
+	requestID := uuid.New().String()
+
+	ueId := c.Param("ueId")
+	docs, err := fetchSubscriberProfileDocs(ueId)
+	if err != nil {
+		logger.WebUILog.Errorf("failed to fetch subscriber %s documents: %+v request ID: %s", ueId, err, requestID)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "failed to fetch the requested subscriber record from DB",
+			"request_id": requestID,
+			"message":    "Please refer to the log with the provided Request ID for details",
+		})
+		return
+	}
+	if docs.authSubs == nil && docs.amData == nil {
+		logger.WebUILog.Errorf("subscriber with ID %s not found", ueId)
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("subscriber with ID %s not found", ueId), "request_id": requestID})
+		return
+	}
+
+	devGroup, err := findDeviceGroupBySubscriber(strings.TrimPrefix(ueId, "imsi-"))
+	if err != nil {
+		logger.WebUILog.Errorf("failed to fetch device group of subscriber %s: %+v request ID: %s", ueId, err, requestID)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "failed to fetch the subscriber device group from DB",
+			"request_id": requestID,
+			"message":    "Please refer to the log with the provided Request ID for details",
+		})
+		return
+	}
+	var slice *configmodels.Slice
+	if devGroup != nil {
+		slice = findSliceByDeviceGroup(devGroup.DeviceGroupName)
+	}
+
+	profile, err := buildSubscriberProfile(ueId, docs, devGroup, slice)
+	if err != nil {
+		logger.WebUILog.Errorf("failed to build subscriber %s profile: %+v request ID: %s", ueId, err, requestID)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "failed to build the subscriber profile",
+			"request_id": requestID,
+			"message":    "Please refer to the log with the provided Request ID for details",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+func fetchSubscriberProfileDocs(ueId string) (*subscriberProfileDocs, error) {
+	filter := bson.M{"ueId": ueId}
+	docs := &subscriberProfileDocs{}
+	var err error
+	if docs.authSubs, err = dbadapter.AuthDBClient.RestfulAPIGetOne(AuthSubsDataColl, filter); err != nil {
+		return nil, err
+	}
+	if docs.amData, err = dbadapter.CommonDBClient.RestfulAPIGetOne(AmDataColl, filter); err != nil {
+		return nil, err
+	}
+	if docs.smData, err = dbadapter.CommonDBClient.RestfulAPIGetMany(SmDataColl, filter); err != nil {
+		return nil, err
+	}
+	if docs.smfSel, err = dbadapter.CommonDBClient.RestfulAPIGetOne(SmfSelDataColl, filter); err != nil {
+		return nil, err
+	}
+	if docs.amPolicy, err = dbadapter.CommonDBClient.RestfulAPIGetOne(AmPolicyDataColl, filter); err != nil {
+		return nil, err
+	}
+	if docs.smPolicy, err = dbadapter.CommonDBClient.RestfulAPIGetOne(SmPolicyDataColl, filter); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// findDeviceGroupBySubscriber returns the device group the IMSI belongs to,
+// or nil when it is not part of any device group.
+func findDeviceGroupBySubscriber(imsi string) (*configmodels.DeviceGroups, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"imsis": imsi},
+		{"imsi-ranges.0": bson.M{"$exists": true}},
+	}}
+	rawDeviceGroups, err := dbadapter.CommonDBClient.RestfulAPIGetMany(devGroupDataColl, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, rawDeviceGroup := range rawDeviceGroups {
+		var devGroup configmodels.DeviceGroups
+		if err = json.Unmarshal(configmodels.MapToByte(rawDeviceGroup), &devGroup); err != nil {
+			return nil, err
+		}
+		if devGroup.ContainsImsi(imsi) {
+			return &devGroup, nil
+		}
+	}
+	return nil, nil
+}
+
+func buildSubscriberProfile(ueId string, docs *subscriberProfileDocs, devGroup *configmodels.DeviceGroups, slice *configmodels.Slice) (*configmodels.SubsProfile, error) {
+	profile := &configmodels.SubsProfile{
+		UeId:      ueId,
+		Documents: make(map[string]configmodels.SubsProfileDocument),
+	}
+	addIssue := func(format string, args ...any) {
+		profile.Issues = append(profile.Issues, fmt.Sprintf(format, args...))
+	}
+	// check records a document state; expected documents that are absent and
+	// present documents with a stale reason are reported as issues
+	check := func(name string, present, expected bool, staleReason string) {
+		doc := configmodels.SubsProfileDocument{Present: present}
+		switch {
+		case !present && expected:
+			addIssue("%s is missing", name)
+		case present && staleReason != "":
+			doc.Stale = true
+			doc.Reason = staleReason
+			addIssue("%s is stale: %s", name, staleReason)
+		}
+		profile.Documents[name] = doc
+	}
+
+	if docs.amData != nil {
+		profile.PlmnID, _ = docs.amData["servingPlmnId"].(string)
+	}
+	if devGroup != nil {
+		profile.DeviceGroup = devGroup.DeviceGroupName
+		if slice == nil {
+			addIssue("device group %s is not part of any network slice", devGroup.DeviceGroupName)
+		}
+	}
+
+	var snssai *models.Snssai
+	var plmn string
+	var dnns []string
+	if devGroup != nil && slice != nil {
+		profile.Slice = &configmodels.SubsProfileSlice{
+			SliceName: slice.SliceName,
+			Sst:       slice.SliceId.Sst,
+			Sd:        slice.SliceId.Sd,
+			Mcc:       slice.SiteInfo.Plmn.Mcc,
+			Mnc:       slice.SiteInfo.Plmn.Mnc,
+		}
+		dnns = devGroup.Dnns()
+		profile.Slice.Dnn = devGroup.IpDomainExpanded.Dnn
+		profile.Slice.Dnns = dnns
+		sst, err := strconv.ParseUint(slice.SliceId.Sst, 10, 32)
+		if err != nil {
+			addIssue("slice %s has an invalid SST %q", slice.SliceName, slice.SliceId.Sst)
+		} else {
+			snssai = &models.Snssai{Sst: int32(sst), Sd: slice.SliceId.Sd}
+			plmn = slice.SiteInfo.Plmn.Mcc + slice.SiteInfo.Plmn.Mnc
+		}
+	}
+	provisioned := snssai != nil
+	notProvisioned := "subscriber is not in a device group attached to a network slice"
+	staleIfNotProvisioned := func(reason string) string {
+		if !provisioned {
+			return notProvisioned
+		}
+		return reason
+	}
+
+	// authentication subscription
+	var authSubsData models.AuthenticationSubscription
+	authStale := ""
+	if docs.authSubs != nil {
+		if err := json.Unmarshal(configmodels.MapToByte(docs.authSubs), &authSubsData); err != nil {
+			return nil, err
+		}
+		if authSubsData.PermanentKey == nil || authSubsData.PermanentKey.PermanentKeyValue == "" ||
+			authSubsData.Opc == nil || authSubsData.Opc.OpcValue == "" {
+			authStale = "Ki or OPc is empty"
+		}
+	}
+	check(profileDocAuthSubscription, docs.authSubs != nil, true, authStale)
+	if docs.authSubs != nil && authSubsData.PermanentKey != nil {
+		k4, err := subscriberProfileK4(&authSubsData)
+		if err != nil {
+			return nil, err
+		}
+		profile.K4 = k4
+		if k4 != nil && !k4.Found {
+			addIssue("K4 key with SNO %d is not present", k4.K4Sno)
+		} else if k4 != nil && !k4.KeyMatches {
+			addIssue("K4 key with SNO %d does not match the key used by the subscriber", k4.K4Sno)
+		}
+	}
+
+	// amData
+	amStale := ""
+	if docs.amData != nil && provisioned {
+		var amData models.AccessAndMobilitySubscriptionData
+		if err := json.Unmarshal(configmodels.MapToByte(docs.amData), &amData); err != nil {
+			return nil, err
+		}
+		switch {
+		case profile.PlmnID != plmn:
+			amStale = fmt.Sprintf("servingPlmnId %q does not match slice PLMN %q", profile.PlmnID, plmn)
+		case amData.Nssai == nil || !slices.Contains(amData.Nssai.SingleNssais, *snssai):
+			amStale = fmt.Sprintf("S-NSSAI %s is not subscribed", SnssaiModelsToHex(*snssai))
+		}
+	}
+	check(profileDocAmData, docs.amData != nil, true, amStale)
+
+	// smData
+	smStale := ""
+	if len(docs.smData) > 0 {
+		smStale = staleIfNotProvisioned("")
+		if provisioned {
+			missing := slices.Clone(dnns)
+			for _, raw := range docs.smData {
+				var smData models.SessionManagementSubscriptionData
+				if err := json.Unmarshal(configmodels.MapToByte(raw), &smData); err != nil {
+					return nil, err
+				}
+				servingPlmnId, _ := raw["servingPlmnId"].(string)
+				if servingPlmnId != plmn || smData.SingleNssai == nil || *smData.SingleNssai != *snssai {
+					continue
+				}
+				missing = slices.DeleteFunc(missing, func(dnn string) bool {
+					_, ok := smData.DnnConfigurations[dnn]
+					return ok
+				})
+			}
+			if len(missing) > 0 {
+				smStale = fmt.Sprintf("no session data for S-NSSAI %s and DNN %q in PLMN %q", SnssaiModelsToHex(*snssai), missing[0], plmn)
+			}
+		}
+	}
+	check(profileDocSmData, len(docs.smData) > 0, provisioned, smStale)
+
+	// smfSelectionData
+	smfSelStale := ""
+	if docs.smfSel != nil {
+		smfSelStale = staleIfNotProvisioned("")
+		if provisioned {
+			var smfSel models.SmfSelectionSubscriptionData
+			if err := json.Unmarshal(configmodels.MapToByte(docs.smfSel), &smfSel); err != nil {
+				return nil, err
+			}
+			info := smfSel.SubscribedSnssaiInfos[SnssaiModelsToHex(*snssai)]
+			for _, dnn := range dnns {
+				if !slices.ContainsFunc(info.DnnInfos, func(d models.DnnInfo) bool { return d.Dnn == dnn }) {
+					smfSelStale = fmt.Sprintf("S-NSSAI %s with DNN %q is not selectable", SnssaiModelsToHex(*snssai), dnn)
+					break
+				}
+			}
+		}
+	}
+	check(profileDocSmfSelection, docs.smfSel != nil, provisioned, smfSelStale)
+
+	// amPolicyData
+	amPolicyStale := ""
+	if docs.amPolicy != nil {
+		amPolicyStale = staleIfNotProvisioned("")
+	}
+	check(profileDocAmPolicy, docs.amPolicy != nil, provisioned, amPolicyStale)
+
+	// smPolicyData
+	smPolicyStale := ""
+	if docs.smPolicy != nil {
+		smPolicyStale = staleIfNotProvisioned("")
+		if provisioned {
+			var smPolicy models.SmPolicyData
+			if err := json.Unmarshal(configmodels.MapToByte(docs.smPolicy), &smPolicy); err != nil {
+				return nil, err
+			}
+			snssaiData := smPolicy.SmPolicySnssaiData[SnssaiModelsToHex(*snssai)]
+			for _, dnn := range dnns {
+				if _, ok := snssaiData.SmPolicyDnnData[dnn]; !ok {
+					smPolicyStale = fmt.Sprintf("no policy for S-NSSAI %s and DNN %q", SnssaiModelsToHex(*snssai), dnn)
+					break
+				}
+			}
+		}
+	}
+	check(profileDocSmPolicy, docs.smPolicy != nil, provisioned, smPolicyStale)
+
+	profile.Consistent = len(profile.Issues) == 0
+	return profile, nil
+}
+
+// subscriberProfileK4 looks up the K4 key referenced by the subscriber. It
+// returns nil when the Ki is not protected by a K4 key.
+func subscriberProfileK4(authSubsData *models.AuthenticationSubscription) (*configmodels.SubsProfileK4, error) {
+	if authSubsData.PermanentKey.EncryptionKey == "" && authSubsData.K4_SNO == 0 {
+		return nil, nil
+	}
+	k4 := &configmodels.SubsProfileK4{
+		K4Sno:               authSubsData.K4_SNO,
+		EncryptionAlgorithm: authSubsData.PermanentKey.EncryptionAlgorithm,
+	}
+	filter := k4KeyFilter(authSubsData.K4_SNO, authSubsData.PermanentKey.EncryptionAlgorithm)
+	k4DataInterface, err := dbadapter.AuthDBClient.RestfulAPIGetOne(K4KeysColl, filter)
+	if err != nil {
+		return nil, err
+	}
+	if k4DataInterface == nil {
+		return k4, nil
+	}
+	var k4Data configmodels.K4
+	if err = json.Unmarshal(configmodels.MapToByte(k4DataInterface), &k4Data); err != nil {
+		return nil, err
+	}
+	k4.Found = true
+	k4.K4Label = k4Data.K4_Label
+	k4.K4Type = k4Data.K4_Type
+	k4.KeyMatches = k4Data.K4 == authSubsData.PermanentKey.EncryptionKey
+	return k4, nil
+}
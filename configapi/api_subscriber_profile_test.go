@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/openapi/models"
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const profileTestUeId = "imsi-001010000000001"
+
+func profileTestSnssai() *models.Snssai {
+	return &models.Snssai{Sst: 1, Sd: "010203"}
+}
+
+func profileTestDocs() map[string]map[string]any {
+	snssai := profileTestSnssai()
+	withUe := func(doc map[string]any, plmn string) map[string]any {
+		doc["ueId"] = profileTestUeId
+		if plmn != "" {
+			doc["servingPlmnId"] = plmn
+		}
+		return doc
+	}
+	return map[string]map[string]any{
+		AuthSubsDataColl: withUe(configmodels.ToBsonM(models.AuthenticationSubscription{
+			Opc:          &models.Opc{OpcValue: "8e27b6af0e692e750f32667a3b14605d"},
+			PermanentKey: &models.PermanentKey{PermanentKeyValue: "8baf473f2f8fd09487cccbd7097c6862", EncryptionKey: "k4-value"},
+			K4_SNO:       3,
+		}), ""),
+		AmDataColl: withUe(configmodels.ToBsonM(models.AccessAndMobilitySubscriptionData{
+			Nssai: &models.Nssai{DefaultSingleNssais: []models.Snssai{*snssai}, SingleNssais: []models.Snssai{*snssai}},
+		}), "00101"),
+		SmDataColl: withUe(configmodels.ToBsonM(models.SessionManagementSubscriptionData{
+			SingleNssai:       snssai,
+			DnnConfigurations: map[string]models.DnnConfiguration{"internet": {}},
+		}), "00101"),
+		SmfSelDataColl: withUe(configmodels.ToBsonM(models.SmfSelectionSubscriptionData{
+			SubscribedSnssaiInfos: map[string]models.SnssaiInfo{"01010203": {DnnInfos: []models.DnnInfo{{Dnn: "internet"}}}},
+		}), "00101"),
+		AmPolicyDataColl: withUe(configmodels.ToBsonM(models.AmPolicyData{SubscCats: []string{"aether"}}), ""),
+		SmPolicyDataColl: withUe(configmodels.ToBsonM(models.SmPolicyData{
+			SmPolicySnssaiData: map[string]models.SmPolicySnssaiData{
+				"01010203": {Snssai: snssai, SmPolicyDnnData: map[string]models.SmPolicyDnnData{"internet": {Dnn: "internet"}}},
+			},
+		}), ""),
+		K4KeysColl: configmodels.ToBsonM(configmodels.K4{K4: "k4-value", K4_SNO: 3, K4_Label: "K4_AES", K4_Type: "AES"}),
+		devGroupDataColl: configmodels.ToBsonM(configmodels.DeviceGroups{
+			DeviceGroupName:  "group1",
+			Imsis:            []string{"001010000000001"},
+			IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{Dnn: "internet"},
+		}),
+		sliceDataColl: configmodels.ToBsonM(configmodels.Slice{
+			SliceName:       "slice1",
+			SliceId:         configmodels.SliceSliceId{Sst: "1", Sd: "010203"},
+			SiteDeviceGroup: []string{"group1"},
+			SiteInfo:        configmodels.SliceSiteInfo{Plmn: configmodels.SliceSiteInfoPlmn{Mcc: "001", Mnc: "01"}},
+		}),
+	}
+}
+
+func getSubscriberProfile(t *testing.T, docs map[string]map[string]any) (*httptest.ResponseRecorder, configmodels.SubsProfile) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	AddApiService(router)
+
+	mockDB := &dbadapter.MockDBClient{
+		GetOneFn: func(collName string, filter bson.M) (map[string]any, error) {
+			return docs[collName], nil
+		},
+		GetManyFn: func(collName string, filter bson.M) ([]map[string]any, error) {
+			if doc, ok := docs[collName]; ok {
+				return []map[string]any{doc}, nil
+			}
+			return nil, nil
+		},
+	}
+	origDBClient := dbadapter.CommonDBClient
+	origAuthDBClient := dbadapter.AuthDBClient
+	originalConfig := factory.WebUIConfig
+	t.Cleanup(func() {
+		dbadapter.CommonDBClient = origDBClient
+		dbadapter.AuthDBClient = origAuthDBClient
+		factory.WebUIConfig = originalConfig
+	})
+	factory.WebUIConfig = &factory.Config{Configuration: &factory.Configuration{SSM: &factory.SSM{}}}
+	dbadapter.CommonDBClient = mockDB
+	dbadapter.AuthDBClient = mockDB
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/subscriber/"+profileTestUeId+"/profile", nil)
+	router.ServeHTTP(w, req)
+	var profile configmodels.SubsProfile
+	_ = json.Unmarshal(w.Body.Bytes(), &profile)
+	return w, profile
+}
+
+func TestGetSubscriberProfile_Consistent(t *testing.T) {
+	w, profile := getSubscriberProfile(t, profileTestDocs())
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+	}
+	if !profile.Consistent {
+		t.Errorf("expected consistent profile, got issues %v", profile.Issues)
+	}
+	if profile.DeviceGroup != "group1" || profile.Slice == nil || profile.Slice.SliceName != "slice1" || profile.Slice.Dnn != "internet" {
+		t.Errorf("unexpected membership: %+v slice %+v", profile, profile.Slice)
+	}
+	if profile.K4 == nil || !profile.K4.Found || !profile.K4.KeyMatches || profile.K4.K4Label != "K4_AES" {
+		t.Errorf("unexpected K4 metadata: %+v", profile.K4)
+	}
+	if len(profile.Documents) != 6 {
+		t.Errorf("expected 6 documents, got %+v", profile.Documents)
+	}
+	if strings.Contains(w.Body.String(), "k4-value") {
+		t.Errorf("K4 key must not be exposed in the profile")
+	}
+}
+
+func TestGetSubscriberProfile_MissingAndStaleDocuments(t *testing.T) {
+	docs := profileTestDocs()
+	delete(docs, SmPolicyDataColl)
+	docs[AmDataColl]["servingPlmnId"] = "20893"
+	docs[K4KeysColl]["k4"] = "rotated"
+	w, profile := getSubscriberProfile(t, docs)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+	}
+	if profile.Consistent {
+		t.Fatalf("expected inconsistent profile")
+	}
+	if doc := profile.Documents[profileDocSmPolicy]; doc.Present {
+		t.Errorf("expected smPolicyData to be reported missing, got %+v", doc)
+	}
+	if doc := profile.Documents[profileDocAmData]; !doc.Stale || !strings.Contains(doc.Reason, "20893") {
+		t.Errorf("expected stale amData, got %+v", doc)
+	}
+	if profile.K4 == nil || profile.K4.KeyMatches {
+		t.Errorf("expected K4 mismatch, got %+v", profile.K4)
+	}
+	if len(profile.Issues) != 3 {
+		t.Errorf("expected 3 issues, got %v", profile.Issues)
+	}
+}
+
+func TestGetSubscriberProfile_NotInDeviceGroup(t *testing.T) {
+	docs := profileTestDocs()
+	delete(docs, devGroupDataColl)
+	delete(docs, sliceDataColl)
+	w, profile := getSubscriberProfile(t, docs)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+	}
+	if profile.DeviceGroup != "" || profile.Slice != nil {
+		t.Errorf("expected no membership, got %+v", profile)
+	}
+	for _, name := range []string{profileDocSmData, profileDocSmfSelection, profileDocAmPolicy, profileDocSmPolicy} {
+		if !profile.Documents[name].Stale {
+			t.Errorf("expected leftover %s to be stale, got %+v", name, profile.Documents[name])
+		}
+	}
+}
+
+func TestGetSubscriberProfile_NotFound(t *testing.T) {
+	w, _ := getSubscriberProfile(t, map[string]map[string]any{})
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestGetSubscriberProfile_DeviceGroupRange(t *testing.T) {
+	docs := profileTestDocs()
+	docs[devGroupDataColl] = configmodels.ToBsonM(configmodels.DeviceGroups{
+		DeviceGroupName:  "group1",
+		ImsiRanges:       []configmodels.DeviceGroupsImsiRange{{Prefix: "0010100000", Count: 100}},
+		IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{Dnn: "internet"},
+	})
+	w, profile := getSubscriberProfile(t, docs)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+	}
+	if profile.DeviceGroup != "group1" || !profile.Consistent {
+		t.Errorf("expected consistent membership through the IMSI range, got %+v", profile)
+	}
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omec-project/webconsole/backend/logger"
+)
+
+// GetSyncJob godoc
+//
+// @Description  Return the status and progress of a network slice or device group sync job
+// @Tags         Sync Jobs
+// @Param        jobId    path    string    true    " "
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  configmodels.SyncJob  "Sync job"
+// @Failure      401  {object}  nil  "Authorization failed"
+// @Failure      403  {object}  nil  "Forbidden"
+// @Failure      404  {object}  nil  "Sync job not found"
+// @Failure      500  {object}  nil  "Error retrieving sync job"
+// @Router       /config/v1/jobs/{jobId}  [get]
+func GetSyncJob(c *gin.Context) {
+	setCorsHeader(c)
+	requestID := uuid.New().String()
+	jobId := c.Param("id")
+	job, err := getSyncJob(jobId)
+	if err != nil {
+		logger.ConfigLog.Errorf("request ID: %s failed to retrieve sync job %s: %+v", requestID, jobId, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      fmt.Sprintf("Failed to retrieve sync job %s", jobId),
+			"request_id": requestID,
+			"message":    "Please refer to the log with the provided Request ID for details",
+		})
+		return
+	}
+	if job == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("sync job %s not found", jobId), "request_id": requestID})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,358 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package configapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omec-project/webconsole/backend/auth"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	errorInvalidAuditLimit   = "limit must be a number between 1 and 1000"
+	errorInvalidAuditTime    = "from and to must be RFC 3339 times"
+	errorRetrieveAuditRecord = "failed to retrieve audit records"
+	auditRequestIDHeader     = "X-Request-ID"
+	auditRedactedValue       = "[REDACTED]"
+	defaultAuditLimit        = 100
+	maxAuditLimit            = 1000
+	maxRequestIDLength       = 128
+)
+
+// auditedGetRoutes are the routes changing the configuration on GET requests.
+var auditedGetRoutes = []string{
+	"/sync-ssm/sync-key",
+	"/sync-ssm/k4-rotation",
+}
+
+// auditSecretFields are the fields whose values are redacted from the changes.
+var auditSecretFields = []string{
+	"password",
+	"keyHash",
+	"k4",
+	"opcValue",
+	"permanentKeyValue",
+}
+
+// auditResource describes the resource changed by the requests on a route.
+// It is identified by the idParam path parameter, or else by the idField of
+// the JSON body.
+type auditResource struct {
+	idParam string
+	idField string
+	// snapshot returns the stored state of the resource, nil if there is none
+	snapshot func(c *gin.Context, id string) (map[string]any, error)
+}
+
+func storedDocument(client *dbadapter.DBInterface, collName string, key string) func(*gin.Context, string) (map[string]any, error) {
+	return func(c *gin.Context, id string) (map[string]any, error) {
+		return (*client).RestfulAPIGetOne(collName, bson.M{key: id})
+	}
+}
+
+func storedK4Key(c *gin.Context, id string) (map[string]any, error) {
+	k4Sno, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil
+	}
+	filter := bson.M{"k4_sno": k4Sno}
+	if keyLabel := c.Param("keylabel"); keyLabel != "" {
+		filter["key_label"] = keyLabel
+	}
+	return dbadapter.AuthDBClient.RestfulAPIGetOne(K4KeysColl, filter)
+}
+
+var (
+	deviceGroupResource = auditResource{idParam: "group-name", snapshot: storedDocument(&dbadapter.CommonDBClient, devGroupDataColl, "group-name")}
+	userAccountResource = auditResource{idParam: "username", snapshot: storedDocument(&dbadapter.WebuiDBClient, configmodels.UserAccountDataColl, "username")}
+	k4KeyResource       = auditResource{idParam: "idsno", snapshot: storedK4Key}
+	sliceResource       = auditResource{idParam: "slice-name", snapshot: storedDocument(&dbadapter.CommonDBClient, sliceDataColl, "slice-name")}
+)
+
+// auditResources are the resources of the audited routes, by route. The
+// requests on the other routes are recorded without resource.
+var auditResources = map[string]auditResource{
+	"/api/subscriber/:ueId":                        {idParam: "ueId", snapshot: storedDocument(&dbadapter.AuthDBClient, AuthSubsDataColl, "ueId")},
+	"/api/k4opt":                                   {idField: "k4_sno", snapshot: storedK4Key},
+	"/api/k4opt/:idsno":                            k4KeyResource,
+	"/api/k4opt/:idsno/:keylabel":                  k4KeyResource,
+	"/config/v1/device-group/:group-name":          deviceGroupResource,
+	"/config/v1/device-group/:group-name/imsis":    deviceGroupResource,
+	"/config/v1/network-slice/:slice-name":         sliceResource,
+	"/config/v1/inventory/gnb":                     {idField: "name", snapshot: storedDocument(&dbadapter.CommonDBClient, configmodels.GnbDataColl, "name")},
+	"/config/v1/inventory/gnb/:gnb-name":           {idParam: "gnb-name", snapshot: storedDocument(&dbadapter.CommonDBClient, configmodels.GnbDataColl, "name")},
+	"/config/v1/inventory/upf":                     {idField: "hostname", snapshot: storedDocument(&dbadapter.CommonDBClient, configmodels.UpfDataColl, "hostname")},
+	"/config/v1/inventory/upf/:upf-hostname":       {idParam: "upf-hostname", snapshot: storedDocument(&dbadapter.CommonDBClient, configmodels.UpfDataColl, "hostname")},
+	"/config/v1/account":                           {idField: "username", snapshot: userAccountResource.snapshot},
+	"/config/v1/account/:username":                 userAccountResource,
+	"/config/v1/account/:username/change_password": userAccountResource,
+	"/config/v1/account/:username/role":            userAccountResource,
+	"/config/v1/account/:username/unlock":          userAccountResource,
+	"/config/v1/account/:username/api-key":         {idParam: "username"},
+	"/config/v1/account/:username/api-key/:key-id": {idParam: "key-id", snapshot: storedDocument(&dbadapter.WebuiDBClient, configmodels.APIKeyDataColl, "id")},
+	"/config/v1/role/:role-name":                   {idParam: "role-name", snapshot: storedDocument(&dbadapter.WebuiDBClient, configmodels.RoleDataColl, "name")},
+}
+
+// resourceID returns the ID of the resource of the request. The body is read
+// and restored for the handler when the ID is one of its fields.
+func (r *auditResource) resourceID(c *gin.Context) string {
+	if r.idParam != "" {
+		return c.Param(r.idParam)
+	}
+	if r.idField == "" || c.Request.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		logger.WebUILog.Warnf("failed to read the body of the audited request: %v", err)
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	var fields map[string]any
+	if err = json.Unmarshal(body, &fields); err != nil {
+		return ""
+	}
+	switch id := fields[r.idField].(type) {
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	}
+	return ""
+}
+
+func (r *auditResource) state(c *gin.Context, id string) map[string]any {
+	if r.snapshot == nil || id == "" {
+		return nil
+	}
+	document, err := r.snapshot(c, id)
+	if err != nil {
+		logger.WebUILog.Warnf("failed to retrieve resource %s for the audit log: %v", id, err)
+		return nil
+	}
+	return document
+}
+
+func isAuditedRequest(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return c.Request.Method == http.MethodGet && slices.Contains(auditedGetRoutes, c.FullPath())
+}
+
+// AuditMiddleware records the requests changing the configuration in the
+// audit log, with the authenticated user and the changes of the resource.
+// The request ID is taken from the X-Request-ID header, or generated, and
+// returned in the same header. It must be used before the authentication
+// middleware so that the denied requests are recorded too.
+func AuditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAuditedRequest(c) {
+			c.Next()
+			return
+		}
+		requestID := c.GetHeader(auditRequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+		c.Header(auditRequestIDHeader, requestID)
+
+		resource, found := auditResources[c.FullPath()]
+		var resourceID string
+		var before map[string]any
+		if found {
+			resourceID = resource.resourceID(c)
+			before = resource.state(c, resourceID)
+		}
+		c.Next()
+
+		record := configmodels.DBAuditRecord{
+			Time:       time.Now().UnixMilli(),
+			Username:   auth.AuthenticatedUsername(c),
+			ClientIP:   c.ClientIP(),
+			Method:     c.Request.Method,
+			Route:      c.FullPath(),
+			Path:       c.Request.URL.Path,
+			ResourceID: resourceID,
+			RequestID:  requestID,
+			StatusCode: c.Writer.Status(),
+			Outcome:    configmodels.AuditOutcomeFailure,
+		}
+		if record.StatusCode/100 == 2 {
+			record.Outcome = configmodels.AuditOutcomeSuccess
+			if found {
+				record.Changes = diffDocuments(before, resource.state(c, resourceID))
+			}
+		}
+		if err := storeAuditRecord(&record); err != nil {
+			logger.WebUILog.Errorf("failed to store the audit record of request %s: %v", requestID, err)
+		}
+	}
+}
+
+func storeAuditRecord(record *configmodels.DBAuditRecord) error {
+	document := configmodels.ToBsonM(record)
+	// a BSON date, which the retention index needs
+	document[configmodels.AuditRecordedAtField] = time.UnixMilli(record.Time)
+	return dbadapter.CommonDBClient.RestfulAPIPostMany(configmodels.AuditDataColl, bson.M{}, []any{document})
+}
+
+// flattenDocument adds the fields of the document to fields, with the dotted
+// path of the nested documents.
+func flattenDocument(prefix string, document map[string]any, fields map[string]any) {
+	for key, value := range document {
+		if prefix == "" && key == "_id" {
+			continue
+		}
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+		switch nested := value.(type) {
+		case bson.M:
+			flattenDocument(field, nested, fields)
+		case map[string]any:
+			flattenDocument(field, nested, fields)
+		default:
+			fields[field] = value
+		}
+	}
+}
+
+// diffDocuments returns the fields changed between the two states of a
+// resource, nil when it is not stored.
+func diffDocuments(before, after map[string]any) []configmodels.AuditChange {
+	beforeFields := map[string]any{}
+	afterFields := map[string]any{}
+	flattenDocument("", before, beforeFields)
+	flattenDocument("", after, afterFields)
+	var changes []configmodels.AuditChange
+	for field, beforeValue := range beforeFields {
+		afterValue, found := afterFields[field]
+		if !found || !sameValue(beforeValue, afterValue) {
+			changes = append(changes, auditChange(field, beforeValue, afterValue))
+		}
+	}
+	for field, afterValue := range afterFields {
+		if _, found := beforeFields[field]; !found {
+			changes = append(changes, auditChange(field, nil, afterValue))
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// sameValue compares the JSON encoding of the values, so that the numbers
+// stored with different BSON types are equal.
+func sameValue(a, b any) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	if aErr != nil || bErr != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return bytes.Equal(aJSON, bJSON)
+}
+
+func auditChange(field string, before, after any) configmodels.AuditChange {
+	name := field
+	if i := strings.LastIndexByte(field, '.'); i >= 0 {
+		name = field[i+1:]
+	}
+	if slices.Contains(auditSecretFields, name) {
+		if before != nil {
+			before = auditRedactedValue
+		}
+		if after != nil {
+			after = auditRedactedValue
+		}
+	}
+	return configmodels.AuditChange{Field: field, Before: before, After: after}
+}
+
+// GetAuditRecords godoc
+//
+// @Description  Return the audit records of the requests changing the configuration, the most recent first
+// @Tags         Audit
+// @Produce      json
+// @Param        from        query    string    false    "Records from this RFC 3339 time"
+// @Param        to          query    string    false    "Records up to this RFC 3339 time"
+// @Param        username    query    string    false    "Records of the requests of this user"
+// @Param        resource    query    string    false    "Records of the requests on the resource with this ID"
+// @Param        route       query    string    false    "Records of the requests on this route, e.g. /config/v1/network-slice/:slice-name"
+// @Param        limit       query    int       false    "Maximum number of records, 100 by default and at most 1000"
+// @Security     BearerAuth
+// @Success      200  {array}   configmodels.AuditRecordResponse  "Audit records"
+// @Failure      400  {object}  nil                               "Invalid filters"
+// @Failure      401  {object}  nil                               "Authorization failed"
+// @Failure      403  {object}  nil                               "Forbidden"
+// @Failure      500  {object}  nil                               "Error retrieving audit records"
+// @Router       /config/v1/audit  [get]
+func GetAuditRecords(c *gin.Context) {
+	logger.WebUILog.Infoln("get audit records")
+	filter := bson.M{}
+	timeRange := bson.M{}
+	for param, operator := range map[string]string{"from": "$gte", "to": "$lte"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errorInvalidAuditTime})
+			return
+		}
+		timeRange[operator] = t.UnixMilli()
+	}
+	if len(timeRange) > 0 {
+		filter["time"] = timeRange
+	}
+	for param, field := range map[string]string{"username": "username", "resource": "resourceId", "route": "route"} {
+		if value := c.Query(param); value != "" {
+			filter[field] = value
+		}
+	}
+	limit := defaultAuditLimit
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxAuditLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errorInvalidAuditLimit})
+			return
+		}
+	}
+	findOptions := dbadapter.FindOptions{
+		Limit: int64(limit),
+		Sort:  bson.D{{Key: "time", Value: -1}},
+	}
+	rawRecords, err := dbadapter.CommonDBClient.RestfulAPIGetManyWithOptions(configmodels.AuditDataColl, filter, findOptions)
+	if err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveAuditRecord})
+		return
+	}
+	records := make([]configmodels.AuditRecordResponse, 0, len(rawRecords))
+	for _, rawRecord := range rawRecords {
+		var record configmodels.DBAuditRecord
+		if err = json.Unmarshal(configmodels.MapToByte(rawRecord), &record); err != nil {
+			logger.AppLog.Errorf("%s: %+v", errorRetrieveAuditRecord, err)
+			continue
+		}
+		records = append(records, record.ToResponse())
+	}
+	c.JSON(http.StatusOK, records)
+}
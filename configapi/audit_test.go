@@ -0,0 +1,433 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package configapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/auth"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MockMongoClientAudit keeps the audit records written to it.
+type MockMongoClientAudit struct {
+	dbadapter.DBInterface
+	records     []map[string]any
+	findFilter  bson.M
+	findOptions dbadapter.FindOptions
+	findErr     error
+}
+
+func (db *MockMongoClientAudit) RestfulAPIPostMany(collName string, filter bson.M, postDataArray []any) error {
+	for _, postData := range postDataArray {
+		db.records = append(db.records, postData.(bson.M))
+	}
+	return nil
+}
+
+func (db *MockMongoClientAudit) RestfulAPIGetManyWithOptions(collName string, filter bson.M, findOpts dbadapter.FindOptions) ([]map[string]any, error) {
+	db.findFilter = filter
+	db.findOptions = findOpts
+	return db.records, db.findErr
+}
+
+// MockMongoClientAuditedAccounts is a webui DB with active sessions, in which
+// user accounts can be created.
+type MockMongoClientAuditedAccounts struct {
+	*MockMongoClientRoles
+}
+
+func (db *MockMongoClientAuditedAccounts) RestfulAPIGetOne(collName string, filter bson.M) (map[string]any, error) {
+	if collName == configmodels.RefreshTokenDataColl {
+		return map[string]any{"sessionId": filter["sessionId"]}, nil
+	}
+	return db.MockMongoClientRoles.RestfulAPIGetOne(collName, filter)
+}
+
+func (db *MockMongoClientAuditedAccounts) RestfulAPIPostMany(collName string, filter bson.M, postDataArray []any) error {
+	db.users[filter["username"].(string)] = postDataArray[0].(bson.M)
+	return nil
+}
+
+func setUpAuditRouter() (*gin.Engine, *MockMongoClientAudit) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	AddUserAccountService(router, mockJWTSecret, AuditMiddleware())
+	auditDB := &MockMongoClientAudit{}
+	dbadapter.CommonDBClient = auditDB
+	dbadapter.WebuiDBClient = &MockMongoClientAuditedAccounts{newMockMongoClientRoles()}
+	return router, auditDB
+}
+
+func auditRecord(t *testing.T, rawRecord map[string]any) configmodels.DBAuditRecord {
+	t.Helper()
+	var record configmodels.DBAuditRecord
+	if err := json.Unmarshal(configmodels.MapToByte(rawRecord), &record); err != nil {
+		t.Fatalf("failed to decode audit record: %v", err)
+	}
+	return record
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	testCases := []struct {
+		name            string
+		method          string
+		url             string
+		body            string
+		username        string
+		requestID       string
+		expectedCode    int
+		expectedRecord  configmodels.DBAuditRecord
+		expectedChanges []configmodels.AuditChange
+	}{
+		{
+			name:         "UpdateRole",
+			method:       http.MethodPut,
+			url:          "/config/v1/role/auditor",
+			body:         `{"permissions":["network:read","network:write"]}`,
+			username:     "janedoe",
+			requestID:    "request-1",
+			expectedCode: http.StatusOK,
+			expectedRecord: configmodels.DBAuditRecord{
+				Username:   "janedoe",
+				Method:     http.MethodPut,
+				Route:      "/config/v1/role/:role-name",
+				Path:       "/config/v1/role/auditor",
+				ResourceID: "auditor",
+				RequestID:  "request-1",
+				StatusCode: http.StatusOK,
+				Outcome:    configmodels.AuditOutcomeSuccess,
+			},
+			expectedChanges: []configmodels.AuditChange{
+				{Field: "permissions", Before: []any{"network:read"}, After: []any{"network:read", "network:write"}},
+			},
+		},
+		{
+			name:         "ChangePasswordRedacted",
+			method:       http.MethodPost,
+			url:          "/config/v1/account/johndoe/change_password",
+			body:         `{"password":"Password-456"}`,
+			username:     "janedoe",
+			expectedCode: http.StatusOK,
+			expectedRecord: configmodels.DBAuditRecord{
+				Username:   "janedoe",
+				Method:     http.MethodPost,
+				Route:      "/config/v1/account/:username/change_password",
+				Path:       "/config/v1/account/johndoe/change_password",
+				ResourceID: "johndoe",
+				StatusCode: http.StatusOK,
+				Outcome:    configmodels.AuditOutcomeSuccess,
+			},
+			expectedChanges: []configmodels.AuditChange{
+				{Field: "password", Before: auditRedactedValue, After: auditRedactedValue},
+			},
+		},
+		{
+			name:         "CreateUserAccountIDFromBody",
+			method:       http.MethodPost,
+			url:          "/config/v1/account",
+			body:         `{"username":"newuser","password":"Password-123"}`,
+			username:     "janedoe",
+			expectedCode: http.StatusCreated,
+			expectedRecord: configmodels.DBAuditRecord{
+				Username:   "janedoe",
+				Method:     http.MethodPost,
+				Route:      "/config/v1/account",
+				Path:       "/config/v1/account",
+				ResourceID: "newuser",
+				StatusCode: http.StatusCreated,
+				Outcome:    configmodels.AuditOutcomeSuccess,
+			},
+			expectedChanges: []configmodels.AuditChange{
+				{Field: "password", After: auditRedactedValue},
+				{Field: "role", After: float64(configmodels.UserRole)},
+				{Field: "username", After: "newuser"},
+			},
+		},
+		{
+			name:         "UnauthenticatedDeleteRole",
+			method:       http.MethodDelete,
+			url:          "/config/v1/role/auditor",
+			expectedCode: http.StatusUnauthorized,
+			expectedRecord: configmodels.DBAuditRecord{
+				Method:     http.MethodDelete,
+				Route:      "/config/v1/role/:role-name",
+				Path:       "/config/v1/role/auditor",
+				ResourceID: "auditor",
+				StatusCode: http.StatusUnauthorized,
+				Outcome:    configmodels.AuditOutcomeFailure,
+			},
+		},
+		{
+			name:         "ForbiddenDeleteRole",
+			method:       http.MethodDelete,
+			url:          "/config/v1/role/auditor",
+			username:     "johndoe",
+			expectedCode: http.StatusForbidden,
+			expectedRecord: configmodels.DBAuditRecord{
+				Method:     http.MethodDelete,
+				Route:      "/config/v1/role/:role-name",
+				Path:       "/config/v1/role/auditor",
+				ResourceID: "auditor",
+				StatusCode: http.StatusForbidden,
+				Outcome:    configmodels.AuditOutcomeFailure,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, auditDB := setUpAuditRouter()
+			req, err := http.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.RemoteAddr = "192.0.2.1:12345"
+			if tc.username != "" {
+				role := configmodels.UserRole
+				if tc.username == "janedoe" {
+					role = configmodels.AdminRole
+				}
+				var token string
+				if token, err = auth.GenerateJWT(tc.username, role, "", "mockSession", mockJWTSecret); err != nil {
+					t.Fatalf("failed to generate token: %v", err)
+				}
+				req.Header.Set("Authorization", bearer+token)
+			}
+			if tc.requestID != "" {
+				req.Header.Set(auditRequestIDHeader, tc.requestID)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedCode {
+				t.Fatalf("expected `%v`, got `%v` %s", tc.expectedCode, w.Code, w.Body.String())
+			}
+			if len(auditDB.records) != 1 {
+				t.Fatalf("expected one audit record, got %+v", auditDB.records)
+			}
+			record := auditRecord(t, auditDB.records[0])
+			if record.Time == 0 || record.RequestID == "" || record.RequestID != w.Header().Get(auditRequestIDHeader) {
+				t.Errorf("expected the time and request ID to be set, got %+v", record)
+			}
+			if record.ClientIP != "192.0.2.1" {
+				t.Errorf("expected the client IP to be recorded, got %+v", record)
+			}
+			if recordedAt, ok := auditDB.records[0][configmodels.AuditRecordedAtField].(time.Time); !ok || recordedAt.UnixMilli() != record.Time {
+				t.Errorf("expected the record date to be set for the retention, got %v", auditDB.records[0][configmodels.AuditRecordedAtField])
+			}
+			expected := tc.expectedRecord
+			expected.Time = record.Time
+			expected.ClientIP = record.ClientIP
+			if expected.RequestID == "" {
+				expected.RequestID = record.RequestID
+			}
+			changes := record.Changes
+			record.Changes = nil
+			if !reflect.DeepEqual(record, expected) {
+				t.Errorf("expected record %+v, got %+v", expected, record)
+			}
+			if !reflect.DeepEqual(changes, tc.expectedChanges) {
+				t.Errorf("expected changes %+v, got %+v", tc.expectedChanges, changes)
+			}
+		})
+	}
+}
+
+func TestAuditMiddleware_ClientIPBehindProxy(t *testing.T) {
+	testCases := []struct {
+		name         string
+		peerIP       string
+		forwardedFor string
+		expectedIP   string
+	}{
+		{name: "TrustedProxy", peerIP: "10.0.0.1", forwardedFor: "198.51.100.1", expectedIP: "198.51.100.1"},
+		{name: "SpoofedHeader", peerIP: "192.0.2.1", forwardedFor: "198.51.100.1", expectedIP: "192.0.2.1"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, auditDB := setUpAuditRouter()
+			if err := router.SetTrustedProxies([]string{"10.0.0.1"}); err != nil {
+				t.Fatalf("failed to set trusted proxies: %v", err)
+			}
+			req, err := http.NewRequest(http.MethodDelete, "/config/v1/role/auditor", nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.RemoteAddr = tc.peerIP + ":12345"
+			req.Header.Set("X-Forwarded-For", tc.forwardedFor)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if len(auditDB.records) != 1 {
+				t.Fatalf("expected one audit record, got %+v", auditDB.records)
+			}
+			if record := auditRecord(t, auditDB.records[0]); record.ClientIP != tc.expectedIP {
+				t.Errorf("expected client IP %s, got %s", tc.expectedIP, record.ClientIP)
+			}
+		})
+	}
+}
+
+func TestAuditMiddleware_ReadsNotRecorded(t *testing.T) {
+	router, auditDB := setUpAuditRouter()
+	token, err := auth.GenerateJWT("janedoe", configmodels.AdminRole, "", "mockSession", mockJWTSecret)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodGet, "/config/v1/role/auditor", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", bearer+token)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected `%v`, got `%v`", http.StatusOK, w.Code)
+	}
+	if len(auditDB.records) != 0 || w.Header().Get(auditRequestIDHeader) != "" {
+		t.Errorf("expected no audit record, got %+v", auditDB.records)
+	}
+}
+
+func TestDiffDocuments(t *testing.T) {
+	before := map[string]any{
+		"_id":  "65a1",
+		"ueId": "imsi-001010000000001",
+		"permanentKey": bson.M{
+			"permanentKeyValue": "8baf473f2f8fd09487cccbd7097c6862",
+			"encryptionKey":     float64(0),
+		},
+		"sequenceNumber": "16f3b3f70fc2",
+		"removed":        true,
+	}
+	after := map[string]any{
+		"_id":  "65a2",
+		"ueId": "imsi-001010000000001",
+		"permanentKey": map[string]any{
+			"permanentKeyValue": "5122250214c33e723a5dd523fc145fc0",
+			"encryptionKey":     int32(0),
+		},
+		"sequenceNumber": "16f3b3f70fc3",
+		"added":          []any{"a"},
+	}
+	expected := []configmodels.AuditChange{
+		{Field: "added", After: []any{"a"}},
+		{Field: "permanentKey.permanentKeyValue", Before: auditRedactedValue, After: auditRedactedValue},
+		{Field: "removed", Before: true},
+		{Field: "sequenceNumber", Before: "16f3b3f70fc2", After: "16f3b3f70fc3"},
+	}
+	if changes := diffDocuments(before, after); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected changes %+v, got %+v", expected, changes)
+	}
+	if changes := diffDocuments(nil, nil); changes != nil {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestGetAuditRecords(t *testing.T) {
+	rawRecord := configmodels.ToBsonM(configmodels.DBAuditRecord{
+		Time:       1735689600123,
+		Username:   "janedoe",
+		ClientIP:   "192.0.2.1",
+		Method:     http.MethodDelete,
+		Route:      "/config/v1/network-slice/:slice-name",
+		Path:       "/config/v1/network-slice/slice1",
+		ResourceID: "slice1",
+		RequestID:  "request-1",
+		StatusCode: http.StatusOK,
+		Outcome:    configmodels.AuditOutcomeSuccess,
+	})
+	testCases := []struct {
+		name           string
+		query          string
+		findErr        error
+		expectedCode   int
+		expectedFilter bson.M
+		expectedLimit  int64
+		expectedBody   string
+	}{
+		{
+			name:           "NoFilter",
+			expectedCode:   http.StatusOK,
+			expectedFilter: bson.M{},
+			expectedLimit:  defaultAuditLimit,
+			expectedBody:   `[{"time":"2025-01-01T00:00:00.123Z","username":"janedoe","clientIp":"192.0.2.1","method":"DELETE","route":"/config/v1/network-slice/:slice-name","path":"/config/v1/network-slice/slice1","resourceId":"slice1","requestId":"request-1","statusCode":200,"outcome":"success"}]`,
+		},
+		{
+			name:         "Filters",
+			query:        "?from=2025-01-01T00:00:00Z&to=2025-01-02T00:00:00Z&username=janedoe&resource=slice1&route=/config/v1/network-slice/:slice-name&limit=10",
+			expectedCode: http.StatusOK,
+			expectedFilter: bson.M{
+				"time":       bson.M{"$gte": int64(1735689600000), "$lte": int64(1735776000000)},
+				"username":   "janedoe",
+				"resourceId": "slice1",
+				"route":      "/config/v1/network-slice/:slice-name",
+			},
+			expectedLimit: 10,
+		},
+		{
+			name:         "InvalidTime",
+			query:        "?from=yesterday",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"error":"` + errorInvalidAuditTime + `"}`,
+		},
+		{
+			name:         "InvalidLimit",
+			query:        "?limit=1001",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"error":"` + errorInvalidAuditLimit + `"}`,
+		},
+		{
+			name:         "DBError",
+			findErr:      errors.New("mock error"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"error":"` + errorRetrieveAuditRecord + `"}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+			AddConfigV1Service(router)
+			auditDB := &MockMongoClientAudit{records: []map[string]any{rawRecord}, findErr: tc.findErr}
+			dbadapter.CommonDBClient = auditDB
+			req, err := http.NewRequest(http.MethodGet, "/config/v1/audit"+tc.query, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedCode {
+				t.Fatalf("expected `%v`, got `%v` %s", tc.expectedCode, w.Code, w.Body.String())
+			}
+			if tc.expectedBody != "" && w.Body.String() != tc.expectedBody {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedBody, w.Body.String())
+			}
+			if tc.expectedFilter == nil {
+				return
+			}
+			if !reflect.DeepEqual(auditDB.findFilter, tc.expectedFilter) {
+				t.Errorf("expected filter %+v, got %+v", tc.expectedFilter, auditDB.findFilter)
+			}
+			expectedSort := bson.D{{Key: "time", Value: -1}}
+			if auditDB.findOptions.Limit != tc.expectedLimit || !reflect.DeepEqual(auditDB.findOptions.Sort, expectedSort) {
+				t.Errorf("expected the latest %d records, got %+v", tc.expectedLimit, auditDB.findOptions)
+			}
+		})
+	}
+}
@@ -20,7 +20,7 @@ const (
 	bearer      = "Bearer "
 )
 
-var mockJWTSecret = []byte("mockSecret")
+var mockJWTSecret = auth.NewSigningKeys([]byte("mockSecret"))
 
 func MockOperation(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"Result": "Operation Executed"})
@@ -91,6 +91,26 @@ func TestAdminOrUserAuthorizationMiddleware_NoHeaderRequest(t *testing.T) {
 			method: http.MethodPost,
 			url:    "/config/v1/device-group/some-name",
 		},
+		{
+			name:   "PostDeviceGroupImsis",
+			method: http.MethodPost,
+			url:    "/config/v1/device-group/some-name/imsis",
+		},
+		{
+			name:   "DeleteDeviceGroupImsis",
+			method: http.MethodDelete,
+			url:    "/config/v1/device-group/some-name/imsis",
+		},
+		{
+			name:   "GetIpPools",
+			method: http.MethodGet,
+			url:    "/config/v1/ip-pools",
+		},
+		{
+			name:   "GetSyncJob",
+			method: http.MethodGet,
+			url:    "/config/v1/jobs/some-id",
+		},
 		{
 			name:   "GetNetworkSlices",
 			method: http.MethodGet,
@@ -297,7 +317,7 @@ func TestGetUserAccounts_AdminOnlyAuthorizationMiddleware(t *testing.T) {
 			if err != nil {
 				t.Fatalf("failed to create request: %v", err)
 			}
-			jwtToken, err := auth.GenerateJWT(tc.username, tc.role, mockJWTSecret)
+			jwtToken, err := auth.GenerateJWT(tc.username, tc.role, "", "mockSession", mockJWTSecret)
 			if err != nil {
 				t.Fatalf("failed to generate token: %v", err)
 			}
@@ -362,7 +382,7 @@ func TestGetUserAccount_AdminOrMeAuthorizationMiddleware(t *testing.T) {
 			if err != nil {
 				t.Fatalf("failed to create request: %v", err)
 			}
-			jwtToken, err := auth.GenerateJWT(tc.username, tc.role, mockJWTSecret)
+			jwtToken, err := auth.GenerateJWT(tc.username, tc.role, "", "mockSession", mockJWTSecret)
 			if err != nil {
 				t.Fatalf("failed to generate token: %v", err)
 			}
@@ -434,7 +454,7 @@ func TestCreateUserAccount_AdminAuthorizationMiddleware(t *testing.T) {
 			if err != nil {
 				t.Fatalf("failed to create request: %v", err)
 			}
-			jwtToken, err := auth.GenerateJWT(tc.username, tc.role, mockJWTSecret)
+			jwtToken, err := auth.GenerateJWT(tc.username, tc.role, "", "mockSession", mockJWTSecret)
 			if err != nil {
 				t.Fatalf("failed to generate token: %v", err)
 			}
@@ -499,7 +519,7 @@ func TestDeleteUserAccount_AdminOnlyAuthorizationMiddleware(t *testing.T) {
 			if err != nil {
 				t.Fatalf("failed to create request: %v", err)
 			}
-			jwtToken, err := auth.GenerateJWT(tc.username, tc.role, mockJWTSecret)
+			jwtToken, err := auth.GenerateJWT(tc.username, tc.role, "", "mockSession", mockJWTSecret)
 			if err != nil {
 				t.Fatalf("failed to generate token: %v", err)
 			}
@@ -564,7 +584,7 @@ func TestChangePassword_AdminOrMeAuthorizationMiddleware(t *testing.T) {
 			if err != nil {
 				t.Fatalf("failed to create request: %v", err)
 			}
-			jwtToken, err := auth.GenerateJWT(tc.username, tc.role, mockJWTSecret)
+			jwtToken, err := auth.GenerateJWT(tc.username, tc.role, "", "mockSession", mockJWTSecret)
 			if err != nil {
 				t.Fatalf("failed to generate token: %v", err)
 			}
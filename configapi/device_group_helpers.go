@@ -5,8 +5,8 @@
 package configapi
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"math"
 	"net/http"
@@ -16,10 +16,12 @@ import (
 	"sync"
 
 	"github.com/omec-project/openapi/models"
+	"github.com/omec-project/webconsole/backend/factory"
 	"github.com/omec-project/webconsole/backend/logger"
 	"github.com/omec-project/webconsole/configmodels"
 	"github.com/omec-project/webconsole/dbadapter"
 	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/sync/errgroup"
 )
 
 var rwLock sync.RWMutex
@@ -60,7 +62,7 @@ func updateDeviceGroupInNetworkSlices(groupName string) error {
 		networkSlice.SiteDeviceGroup = slices.DeleteFunc(networkSlice.SiteDeviceGroup, func(existingDG string) bool {
 			return groupName == existingDG
 		})
-		if statusCode, err := updateNS(networkSlice, *prevSlice); err != nil {
+		if _, statusCode, err := updateNS(networkSlice, *prevSlice); err != nil {
 			logger.ConfigLog.Errorf("Error updating slice: %s status code: %d error: %+v", networkSlice.SliceName, statusCode, err)
 			errorOccurred = true
 			continue
@@ -72,65 +74,56 @@ func updateDeviceGroupInNetworkSlices(groupName string) error {
 	return nil
 }
 
-func deviceGroupPostHelper(requestDeviceGroup configmodels.DeviceGroups, groupName string) (int, error) {
+func deviceGroupPostHelper(requestDeviceGroup configmodels.DeviceGroups, groupName string) (*configmodels.SyncJob, int, error) {
 	logger.ConfigLog.Infof("received device group: %s", groupName)
 
-	ipdomain := &requestDeviceGroup.IpDomainExpanded
 	logger.ConfigLog.Infof("imsis.size: %v, Imsis: %s", len(requestDeviceGroup.Imsis), requestDeviceGroup.Imsis)
-	logger.ConfigLog.Infof("IP Domain Name: %s", requestDeviceGroup.IpDomainName)
-	logger.ConfigLog.Infof("IP Domain details: %+v", ipdomain)
-	logger.ConfigLog.Infof("dnn name: %s", ipdomain.Dnn)
-	logger.ConfigLog.Infof("ue pool: %s", ipdomain.UeIpPool)
-	logger.ConfigLog.Infof("dns Primary: %s", ipdomain.DnsPrimary)
-	logger.ConfigLog.Infof("dns Secondary: %s", ipdomain.DnsSecondary)
-	logger.ConfigLog.Infof("ip mtu: %v", ipdomain.Mtu)
+	logger.ConfigLog.Infof("IMSI ranges: %+v", requestDeviceGroup.ImsiRanges)
 	logger.ConfigLog.Infof("device Group Name: %s", groupName)
 
-	if ipdomain.UeDnnQos != nil {
-		ipdomain.UeDnnQos.DnnMbrDownlink = convertToBps(ipdomain.UeDnnQos.DnnMbrDownlink, ipdomain.UeDnnQos.BitrateUnit)
-		if ipdomain.UeDnnQos.DnnMbrDownlink < 0 {
-			ipdomain.UeDnnQos.DnnMbrDownlink = math.MaxInt64
-		}
-		logger.ConfigLog.Infof("MbrDownLink: %v", ipdomain.UeDnnQos.DnnMbrDownlink)
-		ipdomain.UeDnnQos.DnnMbrUplink = convertToBps(ipdomain.UeDnnQos.DnnMbrUplink, ipdomain.UeDnnQos.BitrateUnit)
-		if ipdomain.UeDnnQos.DnnMbrUplink < 0 {
-			ipdomain.UeDnnQos.DnnMbrUplink = math.MaxInt64
+	for _, ipDomain := range requestDeviceGroup.GetIpDomains() {
+		ipdomain := &ipDomain.IpDomainExpanded
+		logger.ConfigLog.Infof("IP Domain Name: %s", ipDomain.IpDomainName)
+		logger.ConfigLog.Infof("IP Domain details: %+v", ipdomain)
+		logger.ConfigLog.Infof("dnn name: %s", ipdomain.Dnn)
+		logger.ConfigLog.Infof("ue pool: %s", ipdomain.UeIpPool)
+		logger.ConfigLog.Infof("dns Primary: %s", ipdomain.DnsPrimary)
+		logger.ConfigLog.Infof("dns Secondary: %s", ipdomain.DnsSecondary)
+		logger.ConfigLog.Infof("ip mtu: %v", ipdomain.Mtu)
+
+		// the QoS is shared by pointer, so the conversion applies to the device group
+		if ipdomain.UeDnnQos != nil {
+			normalizeUeDnnQos(ipdomain.UeDnnQos)
+			logger.ConfigLog.Infof("MbrDownLink: %v", ipdomain.UeDnnQos.DnnMbrDownlink)
+			logger.ConfigLog.Infof("MbrUpLink: %v", ipdomain.UeDnnQos.DnnMbrUplink)
 		}
-		logger.ConfigLog.Infof("MbrUpLink: %v", ipdomain.UeDnnQos.DnnMbrUplink)
 	}
 
 	prevDevGroup := getDeviceGroupByName(groupName)
 	requestDeviceGroup.DeviceGroupName = groupName
 	if prevDevGroup == nil {
 		logger.ConfigLog.Infof("creating new device group %s", groupName)
-		statusCode, err := createDG(&requestDeviceGroup)
-		if err != nil {
-			return statusCode, err
-		}
-	} else {
-		statusCode, err := updateDG(&requestDeviceGroup, prevDevGroup)
-		if err != nil {
-			return statusCode, err
-		}
+		return createDG(&requestDeviceGroup)
 	}
-
-	return http.StatusOK, nil
+	return updateDG(&requestDeviceGroup, prevDevGroup)
 }
 
-func createDG(devGroup *configmodels.DeviceGroups) (int, error) {
-	if statusCode, err := handleDeviceGroupPost(devGroup, nil); err != nil {
+func createDG(devGroup *configmodels.DeviceGroups) (*configmodels.SyncJob, int, error) {
+	job, statusCode, err := handleDeviceGroupPost(devGroup, nil)
+	if err != nil {
 		logger.ConfigLog.Errorf("error creating device group %+v: %+v", devGroup, err)
-		return statusCode, err
+		return nil, statusCode, err
 	}
-	return http.StatusOK, nil
+	return job, http.StatusAccepted, nil
 }
 
-func updateDG(devGroup *configmodels.DeviceGroups, prevDevGroup *configmodels.DeviceGroups) (int, error) {
-	if statusCode, err := handleDeviceGroupPost(devGroup, prevDevGroup); err != nil {
+func updateDG(devGroup *configmodels.DeviceGroups, prevDevGroup *configmodels.DeviceGroups) (*configmodels.SyncJob, int, error) {
+	job, statusCode, err := handleDeviceGroupPost(devGroup, prevDevGroup)
+	if err != nil {
 		logger.ConfigLog.Errorf("error updating device group %+v: %+v", devGroup, err)
-		return statusCode, err
+		return nil, statusCode, err
 	}
-	return http.StatusOK, nil
+	return job, http.StatusAccepted, nil
 }
 
 func convertToBps(val int64, unit string) int64 {
@@ -149,51 +142,28 @@ func convertToBps(val int64, unit string) int64 {
 	}
 }
 
-func handleDeviceGroupPost(devGroup *configmodels.DeviceGroups, prevDevGroup *configmodels.DeviceGroups) (int, error) {
+// handleDeviceGroupPost stores the device group and queues the sync of its
+// subscribers. The returned job tracks the sync.
+func handleDeviceGroupPost(devGroup *configmodels.DeviceGroups, prevDevGroup *configmodels.DeviceGroups) (*configmodels.SyncJob, int, error) {
 	filter := bson.M{"group-name": devGroup.DeviceGroupName}
 	devGroupDataBsonA := configmodels.ToBsonM(devGroup)
 	result, err := dbadapter.CommonDBClient.RestfulAPIPost(devGroupDataColl, filter, devGroupDataBsonA)
 	if err != nil {
 		logger.AppLog.Errorf("failed to post device group data for %s: %+v", devGroup.DeviceGroupName, err)
-		return http.StatusInternalServerError, err
+		return nil, http.StatusInternalServerError, err
 	}
 	logger.AppLog.Infof("DB operation result for device group %s: %v",
 		devGroup.DeviceGroupName, result)
-	statusCode, err := syncSubConcurrentlyInGroup(devGroup, prevDevGroup)
+	job, err := enqueueDeviceGroupSyncJob(devGroup, prevDevGroup)
 	if err != nil {
 		logger.WebUILog.Errorln(err.Error())
-		return statusCode, err
+		return nil, http.StatusInternalServerError, err
 	}
 	logger.AppLog.Debugf("succeeded to post device group data for %s", devGroup.DeviceGroupName)
-	return http.StatusOK, nil
-}
-
-func syncSubConcurrentlyInGroup(devGroup *configmodels.DeviceGroups, prevDevGroup *configmodels.DeviceGroups) (int, error) {
-	syncSliceStopMutex.Lock()
-	if SyncSliceStop {
-		syncSliceStopMutex.Unlock()
-		return http.StatusServiceUnavailable, errors.New("error: the sync function is running")
-	}
-	SyncSliceStop = true
-	syncSliceStopMutex.Unlock()
-
-	go func() {
-		defer func() {
-			syncSliceStopMutex.Lock()
-			SyncSliceStop = false
-			syncSliceStopMutex.Unlock()
-		}()
-
-		_, err := syncDeviceGroupSubscriber(devGroup, prevDevGroup)
-		if err != nil {
-			logger.AppLog.Errorf("error syncing subscribers: %s", err)
-		}
-	}()
-
-	return 0, nil // Retorno inmediato, operación en background
+	return job, http.StatusAccepted, nil
 }
 
-var syncDeviceGroupSubscriber = func(devGroup *configmodels.DeviceGroups, prevDevGroup *configmodels.DeviceGroups) (int, error) {
+var syncDeviceGroupSubscriber = func(devGroup *configmodels.DeviceGroups, prevDevGroup *configmodels.DeviceGroups, progress *syncJobProgress) (int, error) {
 	rwLock.Lock()
 	defer rwLock.Unlock()
 	slice := findSliceByDeviceGroup(devGroup.DeviceGroupName)
@@ -225,19 +195,20 @@ var syncDeviceGroupSubscriber = func(devGroup *configmodels.DeviceGroups, prevDe
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				dnn := devGroup.IpDomainExpanded.Dnn
 				err := updatePolicyAndProvisionedData(
 					imsi,
 					slice.SiteInfo.Plmn.Mcc,
 					slice.SiteInfo.Plmn.Mnc,
 					snssai,
-					dnn,
-					devGroup.IpDomainExpanded.UeDnnQos,
+					devGroup.GetIpDomains(),
 				)
 				if err != nil {
 					logger.AppLog.Errorf("updatePolicyAndProvisionedData failed for IMSI %s: %+v", imsi, err)
+					progress.fail(imsi)
 					errorOccured = true
+					return
 				}
+				progress.done(1)
 			}()
 		}
 	}
@@ -251,12 +222,53 @@ var syncDeviceGroupSubscriber = func(devGroup *configmodels.DeviceGroups, prevDe
 			err := removeSubscriberEntriesRelatedToDeviceGroups(slice.SiteInfo.Plmn.Mcc, slice.SiteInfo.Plmn.Mnc, imsi)
 			if err != nil {
 				logger.ConfigLog.Errorln(err)
+				progress.fail(imsi)
 				errorOccured = true
+				return
 			}
+			progress.done(1)
 		}()
 	}
 	wg.Wait()
 
+	// IMSI ranges are synced page by page from the subscribers that exist,
+	// without expanding the ranges themselves
+	for _, imsiRange := range devGroup.ImsiRanges {
+		err := forEachImsiInRange(dbadapter.AuthDBClient, AuthSubsDataColl, imsiRange, func(imsis []string) error {
+			err := updatePolicyAndProvisionedDataBatch(
+				imsis,
+				slice.SiteInfo.Plmn.Mcc,
+				slice.SiteInfo.Plmn.Mnc,
+				snssai,
+				devGroup.GetIpDomains(),
+			)
+			if err != nil {
+				progress.fail(imsis...)
+				return err
+			}
+			progress.done(len(imsis))
+			return nil
+		})
+		if err != nil {
+			logger.AppLog.Errorf("failed to sync IMSI range %+v of device group %s: %+v", imsiRange, devGroup.DeviceGroupName, err)
+			errorOccured = true
+		}
+	}
+	for _, imsiRange := range getDeletedImsiRanges(devGroup, prevDevGroup) {
+		err := forEachImsiInRange(dbadapter.CommonDBClient, AmDataColl, imsiRange, func(imsis []string) error {
+			if err := removeSubscribersRelatedToDeviceGroups(slice.SiteInfo.Plmn.Mcc, slice.SiteInfo.Plmn.Mnc, imsis, devGroup); err != nil {
+				progress.fail(imsis...)
+				return err
+			}
+			progress.done(len(imsis))
+			return nil
+		})
+		if err != nil {
+			logger.AppLog.Errorf("failed to remove IMSI range %+v of device group %s: %+v", imsiRange, devGroup.DeviceGroupName, err)
+			errorOccured = true
+		}
+	}
+
 	if errorOccured {
 		return http.StatusInternalServerError, fmt.Errorf("syncDeviceGroupSubscriber failed, please check logs")
 	} else {
@@ -264,6 +276,77 @@ var syncDeviceGroupSubscriber = func(devGroup *configmodels.DeviceGroups, prevDe
 	}
 }
 
+// forEachImsiInRange calls fn with pages of the IMSIs in the range that have a
+// document in the given collection, walking the collection by ueId so the
+// range is never expanded in memory.
+func forEachImsiInRange(client dbadapter.DBInterface, collName string, imsiRange configmodels.DeviceGroupsImsiRange, fn func(imsis []string) error) error {
+	start, end, err := imsiRange.Bounds()
+	if err != nil {
+		return err
+	}
+	if client == nil {
+		logger.AppLog.Debugf("forEachImsiInRange: DB client is nil; skipping range %s-%s", start, end)
+		return nil
+	}
+	lowerOp, lower := "$gte", "imsi-"+start
+	findOpts := dbadapter.FindOptions{
+		Limit:      imsiBatchSize,
+		Sort:       bson.D{{Key: "ueId", Value: 1}},
+		Projection: bson.M{"ueId": 1},
+	}
+	for {
+		filter := bson.M{"ueId": bson.M{lowerOp: lower, "$lte": "imsi-" + end}}
+		docs, err := client.RestfulAPIGetManyWithOptions(collName, filter, findOpts)
+		if err != nil {
+			return err
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+		imsis := make([]string, 0, len(docs))
+		for _, doc := range docs {
+			ueId, _ := doc["ueId"].(string)
+			lower = ueId
+			// ueIds of other lengths sort inside the bounds too
+			if imsi := strings.TrimPrefix(ueId, "imsi-"); imsiRange.Contains(imsi) {
+				imsis = append(imsis, imsi)
+			}
+		}
+		if len(imsis) > 0 {
+			if err = fn(imsis); err != nil {
+				return err
+			}
+		}
+		if len(docs) < imsiBatchSize {
+			return nil
+		}
+		lowerOp = "$gt"
+	}
+}
+
+// removeSubscribersRelatedToDeviceGroups removes the device group provisioned
+// data of the IMSIs that are not members of the kept device group.
+func removeSubscribersRelatedToDeviceGroups(mcc, mnc string, imsis []string, kept *configmodels.DeviceGroups) error {
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(factory.WebUIConfig.Configuration.Mongodb.ConcurrencyOps)
+	for _, imsi := range imsis {
+		if kept != nil && kept.ContainsImsi(imsi) {
+			continue
+		}
+		g.Go(func() error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err := removeSubscriberEntriesRelatedToDeviceGroups(mcc, mnc, imsi); err != nil {
+				logger.ConfigLog.Errorf("failed to remove subscriber for IMSI %s: %+v", imsi, err)
+				return err
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
 func handleDeviceGroupDelete(groupName string) error {
 	rwLock.Lock()
 	defer rwLock.Unlock()
@@ -293,8 +376,29 @@ func getDeviceGroupByName(name string) *configmodels.DeviceGroups {
 	return &devGroupData
 }
 
+func getDeviceGroups() ([]*configmodels.DeviceGroups, error) {
+	rawDeviceGroups, err := dbadapter.CommonDBClient.RestfulAPIGetMany(devGroupDataColl, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch device groups: %w", err)
+	}
+	var deviceGroups []*configmodels.DeviceGroups
+	for _, rawDeviceGroup := range rawDeviceGroups {
+		var deviceGroup configmodels.DeviceGroups
+		if err = json.Unmarshal(configmodels.MapToByte(rawDeviceGroup), &deviceGroup); err != nil {
+			return nil, fmt.Errorf("could not unmarshal device group %+v: %w", rawDeviceGroup, err)
+		}
+		deviceGroups = append(deviceGroups, &deviceGroup)
+	}
+	return deviceGroups, nil
+}
+
 func findSliceByDeviceGroup(DevGroupName string) *configmodels.Slice {
-	for _, slice := range getSlices() {
+	networkSlices, err := getSlices()
+	if err != nil {
+		logger.AppLog.Errorln(err)
+		return nil
+	}
+	for _, slice := range networkSlices {
 		for _, dgName := range slice.SiteDeviceGroup {
 			if dgName == DevGroupName {
 				logger.WebUILog.Infof("device Group [%s] is part of slice: %s", dgName, slice.SliceName)
@@ -304,3 +408,15 @@ func findSliceByDeviceGroup(DevGroupName string) *configmodels.Slice {
 	}
 	return nil
 }
+
+// normalizeUeDnnQos converts the DNN bitrates of an IP domain to bps.
+func normalizeUeDnnQos(qos *configmodels.DeviceGroupsIpDomainExpandedUeDnnQos) {
+	qos.DnnMbrDownlink = convertToBps(qos.DnnMbrDownlink, qos.BitrateUnit)
+	if qos.DnnMbrDownlink < 0 {
+		qos.DnnMbrDownlink = math.MaxInt64
+	}
+	qos.DnnMbrUplink = convertToBps(qos.DnnMbrUplink, qos.BitrateUnit)
+	if qos.DnnMbrUplink < 0 {
+		qos.DnnMbrUplink = math.MaxInt64
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"slices"
+	"sort"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -271,7 +273,7 @@ func Test_handleDeviceGroupPost(t *testing.T) {
 	deviceGroups[3].IpDomainExpanded.UeDnnQos.TrafficClass = nil
 	deviceGroups[4].IpDomainExpanded.UeDnnQos = nil
 
-	syncDeviceGroupSubscriber = func(devGroup, prevDevGroup *configmodels.DeviceGroups) (int, error) {
+	syncDeviceGroupSubscriber = func(devGroup, prevDevGroup *configmodels.DeviceGroups, progress *syncJobProgress) (int, error) {
 		return http.StatusOK, nil
 	}
 
@@ -279,21 +281,11 @@ func Test_handleDeviceGroupPost(t *testing.T) {
 	for _, testGroup := range deviceGroups {
 		dg := testGroup
 
-		for {
-			syncSliceStopMutex.Lock()
-			if !SyncSliceStop {
-				syncSliceStopMutex.Unlock()
-				t.Log("wait wait wait")
-				break
-			}
-			syncSliceStopMutex.Unlock()
-		}
-
 		t.Run(dg.DeviceGroupName, func(t *testing.T) {
 			mockDB := &DeviceGroupMockDBClient{}
 			dbadapter.CommonDBClient = mockDB
 
-			statusCode, err := handleDeviceGroupPost(&dg, nil)
+			_, statusCode, err := handleDeviceGroupPost(&dg, nil)
 			if err != nil {
 				t.Fatalf("Could not handle device group post: %+v status code: %d", err, statusCode)
 			}
@@ -329,23 +321,19 @@ func Test_handleDeviceGroupPost(t *testing.T) {
 	// check the sync condition
 	dbadapter.CommonDBClient = originalDBClient
 
-	t.Run("Check the syncSliceCondition", func(t *testing.T) {
-		// Set SyncSliceStop to true to simulate running sync
-		SyncSliceStop = true
-
+	t.Run("Queue write while a sync is queued", func(t *testing.T) {
 		mockDB := &DeviceGroupMockDBClient{}
 		dbadapter.CommonDBClient = mockDB
 
-		statusCode, err := handleDeviceGroupPost(&deviceGroups[0], nil)
-		if err != nil {
-			t.Logf("Could not handle device group post: %+v status code: %d", err, statusCode)
-		}
-		if err == nil {
-			t.Fatal("expected error due to sync condition, got nil")
+		for range 2 {
+			job, statusCode, err := handleDeviceGroupPost(&deviceGroups[0], nil)
+			if err != nil {
+				t.Fatalf("Could not handle device group post: %+v status code: %d", err, statusCode)
+			}
+			if statusCode != http.StatusAccepted || job == nil || job.JobId == "" {
+				t.Fatalf("expected a queued job, got status code %d and job %+v", statusCode, job)
+			}
 		}
-
-		// Reset the sync flag
-		SyncSliceStop = false
 	})
 	dbadapter.CommonDBClient = originalDBClient
 }
@@ -362,7 +350,7 @@ func Test_handleDeviceGroupPost_alreadyExists(t *testing.T) {
 	deviceGroups[3].IpDomainExpanded.UeDnnQos.TrafficClass = nil
 	deviceGroups[4].IpDomainExpanded.UeDnnQos = nil
 
-	syncDeviceGroupSubscriber = func(devGroup, prevDevGroup *configmodels.DeviceGroups) (int, error) {
+	syncDeviceGroupSubscriber = func(devGroup, prevDevGroup *configmodels.DeviceGroups, progress *syncJobProgress) (int, error) {
 		return http.StatusOK, nil
 	}
 
@@ -371,21 +359,11 @@ func Test_handleDeviceGroupPost_alreadyExists(t *testing.T) {
 	for _, testGroup := range deviceGroups {
 		dg := testGroup
 
-		for {
-			syncSliceStopMutex.Lock()
-			if !SyncSliceStop {
-				syncSliceStopMutex.Unlock()
-				t.Log("wait wait wait")
-				break
-			}
-			syncSliceStopMutex.Unlock()
-		}
-
 		t.Run(dg.DeviceGroupName, func(t *testing.T) {
 			mock := &DeviceGroupMockDBClient{configuredDeviceGroups: []configmodels.DeviceGroups{dg}}
 			dbadapter.CommonDBClient = mock
 
-			statusCode, err := handleDeviceGroupPost(&dg, &dg)
+			_, statusCode, err := handleDeviceGroupPost(&dg, &dg)
 			if err != nil {
 				t.Fatalf("handleDeviceGroupPost returned error: %+v statusCode: %d", err, statusCode)
 			}
@@ -471,7 +449,7 @@ func TestDeviceGroupPostHandler_DeviceGroupNameValidation(t *testing.T) {
 		{
 			name:         "Device Group valid name",
 			route:        "/config/v1/device-group/valid-devicegroup",
-			expectedCode: http.StatusOK,
+			expectedCode: http.StatusAccepted,
 		},
 	}
 
@@ -479,7 +457,7 @@ func TestDeviceGroupPostHandler_DeviceGroupNameValidation(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			originalDBClient := dbadapter.CommonDBClient
 			defer func() { dbadapter.CommonDBClient = originalDBClient }()
-			if tc.expectedCode == http.StatusOK {
+			if tc.expectedCode == http.StatusAccepted {
 				dbadapter.CommonDBClient = &DeviceGroupMockDBClient{}
 			}
 			newDeviceGroup := deviceGroup("name")
@@ -501,3 +479,54 @@ func TestDeviceGroupPostHandler_DeviceGroupNameValidation(t *testing.T) {
 		})
 	}
 }
+
+func Test_forEachImsiInRange(t *testing.T) {
+	var ueIds []string
+	for i := 0; i < 1500; i++ {
+		ueIds = append(ueIds, fmt.Sprintf("imsi-00101%010d", i))
+	}
+	// a shorter IMSI that sorts between the bounds of the range
+	ueIds = append(ueIds, "imsi-00101000000005")
+	sort.Strings(ueIds)
+
+	var queries int
+	mockDB := &dbadapter.MockDBClient{
+		GetManyWithOptionsFn: func(collName string, filter bson.M, findOpts dbadapter.FindOptions) ([]map[string]any, error) {
+			queries++
+			bounds := filter["ueId"].(bson.M)
+			var docs []map[string]any
+			for _, ueId := range ueIds {
+				if gte, ok := bounds["$gte"].(string); ok && ueId < gte {
+					continue
+				}
+				if gt, ok := bounds["$gt"].(string); ok && ueId <= gt {
+					continue
+				}
+				if ueId > bounds["$lte"].(string) || int64(len(docs)) == findOpts.Limit {
+					continue
+				}
+				docs = append(docs, map[string]any{"ueId": ueId})
+			}
+			return docs, nil
+		},
+	}
+
+	imsiRange := configmodels.DeviceGroupsImsiRange{Start: "001010000000000", End: "001010000001199"}
+	var got []string
+	err := forEachImsiInRange(mockDB, AuthSubsDataColl, imsiRange, func(imsis []string) error {
+		got = append(got, imsis...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1200 {
+		t.Errorf("expected 1200 IMSIs, got %d", len(got))
+	}
+	if slices.Contains(got, "00101000000005") {
+		t.Errorf("IMSI of a different length must not be part of the range")
+	}
+	if queries != 2 {
+		t.Errorf("expected the range to be read in 2 pages, got %d", queries)
+	}
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package configapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/auth"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	errorAPIKeyNotFound         = "API key not found"
+	errorCreateAPIKey           = "failed to create API key"
+	errorDeleteAPIKey           = "failed to delete API key"
+	errorExpiredAPIKey          = "expiresAt must be in the future"
+	errorMissingAPIKeyName      = "name is required"
+	errorNotAServiceAccount     = "API keys can only be given to service accounts"
+	errorRetrieveAPIKeys        = "failed to retrieve API keys"
+	errorServiceAccountNotFound = "service account not found"
+)
+
+// fetchServiceAccount writes the error response and returns nil when the
+// account is missing or not a service account.
+func fetchServiceAccount(c *gin.Context) *configmodels.DBUserAccount {
+	dbUser, err := fetchDBUserAccount(c.Param("username"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveUserAccount})
+		return nil
+	}
+	if dbUser == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": errorServiceAccountNotFound})
+		return nil
+	}
+	if !dbUser.ServiceAccount {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorNotAServiceAccount})
+		return nil
+	}
+	return dbUser
+}
+
+// GetAPIKeys godoc
+//
+// @Description  Return the API keys of a service account, without the keys themselves
+// @Tags         User Accounts
+// @Produce      json
+// @Param        username    path    string    true    "Username of the service account"
+// @Security     BearerAuth
+// @Success      200  {array}   configmodels.APIKeyResponse  "API keys"
+// @Failure      400  {object}  nil                          "Not a service account"
+// @Failure      401  {object}  nil                          "Authorization failed"
+// @Failure      403  {object}  nil                          "Forbidden"
+// @Failure      404  {object}  nil                          "Service account not found. Or Page not found if enableAuthentication is disabled"
+// @Failure      500  {object}  nil                          "Error retrieving API keys"
+// @Router      /config/v1/account/{username}/api-key  [get]
+func GetAPIKeys(c *gin.Context) {
+	logger.WebUILog.Infoln("get API keys")
+	dbUser := fetchServiceAccount(c)
+	if dbUser == nil {
+		return
+	}
+	rawAPIKeys, err := dbadapter.WebuiDBClient.RestfulAPIGetMany(configmodels.APIKeyDataColl, bson.M{"username": dbUser.Username})
+	if err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveAPIKeys})
+		return
+	}
+	apiKeys := make([]configmodels.APIKeyResponse, 0, len(rawAPIKeys))
+	for _, rawAPIKey := range rawAPIKeys {
+		var apiKey configmodels.DBAPIKey
+		if err = json.Unmarshal(configmodels.MapToByte(rawAPIKey), &apiKey); err != nil {
+			logger.AppLog.Errorf("%s: %+v", errorRetrieveAPIKeys, err)
+			continue
+		}
+		apiKeys = append(apiKeys, apiKey.ToResponse())
+	}
+	c.JSON(http.StatusOK, apiKeys)
+}
+
+// APIKeyPost godoc
+//
+// @Description  Create an API key for a service account. The key is only returned in this response.
+// @Tags         User Accounts
+// @Produce      json
+// @Param        username    path    string                             true    "Username of the service account"
+// @Param        params      body    configmodels.CreateAPIKeyParams    true    "Name, permissions and expiry of the key"
+// @Security     BearerAuth
+// @Success      201  {object}  configmodels.CreateAPIKeyResponse  "API key created"
+// @Failure      400  {object}  nil                                "Bad request"
+// @Failure      401  {object}  nil                                "Authorization failed"
+// @Failure      403  {object}  nil                                "Forbidden"
+// @Failure      404  {object}  nil                                "Service account not found. Or Page not found if enableAuthentication is disabled"
+// @Failure      500  {object}  nil                                "Failed to create the API key"
+// @Router      /config/v1/account/{username}/api-key  [post]
+func APIKeyPost(c *gin.Context) {
+	logger.WebUILog.Infoln("create API key")
+	var createAPIKeyParams configmodels.CreateAPIKeyParams
+	if err := c.ShouldBindJSON(&createAPIKeyParams); err != nil {
+		logger.WebUILog.Errorln(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorInvalidDataProvided})
+		return
+	}
+	if createAPIKeyParams.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMissingAPIKeyName})
+		return
+	}
+	for _, permission := range createAPIKeyParams.Permissions {
+		if !slices.Contains(configmodels.Permissions, permission) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s %s", errorUnknownPermission, permission)})
+			return
+		}
+	}
+	if createAPIKeyParams.ExpiresAt != nil && !createAPIKeyParams.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorExpiredAPIKey})
+		return
+	}
+	dbUser := fetchServiceAccount(c)
+	if dbUser == nil {
+		return
+	}
+	apiKey, key, err := auth.CreateAPIKey(dbUser.Username, createAPIKeyParams)
+	if err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorCreateAPIKey})
+		return
+	}
+	c.JSON(http.StatusCreated, configmodels.CreateAPIKeyResponse{APIKeyResponse: apiKey.ToResponse(), Key: key})
+}
+
+// APIKeyDelete godoc
+//
+// @Description  Revoke an API key of a service account
+// @Tags         User Accounts
+// @Produce      json
+// @Param        username    path    string    true    "Username of the service account"
+// @Param        key-id      path    string    true    "ID of the API key"
+// @Security     BearerAuth
+// @Success      200  {object}  nil  "API key revoked"
+// @Failure      401  {object}  nil  "Authorization failed"
+// @Failure      403  {object}  nil  "Forbidden"
+// @Failure      404  {object}  nil  "API key not found. Or Page not found if enableAuthentication is disabled"
+// @Failure      500  {object}  nil  "Failed to delete the API key"
+// @Router      /config/v1/account/{username}/api-key/{key-id}  [delete]
+func APIKeyDelete(c *gin.Context) {
+	logger.WebUILog.Infoln("delete API key")
+	filter := bson.M{"id": c.Param("key-id"), "username": c.Param("username")}
+	rawAPIKey, err := dbadapter.WebuiDBClient.RestfulAPIGetOne(configmodels.APIKeyDataColl, filter)
+	if err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveAPIKeys})
+		return
+	}
+	if len(rawAPIKey) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": errorAPIKeyNotFound})
+		return
+	}
+	if err = dbadapter.WebuiDBClient.RestfulAPIDeleteOne(configmodels.APIKeyDataColl, filter); err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorDeleteAPIKey})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package configapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MockMongoClientAPIKeys keeps the user accounts and the API keys written to it.
+type MockMongoClientAPIKeys struct {
+	dbadapter.DBInterface
+	users   map[string]map[string]any
+	apiKeys []map[string]any
+}
+
+func newMockMongoClientAPIKeys() *MockMongoClientAPIKeys {
+	return &MockMongoClientAPIKeys{users: map[string]map[string]any{
+		"janedoe": {"username": "janedoe", "password": hashPassword("password123!"), "role": configmodels.AdminRole},
+		"johndoe": {"username": "johndoe", "password": hashPassword("password-123"), "role": configmodels.UserRole},
+	}}
+}
+
+func matchesAPIKey(apiKey map[string]any, filter bson.M) bool {
+	for key, value := range filter {
+		if apiKey[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (db *MockMongoClientAPIKeys) RestfulAPIGetOne(collName string, filter bson.M) (map[string]any, error) {
+	if collName == configmodels.UserAccountDataColl {
+		return db.users[filter["username"].(string)], nil
+	}
+	for _, apiKey := range db.apiKeys {
+		if matchesAPIKey(apiKey, filter) {
+			return apiKey, nil
+		}
+	}
+	return map[string]any{}, nil
+}
+
+func (db *MockMongoClientAPIKeys) RestfulAPIGetMany(collName string, filter bson.M) ([]map[string]any, error) {
+	apiKeys := []map[string]any{}
+	for _, apiKey := range db.apiKeys {
+		if matchesAPIKey(apiKey, filter) {
+			apiKeys = append(apiKeys, apiKey)
+		}
+	}
+	return apiKeys, nil
+}
+
+func (db *MockMongoClientAPIKeys) RestfulAPIPost(collName string, filter bson.M, postData map[string]any) (bool, error) {
+	db.apiKeys = append(db.apiKeys, postData)
+	return false, nil
+}
+
+func (db *MockMongoClientAPIKeys) RestfulAPIPostMany(collName string, filter bson.M, postDataArray []any) error {
+	for _, postData := range postDataArray {
+		user := postData.(bson.M)
+		db.users[user["username"].(string)] = user
+	}
+	return nil
+}
+
+func (db *MockMongoClientAPIKeys) RestfulAPICount(collName string, filter bson.M) (int64, error) {
+	return int64(len(db.users)), nil
+}
+
+func (db *MockMongoClientAPIKeys) RestfulAPIDeleteOne(collName string, filter bson.M) error {
+	if collName == configmodels.UserAccountDataColl {
+		delete(db.users, filter["username"].(string))
+		return nil
+	}
+	return db.RestfulAPIDeleteMany(collName, filter)
+}
+
+func (db *MockMongoClientAPIKeys) RestfulAPIDeleteMany(collName string, filter bson.M) error {
+	if collName != configmodels.APIKeyDataColl {
+		return nil
+	}
+	kept := []map[string]any{}
+	for _, apiKey := range db.apiKeys {
+		if !matchesAPIKey(apiKey, filter) {
+			kept = append(kept, apiKey)
+		}
+	}
+	db.apiKeys = kept
+	return nil
+}
+
+func setUpAPIKeyRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.POST("/config/v1/account", CreateUserAccount)
+	router.DELETE("/config/v1/account/:username", DeleteUserAccount)
+	router.POST("/config/v1/account/:username/change_password", ChangeUserAccountPasssword)
+	router.GET("/config/v1/account/:username/api-key", GetAPIKeys)
+	router.POST("/config/v1/account/:username/api-key", APIKeyPost)
+	router.DELETE("/config/v1/account/:username/api-key/:key-id", APIKeyDelete)
+	return router
+}
+
+func serveAPIKeyRequest(t *testing.T, router *gin.Engine, method, url, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeys(t *testing.T) {
+	router := setUpAPIKeyRouter()
+	mockDB := newMockMongoClientAPIKeys()
+	dbadapter.WebuiDBClient = mockDB
+
+	w := serveAPIKeyRequest(t, router, http.MethodPost, "/config/v1/account", `{"username": "ci", "serviceAccount": true}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected `%v`, got `%v` %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if _, hasPassword := mockDB.users["ci"]["password"]; hasPassword || mockDB.users["ci"]["serviceAccount"] != true {
+		t.Errorf("expected a service account without password, got %v", mockDB.users["ci"])
+	}
+	w = serveAPIKeyRequest(t, router, http.MethodPost, "/config/v1/account/ci/change_password", `{"password": "Admin1234"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected a password change of a service account to be rejected, got `%v`", w.Code)
+	}
+
+	w = serveAPIKeyRequest(t, router, http.MethodPost, "/config/v1/account/ci/api-key",
+		`{"name": "pipeline", "permissions": ["network:read"], "expiresAt": "2100-01-01T00:00:00Z"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected `%v`, got `%v` %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var created configmodels.CreateAPIKeyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("unable to unmarshal response `%v`", w.Body.String())
+	}
+	if !strings.HasPrefix(created.Key, "wuk_") || created.ID == "" || created.ExpiresAt == nil || created.ExpiresAt.Year() != 2100 {
+		t.Errorf("unexpected API key %+v", created)
+	}
+	if mockDB.apiKeys[0]["keyHash"] == created.Key || mockDB.apiKeys[0]["keyHash"] == "" {
+		t.Errorf("expected the hash of the key to be stored, got %v", mockDB.apiKeys[0])
+	}
+
+	w = serveAPIKeyRequest(t, router, http.MethodGet, "/config/v1/account/ci/api-key", "")
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "key\"") {
+		t.Errorf("expected the API keys without the key, got `%v` %s", w.Code, w.Body.String())
+	}
+	var listed []configmodels.APIKeyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &listed); err != nil || len(listed) != 1 || listed[0].ID != created.ID {
+		t.Errorf("expected the API key to be listed, got %s", w.Body.String())
+	}
+
+	url := "/config/v1/account/ci/api-key/" + created.ID
+	if w = serveAPIKeyRequest(t, router, http.MethodDelete, url, ""); w.Code != http.StatusOK {
+		t.Errorf("expected `%v`, got `%v`", http.StatusOK, w.Code)
+	}
+	if w = serveAPIKeyRequest(t, router, http.MethodDelete, url, ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected `%v`, got `%v`", http.StatusNotFound, w.Code)
+	}
+
+	serveAPIKeyRequest(t, router, http.MethodPost, "/config/v1/account/ci/api-key", `{"name": "other"}`)
+	if w = serveAPIKeyRequest(t, router, http.MethodDelete, "/config/v1/account/ci", ""); w.Code != http.StatusOK {
+		t.Errorf("expected `%v`, got `%v`", http.StatusOK, w.Code)
+	}
+	if len(mockDB.apiKeys) != 0 {
+		t.Errorf("expected the API keys of the deleted account to be revoked, got %v", mockDB.apiKeys)
+	}
+}
+
+func TestAPIKeyPost_FailureCases(t *testing.T) {
+	testCases := []struct {
+		name         string
+		url          string
+		inputData    string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "RegularAccount",
+			url:          "/config/v1/account/johndoe/api-key",
+			inputData:    `{"name": "pipeline"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorNotAServiceAccount),
+		},
+		{
+			name:         "MissingAccount",
+			url:          "/config/v1/account/missing/api-key",
+			inputData:    `{"name": "pipeline"}`,
+			expectedCode: http.StatusNotFound,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorServiceAccountNotFound),
+		},
+		{
+			name:         "MissingName",
+			url:          "/config/v1/account/ci/api-key",
+			inputData:    `{}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorMissingAPIKeyName),
+		},
+		{
+			name:         "UnknownPermission",
+			url:          "/config/v1/account/ci/api-key",
+			inputData:    `{"name": "pipeline", "permissions": ["k4:delete"]}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s k4:delete"}`, errorUnknownPermission),
+		},
+		{
+			name:         "PastExpiry",
+			url:          "/config/v1/account/ci/api-key",
+			inputData:    `{"name": "pipeline", "expiresAt": "2000-01-01T00:00:00Z"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorExpiredAPIKey),
+		},
+		{
+			name:         "InvalidData",
+			url:          "/config/v1/account/ci/api-key",
+			inputData:    `{"name": "pipeline", "expiresAt": "tomorrow"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorInvalidDataProvided),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := setUpAPIKeyRouter()
+			mockDB := newMockMongoClientAPIKeys()
+			mockDB.users["ci"] = map[string]any{"username": "ci", "role": configmodels.UserRole, "serviceAccount": true}
+			dbadapter.WebuiDBClient = mockDB
+
+			w := serveAPIKeyRequest(t, router, http.MethodPost, tc.url, tc.inputData)
+
+			if tc.expectedCode != w.Code {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedCode, w.Code)
+			}
+			if w.Body.String() != tc.expectedBody {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedBody, w.Body.String())
+			}
+			if len(mockDB.apiKeys) != 0 {
+				t.Errorf("expected no API key to be stored, got %v", mockDB.apiKeys)
+			}
+		})
+	}
+}
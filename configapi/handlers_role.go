@@ -0,0 +1,328 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package configapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/auth"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	errorCreateRole         = "failed to create role"
+	errorDeleteBuiltinRole  = "deleting a built-in role is not allowed"
+	errorDeleteRole         = "failed to delete role"
+	errorInvalidRoleName    = "role name must be lowercase letters, digits and dashes"
+	errorRetrieveRole       = "failed to retrieve role"
+	errorRetrieveRoles      = "failed to retrieve roles"
+	errorRoleAlreadyExists  = "role already exists"
+	errorRoleInUse          = "role is given to user accounts"
+	errorRoleNameMismatch   = "role name in the body does not match the path"
+	errorRoleNotFound       = "role not found"
+	errorUpdateRole         = "failed to update role"
+	errorUnknownPermission  = "unknown permission"
+	errorAdminAccountRole   = "the admin account has every permission"
+	errorMissingRoleName    = "roleName is required"
+	errorUpdateAccountRole  = "failed to update the role of the user account"
+	roleNameMaxLength       = 63
+	roleNameValidatorRegexp = `^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`
+)
+
+var roleNameValidator = regexp.MustCompile(roleNameValidatorRegexp)
+
+func validateRole(name string, role *configmodels.Role) (int, string) {
+	if len(name) > roleNameMaxLength || !roleNameValidator.MatchString(name) {
+		return http.StatusBadRequest, errorInvalidRoleName
+	}
+	if role.Name != "" && role.Name != name {
+		return http.StatusBadRequest, errorRoleNameMismatch
+	}
+	role.Name = name
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+	for _, permission := range role.Permissions {
+		if !slices.Contains(configmodels.Permissions, permission) {
+			return http.StatusBadRequest, fmt.Sprintf("%s %s", errorUnknownPermission, permission)
+		}
+	}
+	return http.StatusOK, ""
+}
+
+// GetRoles godoc
+//
+// @Description  Return the list of roles
+// @Tags         Roles
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   configmodels.Role  "List of roles"
+// @Failure      401  {object}  nil                "Authorization failed"
+// @Failure      403  {object}  nil                "Forbidden"
+// @Failure      404  {object}  nil                "Page not found if enableAuthentication is disabled"
+// @Failure      500  {object}  nil                "Error retrieving roles"
+// @Router       /config/v1/role  [get]
+func GetRoles(c *gin.Context) {
+	logger.WebUILog.Infoln("get roles")
+	rawRoles, err := dbadapter.WebuiDBClient.RestfulAPIGetMany(configmodels.RoleDataColl, bson.M{})
+	if err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveRoles})
+		return
+	}
+	roles := make([]configmodels.Role, 0, len(rawRoles))
+	for _, rawRole := range rawRoles {
+		var role configmodels.Role
+		if err = json.Unmarshal(configmodels.MapToByte(rawRole), &role); err != nil {
+			logger.AppLog.Errorf("%s: %+v", errorRetrieveRole, err)
+			continue
+		}
+		roles = append(roles, role)
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// GetRole godoc
+//
+// @Description  Return the role
+// @Tags         Roles
+// @Produce      json
+// @Param        role-name    path    string    true    "Name of the role"
+// @Security     BearerAuth
+// @Success      200  {object}  configmodels.Role  "Role"
+// @Failure      401  {object}  nil                "Authorization failed"
+// @Failure      403  {object}  nil                "Forbidden"
+// @Failure      404  {object}  nil                "Role not found. Or Page not found if enableAuthentication is disabled"
+// @Failure      500  {object}  nil                "Error retrieving role"
+// @Router       /config/v1/role/{role-name}  [get]
+func GetRole(c *gin.Context) {
+	logger.WebUILog.Infoln("get role")
+	role, err := auth.FetchRole(c.Param("role-name"))
+	if err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveRole})
+		return
+	}
+	if role == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": errorRoleNotFound})
+		return
+	}
+	c.JSON(http.StatusOK, role)
+}
+
+// RoleRoleNamePost godoc
+//
+// @Description  Create a new role
+// @Tags         Roles
+// @Produce      json
+// @Param        role-name    path    string               true    "Name of the role"
+// @Param        content      body    configmodels.Role    true    "Permissions of the role"
+// @Security     BearerAuth
+// @Success      201  {object}  nil  "Role created"
+// @Failure      400  {object}  nil  "Bad request"
+// @Failure      401  {object}  nil  "Authorization failed"
+// @Failure      403  {object}  nil  "Forbidden"
+// @Failure      404  {object}  nil  "Page not found if enableAuthentication is disabled"
+// @Failure      409  {object}  nil  "Role already exists"
+// @Failure      500  {object}  nil  "Failed to create the role"
+// @Router       /config/v1/role/{role-name}  [post]
+func RoleRoleNamePost(c *gin.Context) {
+	logger.WebUILog.Infoln("create role")
+	var role configmodels.Role
+	if err := c.ShouldBindJSON(&role); err != nil {
+		logger.WebUILog.Errorln(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorInvalidDataProvided})
+		return
+	}
+	if code, message := validateRole(c.Param("role-name"), &role); code != http.StatusOK {
+		c.JSON(code, gin.H{"error": message})
+		return
+	}
+	existingRole, err := auth.FetchRole(role.Name)
+	if err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveRole})
+		return
+	}
+	if existingRole != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": errorRoleAlreadyExists})
+		return
+	}
+	filter := bson.M{"name": role.Name}
+	if _, err = dbadapter.WebuiDBClient.RestfulAPIPost(configmodels.RoleDataColl, filter, configmodels.ToBsonM(role)); err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorCreateRole})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{})
+}
+
+// RoleRoleNamePut godoc
+//
+// @Description  Replace the permissions of a role. They apply to the tokens already issued.
+// @Tags         Roles
+// @Produce      json
+// @Param        role-name    path    string               true    "Name of the role"
+// @Param        content      body    configmodels.Role    true    "Permissions of the role"
+// @Security     BearerAuth
+// @Success      200  {object}  nil  "Role updated"
+// @Failure      400  {object}  nil  "Bad request"
+// @Failure      401  {object}  nil  "Authorization failed"
+// @Failure      403  {object}  nil  "Forbidden"
+// @Failure      404  {object}  nil  "Role not found. Or Page not found if enableAuthentication is disabled"
+// @Failure      500  {object}  nil  "Failed to update the role"
+// @Router       /config/v1/role/{role-name}  [put]
+func RoleRoleNamePut(c *gin.Context) {
+	logger.WebUILog.Infoln("update role")
+	var role configmodels.Role
+	if err := c.ShouldBindJSON(&role); err != nil {
+		logger.WebUILog.Errorln(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorInvalidDataProvided})
+		return
+	}
+	if code, message := validateRole(c.Param("role-name"), &role); code != http.StatusOK {
+		c.JSON(code, gin.H{"error": message})
+		return
+	}
+	existingRole, err := auth.FetchRole(role.Name)
+	if err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveRole})
+		return
+	}
+	if existingRole == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": errorRoleNotFound})
+		return
+	}
+	filter := bson.M{"name": role.Name}
+	if _, err = dbadapter.WebuiDBClient.RestfulAPIPost(configmodels.RoleDataColl, filter, configmodels.ToBsonM(role)); err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorUpdateRole})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// RoleRoleNameDelete godoc
+//
+// @Description  Delete a role that is not given to any user account
+// @Tags         Roles
+// @Produce      json
+// @Param        role-name    path    string    true    "Name of the role"
+// @Security     BearerAuth
+// @Success      200  {object}  nil  "Role deleted"
+// @Failure      400  {object}  nil  "Built-in roles cannot be deleted"
+// @Failure      401  {object}  nil  "Authorization failed"
+// @Failure      403  {object}  nil  "Forbidden"
+// @Failure      404  {object}  nil  "Role not found. Or Page not found if enableAuthentication is disabled"
+// @Failure      409  {object}  nil  "Role given to user accounts"
+// @Failure      500  {object}  nil  "Failed to delete the role"
+// @Router       /config/v1/role/{role-name}  [delete]
+func RoleRoleNameDelete(c *gin.Context) {
+	logger.WebUILog.Infoln("delete role")
+	roleName := c.Param("role-name")
+	if configmodels.IsBuiltinRole(roleName) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorDeleteBuiltinRole})
+		return
+	}
+	role, err := auth.FetchRole(roleName)
+	if err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveRole})
+		return
+	}
+	if role == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": errorRoleNotFound})
+		return
+	}
+	numOfUserAccounts, err := dbadapter.WebuiDBClient.RestfulAPICount(configmodels.UserAccountDataColl, bson.M{"roleName": roleName})
+	if err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveUserAccounts})
+		return
+	}
+	if numOfUserAccounts > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": errorRoleInUse})
+		return
+	}
+	if err = dbadapter.WebuiDBClient.RestfulAPIDeleteOne(configmodels.RoleDataColl, bson.M{"name": roleName}); err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorDeleteRole})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// ChangeUserAccountRole godoc
+//
+// @Description  Give a role to a user account. The sessions of the user are revoked so that the role applies at the next log in.
+// @Tags         User Accounts
+// @Produce      json
+// @Param        username    path    string                           true    "Username"
+// @Param        params      body    configmodels.ChangeRoleParams    true    "Name of the role"
+// @Security     BearerAuth
+// @Success      200  {object}  nil  "Role changed"
+// @Failure      400  {object}  nil  "Bad request"
+// @Failure      401  {object}  nil  "Authorization failed"
+// @Failure      403  {object}  nil  "Forbidden"
+// @Failure      404  {object}  nil  "User account not found. Or Page not found if enableAuthentication is disabled"
+// @Failure      500  {object}  nil  "Failed to update the user account"
+// @Router      /config/v1/account/{username}/role  [post]
+func ChangeUserAccountRole(c *gin.Context) {
+	logger.WebUILog.Infoln("change user role")
+	var changeRoleParams configmodels.ChangeRoleParams
+	if err := c.ShouldBindJSON(&changeRoleParams); err != nil {
+		logger.WebUILog.Errorln(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorInvalidDataProvided})
+		return
+	}
+	if changeRoleParams.RoleName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMissingRoleName})
+		return
+	}
+	dbUser, err := fetchDBUserAccount(c.Param("username"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveUserAccount})
+		return
+	}
+	if dbUser == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": errorUsernameNotFound})
+		return
+	}
+	if dbUser.Role == configmodels.AdminRole {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorAdminAccountRole})
+		return
+	}
+	role, err := auth.FetchRole(changeRoleParams.RoleName)
+	if err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveRole})
+		return
+	}
+	if role == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorRoleNotFound})
+		return
+	}
+	dbUser.RoleName = role.Name
+	filter := bson.M{"username": dbUser.Username}
+	if _, err = dbadapter.WebuiDBClient.RestfulAPIPost(configmodels.UserAccountDataColl, filter, configmodels.ToBsonM(dbUser)); err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorUpdateAccountRole})
+		return
+	}
+	if err = auth.RevokeUserSessions(dbUser.Username); err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRevokeSessions})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,348 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package configapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MockMongoClientRoles keeps the roles and the user accounts written to it.
+type MockMongoClientRoles struct {
+	dbadapter.DBInterface
+	roles    map[string]map[string]any
+	users    map[string]map[string]any
+	revoked  []string
+	postErr  error
+	countErr error
+}
+
+func newMockMongoClientRoles() *MockMongoClientRoles {
+	db := &MockMongoClientRoles{
+		roles: map[string]map[string]any{},
+		users: map[string]map[string]any{
+			"janedoe": {"username": "janedoe", "password": hashPassword("password123!"), "role": configmodels.AdminRole},
+			"johndoe": {"username": "johndoe", "password": hashPassword("password-123"), "role": configmodels.UserRole},
+		},
+	}
+	for _, role := range configmodels.BuiltinRoles {
+		db.roles[role.Name] = configmodels.ToBsonM(role)
+	}
+	db.roles["auditor"] = configmodels.ToBsonM(configmodels.Role{Name: "auditor", Permissions: []string{configmodels.PermissionNetworkRead}})
+	return db
+}
+
+func (db *MockMongoClientRoles) RestfulAPIGetOne(collName string, filter bson.M) (map[string]any, error) {
+	if collName == configmodels.RoleDataColl {
+		return db.roles[filter["name"].(string)], nil
+	}
+	return db.users[filter["username"].(string)], nil
+}
+
+func (db *MockMongoClientRoles) RestfulAPIGetMany(collName string, filter bson.M) ([]map[string]any, error) {
+	rawRoles := []map[string]any{}
+	for _, rawRole := range db.roles {
+		rawRoles = append(rawRoles, rawRole)
+	}
+	return rawRoles, nil
+}
+
+func (db *MockMongoClientRoles) RestfulAPIPost(collName string, filter bson.M, postData map[string]any) (bool, error) {
+	if db.postErr != nil {
+		return false, db.postErr
+	}
+	if collName == configmodels.RoleDataColl {
+		db.roles[filter["name"].(string)] = postData
+		return true, nil
+	}
+	db.users[filter["username"].(string)] = postData
+	return true, nil
+}
+
+func (db *MockMongoClientRoles) RestfulAPICount(collName string, filter bson.M) (int64, error) {
+	var count int64
+	for _, user := range db.users {
+		if user["roleName"] == filter["roleName"] {
+			count++
+		}
+	}
+	return count, db.countErr
+}
+
+func (db *MockMongoClientRoles) RestfulAPIDeleteOne(collName string, filter bson.M) error {
+	delete(db.roles, filter["name"].(string))
+	return nil
+}
+
+func (db *MockMongoClientRoles) RestfulAPIDeleteMany(collName string, filter bson.M) error {
+	db.revoked = append(db.revoked, filter["username"].(string))
+	return nil
+}
+
+func setUpRoleRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/config/v1/role", GetRoles)
+	router.GET("/config/v1/role/:role-name", GetRole)
+	router.POST("/config/v1/role/:role-name", RoleRoleNamePost)
+	router.PUT("/config/v1/role/:role-name", RoleRoleNamePut)
+	router.DELETE("/config/v1/role/:role-name", RoleRoleNameDelete)
+	router.POST("/config/v1/account/:username/role", ChangeUserAccountRole)
+	return router
+}
+
+func TestGetRoles(t *testing.T) {
+	router := setUpRoleRouter()
+	dbadapter.WebuiDBClient = newMockMongoClientRoles()
+	req, err := http.NewRequest(http.MethodGet, "/config/v1/role", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected `%v`, got `%v`", http.StatusOK, w.Code)
+	}
+	var roles []configmodels.Role
+	if err = json.Unmarshal(w.Body.Bytes(), &roles); err != nil {
+		t.Fatalf("unable to unmarshal response `%v`", w.Body.String())
+	}
+	if len(roles) != len(configmodels.BuiltinRoles)+1 {
+		t.Errorf("expected %d roles, got %+v", len(configmodels.BuiltinRoles)+1, roles)
+	}
+}
+
+func TestRoleHandlers(t *testing.T) {
+	testCases := []struct {
+		name         string
+		method       string
+		url          string
+		inputData    string
+		prepare      func(db *MockMongoClientRoles)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "GetRole",
+			method:       http.MethodGet,
+			url:          "/config/v1/role/auditor",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"name":"auditor","description":"","permissions":["network:read"]}`,
+		},
+		{
+			name:         "GetMissingRole",
+			method:       http.MethodGet,
+			url:          "/config/v1/role/missing",
+			expectedCode: http.StatusNotFound,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorRoleNotFound),
+		},
+		{
+			name:         "CreateRole",
+			method:       http.MethodPost,
+			url:          "/config/v1/role/k4-reader",
+			inputData:    `{"description": "Read K4 keys", "permissions": ["k4:read"]}`,
+			expectedCode: http.StatusCreated,
+			expectedBody: "{}",
+		},
+		{
+			name:         "CreateExistingRole",
+			method:       http.MethodPost,
+			url:          "/config/v1/role/auditor",
+			inputData:    `{"permissions": ["k4:read"]}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorRoleAlreadyExists),
+		},
+		{
+			name:         "CreateRoleInvalidName",
+			method:       http.MethodPost,
+			url:          "/config/v1/role/Bad_Name",
+			inputData:    `{"permissions": ["k4:read"]}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorInvalidRoleName),
+		},
+		{
+			name:         "CreateRoleNameMismatch",
+			method:       http.MethodPost,
+			url:          "/config/v1/role/k4-reader",
+			inputData:    `{"name": "other", "permissions": ["k4:read"]}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorRoleNameMismatch),
+		},
+		{
+			name:         "CreateRoleUnknownPermission",
+			method:       http.MethodPost,
+			url:          "/config/v1/role/k4-reader",
+			inputData:    `{"permissions": ["k4:delete"]}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s k4:delete"}`, errorUnknownPermission),
+		},
+		{
+			name:         "CreateRoleInvalidData",
+			method:       http.MethodPost,
+			url:          "/config/v1/role/k4-reader",
+			inputData:    `{"permissions": "k4:read"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorInvalidDataProvided),
+		},
+		{
+			name:         "UpdateRole",
+			method:       http.MethodPut,
+			url:          "/config/v1/role/read-only",
+			inputData:    `{"permissions": ["network:read"]}`,
+			expectedCode: http.StatusOK,
+			expectedBody: "{}",
+		},
+		{
+			name:         "UpdateMissingRole",
+			method:       http.MethodPut,
+			url:          "/config/v1/role/missing",
+			inputData:    `{"permissions": ["network:read"]}`,
+			expectedCode: http.StatusNotFound,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorRoleNotFound),
+		},
+		{
+			name:         "DeleteRole",
+			method:       http.MethodDelete,
+			url:          "/config/v1/role/auditor",
+			expectedCode: http.StatusOK,
+			expectedBody: "{}",
+		},
+		{
+			name:         "DeleteBuiltinRole",
+			method:       http.MethodDelete,
+			url:          "/config/v1/role/read-only",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorDeleteBuiltinRole),
+		},
+		{
+			name:   "DeleteRoleInUse",
+			method: http.MethodDelete,
+			url:    "/config/v1/role/auditor",
+			prepare: func(db *MockMongoClientRoles) {
+				db.users["johndoe"]["roleName"] = "auditor"
+			},
+			expectedCode: http.StatusConflict,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorRoleInUse),
+		},
+		{
+			name:         "DeleteMissingRole",
+			method:       http.MethodDelete,
+			url:          "/config/v1/role/missing",
+			expectedCode: http.StatusNotFound,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorRoleNotFound),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := setUpRoleRouter()
+			mockDB := newMockMongoClientRoles()
+			if tc.prepare != nil {
+				tc.prepare(mockDB)
+			}
+			dbadapter.WebuiDBClient = mockDB
+			req, err := http.NewRequest(tc.method, tc.url, strings.NewReader(tc.inputData))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if tc.expectedCode != w.Code {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedCode, w.Code)
+			}
+			if w.Body.String() != tc.expectedBody {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestChangeUserAccountRole(t *testing.T) {
+	testCases := []struct {
+		name           string
+		url            string
+		inputData      string
+		expectedCode   int
+		expectedBody   string
+		expectedRole   string
+		expectRevoking bool
+	}{
+		{
+			name:           "Success",
+			url:            "/config/v1/account/johndoe/role",
+			inputData:      `{"roleName": "read-only"}`,
+			expectedCode:   http.StatusOK,
+			expectedBody:   "{}",
+			expectedRole:   "read-only",
+			expectRevoking: true,
+		},
+		{
+			name:         "MissingRoleName",
+			url:          "/config/v1/account/johndoe/role",
+			inputData:    `{}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorMissingRoleName),
+		},
+		{
+			name:         "UnknownRole",
+			url:          "/config/v1/account/johndoe/role",
+			inputData:    `{"roleName": "missing"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorRoleNotFound),
+		},
+		{
+			name:         "AdminAccount",
+			url:          "/config/v1/account/janedoe/role",
+			inputData:    `{"roleName": "read-only"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorAdminAccountRole),
+		},
+		{
+			name:         "MissingUserAccount",
+			url:          "/config/v1/account/missing/role",
+			inputData:    `{"roleName": "read-only"}`,
+			expectedCode: http.StatusNotFound,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorUsernameNotFound),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := setUpRoleRouter()
+			mockDB := newMockMongoClientRoles()
+			dbadapter.WebuiDBClient = mockDB
+			req, err := http.NewRequest(http.MethodPost, tc.url, strings.NewReader(tc.inputData))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if tc.expectedCode != w.Code {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedCode, w.Code)
+			}
+			if w.Body.String() != tc.expectedBody {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedBody, w.Body.String())
+			}
+			if tc.expectedRole != "" && mockDB.users["johndoe"]["roleName"] != tc.expectedRole {
+				t.Errorf("expected role `%v`, got `%v`", tc.expectedRole, mockDB.users["johndoe"]["roleName"])
+			}
+			if tc.expectRevoking != (len(mockDB.revoked) == 1) {
+				t.Errorf("unexpected revoked sessions %v", mockDB.revoked)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/auth"
 	"github.com/omec-project/webconsole/backend/logger"
 	"github.com/omec-project/webconsole/configmodels"
 	"github.com/omec-project/webconsole/dbadapter"
@@ -17,18 +18,24 @@ import (
 )
 
 const (
-	errorCreateUserAccount    = "failed to create user account"
-	errorDeleteAdminAccount   = "deleting an admin user account is not allowed"
-	errorDeleteUserAccount    = "failed to delete user account"
-	errorIncorrectCredentials = "incorrect username or password. Try again"
-	errorInvalidDataProvided  = "invalid data provided"
-	errorInvalidPassword      = "password must have 8 or more characters, must include at least one capital letter, one lowercase letter, and either a number or a symbol."
-	errorMissingPassword      = "password is required"
-	errorMissingUsername      = "username is required"
-	errorRetrieveUserAccount  = "failed to retrieve user account"
-	errorRetrieveUserAccounts = "failed to retrieve user accounts"
-	errorUpdateUserAccount    = "failed to update user account"
-	errorUsernameNotFound     = "username not found"
+	errorCreateUserAccount       = "failed to create user account"
+	errorDeleteAdminAccount      = "deleting an admin user account is not allowed"
+	errorDeleteUserAccount       = "failed to delete user account"
+	errorExternalAccountPassword = "the password of the account is managed by its identity provider"
+	errorFirstServiceAccount     = "the first user account cannot be a service account"
+	errorIncorrectCredentials    = "incorrect username or password. Try again"
+	errorInvalidDataProvided     = "invalid data provided"
+	errorInvalidPassword         = "password must have 8 or more characters, must include at least one capital letter, one lowercase letter, and either a number or a symbol."
+	errorMissingPassword         = "password is required"
+	errorMissingUsername         = "username is required"
+	errorRetrieveUserAccount     = "failed to retrieve user account"
+	errorRetrieveUserAccounts    = "failed to retrieve user accounts"
+	errorRevokeAPIKeys           = "failed to revoke the API keys of the user account"
+	errorRevokeSessions          = "failed to revoke the sessions of the user account"
+	errorServiceAccountPassword  = "service accounts have no password"
+	errorUnlockUserAccount       = "failed to unlock user account"
+	errorUpdateUserAccount       = "failed to update user account"
+	errorUsernameNotFound        = "username not found"
 )
 
 // GetUserAccounts godoc
@@ -59,11 +66,8 @@ func GetUserAccounts(c *gin.Context) {
 			logger.AppLog.Errorf(errorRetrieveUserAccount)
 			continue
 		}
-		userResponse := &configmodels.GetUserAccountResponse{
-			Username: dbUserAccount.Username,
-			Role:     dbUserAccount.Role,
-		}
-		userResponses = append(userResponses, userResponse)
+		userResponse := dbUserAccount.ToResponse()
+		userResponses = append(userResponses, &userResponse)
 	}
 	c.JSON(http.StatusOK, userResponses)
 }
@@ -93,11 +97,7 @@ func GetUserAccount(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": errorUsernameNotFound})
 		return
 	}
-	userResponse := configmodels.GetUserAccountResponse{
-		Username: dbUserAccount.Username,
-		Role:     dbUserAccount.Role,
-	}
-	c.JSON(http.StatusOK, userResponse)
+	c.JSON(http.StatusOK, dbUserAccount.ToResponse())
 }
 
 func fetchDBUserAccount(username string) (*configmodels.DBUserAccount, error) {
@@ -147,11 +147,15 @@ func CreateUserAccount(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": errorMissingUsername})
 		return
 	}
-	if createUserParams.Password == "" {
+	if createUserParams.ServiceAccount && createUserParams.Password != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorServiceAccountPassword})
+		return
+	}
+	if !createUserParams.ServiceAccount && createUserParams.Password == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": errorMissingPassword})
 		return
 	}
-	if !validatePassword(createUserParams.Password) {
+	if !createUserParams.ServiceAccount && !validatePassword(createUserParams.Password) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": errorInvalidPassword})
 		return
 	}
@@ -162,13 +166,35 @@ func CreateUserAccount(c *gin.Context) {
 		return
 	}
 	if !isFirstAccountIssued {
+		if createUserParams.ServiceAccount {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errorFirstServiceAccount})
+			return
+		}
 		newUserRole = configmodels.AdminRole
 	}
-	dbUser, err := configmodels.CreateNewDBUserAccount(createUserParams.Username, createUserParams.Password, newUserRole)
-	if err != nil {
-		logger.WebUILog.Errorln(err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": errorCreateUserAccount})
-		return
+	if createUserParams.RoleName != "" {
+		role, roleErr := auth.FetchRole(createUserParams.RoleName)
+		if roleErr != nil {
+			logger.AppLog.Errorln(roleErr.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveRole})
+			return
+		}
+		if role == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errorRoleNotFound})
+			return
+		}
+	}
+	dbUser := &configmodels.DBUserAccount{Username: createUserParams.Username, Role: newUserRole, ServiceAccount: true}
+	if !createUserParams.ServiceAccount {
+		dbUser, err = configmodels.CreateNewDBUserAccount(createUserParams.Username, createUserParams.Password, newUserRole)
+		if err != nil {
+			logger.WebUILog.Errorln(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errorCreateUserAccount})
+			return
+		}
+	}
+	if newUserRole == configmodels.UserRole {
+		dbUser.RoleName = createUserParams.RoleName
 	}
 
 	filter := bson.M{"username": dbUser.Username}
@@ -188,7 +214,7 @@ func CreateUserAccount(c *gin.Context) {
 
 // DeleteUserAccount godoc
 //
-// @Description  Delete an existing user account
+// @Description  Delete an existing user account and revoke its sessions
 // @Tags         User Accounts
 // @Produce      json
 // @Param        username    path    string    true    "Username of the user account"
@@ -212,7 +238,9 @@ func DeleteUserAccount(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": errorUsernameNotFound})
 		return
 	}
-	if dbUserAccount.Role == configmodels.AdminRole {
+	// the admin role of an identity provider user is given by its groups, so
+	// only the local admin account is kept
+	if dbUserAccount.Role == configmodels.AdminRole && dbUserAccount.IdentityProvider == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": errorDeleteAdminAccount})
 		return
 	}
@@ -223,6 +251,16 @@ func DeleteUserAccount(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": errorDeleteUserAccount})
 		return
 	}
+	if err = auth.RevokeUserSessions(username); err != nil {
+		logger.AppLog.Errorln(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRevokeSessions})
+		return
+	}
+	if err = auth.RevokeAPIKeys(username); err != nil {
+		logger.AppLog.Errorln(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRevokeAPIKeys})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{})
 }
 
@@ -234,7 +272,7 @@ func DeleteUserAccount(c *gin.Context) {
 // @Param        username    path    string                               true    "Username"
 // @Param        params      body    configmodels.ChangePasswordParams    true    "Username and password"
 // @Security     BearerAuth
-// @Success      200  {object}  nil  "Password changed"
+// @Success      200  {object}  nil  "Password changed and sessions revoked"
 // @Failure      400  {object}  nil  "Bad request"
 // @Failure      401  {object}  nil  "Authorization failed"
 // @Failure      403  {object}  nil  "Forbidden"
@@ -268,12 +306,21 @@ func ChangeUserAccountPasssword(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": errorUsernameNotFound})
 		return
 	}
+	if dbUser.ServiceAccount {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorServiceAccountPassword})
+		return
+	}
+	if dbUser.IdentityProvider != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorExternalAccountPassword})
+		return
+	}
 	newPasswordDbUser, err := configmodels.CreateNewDBUserAccount(dbUser.Username, changePasswordParams.Password, dbUser.Role)
 	if err != nil {
 		logger.WebUILog.Errorln(err.Error())
 		c.JSON(http.StatusInternalServerError, gin.H{"error": errorUpdateUserAccount})
 		return
 	}
+	newPasswordDbUser.RoleName = dbUser.RoleName
 	filter := bson.M{"username": newPasswordDbUser.Username}
 	_, err = dbadapter.WebuiDBClient.RestfulAPIPost(configmodels.UserAccountDataColl, filter, configmodels.ToBsonM(newPasswordDbUser))
 	if err != nil {
@@ -281,11 +328,48 @@ func ChangeUserAccountPasssword(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": errorUpdateUserAccount})
 		return
 	}
+	if err = auth.RevokeUserSessions(newPasswordDbUser.Username); err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRevokeSessions})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// UnlockUserAccount godoc
+//
+// @Description  Unlock a user account locked after too many failed log in attempts, and reset its failed attempts
+// @Tags         User Accounts
+// @Produce      json
+// @Param        username    path    string    true    "Username"
+// @Security     BearerAuth
+// @Success      200  {object}  nil  "User account unlocked"
+// @Failure      401  {object}  nil  "Authorization failed"
+// @Failure      403  {object}  nil  "Forbidden"
+// @Failure      404  {object}  nil  "User account not found. Or Page not found if enableAuthentication is disabled"
+// @Failure      500  {object}  nil  "Failed to unlock the user account"
+// @Router      /config/v1/account/{username}/unlock  [post]
+func UnlockUserAccount(c *gin.Context) {
+	logger.WebUILog.Infoln("unlock user account")
+	dbUser, err := fetchDBUserAccount(c.Param("username"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorRetrieveUserAccount})
+		return
+	}
+	if dbUser == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": errorUsernameNotFound})
+		return
+	}
+	if err = auth.UnlockUserAccount(dbUser.Username); err != nil {
+		logger.AppLog.Errorln(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errorUnlockUserAccount})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{})
 }
 
 var isFirstAccountIssued = func() (bool, error) {
-	numOfUserAccounts, err := dbadapter.WebuiDBClient.RestfulAPICount(configmodels.UserAccountDataColl, bson.M{})
+	numOfUserAccounts, err := auth.CountLocalUserAccounts()
 	if err != nil {
 		return false, err
 	}
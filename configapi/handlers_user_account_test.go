@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/configmodels"
 	"github.com/omec-project/webconsole/dbadapter"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -79,6 +80,10 @@ func (db *MockMongoClientSuccess) RestfulAPICount(collName string, filter bson.M
 	return 5, nil
 }
 
+func (db *MockMongoClientSuccess) RestfulAPIDeleteMany(collName string, filter bson.M) error {
+	return nil
+}
+
 func (db *MockMongoClientRegularUser) RestfulAPIGetOne(coll string, filter bson.M) (map[string]any, error) {
 	rawUser := map[string]any{
 		"username": "johndoe", "password": hashPassword("password-123"), "role": 0,
@@ -90,6 +95,62 @@ func (db *MockMongoClientRegularUser) RestfulAPIDeleteOne(collName string, filte
 	return nil
 }
 
+func (db *MockMongoClientRegularUser) RestfulAPIDeleteMany(collName string, filter bson.M) error {
+	return nil
+}
+
+// MockMongoClientExternalAdmin returns the shadow account of an identity
+// provider user mapped to the admin role.
+type MockMongoClientExternalAdmin struct {
+	MockMongoClientRegularUser
+}
+
+func (db *MockMongoClientExternalAdmin) RestfulAPIGetOne(coll string, filter bson.M) (map[string]any, error) {
+	rawUser := map[string]any{
+		"username": "oidc-0123456789abcdef", "displayName": "janedoe", "role": 1, "identityProvider": "oidc",
+	}
+	return rawUser, nil
+}
+
+// MockMongoClientSessions records the sessions revoked for the regular user.
+type MockMongoClientSessions struct {
+	MockMongoClientRegularUser
+	revokeErr error
+	revoked   []bson.M
+}
+
+func (db *MockMongoClientSessions) RestfulAPIPost(collName string, filter bson.M, postData map[string]any) (bool, error) {
+	return true, nil
+}
+
+func (db *MockMongoClientSessions) RestfulAPIDeleteMany(collName string, filter bson.M) error {
+	if collName == configmodels.RefreshTokenDataColl {
+		db.revoked = append(db.revoked, filter)
+	}
+	return db.revokeErr
+}
+
+// MockMongoClientLockedUser records the updates of a user locked after failed
+// log in attempts.
+type MockMongoClientLockedUser struct {
+	dbadapter.DBInterface
+	postErr error
+	posted  []map[string]any
+}
+
+func (db *MockMongoClientLockedUser) RestfulAPIGetOne(coll string, filter bson.M) (map[string]any, error) {
+	rawUser := map[string]any{
+		"username": "johndoe", "password": hashPassword("password-123"), "role": 0,
+		"lastLoginAt": 1735689600, "lastFailedLoginAt": 1735693200, "failedLoginAttempts": 5, "lockedUntil": 1735694100,
+	}
+	return rawUser, nil
+}
+
+func (db *MockMongoClientLockedUser) RestfulAPIPost(collName string, filter bson.M, postData map[string]any) (bool, error) {
+	db.posted = append(db.posted, postData)
+	return true, db.postErr
+}
+
 type MockMongoClientDuplicateCreation struct {
 	dbadapter.DBInterface
 }
@@ -191,6 +252,12 @@ func TestGetUserAccountHandler(t *testing.T) {
 			expectedCode: http.StatusOK,
 			expectedBody: `{"username":"janedoe","role":1}`,
 		},
+		{
+			name:         "LockedUserAccount",
+			dbAdapter:    &MockMongoClientLockedUser{},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"username":"johndoe","role":0,"lastLoginAt":"2025-01-01T00:00:00Z","lastFailedLoginAt":"2025-01-01T01:00:00Z","failedLoginAttempts":5,"lockedUntil":"2025-01-01T01:15:00Z"}`,
+		},
 		{
 			name:         "DBError",
 			dbAdapter:    &MockMongoClientDBError{},
@@ -292,6 +359,20 @@ func TestCreateUserAccountHandler(t *testing.T) {
 			expectedCode: http.StatusBadRequest,
 			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorInvalidDataProvided),
 		},
+		{
+			name:         "ServiceAccountWithPassword",
+			dbAdapter:    &MockMongoClientSuccess{},
+			inputData:    `{"username": "ci", "password": "Admin1234", "serviceAccount": true}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorServiceAccountPassword),
+		},
+		{
+			name:         "FirstAccountIsServiceAccount",
+			dbAdapter:    &MockMongoClientEmptyDB{},
+			inputData:    `{"username": "ci", "serviceAccount": true}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorFirstServiceAccount),
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -338,6 +419,12 @@ func TestDeleteUserAccountHandler(t *testing.T) {
 			expectedCode: http.StatusBadRequest,
 			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorDeleteAdminAccount),
 		},
+		{
+			name:         "DeleteExternalAdminUser",
+			dbAdapter:    &MockMongoClientExternalAdmin{},
+			expectedCode: http.StatusOK,
+			expectedBody: "{}",
+		},
 		{
 			name:         "DeleteInvalidUser",
 			dbAdapter:    &MockMongoClientInvalidUser{},
@@ -452,3 +539,131 @@ func TestChangePasswordHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestUnlockUserAccountHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.POST("/config/v1/account/:username/unlock", UnlockUserAccount)
+
+	testCases := []struct {
+		name         string
+		dbAdapter    dbadapter.DBInterface
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Success",
+			dbAdapter:    &MockMongoClientLockedUser{},
+			expectedCode: http.StatusOK,
+			expectedBody: "{}",
+		},
+		{
+			name:         "DBError",
+			dbAdapter:    &MockMongoClientDBError{},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorRetrieveUserAccount),
+		},
+		{
+			name:         "UserDoesNotExist",
+			dbAdapter:    &MockMongoClientEmptyDB{},
+			expectedCode: http.StatusNotFound,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorUsernameNotFound),
+		},
+		{
+			name:         "UpdateError",
+			dbAdapter:    &MockMongoClientLockedUser{postErr: errors.New("mock error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorUnlockUserAccount),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbadapter.WebuiDBClient = tc.dbAdapter
+			req, err := http.NewRequest(http.MethodPost, "/config/v1/account/johndoe/unlock", nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if tc.expectedCode != w.Code {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedCode, w.Code)
+			}
+			if w.Body.String() != tc.expectedBody {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedBody, w.Body.String())
+			}
+			if mockDB, ok := tc.dbAdapter.(*MockMongoClientLockedUser); ok && tc.expectedCode == http.StatusOK {
+				expected := map[string]any{"failedLoginAttempts": 0, "lockedUntil": 0}
+				if len(mockDB.posted) != 1 || fmt.Sprint(mockDB.posted[0]) != fmt.Sprint(expected) {
+					t.Errorf("expected the account to be unlocked, got %v", mockDB.posted)
+				}
+			}
+		})
+	}
+}
+
+func TestUserAccountChanges_RevokeSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.DELETE("/config/v1/account/:username", DeleteUserAccount)
+	router.POST("/config/v1/account/:username/change_password", ChangeUserAccountPasssword)
+
+	testCases := []struct {
+		name         string
+		method       string
+		url          string
+		inputData    string
+		revokeErr    error
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "DeleteUserAccount",
+			method:       http.MethodDelete,
+			url:          "/config/v1/account/johndoe",
+			expectedCode: http.StatusOK,
+			expectedBody: "{}",
+		},
+		{
+			name:         "ChangePassword",
+			method:       http.MethodPost,
+			url:          "/config/v1/account/johndoe/change_password",
+			inputData:    `{"password": "Admin1234"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: "{}",
+		},
+		{
+			name:         "RevokeError",
+			method:       http.MethodPost,
+			url:          "/config/v1/account/johndoe/change_password",
+			inputData:    `{"password": "Admin1234"}`,
+			revokeErr:    errors.New("DB error"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: fmt.Sprintf(`{"error":"%s"}`, errorRevokeSessions),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := &MockMongoClientSessions{revokeErr: tc.revokeErr}
+			dbadapter.WebuiDBClient = mockDB
+			req, err := http.NewRequest(tc.method, tc.url, strings.NewReader(tc.inputData))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if tc.expectedCode != w.Code {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedCode, w.Code)
+			}
+			if w.Body.String() != tc.expectedBody {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedBody, w.Body.String())
+			}
+			if len(mockDB.revoked) != 1 || mockDB.revoked[0]["username"] != "johndoe" {
+				t.Errorf("expected the sessions of johndoe to be revoked, got %v", mockDB.revoked)
+			}
+		})
+	}
+}
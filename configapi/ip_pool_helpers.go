@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/omec-project/webconsole/configmodels"
+)
+
+var (
+	errInvalidUeIpPool  = errors.New("invalid UE IP pool")
+	errUeIpPoolTooSmall = errors.New("UE IP pool too small")
+	errUeIpPoolOverlap  = errors.New("UE IP pools overlap")
+)
+
+// ipPoolEntry is the UE IP pool of an IP domain of a device group together
+// with the slices, UPFs and DNN it is served by. Pools only conflict within
+// one of those.
+type ipPoolEntry struct {
+	group    *configmodels.DeviceGroups
+	ipDomain configmodels.DeviceGroupsIpDomainExpanded
+	prefix   netip.Prefix
+	err      error
+	slices   []string
+	upfs     []string
+}
+
+func parseUeIpPool(pool string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(pool))
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%w %q: %v", errInvalidUeIpPool, pool, err)
+	}
+	return prefix.Masked(), nil
+}
+
+// ipPoolSize returns the number of addresses in the prefix, saturated to the
+// maximum uint64 for large IPv6 pools.
+func ipPoolSize(prefix netip.Prefix) uint64 {
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	if hostBits >= 64 {
+		return math.MaxUint64
+	}
+	return 1 << hostBits
+}
+
+func deviceGroupImsiCount(devGroup *configmodels.DeviceGroups) uint64 {
+	count := uint64(len(devGroup.Imsis))
+	for _, imsiRange := range devGroup.ImsiRanges {
+		count += imsiRange.Size()
+	}
+	return count
+}
+
+func ipPoolHeadroom(size, assigned uint64) int64 {
+	if size >= assigned {
+		return int64(min(size-assigned, math.MaxInt64))
+	}
+	return -int64(min(assigned-size, math.MaxInt64))
+}
+
+func upfHostname(slice *configmodels.Slice) string {
+	hostname, _ := slice.SiteInfo.Upf["upf-name"].(string)
+	return hostname
+}
+
+func newIpPoolEntries(devGroups []*configmodels.DeviceGroups, networkSlices []*configmodels.Slice) []*ipPoolEntry {
+	entries := make([]*ipPoolEntry, 0, len(devGroups))
+	for _, devGroup := range devGroups {
+		var sliceNames, upfs []string
+		for _, slice := range networkSlices {
+			if !slices.Contains(slice.SiteDeviceGroup, devGroup.DeviceGroupName) {
+				continue
+			}
+			sliceNames = append(sliceNames, slice.SliceName)
+			if upf := upfHostname(slice); upf != "" && !slices.Contains(upfs, upf) {
+				upfs = append(upfs, upf)
+			}
+		}
+		for _, ipDomain := range devGroup.GetIpDomains() {
+			entry := &ipPoolEntry{
+				group:    devGroup,
+				ipDomain: ipDomain.IpDomainExpanded,
+				slices:   sliceNames,
+				upfs:     upfs,
+			}
+			entry.prefix, entry.err = parseUeIpPool(ipDomain.IpDomainExpanded.UeIpPool)
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// sharedScope returns what the two pools have in common, or an empty string
+// when they cannot conflict.
+func (e *ipPoolEntry) sharedScope(other *ipPoolEntry) string {
+	for _, slice := range e.slices {
+		if slices.Contains(other.slices, slice) {
+			return "slice " + slice
+		}
+	}
+	for _, upf := range e.upfs {
+		if slices.Contains(other.upfs, upf) {
+			return "UPF " + upf
+		}
+	}
+	if dnn := e.ipDomain.Dnn; dnn != "" && dnn == other.ipDomain.Dnn {
+		return "DNN " + dnn
+	}
+	return ""
+}
+
+// overlaps returns the entries whose pool overlaps the one of e on a shared
+// slice, UPF or DNN.
+func (e *ipPoolEntry) overlaps(entries []*ipPoolEntry) []*ipPoolEntry {
+	if e.err != nil {
+		return nil
+	}
+	var conflicts []*ipPoolEntry
+	for _, other := range entries {
+		if other == e || other.err != nil {
+			continue
+		}
+		if e.prefix.Overlaps(other.prefix) && e.sharedScope(other) != "" {
+			conflicts = append(conflicts, other)
+		}
+	}
+	return conflicts
+}
+
+func checkIpPoolOverlaps(entries []*ipPoolEntry, groupNames []string) error {
+	for _, entry := range entries {
+		if !slices.Contains(groupNames, entry.group.DeviceGroupName) {
+			continue
+		}
+		if conflicts := entry.overlaps(entries); len(conflicts) > 0 {
+			other := conflicts[0]
+			return fmt.Errorf("%w: %s of device group %s overlaps %s of device group %s on %s",
+				errUeIpPoolOverlap, entry.prefix, entry.group.DeviceGroupName,
+				other.prefix, other.group.DeviceGroupName, entry.sharedScope(other))
+		}
+	}
+	return nil
+}
+
+// validateDeviceGroupIpPool checks the UE IP pools of a device group being
+// created or updated: each must be a CIDR large enough for the IMSIs of the
+// group and must not overlap another pool sharing its slice, UPF or DNN.
+func validateDeviceGroupIpPool(devGroup *configmodels.DeviceGroups) (int, error) {
+	count := deviceGroupImsiCount(devGroup)
+	for _, ipDomain := range devGroup.GetIpDomains() {
+		prefix, err := parseUeIpPool(ipDomain.IpDomainExpanded.UeIpPool)
+		if err != nil {
+			return http.StatusBadRequest, err
+		}
+		if size := ipPoolSize(prefix); size < count {
+			return http.StatusBadRequest, fmt.Errorf("%w: %s has %d addresses for %d IMSIs", errUeIpPoolTooSmall, prefix, size, count)
+		}
+	}
+	devGroups, err := getDeviceGroups()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	networkSlices, err := getSlices()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	devGroups = slices.DeleteFunc(devGroups, func(dg *configmodels.DeviceGroups) bool {
+		return dg.DeviceGroupName == devGroup.DeviceGroupName
+	})
+	devGroups = append(devGroups, devGroup)
+	entries := newIpPoolEntries(devGroups, networkSlices)
+	if err = checkIpPoolOverlaps(entries, []string{devGroup.DeviceGroupName}); err != nil {
+		return http.StatusConflict, err
+	}
+	return http.StatusOK, nil
+}
+
+// validateSliceIpPools checks that the slice does not bring together device
+// groups with overlapping UE IP pools, directly or through its UPF.
+func validateSliceIpPools(slice configmodels.Slice) (int, error) {
+	devGroups, err := getDeviceGroups()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	networkSlices, err := getSlices()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	networkSlices = slices.DeleteFunc(networkSlices, func(s *configmodels.Slice) bool {
+		return s.SliceName == slice.SliceName
+	})
+	networkSlices = append(networkSlices, &slice)
+	entries := newIpPoolEntries(devGroups, networkSlices)
+	if err = checkIpPoolOverlaps(entries, slice.SiteDeviceGroup); err != nil {
+		return http.StatusConflict, err
+	}
+	return http.StatusOK, nil
+}
+
+func getIpPools() ([]configmodels.IpPool, error) {
+	devGroups, err := getDeviceGroups()
+	if err != nil {
+		return nil, err
+	}
+	networkSlices, err := getSlices()
+	if err != nil {
+		return nil, err
+	}
+	entries := newIpPoolEntries(devGroups, networkSlices)
+	ipPools := make([]configmodels.IpPool, 0, len(entries))
+	for _, entry := range entries {
+		ipPool := configmodels.IpPool{
+			DeviceGroupName: entry.group.DeviceGroupName,
+			Dnn:             entry.ipDomain.Dnn,
+			UeIpPool:        entry.ipDomain.UeIpPool,
+			Slices:          entry.slices,
+			Upfs:            entry.upfs,
+			AssignedImsis:   deviceGroupImsiCount(entry.group),
+		}
+		if entry.err != nil {
+			ipPool.Error = entry.err.Error()
+			ipPool.Headroom = ipPoolHeadroom(0, ipPool.AssignedImsis)
+			ipPools = append(ipPools, ipPool)
+			continue
+		}
+		ipPool.Size = ipPoolSize(entry.prefix)
+		ipPool.Headroom = ipPoolHeadroom(ipPool.Size, ipPool.AssignedImsis)
+		for _, other := range entry.overlaps(entries) {
+			if !slices.Contains(ipPool.Overlaps, other.group.DeviceGroupName) {
+				ipPool.Overlaps = append(ipPool.Overlaps, other.group.DeviceGroupName)
+			}
+		}
+		ipPools = append(ipPools, ipPool)
+	}
+	slices.SortStableFunc(ipPools, func(a, b configmodels.IpPool) int {
+		return strings.Compare(a.DeviceGroupName, b.DeviceGroupName)
+	})
+	return ipPools, nil
+}
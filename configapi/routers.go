@@ -17,6 +17,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/backend/auth"
+	"github.com/omec-project/webconsole/configmodels"
 )
 
 // Route is the information for every URI.
@@ -29,6 +31,9 @@ type Route struct {
 	Pattern string
 	// HandlerFunc is the handler function of this route.
 	HandlerFunc gin.HandlerFunc
+	// Permission is the permission the role of the user needs for this route
+	// when authentication is enabled. Any user is allowed if empty.
+	Permission string
 }
 
 // Routes is the list of the generated Route.
@@ -46,15 +51,21 @@ func AddConfigV1Service(engine *gin.Engine, middlewares ...gin.HandlerFunc) *gin
 
 func addRoutes(group *gin.RouterGroup, routes Routes) {
 	for _, route := range routes {
+		handlers := []gin.HandlerFunc{route.HandlerFunc}
+		if route.Permission != "" {
+			handlers = []gin.HandlerFunc{auth.RequirePermission(route.Permission), route.HandlerFunc}
+		}
 		switch route.Method {
 		case http.MethodGet:
-			group.GET(route.Pattern, route.HandlerFunc)
+			group.GET(route.Pattern, handlers...)
 		case http.MethodPost:
-			group.POST(route.Pattern, route.HandlerFunc)
+			group.POST(route.Pattern, handlers...)
 		case http.MethodPut:
-			group.PUT(route.Pattern, route.HandlerFunc)
+			group.PUT(route.Pattern, handlers...)
+		case http.MethodPatch:
+			group.PATCH(route.Pattern, handlers...)
 		case http.MethodDelete:
-			group.DELETE(route.Pattern, route.HandlerFunc)
+			group.DELETE(route.Pattern, handlers...)
 		}
 	}
 }
@@ -70,6 +81,7 @@ var routes = Routes{
 		http.MethodGet,
 		"/",
 		Index,
+		"",
 	},
 
 	{
@@ -77,6 +89,7 @@ var routes = Routes{
 		http.MethodGet,
 		"/device-group",
 		GetDeviceGroups,
+		configmodels.PermissionNetworkRead,
 	},
 
 	{
@@ -84,6 +97,7 @@ var routes = Routes{
 		http.MethodGet,
 		"/device-group/:group-name",
 		GetDeviceGroupByName,
+		configmodels.PermissionNetworkRead,
 	},
 
 	{
@@ -91,6 +105,7 @@ var routes = Routes{
 		http.MethodDelete,
 		"/device-group/:group-name",
 		DeviceGroupGroupNameDelete,
+		configmodels.PermissionNetworkWrite,
 	},
 
 	{
@@ -98,6 +113,7 @@ var routes = Routes{
 		http.MethodPut,
 		"/device-group/:group-name",
 		DeviceGroupGroupNamePut,
+		configmodels.PermissionNetworkWrite,
 	},
 
 	{
@@ -105,6 +121,47 @@ var routes = Routes{
 		http.MethodPost,
 		"/device-group/:group-name",
 		DeviceGroupGroupNamePost,
+		configmodels.PermissionNetworkWrite,
+	},
+
+	{
+		"PostDeviceGroupImsis",
+		http.MethodPost,
+		"/device-group/:group-name/imsis",
+		PostDeviceGroupImsis,
+		configmodels.PermissionSubscribersWrite,
+	},
+
+	{
+		"DeleteDeviceGroupImsis",
+		http.MethodDelete,
+		"/device-group/:group-name/imsis",
+		DeleteDeviceGroupImsis,
+		configmodels.PermissionSubscribersWrite,
+	},
+
+	{
+		"GetIpPools",
+		http.MethodGet,
+		"/ip-pools",
+		GetIpPools,
+		configmodels.PermissionNetworkRead,
+	},
+
+	{
+		"GetAuditRecords",
+		http.MethodGet,
+		"/audit",
+		GetAuditRecords,
+		configmodels.PermissionAuditRead,
+	},
+
+	{
+		"GetSyncJob",
+		http.MethodGet,
+		"/jobs/:id",
+		GetSyncJob,
+		configmodels.PermissionNetworkRead,
 	},
 
 	{
@@ -112,6 +169,7 @@ var routes = Routes{
 		http.MethodGet,
 		"/network-slice",
 		GetNetworkSlices,
+		configmodels.PermissionNetworkRead,
 	},
 
 	{
@@ -119,6 +177,7 @@ var routes = Routes{
 		http.MethodGet,
 		"/network-slice/:slice-name",
 		GetNetworkSliceByName,
+		configmodels.PermissionNetworkRead,
 	},
 
 	{
@@ -126,6 +185,7 @@ var routes = Routes{
 		http.MethodDelete,
 		"/network-slice/:slice-name",
 		NetworkSliceSliceNameDelete,
+		configmodels.PermissionNetworkWrite,
 	},
 
 	{
@@ -133,6 +193,7 @@ var routes = Routes{
 		http.MethodPost,
 		"/network-slice/:slice-name",
 		NetworkSliceSliceNamePost,
+		configmodels.PermissionNetworkWrite,
 	},
 
 	{
@@ -140,59 +201,69 @@ var routes = Routes{
 		http.MethodPut,
 		"/network-slice/:slice-name",
 		NetworkSliceSliceNamePut,
+		configmodels.PermissionNetworkWrite,
 	},
 	{
 		"GetGnbs",
 		http.MethodGet,
 		"/inventory/gnb",
 		GetGnbs,
+		configmodels.PermissionNetworkRead,
 	},
 	{
 		"GetGnb",
 		http.MethodGet,
 		"/inventory/gnb/:gnbName",
 		GetGnb,
+		configmodels.PermissionNetworkRead,
 	},
 	{
 		"PostGnb",
 		http.MethodPost,
 		"/inventory/gnb",
 		PostGnb,
+		configmodels.PermissionNetworkWrite,
 	},
 	{
 		"PutGnb",
 		http.MethodPut,
 		"/inventory/gnb/:gnb-name",
 		PutGnb,
+		configmodels.PermissionNetworkWrite,
 	},
 	{
 		"DeleteGnb",
 		http.MethodDelete,
 		"/inventory/gnb/:gnb-name",
 		DeleteGnb,
+		configmodels.PermissionNetworkWrite,
 	},
 	{
 		"GetUpfs",
 		http.MethodGet,
 		"/inventory/upf",
 		GetUpfs,
+		configmodels.PermissionNetworkRead,
 	},
 	{
 		"PostUpf",
 		http.MethodPost,
 		"/inventory/upf",
 		PostUpf,
+		configmodels.PermissionNetworkWrite,
 	},
 	{
 		"PutUpf",
 		http.MethodPut,
 		"/inventory/upf/:upf-hostname",
 		PutUpf,
+		configmodels.PermissionNetworkWrite,
 	},
 	{
 		"DeleteUpf",
 		http.MethodDelete,
 		"/inventory/upf/:upf-hostname",
 		DeleteUpf,
+		configmodels.PermissionNetworkWrite,
 	},
 }
@@ -11,6 +11,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/configmodels"
 )
 
 func AddApiService(engine *gin.Engine, middlewares ...gin.HandlerFunc) *gin.RouterGroup {
@@ -28,6 +29,7 @@ var apiRoutes = Routes{
 		http.MethodGet,
 		"/sample",
 		GetSampleJSON,
+		"",
 	},
 
 	{
@@ -35,6 +37,7 @@ var apiRoutes = Routes{
 		http.MethodGet,
 		"/subscriber",
 		GetSubscribers,
+		configmodels.PermissionSubscribersRead,
 	},
 
 	{
@@ -42,6 +45,15 @@ var apiRoutes = Routes{
 		http.MethodGet,
 		"/subscriber/:ueId",
 		GetSubscriberByID,
+		configmodels.PermissionSubscribersRead,
+	},
+
+	{
+		"GetSubscriberProfile",
+		http.MethodGet,
+		"/subscriber/:ueId/profile",
+		GetSubscriberProfile,
+		configmodels.PermissionSubscribersRead,
 	},
 
 	{
@@ -49,6 +61,15 @@ var apiRoutes = Routes{
 		http.MethodPost,
 		"/subscriber/:ueId",
 		PostSubscriberByID,
+		configmodels.PermissionSubscribersWrite,
+	},
+
+	{
+		"PostSubscribersBulk",
+		http.MethodPost,
+		"/subscriber:action",
+		PostSubscribersBulk,
+		configmodels.PermissionSubscribersWrite,
 	},
 
 	{
@@ -56,6 +77,7 @@ var apiRoutes = Routes{
 		http.MethodPut,
 		"/subscriber/:ueId",
 		PutSubscriberByID,
+		configmodels.PermissionSubscribersWrite,
 	},
 
 	{
@@ -63,6 +85,7 @@ var apiRoutes = Routes{
 		http.MethodDelete,
 		"/subscriber/:ueId",
 		DeleteSubscriberByID,
+		configmodels.PermissionSubscribersWrite,
 	},
 
 	{
@@ -70,6 +93,7 @@ var apiRoutes = Routes{
 		http.MethodPatch,
 		"/subscriber/:ueId",
 		PatchSubscriberByID,
+		configmodels.PermissionSubscribersWrite,
 	},
 
 	{
@@ -77,6 +101,7 @@ var apiRoutes = Routes{
 		http.MethodGet,
 		"/registered-ue-context",
 		GetRegisteredUEContext,
+		configmodels.PermissionSubscribersRead,
 	},
 
 	{
@@ -84,6 +109,7 @@ var apiRoutes = Routes{
 		http.MethodGet,
 		"/registered-ue-context/:supi",
 		GetRegisteredUEContext,
+		configmodels.PermissionSubscribersRead,
 	},
 
 	{
@@ -91,6 +117,7 @@ var apiRoutes = Routes{
 		http.MethodGet,
 		"/ue-pdu-session-info/:smContextRef",
 		GetUEPDUSessionInfo,
+		configmodels.PermissionSubscribersRead,
 	},
 	// K4 api endpoint (CRUD)
 	{
@@ -98,29 +125,34 @@ var apiRoutes = Routes{
 		http.MethodGet,
 		"/k4opt",
 		HandleGetsK4,
+		configmodels.PermissionK4Read,
 	},
 	{
 		"Get a only k4 keys filtering using the sno",
 		http.MethodGet,
 		"/k4opt/:idsno",
 		HandleGetK4,
+		configmodels.PermissionK4Read,
 	},
 	{
 		"Post k4 key to create a k4 key",
 		http.MethodPost,
 		"/k4opt",
 		HandlePostK4,
+		configmodels.PermissionK4Write,
 	},
 	{
 		"Update k4 keys",
 		http.MethodPut,
 		"/k4opt/:idsno",
 		HandlePutK4,
+		configmodels.PermissionK4Write,
 	},
 	{
 		"Delete k4 keys",
 		http.MethodDelete,
 		"/k4opt/:idsno/:keylabel",
 		HandleDeleteK4,
+		configmodels.PermissionK4Write,
 	},
 }
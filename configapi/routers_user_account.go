@@ -10,42 +10,120 @@ import (
 	"github.com/omec-project/webconsole/backend/auth"
 )
 
-func AddUserAccountService(engine *gin.Engine, jwtSecret []byte) {
+func AddUserAccountService(engine *gin.Engine, signingKeys *auth.SigningKeys, middlewares ...gin.HandlerFunc) {
 	group := engine.Group("/config/v1")
-	addRoutes(group, getUserAccountRoutes(jwtSecret))
+	if len(middlewares) > 0 {
+		group.Use(middlewares...)
+	}
+	addRoutes(group, getUserAccountRoutes(signingKeys))
 }
 
-func getUserAccountRoutes(jwtSecret []byte) Routes {
+func getUserAccountRoutes(signingKeys *auth.SigningKeys) Routes {
 	return Routes{
 		{
 			"GetUserAccounts",
 			http.MethodGet,
 			"/account",
-			auth.AdminOnly(jwtSecret, GetUserAccounts),
+			auth.AdminOnly(signingKeys, GetUserAccounts),
+			"",
 		},
 		{
 			"GetUserAccount",
 			http.MethodGet,
 			"/account/:username",
-			auth.AdminOrMe(jwtSecret, GetUserAccount),
+			auth.AdminOrMe(signingKeys, GetUserAccount),
+			"",
 		},
 		{
 			"CreateUserAccount",
 			http.MethodPost,
 			"/account",
-			auth.AdminOrFirstUser(jwtSecret, CreateUserAccount),
+			auth.AdminOrFirstUser(signingKeys, CreateUserAccount),
+			"",
 		},
 		{
 			"DeleteUserAccount",
 			http.MethodDelete,
 			"/account/:username",
-			auth.AdminOnly(jwtSecret, DeleteUserAccount),
+			auth.AdminOnly(signingKeys, DeleteUserAccount),
+			"",
 		},
 		{
 			"ChangeUserAccountPasssword",
 			http.MethodPost,
 			"/account/:username/change_password",
-			auth.AdminOrMe(jwtSecret, ChangeUserAccountPasssword),
+			auth.AdminOrMe(signingKeys, ChangeUserAccountPasssword),
+			"",
+		},
+		{
+			"ChangeUserAccountRole",
+			http.MethodPost,
+			"/account/:username/role",
+			auth.AdminOnly(signingKeys, ChangeUserAccountRole),
+			"",
+		},
+		{
+			"UnlockUserAccount",
+			http.MethodPost,
+			"/account/:username/unlock",
+			auth.AdminOnly(signingKeys, UnlockUserAccount),
+			"",
+		},
+		{
+			"GetAPIKeys",
+			http.MethodGet,
+			"/account/:username/api-key",
+			auth.AdminOnly(signingKeys, GetAPIKeys),
+			"",
+		},
+		{
+			"APIKeyPost",
+			http.MethodPost,
+			"/account/:username/api-key",
+			auth.AdminOnly(signingKeys, APIKeyPost),
+			"",
+		},
+		{
+			"APIKeyDelete",
+			http.MethodDelete,
+			"/account/:username/api-key/:key-id",
+			auth.AdminOnly(signingKeys, APIKeyDelete),
+			"",
+		},
+		{
+			"GetRoles",
+			http.MethodGet,
+			"/role",
+			auth.AdminOnly(signingKeys, GetRoles),
+			"",
+		},
+		{
+			"GetRole",
+			http.MethodGet,
+			"/role/:role-name",
+			auth.AdminOnly(signingKeys, GetRole),
+			"",
+		},
+		{
+			"RoleRoleNamePost",
+			http.MethodPost,
+			"/role/:role-name",
+			auth.AdminOnly(signingKeys, RoleRoleNamePost),
+			"",
+		},
+		{
+			"RoleRoleNamePut",
+			http.MethodPut,
+			"/role/:role-name",
+			auth.AdminOnly(signingKeys, RoleRoleNamePut),
+			"",
+		},
+		{
+			"RoleRoleNameDelete",
+			http.MethodDelete,
+			"/role/:role-name",
+			auth.AdminOnly(signingKeys, RoleRoleNameDelete),
+			"",
 		},
 	}
 }
@@ -6,7 +6,6 @@ package configapi
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"math"
 	"net/http"
@@ -14,7 +13,6 @@ import (
 	"slices"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/omec-project/openapi/models"
@@ -27,11 +25,6 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-var (
-	SyncSliceStop      bool = false
-	syncSliceStopMutex sync.Mutex
-)
-
 var execCommand = exec.Command
 
 func networkSliceDeleteHelper(sliceName string) error {
@@ -42,11 +35,18 @@ func networkSliceDeleteHelper(sliceName string) error {
 	return nil
 }
 
-func networkSlicePostHelper(c *gin.Context, sliceName string) (int, error) {
+func networkSlicePostHelper(c *gin.Context, sliceName string) (*configmodels.SyncJob, int, error) {
 	logger.ConfigLog.Infof("received slice: %s", sliceName)
 	requestSlice, err := parseAndValidateSliceRequest(c, sliceName)
 	if err != nil {
-		return http.StatusBadRequest, err
+		return nil, http.StatusBadRequest, err
+	}
+	// held until the slice is stored, so that the overlap checks see the
+	// device groups and slices written before this request
+	rwLock.Lock()
+	defer rwLock.Unlock()
+	if statusCode, err := validateSliceIpPools(requestSlice); err != nil {
+		return nil, statusCode, err
 	}
 
 	logSliceMetadata(requestSlice)
@@ -56,17 +56,19 @@ func networkSlicePostHelper(c *gin.Context, sliceName string) (int, error) {
 
 	if prevSlice == nil {
 		logger.ConfigLog.Infof("Adding new slice [%s]", sliceName)
-		if statusCode, err := createNS(requestSlice); err != nil {
+		job, statusCode, err := createNS(requestSlice)
+		if err != nil {
 			logger.ConfigLog.Errorf("Error creating slice %s: %+v", sliceName, err)
-			return statusCode, err
-		}
-	} else {
-		if statusCode, err := updateNS(requestSlice, *prevSlice); err != nil {
-			logger.ConfigLog.Errorf("Error updating slice %s: %+v", sliceName, err)
-			return statusCode, err
+			return nil, statusCode, err
 		}
+		return job, http.StatusAccepted, nil
 	}
-	return http.StatusOK, nil
+	job, statusCode, err := updateNS(requestSlice, *prevSlice)
+	if err != nil {
+		logger.ConfigLog.Errorf("Error updating slice %s: %+v", sliceName, err)
+		return nil, statusCode, err
+	}
+	return job, http.StatusAccepted, nil
 }
 
 func parseAndValidateSliceRequest(c *gin.Context, sliceName string) (configmodels.Slice, error) {
@@ -81,50 +83,60 @@ func parseAndValidateSliceRequest(c *gin.Context, sliceName string) (configmodel
 		return request, fmt.Errorf("JSON bind error: %+v", err)
 	}
 
+	request.SliceName = sliceName
+	if err := validateSlice(&request); err != nil {
+		return request, err
+	}
+	return request, nil
+}
+
+// validateSlice checks the fields of a network slice, adding the default
+// application filtering rule if it has none.
+func validateSlice(request *configmodels.Slice) error {
+	sliceName := request.SliceName
 	for i, gnb := range request.SiteInfo.GNodeBs {
 		if !isValidName(gnb.Name) {
-			return request, fmt.Errorf("invalid gNodeBs[%d].name `%s` in Network Slice %s", i, gnb.Name, sliceName)
+			return fmt.Errorf("invalid gNodeBs[%d].name `%s` in Network Slice %s", i, gnb.Name, sliceName)
 		}
 		if !isValidGnbTac(gnb.Tac) {
-			return request, fmt.Errorf("invalid gNodeBs[%d].tac %d for gNB %s in Network Slice %s", i, gnb.Tac, gnb.Name, sliceName)
+			return fmt.Errorf("invalid gNodeBs[%d].tac %d for gNB %s in Network Slice %s", i, gnb.Tac, gnb.Name, sliceName)
 		}
 	}
 
-	request.SliceName = sliceName
 	// Validate required fields are not empty
 	if strings.TrimSpace(request.SliceName) == "" {
-		return request, fmt.Errorf("slice-name cannot be empty")
+		return fmt.Errorf("slice-name cannot be empty")
 	}
 	if strings.TrimSpace(request.SliceId.Sst) == "" {
-		return request, fmt.Errorf("slice-id.sst cannot be empty")
+		return fmt.Errorf("slice-id.sst cannot be empty")
 	}
 	if strings.TrimSpace(request.SliceId.Sd) == "" {
-		return request, fmt.Errorf("slice-id.sd cannot be empty")
+		return fmt.Errorf("slice-id.sd cannot be empty")
 	}
 	if len(request.SiteDeviceGroup) == 0 {
-		return request, fmt.Errorf("site-device-group cannot be empty")
+		return fmt.Errorf("site-device-group cannot be empty")
 	}
 	if strings.TrimSpace(request.SiteInfo.SiteName) == "" {
-		return request, fmt.Errorf("site-info.site-name cannot be empty")
+		return fmt.Errorf("site-info.site-name cannot be empty")
 	}
 	if strings.TrimSpace(request.SiteInfo.Plmn.Mcc) == "" {
-		return request, fmt.Errorf("site-info.plmn.mcc cannot be empty")
+		return fmt.Errorf("site-info.plmn.mcc cannot be empty")
 	}
 	if strings.TrimSpace(request.SiteInfo.Plmn.Mnc) == "" {
-		return request, fmt.Errorf("site-info.plmn.mnc cannot be empty")
+		return fmt.Errorf("site-info.plmn.mnc cannot be empty")
 	}
 	if request.SiteInfo.Upf == nil {
-		return request, fmt.Errorf("site-info.upf cannot be empty")
+		return fmt.Errorf("site-info.upf cannot be empty")
 	}
 	if len(request.SiteInfo.GNodeBs) == 0 {
-		return request, fmt.Errorf("site-info.gNodeBs cannot be empty")
+		return fmt.Errorf("site-info.gNodeBs cannot be empty")
 	}
 	for i, gnodeb := range request.SiteInfo.GNodeBs {
 		if strings.TrimSpace(gnodeb.Name) == "" {
-			return request, fmt.Errorf("site-info.gNodeBs[%d].name cannot be empty", i)
+			return fmt.Errorf("site-info.gNodeBs[%d].name cannot be empty", i)
 		}
 		if gnodeb.Tac <= 0 {
-			return request, fmt.Errorf("site-info.gNodeBs[%d].tac must be > 0", i)
+			return fmt.Errorf("site-info.gNodeBs[%d].tac must be > 0", i)
 		}
 	}
 
@@ -152,51 +164,51 @@ func parseAndValidateSliceRequest(c *gin.Context, sliceName string) (configmodel
 	} else {
 		for i, rule := range request.ApplicationFilteringRules {
 			if strings.TrimSpace(rule.RuleName) == "" {
-				return request, fmt.Errorf("application-filtering-rules[%d]: rule-name cannot be empty", i)
+				return fmt.Errorf("application-filtering-rules[%d]: rule-name cannot be empty", i)
 			}
 			if strings.TrimSpace(rule.Action) == "" {
-				return request, fmt.Errorf("application-filtering-rules[%d]: action cannot be empty", i)
+				return fmt.Errorf("application-filtering-rules[%d]: action cannot be empty", i)
 			}
 			if strings.TrimSpace(rule.Endpoint) == "" {
-				return request, fmt.Errorf("application-filtering-rules[%d]: endpoint cannot be empty", i)
+				return fmt.Errorf("application-filtering-rules[%d]: endpoint cannot be empty", i)
 			}
 			if rule.Protocol < 0 {
-				return request, fmt.Errorf("application-filtering-rules[%d]: protocol must be >= 0", i)
+				return fmt.Errorf("application-filtering-rules[%d]: protocol must be >= 0", i)
 			}
 			if rule.StartPort < 0 || rule.EndPort < 0 {
-				return request, fmt.Errorf("application-filtering-rules[%d]: port values must be >= 0", i)
+				return fmt.Errorf("application-filtering-rules[%d]: port values must be >= 0", i)
 			}
 			if rule.EndPort < rule.StartPort {
-				return request, fmt.Errorf("application-filtering-rules[%d]: dest-port-end must be >= dest-port-start", i)
+				return fmt.Errorf("application-filtering-rules[%d]: dest-port-end must be >= dest-port-start", i)
 			}
 			if rule.AppMbrUplink < 0 {
-				return request, fmt.Errorf("application-filtering-rules[%d]: app-mbr-uplink must be >= 0", i)
+				return fmt.Errorf("application-filtering-rules[%d]: app-mbr-uplink must be >= 0", i)
 			}
 			if rule.AppMbrDownlink < 0 {
-				return request, fmt.Errorf("application-filtering-rules[%d]: app-mbr-downlink must be >= 0", i)
+				return fmt.Errorf("application-filtering-rules[%d]: app-mbr-downlink must be >= 0", i)
 			}
 			if rule.BitrateUnit == "" {
-				return request, fmt.Errorf("application-filtering-rules[%d]: bitrate-unit cannot be empty", i)
+				return fmt.Errorf("application-filtering-rules[%d]: bitrate-unit cannot be empty", i)
 			}
 			if rule.TrafficClass != nil {
 				if strings.TrimSpace(rule.TrafficClass.Name) == "" {
-					return request, fmt.Errorf("application-filtering-rules[%d]: traffic-class.name cannot be empty", i)
+					return fmt.Errorf("application-filtering-rules[%d]: traffic-class.name cannot be empty", i)
 				}
 				if rule.TrafficClass.Qci < 1 || rule.TrafficClass.Qci > 9 {
-					return request, fmt.Errorf("application-filtering-rules[%d]: traffic-class.qci must be between 1 and 9", i)
+					return fmt.Errorf("application-filtering-rules[%d]: traffic-class.qci must be between 1 and 9", i)
 				}
 				if rule.TrafficClass.Arp < 1 || rule.TrafficClass.Arp > 15 {
-					return request, fmt.Errorf("application-filtering-rules[%d]: traffic-class.arp must be between 1 and 15", i)
+					return fmt.Errorf("application-filtering-rules[%d]: traffic-class.arp must be between 1 and 15", i)
 				}
 				if rule.TrafficClass.Pdb < 0 {
-					return request, fmt.Errorf("application-filtering-rules[%d]: traffic-class.pdb must be >= 0", i)
+					return fmt.Errorf("application-filtering-rules[%d]: traffic-class.pdb must be >= 0", i)
 				}
 				if rule.TrafficClass.Pelr < 1 || rule.TrafficClass.Pelr > 8 {
-					return request, fmt.Errorf("application-filtering-rules[%d]: traffic-class.pelr must be between 1 and 8", i)
+					return fmt.Errorf("application-filtering-rules[%d]: traffic-class.pelr must be between 1 and 8", i)
 				}
 			}
 			if rule.TrafficClass == nil {
-				return request, fmt.Errorf("application-filtering-rules[%d]: traffic-class cannot be empty", i)
+				return fmt.Errorf("application-filtering-rules[%d]: traffic-class cannot be empty", i)
 			}
 		}
 	}
@@ -204,7 +216,7 @@ func parseAndValidateSliceRequest(c *gin.Context, sliceName string) (configmodel
 	slices.Sort(request.SiteDeviceGroup)
 	request.SiteDeviceGroup = slices.Compact(request.SiteDeviceGroup)
 
-	return request, nil
+	return nil
 }
 
 func logSliceMetadata(slice configmodels.Slice) {
@@ -248,35 +260,39 @@ func convertBitrateToInt32(bitrate int64) int32 {
 	return int32(bitrate)
 }
 
-func createNS(slice configmodels.Slice) (int, error) {
-	if statusCode, err := handleNetworkSlicePost(slice, configmodels.Slice{}); err != nil {
+func createNS(slice configmodels.Slice) (*configmodels.SyncJob, int, error) {
+	job, statusCode, err := handleNetworkSlicePost(slice, configmodels.Slice{})
+	if err != nil {
 		logger.ConfigLog.Errorf("Error creating slice %s: %+v", slice.SliceName, err)
-		return statusCode, err
+		return nil, statusCode, err
 	}
-	return http.StatusOK, nil
+	return job, http.StatusAccepted, nil
 }
 
-func updateNS(slice, prevSlice configmodels.Slice) (int, error) {
-	if statusCode, err := handleNetworkSlicePost(slice, prevSlice); err != nil {
+func updateNS(slice, prevSlice configmodels.Slice) (*configmodels.SyncJob, int, error) {
+	job, statusCode, err := handleNetworkSlicePost(slice, prevSlice)
+	if err != nil {
 		logger.ConfigLog.Errorf("Error updating slice %s: %+v", slice.SliceName, err)
-		return statusCode, err
+		return nil, statusCode, err
 	}
-	return http.StatusOK, nil
+	return job, http.StatusAccepted, nil
 }
 
-func handleNetworkSlicePost(slice configmodels.Slice, prevSlice configmodels.Slice) (int, error) {
+// handleNetworkSlicePost stores the slice and queues the sync of its
+// subscribers. The returned job tracks the sync.
+func handleNetworkSlicePost(slice configmodels.Slice, prevSlice configmodels.Slice) (*configmodels.SyncJob, int, error) {
 	filter := bson.M{"slice-name": slice.SliceName}
 	sliceDataBsonA := configmodels.ToBsonM(slice)
 	_, err := dbadapter.CommonDBClient.RestfulAPIPost(sliceDataColl, filter, sliceDataBsonA)
 	if err != nil {
 		logger.AppLog.Errorf("failed to post slice data for %s: %+v", slice.SliceName, err)
-		return http.StatusInternalServerError, err
+		return nil, http.StatusInternalServerError, err
 	}
 	logger.AppLog.Debugf("succeeded to post slice data for %s", slice.SliceName)
 
-	statusCode, err := syncSubConcurrently(slice, prevSlice)
+	job, err := enqueueSliceSyncJob(slice, prevSlice)
 	if err != nil {
-		return statusCode, err
+		return nil, http.StatusInternalServerError, err
 	}
 	if factory.WebUIConfig.Configuration.SendPebbleNotifications {
 		err = sendPebbleNotification("aetherproject.org/webconsole/networkslice/create")
@@ -284,31 +300,7 @@ func handleNetworkSlicePost(slice configmodels.Slice, prevSlice configmodels.Sli
 			logger.ConfigLog.Warnf("sending Pebble notification failed: %s. continuing silently", err.Error())
 		}
 	}
-	return http.StatusOK, nil
-}
-
-func syncSubConcurrently(slice configmodels.Slice, prevSlice configmodels.Slice) (int, error) {
-	syncSliceStopMutex.Lock()
-	if SyncSliceStop {
-		syncSliceStopMutex.Unlock()
-		return http.StatusServiceUnavailable, errors.New("error: the sync function is running")
-	}
-	SyncSliceStop = true
-	syncSliceStopMutex.Unlock()
-
-	go func() {
-		defer func() {
-			syncSliceStopMutex.Lock()
-			SyncSliceStop = false
-			syncSliceStopMutex.Unlock()
-		}()
-		_, err := syncSubscribersOnSliceCreateOrUpdate(slice, prevSlice)
-		if err != nil {
-			logger.AppLog.Errorf("error syncing subscribers: %s", err)
-		}
-	}()
-
-	return 0, nil
+	return job, http.StatusAccepted, nil
 }
 
 func sendPebbleNotification(key string) error {
@@ -330,7 +322,7 @@ var syncSubscribersOnSliceDelete = func(slice *configmodels.Slice, prevSlice *co
 	return nil
 }
 
-var syncSubscribersOnSliceCreateOrUpdate = func(slice configmodels.Slice, prevSlice configmodels.Slice) (int, error) {
+var syncSubscribersOnSliceCreateOrUpdate = func(slice configmodels.Slice, prevSlice configmodels.Slice, progress *syncJobProgress) (int, error) {
 	rwLock.Lock()
 	defer rwLock.Unlock()
 	logger.WebUILog.Debugln("insert/update Slice:", slice)
@@ -356,7 +348,29 @@ var syncSubscribersOnSliceCreateOrUpdate = func(slice configmodels.Slice, prevSl
 			logger.ConfigLog.Warnf("Device group not found: %s", dgName)
 			continue
 		}
-		logger.AppLog.Debugf("slice=%s dg=%s: inputIMSIs=%d", slice.SliceName, dgName, len(devGroupConfig.Imsis))
+		logger.AppLog.Debugf("slice=%s dg=%s: inputIMSIs=%d inputRanges=%d", slice.SliceName, dgName, len(devGroupConfig.Imsis), len(devGroupConfig.ImsiRanges))
+
+		updateBatch := func(imsis []string) error {
+			err := updatePolicyAndProvisionedDataBatch(
+				imsis,
+				slice.SiteInfo.Plmn.Mcc,
+				slice.SiteInfo.Plmn.Mnc,
+				snssai,
+				devGroupConfig.GetIpDomains(),
+			)
+			if err != nil {
+				progress.fail(imsis...)
+				return err
+			}
+			progress.done(len(imsis))
+			return nil
+		}
+		for _, imsiRange := range devGroupConfig.ImsiRanges {
+			if err := forEachImsiInRange(dbadapter.AuthDBClient, AuthSubsDataColl, imsiRange, updateBatch); err != nil {
+				logger.AppLog.Errorf("batch update failed for IMSI range %+v of device group %s: %v", imsiRange, dgName, err)
+				return http.StatusInternalServerError, err
+			}
+		}
 
 		existing, err := filterExistingIMSIsFromAuthDB(devGroupConfig.Imsis)
 		if err != nil {
@@ -368,14 +382,7 @@ var syncSubscribersOnSliceCreateOrUpdate = func(slice configmodels.Slice, prevSl
 		}
 		logger.AppLog.Debugf("slice=%s dg=%s: existingIMSIs=%d", slice.SliceName, dgName, len(existing))
 
-		if err := updatePolicyAndProvisionedDataBatch(
-			existing,
-			slice.SiteInfo.Plmn.Mcc,
-			slice.SiteInfo.Plmn.Mnc,
-			snssai,
-			devGroupConfig.IpDomainExpanded.Dnn,
-			devGroupConfig.IpDomainExpanded.UeDnnQos,
-		); err != nil {
+		if err := updateBatch(existing); err != nil {
 			logger.AppLog.Errorf("batch update failed for device group %s: %v", dgName, err)
 			return http.StatusInternalServerError, err
 		}
@@ -609,40 +616,48 @@ func cleanupDeviceGroups(slice, prevSlice configmodels.Slice) error {
 		if err := g.Wait(); err != nil {
 			return err
 		}
+		for _, imsiRange := range devGroupConfig.ImsiRanges {
+			err := forEachImsiInRange(dbadapter.CommonDBClient, AmDataColl, imsiRange, func(imsis []string) error {
+				return removeSubscribersRelatedToDeviceGroups(prevSlice.SiteInfo.Plmn.Mcc, prevSlice.SiteInfo.Plmn.Mnc, imsis, nil)
+			})
+			if err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
-func updatePolicyAndProvisionedData(imsi string, mcc string, mnc string, snssai *models.Snssai, dnn string, qos *configmodels.DeviceGroupsIpDomainExpandedUeDnnQos) error {
+func updatePolicyAndProvisionedData(imsi string, mcc string, mnc string, snssai *models.Snssai, ipDomains []configmodels.DeviceGroupsIpDomain) error {
 	err := updateAmPolicyData(imsi)
 	if err != nil {
 		return fmt.Errorf("updateAmPolicyData failed: %w", err)
 	}
-	err = updateSmPolicyData(snssai, dnn, imsi)
+	err = updateSmPolicyData(snssai, ipDomains, imsi)
 	if err != nil {
 		return fmt.Errorf("updateSmPolicyData failed: %w", err)
 	}
-	err = updateAmProvisionedData(snssai, qos, mcc, mnc, imsi)
+	err = updateAmProvisionedData(snssai, ueAmbrQos(ipDomains), mcc, mnc, imsi)
 	if err != nil {
 		return fmt.Errorf("updateAmProvisionedData failed: %w", err)
 	}
-	err = updateSmProvisionedData(snssai, qos, mcc, mnc, dnn, imsi)
+	err = updateSmProvisionedData(snssai, ipDomains, mcc, mnc, imsi)
 	if err != nil {
 		return fmt.Errorf("updateSmProvisionedData failed: %w", err)
 	}
-	err = updateSmfSelectionProvisionedData(snssai, mcc, mnc, dnn, imsi)
+	err = updateSmfSelectionProvisionedData(snssai, mcc, mnc, ipDomains, imsi)
 	if err != nil {
 		return fmt.Errorf("updateSmfSelectionProvisionedData failed: %w", err)
 	}
 	return nil
 }
 
-func updatePolicyAndProvisionedDataBatch(imsis []string, mcc string, mnc string, snssai *models.Snssai, dnn string, qos *configmodels.DeviceGroupsIpDomainExpandedUeDnnQos) error {
-	logger.AppLog.Debugf("updatePolicyAndProvisionedDataBatch: imsis=%d batchSize=%d mcc=%s mnc=%s dnn=%s", len(imsis), imsiBatchSize, mcc, mnc, dnn)
-	return updatePoliciesAndProvisionedDatas(imsis, mcc, mnc, snssai, dnn, qos)
+func updatePolicyAndProvisionedDataBatch(imsis []string, mcc string, mnc string, snssai *models.Snssai, ipDomains []configmodels.DeviceGroupsIpDomain) error {
+	logger.AppLog.Debugf("updatePolicyAndProvisionedDataBatch: imsis=%d batchSize=%d mcc=%s mnc=%s dnns=%v", len(imsis), imsiBatchSize, mcc, mnc, ipDomainDnns(ipDomains))
+	return updatePoliciesAndProvisionedDatas(imsis, mcc, mnc, snssai, ipDomains)
 }
 
-func updatePoliciesAndProvisionedDatas(imsis []string, mcc string, mnc string, snssai *models.Snssai, dnn string, qos *configmodels.DeviceGroupsIpDomainExpandedUeDnnQos) error {
+func updatePoliciesAndProvisionedDatas(imsis []string, mcc string, mnc string, snssai *models.Snssai, ipDomains []configmodels.DeviceGroupsIpDomain) error {
 	if len(imsis) == 0 {
 		logger.AppLog.Debugf("updatePoliciesAndProvisionedDatas: no IMSIs; nothing to do")
 		return nil
@@ -651,6 +666,7 @@ func updatePoliciesAndProvisionedDatas(imsis []string, mcc string, mnc string, s
 	chunks := chunkStrings(imsis, imsiBatchSize)
 	logger.AppLog.Debugf("updatePoliciesAndProvisionedDatas: totalIMSIs=%d chunks=%d batchSize=%d", len(imsis), len(chunks), imsiBatchSize)
 
+	qos := ueAmbrQos(ipDomains)
 	g := errgroup.Group{}
 	g.SetLimit(factory.WebUIConfig.Configuration.Mongodb.ConcurrencyOps)
 
@@ -661,7 +677,7 @@ func updatePoliciesAndProvisionedDatas(imsis []string, mcc string, mnc string, s
 			if err != nil {
 				return fmt.Errorf("updateAmPolicyData failed (chunk %d/%d): %w", i+1, len(chunks), err)
 			}
-			err = updateSmPolicyDatas(snssai, dnn, chunk)
+			err = updateSmPolicyDatas(snssai, ipDomains, chunk)
 			if err != nil {
 				return fmt.Errorf("updateSmPolicyData failed (chunk %d/%d): %w", i+1, len(chunks), err)
 			}
@@ -669,11 +685,11 @@ func updatePoliciesAndProvisionedDatas(imsis []string, mcc string, mnc string, s
 			if err != nil {
 				return fmt.Errorf("updateAmProvisionedData failed (chunk %d/%d): %w", i+1, len(chunks), err)
 			}
-			err = updateSmProvisionedDatas(snssai, qos, mcc, mnc, dnn, chunk)
+			err = updateSmProvisionedDatas(snssai, ipDomains, mcc, mnc, chunk)
 			if err != nil {
 				return fmt.Errorf("updateSmProvisionedData failed (chunk %d/%d): %w", i+1, len(chunks), err)
 			}
-			err = updateSmfSelectionProvisionedDatas(snssai, mcc, mnc, dnn, chunk)
+			err = updateSmfSelectionProvisionedDatas(snssai, mcc, mnc, ipDomains, chunk)
 			if err != nil {
 				return fmt.Errorf("updateSmfSelectionProvisionedData failed (chunk %d/%d): %w", i+1, len(chunks), err)
 			}
@@ -724,16 +740,15 @@ func updateAmPolicyDatas(imsis []string) error {
 	return nil
 }
 
-func updateSmPolicyDatas(snssai *models.Snssai, dnn string, imsis []string) error {
+func updateSmPolicyDatas(snssai *models.Snssai, ipDomains []configmodels.DeviceGroupsIpDomain, imsis []string) error {
 	if len(imsis) == 0 {
 		return nil
 	}
-	logger.AppLog.Debugf("updateSmPolicyDatas: coll=%s imsis=%d dnn=%s", SmPolicyDataColl, len(imsis), dnn)
+	logger.AppLog.Debugf("updateSmPolicyDatas: coll=%s imsis=%d dnns=%v", SmPolicyDataColl, len(imsis), ipDomainDnns(ipDomains))
 	var smPolicyData models.SmPolicyData
 	var smPolicySnssaiData models.SmPolicySnssaiData
-	dnnData := map[string]models.SmPolicyDnnData{dnn: {Dnn: dnn}}
 	smPolicySnssaiData.Snssai = snssai
-	smPolicySnssaiData.SmPolicyDnnData = dnnData
+	smPolicySnssaiData.SmPolicyDnnData = smPolicyDnnData(ipDomains)
 	smPolicyData.SmPolicySnssaiData = make(map[string]models.SmPolicySnssaiData)
 	smPolicyData.SmPolicySnssaiData[SnssaiModelsToHex(*snssai)] = smPolicySnssaiData
 	baseDoc := configmodels.ToBsonM(smPolicyData)
@@ -791,22 +806,15 @@ func updateAmProvisionedDatas(snssai *models.Snssai, qos *configmodels.DeviceGro
 	return nil
 }
 
-func updateSmProvisionedDatas(snssai *models.Snssai, qos *configmodels.DeviceGroupsIpDomainExpandedUeDnnQos, mcc string, mnc string, dnn string, imsis []string) error {
+func updateSmProvisionedDatas(snssai *models.Snssai, ipDomains []configmodels.DeviceGroupsIpDomain, mcc string, mnc string, imsis []string) error {
 	if len(imsis) == 0 {
 		return nil
 	}
-	logger.AppLog.Debugf("updateSmProvisionedDatas: coll=%s imsis=%d mcc=%s mnc=%s dnn=%s", SmDataColl, len(imsis), mcc, mnc, dnn)
+	logger.AppLog.Debugf("updateSmProvisionedDatas: coll=%s imsis=%d mcc=%s mnc=%s dnns=%v", SmDataColl, len(imsis), mcc, mnc, ipDomainDnns(ipDomains))
 	plmn := mcc + mnc
 	smData := models.SessionManagementSubscriptionData{
-		SingleNssai: snssai,
-		DnnConfigurations: map[string]models.DnnConfiguration{
-			dnn: {
-				PduSessionTypes: &models.PduSessionTypes{DefaultSessionType: models.PduSessionType_IPV4, AllowedSessionTypes: []models.PduSessionType{models.PduSessionType_IPV4}},
-				SscModes:        &models.SscModes{DefaultSscMode: models.SscMode__1, AllowedSscModes: []models.SscMode{"SSC_MODE_2", "SSC_MODE_3"}},
-				SessionAmbr:     &models.Ambr{Downlink: ConvertToString(uint64(qos.DnnMbrDownlink)), Uplink: ConvertToString(uint64(qos.DnnMbrUplink))},
-				Var5gQosProfile: &models.SubscribedDefaultQos{Var5qi: 9, Arp: &models.Arp{PriorityLevel: 8}, PriorityLevel: 8},
-			},
-		},
+		SingleNssai:       snssai,
+		DnnConfigurations: dnnConfigurations(ipDomains),
 	}
 	baseDoc := configmodels.ToBsonM(smData)
 
@@ -829,15 +837,15 @@ func updateSmProvisionedDatas(snssai *models.Snssai, qos *configmodels.DeviceGro
 	return nil
 }
 
-func updateSmfSelectionProvisionedDatas(snssai *models.Snssai, mcc string, mnc string, dnn string, imsis []string) error {
+func updateSmfSelectionProvisionedDatas(snssai *models.Snssai, mcc string, mnc string, ipDomains []configmodels.DeviceGroupsIpDomain, imsis []string) error {
 	if len(imsis) == 0 {
 		return nil
 	}
-	logger.AppLog.Debugf("updateSmfSelectionProvisionedDatas: coll=%s imsis=%d mcc=%s mnc=%s dnn=%s", SmfSelDataColl, len(imsis), mcc, mnc, dnn)
+	logger.AppLog.Debugf("updateSmfSelectionProvisionedDatas: coll=%s imsis=%d mcc=%s mnc=%s dnns=%v", SmfSelDataColl, len(imsis), mcc, mnc, ipDomainDnns(ipDomains))
 	plmn := mcc + mnc
 	smfSelData := models.SmfSelectionSubscriptionData{
 		SubscribedSnssaiInfos: map[string]models.SnssaiInfo{
-			SnssaiModelsToHex(*snssai): {DnnInfos: []models.DnnInfo{{Dnn: dnn}}},
+			SnssaiModelsToHex(*snssai): {DnnInfos: dnnInfos(ipDomains)},
 		},
 	}
 	baseDoc := configmodels.ToBsonM(smfSelData)
@@ -861,6 +869,90 @@ func updateSmfSelectionProvisionedDatas(snssai *models.Snssai, mcc string, mnc s
 	return nil
 }
 
+func ipDomainDnns(ipDomains []configmodels.DeviceGroupsIpDomain) []string {
+	dnns := make([]string, 0, len(ipDomains))
+	for _, ipDomain := range ipDomains {
+		dnns = append(dnns, ipDomain.IpDomainExpanded.Dnn)
+	}
+	return dnns
+}
+
+// ueAmbrQos returns the QoS whose MBRs are used as the UE AMBR: the sum of
+// the session AMBRs of all the DNNs of the device group.
+func ueAmbrQos(ipDomains []configmodels.DeviceGroupsIpDomain) *configmodels.DeviceGroupsIpDomainExpandedUeDnnQos {
+	if len(ipDomains) == 1 {
+		return ipDomains[0].IpDomainExpanded.UeDnnQos
+	}
+	qos := &configmodels.DeviceGroupsIpDomainExpandedUeDnnQos{}
+	for _, ipDomain := range ipDomains {
+		dnnQos := ipDomain.IpDomainExpanded.UeDnnQos
+		if dnnQos == nil {
+			continue
+		}
+		qos.DnnMbrUplink = saturatingAdd(qos.DnnMbrUplink, dnnQos.DnnMbrUplink)
+		qos.DnnMbrDownlink = saturatingAdd(qos.DnnMbrDownlink, dnnQos.DnnMbrDownlink)
+	}
+	return qos
+}
+
+func saturatingAdd(a, b int64) int64 {
+	if a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
+
+func smPolicyDnnData(ipDomains []configmodels.DeviceGroupsIpDomain) map[string]models.SmPolicyDnnData {
+	dnnData := make(map[string]models.SmPolicyDnnData, len(ipDomains))
+	for _, ipDomain := range ipDomains {
+		dnn := ipDomain.IpDomainExpanded.Dnn
+		dnnData[dnn] = models.SmPolicyDnnData{
+			Dnn: dnn,
+		}
+	}
+	return dnnData
+}
+
+func dnnConfigurations(ipDomains []configmodels.DeviceGroupsIpDomain) map[string]models.DnnConfiguration {
+	dnnConfigs := make(map[string]models.DnnConfiguration, len(ipDomains))
+	for _, ipDomain := range ipDomains {
+		qos := ipDomain.IpDomainExpanded.UeDnnQos
+		dnnConfigs[ipDomain.IpDomainExpanded.Dnn] = models.DnnConfiguration{
+			PduSessionTypes: &models.PduSessionTypes{
+				DefaultSessionType:  models.PduSessionType_IPV4,
+				AllowedSessionTypes: []models.PduSessionType{models.PduSessionType_IPV4},
+			},
+			SscModes: &models.SscModes{
+				DefaultSscMode: models.SscMode__1,
+				AllowedSscModes: []models.SscMode{
+					"SSC_MODE_2",
+					"SSC_MODE_3",
+				},
+			},
+			SessionAmbr: &models.Ambr{
+				Downlink: ConvertToString(uint64(qos.DnnMbrDownlink)),
+				Uplink:   ConvertToString(uint64(qos.DnnMbrUplink)),
+			},
+			Var5gQosProfile: &models.SubscribedDefaultQos{
+				Var5qi: 9,
+				Arp: &models.Arp{
+					PriorityLevel: 8,
+				},
+				PriorityLevel: 8,
+			},
+		}
+	}
+	return dnnConfigs
+}
+
+func dnnInfos(ipDomains []configmodels.DeviceGroupsIpDomain) []models.DnnInfo {
+	infos := make([]models.DnnInfo, 0, len(ipDomains))
+	for _, ipDomain := range ipDomains {
+		infos = append(infos, models.DnnInfo{Dnn: ipDomain.IpDomainExpanded.Dnn})
+	}
+	return infos
+}
+
 func updateAmPolicyData(imsi string) error {
 	var amPolicy models.AmPolicyData
 	amPolicy.SubscCats = append(amPolicy.SubscCats, "aether")
@@ -876,17 +968,12 @@ func updateAmPolicyData(imsi string) error {
 	return nil
 }
 
-func updateSmPolicyData(snssai *models.Snssai, dnn string, imsi string) error {
+func updateSmPolicyData(snssai *models.Snssai, ipDomains []configmodels.DeviceGroupsIpDomain, imsi string) error {
 	var smPolicyData models.SmPolicyData
 	var smPolicySnssaiData models.SmPolicySnssaiData
-	dnnData := map[string]models.SmPolicyDnnData{
-		dnn: {
-			Dnn: dnn,
-		},
-	}
 	// smpolicydata
 	smPolicySnssaiData.Snssai = snssai
-	smPolicySnssaiData.SmPolicyDnnData = dnnData
+	smPolicySnssaiData.SmPolicyDnnData = smPolicyDnnData(ipDomains)
 	smPolicyData.SmPolicySnssaiData = make(map[string]models.SmPolicySnssaiData)
 	smPolicyData.SmPolicySnssaiData[SnssaiModelsToHex(*snssai)] = smPolicySnssaiData
 	smPolicyDatBsonA := configmodels.ToBsonM(smPolicyData)
@@ -934,35 +1021,10 @@ func updateAmProvisionedData(snssai *models.Snssai, qos *configmodels.DeviceGrou
 	return nil
 }
 
-func updateSmProvisionedData(snssai *models.Snssai, qos *configmodels.DeviceGroupsIpDomainExpandedUeDnnQos, mcc, mnc, dnn, imsi string) error {
+func updateSmProvisionedData(snssai *models.Snssai, ipDomains []configmodels.DeviceGroupsIpDomain, mcc, mnc, imsi string) error {
 	smData := models.SessionManagementSubscriptionData{
-		SingleNssai: snssai,
-		DnnConfigurations: map[string]models.DnnConfiguration{
-			dnn: {
-				PduSessionTypes: &models.PduSessionTypes{
-					DefaultSessionType:  models.PduSessionType_IPV4,
-					AllowedSessionTypes: []models.PduSessionType{models.PduSessionType_IPV4},
-				},
-				SscModes: &models.SscModes{
-					DefaultSscMode: models.SscMode__1,
-					AllowedSscModes: []models.SscMode{
-						"SSC_MODE_2",
-						"SSC_MODE_3",
-					},
-				},
-				SessionAmbr: &models.Ambr{
-					Downlink: ConvertToString(uint64(qos.DnnMbrDownlink)),
-					Uplink:   ConvertToString(uint64(qos.DnnMbrUplink)),
-				},
-				Var5gQosProfile: &models.SubscribedDefaultQos{
-					Var5qi: 9,
-					Arp: &models.Arp{
-						PriorityLevel: 8,
-					},
-					PriorityLevel: 8,
-				},
-			},
-		},
+		SingleNssai:       snssai,
+		DnnConfigurations: dnnConfigurations(ipDomains),
 	}
 	smDataBsonA := configmodels.ToBsonM(smData)
 	smDataBsonA["ueId"] = "imsi-" + imsi
@@ -977,15 +1039,11 @@ func updateSmProvisionedData(snssai *models.Snssai, qos *configmodels.DeviceGrou
 	return nil
 }
 
-func updateSmfSelectionProvisionedData(snssai *models.Snssai, mcc, mnc, dnn, imsi string) error {
+func updateSmfSelectionProvisionedData(snssai *models.Snssai, mcc, mnc string, ipDomains []configmodels.DeviceGroupsIpDomain, imsi string) error {
 	smfSelData := models.SmfSelectionSubscriptionData{
 		SubscribedSnssaiInfos: map[string]models.SnssaiInfo{
 			SnssaiModelsToHex(*snssai): {
-				DnnInfos: []models.DnnInfo{
-					{
-						Dnn: dnn,
-					},
-				},
+				DnnInfos: dnnInfos(ipDomains),
 			},
 		},
 	}
@@ -1026,21 +1084,20 @@ func ConvertToString(val uint64) string {
 	return retStr
 }
 
-func getSlices() []*configmodels.Slice {
-	rawSlices, errGetMany := dbadapter.CommonDBClient.RestfulAPIGetMany(sliceDataColl, nil)
-	if errGetMany != nil {
-		logger.AppLog.Warnln(errGetMany)
+func getSlices() ([]*configmodels.Slice, error) {
+	rawSlices, err := dbadapter.CommonDBClient.RestfulAPIGetMany(sliceDataColl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch network slices: %w", err)
 	}
 	var slices []*configmodels.Slice
 	for _, rawSlice := range rawSlices {
 		var sliceData configmodels.Slice
-		err := json.Unmarshal(configmodels.MapToByte(rawSlice), &sliceData)
-		if err != nil {
-			logger.AppLog.Errorf("could not unmarshall slice %+v", rawSlice)
+		if err = json.Unmarshal(configmodels.MapToByte(rawSlice), &sliceData); err != nil {
+			return nil, fmt.Errorf("could not unmarshal slice %+v: %w", rawSlice, err)
 		}
 		slices = append(slices, &sliceData)
 	}
-	return slices
+	return slices, nil
 }
 
 func getSliceByName(name string) *configmodels.Slice {
@@ -3,7 +3,10 @@
 package configapi
 
 import (
+	"encoding/json"
+	"reflect"
 	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/omec-project/openapi/models"
@@ -69,7 +72,9 @@ func Test_updatePolicyAndProvisionedDataBatch_UsesPutMany(t *testing.T) {
 	snssai := &models.Snssai{Sst: 1, Sd: "010203"}
 	qos := &configmodels.DeviceGroupsIpDomainExpandedUeDnnQos{DnnMbrDownlink: 1000, DnnMbrUplink: 1000}
 
-	err := updatePolicyAndProvisionedDataBatch([]string{"001", "002"}, "208", "93", snssai, "internet", qos)
+	err := updatePolicyAndProvisionedDataBatch([]string{"001", "002"}, "208", "93", snssai, []configmodels.DeviceGroupsIpDomain{
+		{IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{Dnn: "internet", UeDnnQos: qos}},
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -114,7 +119,9 @@ func Test_updatePolicyAndProvisionedDataBatch_ChunksBy1000(t *testing.T) {
 	snssai := &models.Snssai{Sst: 1, Sd: "010203"}
 	qos := &configmodels.DeviceGroupsIpDomainExpandedUeDnnQos{DnnMbrDownlink: 1000, DnnMbrUplink: 1000}
 
-	err := updatePolicyAndProvisionedDataBatch(imsis, "208", "93", snssai, "internet", qos)
+	err := updatePolicyAndProvisionedDataBatch(imsis, "208", "93", snssai, []configmodels.DeviceGroupsIpDomain{
+		{IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{Dnn: "internet", UeDnnQos: qos}},
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -140,3 +147,74 @@ func Test_updatePolicyAndProvisionedDataBatch_ChunksBy1000(t *testing.T) {
 		t.Fatalf("expected five 1000-sized and five 1-sized calls; got 1000=%d 1=%d", count1000, count1)
 	}
 }
+
+func Test_updatePolicyAndProvisionedDataBatch_MultipleDnns(t *testing.T) {
+	origCommon := dbadapter.CommonDBClient
+	defer func() { dbadapter.CommonDBClient = origCommon }()
+	oldConfig := factory.WebUIConfig
+	defer func() { factory.WebUIConfig = oldConfig }()
+	factory.WebUIConfig = &factory.Config{
+		Configuration: &factory.Configuration{
+			Mongodb: &factory.Mongodb{
+				ConcurrencyOps: 5,
+			},
+		},
+	}
+	var mu sync.Mutex
+	docs := map[string]map[string]any{}
+	dbadapter.CommonDBClient = &dbadapter.MockDBClient{
+		PutManyFn: func(collName string, filterArray []primitive.M, putDataArray []map[string]any) error {
+			mu.Lock()
+			defer mu.Unlock()
+			docs[collName] = putDataArray[0]
+			return nil
+		},
+	}
+
+	snssai := &models.Snssai{Sst: 1, Sd: "010203"}
+	ipDomains := []configmodels.DeviceGroupsIpDomain{
+		{IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{
+			Dnn:      "internet",
+			UeDnnQos: &configmodels.DeviceGroupsIpDomainExpandedUeDnnQos{DnnMbrDownlink: 1000, DnnMbrUplink: 500},
+		}},
+		{IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{
+			Dnn:      "iot",
+			UeDnnQos: &configmodels.DeviceGroupsIpDomainExpandedUeDnnQos{DnnMbrDownlink: 200, DnnMbrUplink: 100},
+		}},
+	}
+
+	err := updatePolicyAndProvisionedDataBatch([]string{"001010000000001"}, "001", "01", snssai, ipDomains)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var smData models.SessionManagementSubscriptionData
+	if err = json.Unmarshal(configmodels.MapToByte(docs[SmDataColl]), &smData); err != nil {
+		t.Fatalf("could not unmarshal smData: %v", err)
+	}
+	if len(smData.DnnConfigurations) != 2 || smData.DnnConfigurations["iot"].SessionAmbr.Downlink != ConvertToString(200) {
+		t.Errorf("expected a DNN configuration per DNN, got %+v", smData.DnnConfigurations)
+	}
+	var smPolicy models.SmPolicyData
+	if err = json.Unmarshal(configmodels.MapToByte(docs[SmPolicyDataColl]), &smPolicy); err != nil {
+		t.Fatalf("could not unmarshal smPolicyData: %v", err)
+	}
+	if dnnData := smPolicy.SmPolicySnssaiData[SnssaiModelsToHex(*snssai)].SmPolicyDnnData; len(dnnData) != 2 {
+		t.Errorf("expected SM policy data per DNN, got %+v", dnnData)
+	}
+	var smfSel models.SmfSelectionSubscriptionData
+	if err = json.Unmarshal(configmodels.MapToByte(docs[SmfSelDataColl]), &smfSel); err != nil {
+		t.Fatalf("could not unmarshal smfSelectionSubscriptionData: %v", err)
+	}
+	expectedDnnInfos := []models.DnnInfo{{Dnn: "internet"}, {Dnn: "iot"}}
+	if dnnInfos := smfSel.SubscribedSnssaiInfos[SnssaiModelsToHex(*snssai)].DnnInfos; !reflect.DeepEqual(dnnInfos, expectedDnnInfos) {
+		t.Errorf("expected DNN infos %+v, got %+v", expectedDnnInfos, dnnInfos)
+	}
+	var amData models.AccessAndMobilitySubscriptionData
+	if err = json.Unmarshal(configmodels.MapToByte(docs[AmDataColl]), &amData); err != nil {
+		t.Fatalf("could not unmarshal amData: %v", err)
+	}
+	if amData.SubscribedUeAmbr.Downlink != ConvertToString(1200) || amData.SubscribedUeAmbr.Uplink != ConvertToString(600) {
+		t.Errorf("expected the UE AMBR to add up the DNN AMBRs, got %+v", amData.SubscribedUeAmbr)
+	}
+}
@@ -270,7 +270,7 @@ func Test_sendPebbleNotification_on_when_handleNetworkSlicePost(t *testing.T) {
 	defer func() { execCommand = exec.Command }()
 
 	origSync := syncSubscribersOnSliceCreateOrUpdate
-	syncSubscribersOnSliceCreateOrUpdate = func(_, _ configmodels.Slice) (int, error) {
+	syncSubscribersOnSliceCreateOrUpdate = func(_, _ configmodels.Slice, _ *syncJobProgress) (int, error) {
 		return http.StatusOK, nil
 	}
 	defer func() { syncSubscribersOnSliceCreateOrUpdate = origSync }()
@@ -287,7 +287,7 @@ func Test_sendPebbleNotification_on_when_handleNetworkSlicePost(t *testing.T) {
 	}()
 	dbadapter.CommonDBClient = &NetworkSliceMockDBClient{}
 
-	statusCode, err := handleNetworkSlicePost(slice, prevSlice)
+	_, statusCode, err := handleNetworkSlicePost(slice, prevSlice)
 	if err != nil {
 		t.Errorf("could not handle network slice post: %+v statusCode: %d", err, statusCode)
 	}
@@ -302,7 +302,7 @@ func Test_sendPebbleNotification_off_when_handleNetworkSlicePost(t *testing.T) {
 	execCommandTimesCalled = 0
 
 	origSync := syncSubscribersOnSliceCreateOrUpdate
-	syncSubscribersOnSliceCreateOrUpdate = func(_, _ configmodels.Slice) (int, error) {
+	syncSubscribersOnSliceCreateOrUpdate = func(_, _ configmodels.Slice, _ *syncJobProgress) (int, error) {
 		return http.StatusOK, nil
 	}
 	defer func() { syncSubscribersOnSliceCreateOrUpdate = origSync }()
@@ -321,7 +321,7 @@ func Test_sendPebbleNotification_off_when_handleNetworkSlicePost(t *testing.T) {
 	}()
 	dbadapter.CommonDBClient = &NetworkSliceMockDBClient{}
 
-	statusCode, err := handleNetworkSlicePost(slice, prevSlice)
+	_, statusCode, err := handleNetworkSlicePost(slice, prevSlice)
 	if err != nil {
 		t.Errorf("handleNetworkSlicePost returned error: %+v statusCode: %d", err, statusCode)
 	}
@@ -341,23 +341,13 @@ func Test_handleNetworkSlicePost(t *testing.T) {
 	networkSlices[2].SiteInfo.GNodeBs = []configmodels.SliceSiteInfoGNodeBs{}
 	networkSlices[3].SiteDeviceGroup = []string{}
 
-	syncSubscribersOnSliceCreateOrUpdate = func(slice, prevSlice configmodels.Slice) (int, error) {
+	syncSubscribersOnSliceCreateOrUpdate = func(slice, prevSlice configmodels.Slice, progress *syncJobProgress) (int, error) {
 		return http.StatusOK, nil
 	}
 
 	for _, testSlice := range networkSlices {
 		ts := testSlice
 
-		for {
-			syncSliceStopMutex.Lock()
-			if !SyncSliceStop {
-				t.Log("wait wait wait")
-				syncSliceStopMutex.Unlock()
-				break
-			}
-			syncSliceStopMutex.Unlock()
-		}
-
 		t.Run(ts.SliceName, func(t *testing.T) {
 			originalDBClient := dbadapter.CommonDBClient
 			defer func() {
@@ -366,7 +356,7 @@ func Test_handleNetworkSlicePost(t *testing.T) {
 			mock := &NetworkSliceMockDBClient{slices: []configmodels.Slice{ts}}
 			dbadapter.CommonDBClient = mock
 
-			statusCode, err := handleNetworkSlicePost(ts, ts)
+			_, statusCode, err := handleNetworkSlicePost(ts, ts)
 			if err != nil {
 				t.Fatalf("handleNetworkSlicePost returned error: %+v status code: %d", err, statusCode)
 			}
@@ -405,7 +395,7 @@ func TestNetworkSlicePostHandler_NetworkSliceNameValidation(t *testing.T) {
 	router := gin.Default()
 	AddConfigV1Service(router)
 
-	syncSubscribersOnSliceCreateOrUpdate = func(slice, prevSlice configmodels.Slice) (int, error) {
+	syncSubscribersOnSliceCreateOrUpdate = func(slice, prevSlice configmodels.Slice, progress *syncJobProgress) (int, error) {
 		return http.StatusOK, nil
 	}
 
@@ -427,7 +417,7 @@ func TestNetworkSlicePostHandler_NetworkSliceNameValidation(t *testing.T) {
 		{
 			name:         "Network Slice valid name",
 			route:        "/config/v1/network-slice/slice1",
-			expectedCode: http.StatusOK,
+			expectedCode: http.StatusAccepted,
 		},
 	}
 
@@ -435,7 +425,7 @@ func TestNetworkSlicePostHandler_NetworkSliceNameValidation(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			originalDBClient := dbadapter.CommonDBClient
 			defer func() { dbadapter.CommonDBClient = originalDBClient }()
-			if tc.expectedCode == http.StatusOK {
+			if tc.expectedCode == http.StatusAccepted {
 				dbadapter.CommonDBClient = &NetworkSliceMockDBClient{}
 			}
 			jsonBody, err := json.Marshal(networkSlice("name"))
@@ -462,7 +452,7 @@ func TestNetworkSlicePostHandler_NetworkSliceGnbTacValidation(t *testing.T) {
 	router := gin.Default()
 	AddConfigV1Service(router)
 
-	syncSubscribersOnSliceCreateOrUpdate = func(slice, prevSlice configmodels.Slice) (int, error) {
+	syncSubscribersOnSliceCreateOrUpdate = func(slice, prevSlice configmodels.Slice, progress *syncJobProgress) (int, error) {
 		return http.StatusOK, nil
 	}
 
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
 
 	"github.com/omec-project/openapi/models"
@@ -139,19 +140,77 @@ func getDeletedImsisList(group, prevGroup *configmodels.DeviceGroups) (dimsis []
 	}
 
 	for _, pimsi := range prevGroup.Imsis {
-		var found bool
-		for _, imsi := range group.Imsis {
-			if pimsi == imsi {
-				found = true
-			}
-		}
-		if !found {
+		if !group.ContainsImsi(pimsi) {
 			dimsis = append(dimsis, pimsi)
 		}
 	}
 	return
 }
 
+// getDeletedImsiRanges returns the parts of the previous group's IMSI ranges
+// that are no longer covered by the ranges of the group. IMSIs inside the
+// returned ranges may still be enumerated in group.Imsis, so callers must
+// check group.ContainsImsi before removing a subscriber.
+func getDeletedImsiRanges(group, prevGroup *configmodels.DeviceGroups) []configmodels.DeviceGroupsImsiRange {
+	if prevGroup == nil {
+		return nil
+	}
+	var kept []configmodels.DeviceGroupsImsiRange
+	if group != nil {
+		kept = group.ImsiRanges
+	}
+	var deleted []configmodels.DeviceGroupsImsiRange
+	for _, prevRange := range prevGroup.ImsiRanges {
+		deleted = append(deleted, subtractImsiRanges(prevRange, kept)...)
+	}
+	return deleted
+}
+
+// subtractImsiRanges returns the pieces of r that are not covered by any of
+// the given ranges, as start/end ranges.
+func subtractImsiRanges(r configmodels.DeviceGroupsImsiRange, others []configmodels.DeviceGroupsImsiRange) []configmodels.DeviceGroupsImsiRange {
+	start, end, err := r.Bounds()
+	if err != nil {
+		logger.ConfigLog.Warnf("ignoring invalid IMSI range %+v: %+v", r, err)
+		return nil
+	}
+	width := len(start)
+	first, _ := strconv.ParseUint(start, 10, 64)
+	last, _ := strconv.ParseUint(end, 10, 64)
+	type span struct{ first, last uint64 }
+	remaining := []span{{first, last}}
+	for _, other := range others {
+		oStart, oEnd, err := other.Bounds()
+		if err != nil || len(oStart) != width {
+			continue
+		}
+		oFirst, _ := strconv.ParseUint(oStart, 10, 64)
+		oLast, _ := strconv.ParseUint(oEnd, 10, 64)
+		var next []span
+		for _, s := range remaining {
+			if oLast < s.first || oFirst > s.last {
+				next = append(next, s)
+				continue
+			}
+			if oFirst > s.first {
+				next = append(next, span{s.first, oFirst - 1})
+			}
+			if oLast < s.last {
+				next = append(next, span{oLast + 1, s.last})
+			}
+		}
+		remaining = next
+	}
+	pieces := make([]configmodels.DeviceGroupsImsiRange, 0, len(remaining))
+	for _, s := range remaining {
+		pieces = append(pieces, configmodels.DeviceGroupsImsiRange{
+			Start: configmodels.FormatImsi(s.first, width),
+			End:   configmodels.FormatImsi(s.last, width),
+		})
+	}
+	return pieces
+}
+
 func removeSubscriberEntriesRelatedToDeviceGroups(mcc, mnc, imsi string) error {
 	filterImsiOnly := bson.M{"ueId": "imsi-" + imsi}
 	filter := bson.M{"ueId": "imsi-" + imsi, "servingPlmnId": mcc + mnc}
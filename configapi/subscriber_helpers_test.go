@@ -350,3 +350,43 @@ func Test_handleSubscriberGet(t *testing.T) {
 		t.Errorf("expected subscriber %v, got %v", &subscriber, subscriberResult)
 	}
 }
+
+func Test_getDeletedImsisList(t *testing.T) {
+	prevGroup := &configmodels.DeviceGroups{Imsis: []string{"001010000000001", "001010000000002", "001010000000500"}}
+	group := &configmodels.DeviceGroups{
+		Imsis:      []string{"001010000000001"},
+		ImsiRanges: []configmodels.DeviceGroupsImsiRange{{Start: "001010000000100", End: "001010000000999"}},
+	}
+
+	got := getDeletedImsisList(group, prevGroup)
+	if !reflect.DeepEqual(got, []string{"001010000000002"}) {
+		t.Errorf("expected only the IMSI outside the new list and ranges, got %v", got)
+	}
+	if got := getDeletedImsisList(nil, prevGroup); len(got) != 3 {
+		t.Errorf("expected all IMSIs when the group is deleted, got %v", got)
+	}
+}
+
+func Test_getDeletedImsiRanges(t *testing.T) {
+	prevGroup := &configmodels.DeviceGroups{
+		ImsiRanges: []configmodels.DeviceGroupsImsiRange{{Prefix: "001010000000", Count: 1000}},
+	}
+	group := &configmodels.DeviceGroups{
+		ImsiRanges: []configmodels.DeviceGroupsImsiRange{
+			{Start: "001010000000100", End: "001010000000199"},
+			{Start: "001010000000500", End: "001010000001500"},
+		},
+	}
+
+	got := getDeletedImsiRanges(group, prevGroup)
+	expected := []configmodels.DeviceGroupsImsiRange{
+		{Start: "001010000000000", End: "001010000000099"},
+		{Start: "001010000000200", End: "001010000000499"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+	if got := getDeletedImsiRanges(nil, prevGroup); len(got) != 1 || got[0].Start != "001010000000000" || got[0].End != "001010000000999" {
+		t.Errorf("expected the whole range when the group is deleted, got %+v", got)
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/omec-project/openapi/models"
+	"github.com/omec-project/webconsole/configmodels"
+)
+
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+// subsOverrideDataFromAuthSubscription builds the editable view of a stored
+// authentication subscription, which is the document PATCH requests apply to.
+func subsOverrideDataFromAuthSubscription(authSubsData *models.AuthenticationSubscription) configmodels.SubsOverrideData {
+	subsOverrideData := configmodels.SubsOverrideData{
+		SequenceNumber: authSubsData.SequenceNumber,
+	}
+	if authSubsData.Opc != nil {
+		subsOverrideData.OPc = authSubsData.Opc.OpcValue
+	}
+	encryptionAlgorithm := int32(0)
+	if authSubsData.PermanentKey != nil {
+		subsOverrideData.Key = authSubsData.PermanentKey.PermanentKeyValue
+		encryptionAlgorithm = authSubsData.PermanentKey.EncryptionAlgorithm
+		if authSubsData.PermanentKey.EncryptionKey != "" || authSubsData.K4_SNO != 0 {
+			k4Sno := authSubsData.K4_SNO
+			subsOverrideData.K4Sno = &k4Sno
+		}
+	}
+	subsOverrideData.EncryptionAlgorithm = &encryptionAlgorithm
+	return subsOverrideData
+}
+
+// applySubscriberPatch applies a merge patch (RFC 7396) or a JSON patch
+// (RFC 6902) document to the given subscriber data and returns the result.
+// A failed JSON patch test operation is reported as jsonpatch.ErrTestFailed.
+func applySubscriberPatch(contentType string, current configmodels.SubsOverrideData, patch []byte) (configmodels.SubsOverrideData, error) {
+	var patched configmodels.SubsOverrideData
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return patched, err
+	}
+
+	switch contentType {
+	case jsonPatchContentType:
+		var operations jsonpatch.Patch
+		if operations, err = jsonpatch.DecodePatch(patch); err != nil {
+			return patched, fmt.Errorf("invalid JSON patch document: %w", err)
+		}
+		doc, err = operations.Apply(doc)
+	default:
+		doc, err = jsonpatch.MergePatch(doc, patch)
+	}
+	if err != nil {
+		return patched, fmt.Errorf("failed to apply patch: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&patched); err != nil {
+		return patched, fmt.Errorf("patched subscriber is not valid: %w", err)
+	}
+	return patched, nil
+}
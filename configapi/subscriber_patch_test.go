@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/openapi/models"
+	"github.com/omec-project/webconsole/backend/factory"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type PatchSubscriberMockDBClient struct {
+	dbadapter.DBInterface
+	authSubscription *models.AuthenticationSubscription
+	k4Keys           map[byte]string
+	k4Filters        []bson.M
+	putData          []map[string]any
+}
+
+func (db *PatchSubscriberMockDBClient) RestfulAPIGetOne(collName string, filter bson.M) (map[string]any, error) {
+	switch collName {
+	case AuthSubsDataColl:
+		if db.authSubscription == nil {
+			return nil, nil
+		}
+		subscriber := configmodels.ToBsonM(db.authSubscription)
+		subscriber["ueId"] = filter["ueId"]
+		return subscriber, nil
+	case AmDataColl:
+		if db.authSubscription == nil {
+			return nil, nil
+		}
+		return bson.M{"ueId": filter["ueId"]}, nil
+	case K4KeysColl:
+		db.k4Filters = append(db.k4Filters, filter)
+		sno := byte(filter["k4_sno"].(int))
+		return configmodels.ToBsonM(configmodels.K4{K4: db.k4Keys[sno], K4_SNO: sno}), nil
+	}
+	return nil, nil
+}
+
+func (db *PatchSubscriberMockDBClient) RestfulAPIPutOne(collName string, filter bson.M, putData map[string]any) (bool, error) {
+	db.putData = append(db.putData, map[string]any{
+		"coll":   collName,
+		"filter": filter,
+		"data":   putData,
+	})
+	return true, nil
+}
+
+func newPatchTestAuthSubscription() *models.AuthenticationSubscription {
+	return &models.AuthenticationSubscription{
+		AuthenticationManagementField: "8000",
+		AuthenticationMethod:          "5G_AKA",
+		Opc: &models.Opc{
+			OpcValue: "8e27b6af0e692e750f32667a3b14605d",
+		},
+		PermanentKey: &models.PermanentKey{
+			PermanentKeyValue: "8baf473f2f8fd09487cccbd7097c6862",
+		},
+		SequenceNumber: "16f3b3f70fc2",
+	}
+}
+
+func TestSubscriberPatch(t *testing.T) {
+	tests := []struct {
+		name              string
+		contentType       string
+		body              string
+		subscriberExists  bool
+		expectedCode      int
+		expectedBody      string
+		expectedAuthData  map[string]any
+		expectedK4Lookups int
+	}{
+		{
+			name:             "Merge patch updates sequence number only",
+			contentType:      mergePatchContentType,
+			body:             `{"sequenceNumber": "000000000001"}`,
+			subscriberExists: true,
+			expectedCode:     http.StatusNoContent,
+			expectedAuthData: map[string]any{
+				"sequenceNumber":    "000000000001",
+				"permanentKeyValue": "8baf473f2f8fd09487cccbd7097c6862",
+				"opcValue":          "8e27b6af0e692e750f32667a3b14605d",
+				"encryptionKey":     "",
+			},
+		},
+		{
+			name:             "Merge patch setting k4_sno reassigns K4 key",
+			contentType:      mergePatchContentType,
+			body:             `{"k4_sno": 2, "encryptionAlgorithm": 1}`,
+			subscriberExists: true,
+			expectedCode:     http.StatusNoContent,
+			expectedAuthData: map[string]any{
+				"sequenceNumber":    "16f3b3f70fc2",
+				"permanentKeyValue": "8baf473f2f8fd09487cccbd7097c6862",
+				"opcValue":          "8e27b6af0e692e750f32667a3b14605d",
+				"encryptionKey":     "k4-two",
+			},
+			expectedK4Lookups: 1,
+		},
+		{
+			name:             "JSON patch replaces key",
+			contentType:      jsonPatchContentType,
+			body:             `[{"op": "test", "path": "/key", "value": "8baf473f2f8fd09487cccbd7097c6862"}, {"op": "replace", "path": "/key", "value": "00112233445566778899aabbccddeeff"}]`,
+			subscriberExists: true,
+			expectedCode:     http.StatusNoContent,
+			expectedAuthData: map[string]any{
+				"sequenceNumber":    "16f3b3f70fc2",
+				"permanentKeyValue": "00112233445566778899aabbccddeeff",
+				"opcValue":          "8e27b6af0e692e750f32667a3b14605d",
+				"encryptionKey":     "",
+			},
+		},
+		{
+			name:             "JSON patch failing test operation is a conflict",
+			contentType:      jsonPatchContentType,
+			body:             `[{"op": "test", "path": "/key", "value": "wrong"}, {"op": "replace", "path": "/key", "value": "00112233445566778899aabbccddeeff"}]`,
+			subscriberExists: true,
+			expectedCode:     http.StatusConflict,
+			expectedBody:     "test failed",
+		},
+		{
+			name:             "JSON patch replacing a missing path is rejected",
+			contentType:      jsonPatchContentType,
+			body:             `[{"op": "replace", "path": "/missing", "value": "1"}]`,
+			subscriberExists: true,
+			expectedCode:     http.StatusBadRequest,
+			expectedBody:     "failed to apply patch",
+		},
+		{
+			name:             "Invalid JSON patch document is rejected",
+			contentType:      jsonPatchContentType,
+			body:             `{"op": "replace"}`,
+			subscriberExists: true,
+			expectedCode:     http.StatusBadRequest,
+			expectedBody:     "invalid JSON patch document",
+		},
+		{
+			name:             "Removing a required field is rejected",
+			contentType:      mergePatchContentType,
+			body:             `{"opc": null}`,
+			subscriberExists: true,
+			expectedCode:     http.StatusBadRequest,
+			expectedBody:     "Missing required authentication data",
+		},
+		{
+			name:             "Unknown field is rejected",
+			contentType:      mergePatchContentType,
+			body:             `{"unknown": "value"}`,
+			subscriberExists: true,
+			expectedCode:     http.StatusBadRequest,
+			expectedBody:     "patched subscriber is not valid",
+		},
+		{
+			name:             "Invalid encryption algorithm is rejected",
+			contentType:      mergePatchContentType,
+			body:             `{"encryptionAlgorithm": 9}`,
+			subscriberExists: true,
+			expectedCode:     http.StatusBadRequest,
+			expectedBody:     "encryption Algorithm is not valid",
+		},
+		{
+			name:             "Unsupported content type is rejected",
+			contentType:      "text/plain",
+			body:             `sequenceNumber=1`,
+			subscriberExists: true,
+			expectedCode:     http.StatusUnsupportedMediaType,
+			expectedBody:     "unsupported Content-Type",
+		},
+		{
+			name:             "Missing subscriber returns not found",
+			contentType:      mergePatchContentType,
+			body:             `{"sequenceNumber": "000000000001"}`,
+			subscriberExists: false,
+			expectedCode:     http.StatusNotFound,
+			expectedBody:     "does not exist",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+			AddApiService(router)
+
+			mockDB := &PatchSubscriberMockDBClient{k4Keys: map[byte]string{2: "k4-two"}}
+			if tc.subscriberExists {
+				mockDB.authSubscription = newPatchTestAuthSubscription()
+			}
+			origDBClient := dbadapter.CommonDBClient
+			origAuthDBClient := dbadapter.AuthDBClient
+			originalConfig := factory.WebUIConfig
+			defer func() {
+				dbadapter.CommonDBClient = origDBClient
+				dbadapter.AuthDBClient = origAuthDBClient
+				factory.WebUIConfig = originalConfig
+			}()
+			factory.WebUIConfig = &factory.Config{Configuration: &factory.Configuration{SSM: &factory.SSM{}}}
+			dbadapter.CommonDBClient = mockDB
+			dbadapter.AuthDBClient = mockDB
+
+			req, err := http.NewRequest(http.MethodPatch, "/api/subscriber/imsi-208930100007487", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", tc.contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedCode {
+				t.Fatalf("expected `%v`, got `%v` (body: %s)", tc.expectedCode, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tc.expectedBody) {
+				t.Errorf("expected `%v`, got `%v`", tc.expectedBody, w.Body.String())
+			}
+			if len(mockDB.k4Filters) != tc.expectedK4Lookups {
+				t.Errorf("expected %d K4 lookups, got %d", tc.expectedK4Lookups, len(mockDB.k4Filters))
+			}
+			if tc.expectedAuthData == nil {
+				if len(mockDB.putData) != 0 {
+					t.Errorf("expected no DB writes, got %+v", mockDB.putData)
+				}
+				return
+			}
+			if len(mockDB.putData) != 2 || mockDB.putData[0]["coll"] != AuthSubsDataColl || mockDB.putData[1]["coll"] != AmDataColl {
+				t.Fatalf("expected writes to auth and amData collections, got %+v", mockDB.putData)
+			}
+			authData := mockDB.putData[0]["data"].(map[string]any)
+			permanentKey := authData["permanentKey"].(map[string]any)
+			opc := authData["opc"].(map[string]any)
+			got := map[string]any{
+				"sequenceNumber":    authData["sequenceNumber"],
+				"permanentKeyValue": permanentKey["permanentKeyValue"],
+				"opcValue":          opc["opcValue"],
+				"encryptionKey":     permanentKey["encryptionKey"],
+			}
+			for key, expected := range tc.expectedAuthData {
+				if got[key] != expected {
+					t.Errorf("expected %s to be `%v`, got `%v`", key, expected, got[key])
+				}
+			}
+		})
+	}
+}
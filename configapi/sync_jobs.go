@@ -0,0 +1,339 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/omec-project/webconsole/backend/logger"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// syncJobRecord is the persisted form of a sync job: its status plus what is
+// needed to run, or resume, the sync. The network slice or device group is
+// the target of the job and is read when the job runs, of the previous
+// configuration only what the sync has to remove is kept.
+type syncJobRecord struct {
+	configmodels.SyncJob
+
+	// RemovedDeviceGroups are the device groups a network slice no longer
+	// has, their subscribers are cleaned up in the PLMN of the previous slice
+	RemovedDeviceGroups []string `json:"removed-device-groups,omitempty"`
+
+	PrevPlmn *configmodels.SliceSiteInfoPlmn `json:"prev-plmn,omitempty"`
+
+	AddedImsis []string `json:"added-imsis,omitempty"`
+
+	RemovedImsis []string `json:"removed-imsis,omitempty"`
+
+	RemovedImsiRanges []configmodels.DeviceGroupsImsiRange `json:"removed-imsi-ranges,omitempty"`
+
+	// db is the client the job is persisted with, fixed when it is queued
+	db       dbadapter.DBInterface
+	progress *syncJobProgress
+}
+
+// syncJobProgress collects the progress reported by a running sync. Calls on a
+// nil progress are ignored.
+type syncJobProgress struct {
+	mu          sync.Mutex
+	processed   int64
+	failedImsis []string
+}
+
+func (p *syncJobProgress) done(n int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.processed += int64(n)
+}
+
+func (p *syncJobProgress) fail(imsis ...string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failedImsis = append(p.failedImsis, imsis...)
+}
+
+func (p *syncJobProgress) snapshot() (int64, []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.processed, slices.Clone(p.failedImsis)
+}
+
+// syncJobQueue runs the sync jobs one at a time, in the order they were
+// queued, so that a write arriving while a sync is running waits for it
+// instead of being rejected.
+type syncJobQueue struct {
+	mu      sync.Mutex
+	pending []*syncJobRecord
+	active  *syncJobRecord
+	wake    chan struct{}
+	start   sync.Once
+}
+
+var syncJobs = &syncJobQueue{wake: make(chan struct{}, 1)}
+
+func enqueueSliceSyncJob(slice, prevSlice configmodels.Slice) (*configmodels.SyncJob, error) {
+	rec := newSyncJobRecord(configmodels.SyncJobKindNetworkSlice, slice.SliceName)
+	rec.RemovedDeviceGroups = getDeletedDeviceGroupsList(slice, prevSlice)
+	if len(rec.RemovedDeviceGroups) > 0 {
+		rec.PrevPlmn = &prevSlice.SiteInfo.Plmn
+	}
+	return syncJobs.enqueue(rec)
+}
+
+func enqueueDeviceGroupSyncJob(devGroup, prevDevGroup *configmodels.DeviceGroups) (*configmodels.SyncJob, error) {
+	rec := newSyncJobRecord(configmodels.SyncJobKindDeviceGroup, devGroup.DeviceGroupName)
+	rec.RemovedImsis = getDeletedImsisList(devGroup, prevDevGroup)
+	rec.RemovedImsiRanges = getDeletedImsiRanges(devGroup, prevDevGroup)
+	return syncJobs.enqueue(rec)
+}
+
+func enqueueDeviceGroupImsisSyncJob(groupName string, added, removed []string) (*configmodels.SyncJob, error) {
+	rec := newSyncJobRecord(configmodels.SyncJobKindDeviceGroupImsis, groupName)
+	rec.AddedImsis = added
+	rec.RemovedImsis = removed
+	return syncJobs.enqueue(rec)
+}
+
+func newSyncJobRecord(kind, target string) *syncJobRecord {
+	return &syncJobRecord{
+		SyncJob: configmodels.SyncJob{
+			JobId:     uuid.New().String(),
+			Kind:      kind,
+			Target:    target,
+			Status:    configmodels.SyncJobStatusQueued,
+			CreatedAt: time.Now().UTC(),
+		},
+		db: dbadapter.CommonDBClient,
+	}
+}
+
+func (q *syncJobQueue) enqueue(rec *syncJobRecord) (*configmodels.SyncJob, error) {
+	if err := q.persist(rec); err != nil {
+		return nil, err
+	}
+	q.mu.Lock()
+	q.pending = append(q.pending, rec)
+	job := rec.SyncJob
+	q.mu.Unlock()
+	logger.AppLog.Infof("queued %s sync job %s for %s", rec.Kind, rec.JobId, rec.Target)
+
+	q.start.Do(func() { go q.run() })
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return &job, nil
+}
+
+func (q *syncJobQueue) persist(rec *syncJobRecord) error {
+	q.mu.Lock()
+	doc := configmodels.ToBsonM(rec)
+	if rec.FinishedAt != nil {
+		// stored as a date for the retention index to expire the job
+		doc["finished-at"] = *rec.FinishedAt
+	}
+	db := rec.db
+	q.mu.Unlock()
+	if db == nil {
+		return fmt.Errorf("no database to persist sync job %s", rec.JobId)
+	}
+	if _, err := db.RestfulAPIPost(configmodels.SyncJobsColl, bson.M{"job-id": rec.JobId}, doc); err != nil {
+		logger.AppLog.Errorf("failed to persist sync job %s: %+v", rec.JobId, err)
+		return err
+	}
+	return nil
+}
+
+func (q *syncJobQueue) run() {
+	for {
+		q.execute(q.next())
+	}
+}
+
+func (q *syncJobQueue) next() *syncJobRecord {
+	for {
+		q.mu.Lock()
+		if len(q.pending) > 0 {
+			rec := q.pending[0]
+			q.pending = q.pending[1:]
+			q.active = rec
+			q.mu.Unlock()
+			return rec
+		}
+		q.mu.Unlock()
+		<-q.wake
+	}
+}
+
+func (q *syncJobQueue) execute(rec *syncJobRecord) {
+	startedAt := time.Now().UTC()
+	progress := &syncJobProgress{}
+	q.mu.Lock()
+	rec.Status = configmodels.SyncJobStatusRunning
+	rec.StartedAt = &startedAt
+	rec.FinishedAt = nil
+	rec.Error = ""
+	rec.Attempts++
+	rec.progress = progress
+	q.mu.Unlock()
+	_ = q.persist(rec)
+	logger.AppLog.Infof("running %s sync job %s for %s", rec.Kind, rec.JobId, rec.Target)
+
+	err := runSyncJob(rec, progress)
+
+	finishedAt := time.Now().UTC()
+	processed, failedImsis := progress.snapshot()
+	q.mu.Lock()
+	rec.Processed = processed
+	rec.FailedImsis = failedImsis
+	rec.FinishedAt = &finishedAt
+	rec.Duration = finishedAt.Sub(startedAt).String()
+	rec.Status = configmodels.SyncJobStatusSucceeded
+	if err != nil {
+		rec.Status = configmodels.SyncJobStatusFailed
+		rec.Error = err.Error()
+	} else if len(failedImsis) > 0 {
+		rec.Status = configmodels.SyncJobStatusFailed
+		rec.Error = fmt.Sprintf("sync failed for %d IMSIs", len(failedImsis))
+	}
+	rec.progress = nil
+	q.active = nil
+	q.mu.Unlock()
+	_ = q.persist(rec)
+	logger.AppLog.Infof("%s sync job %s for %s %s in %s", rec.Kind, rec.JobId, rec.Target, rec.Status, rec.Duration)
+}
+
+func runSyncJob(rec *syncJobRecord, progress *syncJobProgress) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("sync job panicked: %v", r)
+		}
+	}()
+	switch rec.Kind {
+	case configmodels.SyncJobKindNetworkSlice:
+		slice := getSliceByName(rec.Target)
+		if slice == nil {
+			return fmt.Errorf("could not read network slice %s", rec.Target)
+		}
+		if slice.SliceName == "" {
+			logger.AppLog.Infof("network slice %s no longer exists — skipping sync job %s", rec.Target, rec.JobId)
+			return nil
+		}
+		// the previous slice is rebuilt from what the job kept of it
+		prevSlice := configmodels.Slice{SliceName: rec.Target, SiteDeviceGroup: rec.RemovedDeviceGroups}
+		if rec.PrevPlmn != nil {
+			prevSlice.SiteInfo.Plmn = *rec.PrevPlmn
+		}
+		_, err = syncSubscribersOnSliceCreateOrUpdate(*slice, prevSlice, progress)
+	case configmodels.SyncJobKindDeviceGroup:
+		devGroup := getDeviceGroupByName(rec.Target)
+		if devGroup == nil {
+			return fmt.Errorf("could not read device group %s", rec.Target)
+		}
+		if devGroup.DeviceGroupName == "" {
+			logger.AppLog.Infof("device group %s no longer exists — skipping sync job %s", rec.Target, rec.JobId)
+			return nil
+		}
+		var prevDevGroup *configmodels.DeviceGroups
+		if len(rec.RemovedImsis) > 0 || len(rec.RemovedImsiRanges) > 0 {
+			prevDevGroup = &configmodels.DeviceGroups{
+				DeviceGroupName: rec.Target,
+				Imsis:           rec.RemovedImsis,
+				ImsiRanges:      rec.RemovedImsiRanges,
+			}
+		}
+		_, err = syncDeviceGroupSubscriber(devGroup, prevDevGroup, progress)
+	case configmodels.SyncJobKindDeviceGroupImsis:
+		err = syncDeviceGroupImsis(rec.Target, rec.AddedImsis, rec.RemovedImsis, progress)
+	default:
+		err = fmt.Errorf("unknown sync job kind %s", rec.Kind)
+	}
+	return err
+}
+
+// get returns the job if it is queued or running in this process.
+func (q *syncJobQueue) get(jobId string) *configmodels.SyncJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.active != nil && q.active.JobId == jobId {
+		job := q.active.SyncJob
+		if q.active.progress != nil {
+			job.Processed, job.FailedImsis = q.active.progress.snapshot()
+		}
+		return &job
+	}
+	for _, rec := range q.pending {
+		if rec.JobId == jobId {
+			job := rec.SyncJob
+			return &job
+		}
+	}
+	return nil
+}
+
+func getSyncJob(jobId string) (*configmodels.SyncJob, error) {
+	if job := syncJobs.get(jobId); job != nil {
+		return job, nil
+	}
+	rawJob, err := dbadapter.CommonDBClient.RestfulAPIGetOne(configmodels.SyncJobsColl, bson.M{"job-id": jobId})
+	if err != nil {
+		return nil, err
+	}
+	if len(rawJob) == 0 {
+		return nil, nil
+	}
+	var rec syncJobRecord
+	if err = json.Unmarshal(configmodels.MapToByte(rawJob), &rec); err != nil {
+		return nil, err
+	}
+	return &rec.SyncJob, nil
+}
+
+// ResumeSyncJobs queues again, in their original order, the sync jobs that
+// were queued or running when the webconsole stopped.
+func ResumeSyncJobs() error {
+	if dbadapter.CommonDBClient == nil {
+		return fmt.Errorf("no database to resume sync jobs from")
+	}
+	filter := bson.M{"status": bson.M{"$in": []string{configmodels.SyncJobStatusQueued, configmodels.SyncJobStatusRunning}}}
+	rawJobs, err := dbadapter.CommonDBClient.RestfulAPIGetMany(configmodels.SyncJobsColl, filter)
+	if err != nil {
+		return fmt.Errorf("failed to fetch unfinished sync jobs: %w", err)
+	}
+	records := make([]*syncJobRecord, 0, len(rawJobs))
+	for _, rawJob := range rawJobs {
+		var rec syncJobRecord
+		if err = json.Unmarshal(configmodels.MapToByte(rawJob), &rec); err != nil {
+			logger.AppLog.Errorf("could not unmarshal sync job %+v: %+v", rawJob, err)
+			continue
+		}
+		rec.Status = configmodels.SyncJobStatusQueued
+		rec.db = dbadapter.CommonDBClient
+		records = append(records, &rec)
+	}
+	slices.SortStableFunc(records, func(a, b *syncJobRecord) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	for _, rec := range records {
+		logger.AppLog.Infof("resuming %s sync job %s for %s", rec.Kind, rec.JobId, rec.Target)
+		if _, err = syncJobs.enqueue(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/webconsole/configmodels"
+	"github.com/omec-project/webconsole/dbadapter"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type syncJobsMock struct {
+	mu        sync.Mutex
+	jobs      map[string]map[string]any
+	devGroups map[string]*configmodels.DeviceGroups
+	mockDB    *dbadapter.MockDBClient
+}
+
+func newSyncJobsMock(stored ...map[string]any) *syncJobsMock {
+	m := &syncJobsMock{
+		jobs:      map[string]map[string]any{},
+		devGroups: map[string]*configmodels.DeviceGroups{"group1": {DeviceGroupName: "group1"}},
+	}
+	for _, job := range stored {
+		m.jobs[job["job-id"].(string)] = job
+	}
+	m.mockDB = &dbadapter.MockDBClient{
+		PostFn: func(collName string, filter bson.M, postData map[string]any) (bool, error) {
+			if collName == configmodels.SyncJobsColl {
+				m.mu.Lock()
+				m.jobs[filter["job-id"].(string)] = postData
+				m.mu.Unlock()
+			}
+			return true, nil
+		},
+		GetOneFn: func(collName string, filter bson.M) (map[string]any, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			switch collName {
+			case configmodels.SyncJobsColl:
+				return m.jobs[filter["job-id"].(string)], nil
+			case devGroupDataColl:
+				if devGroup, ok := m.devGroups[filter["group-name"].(string)]; ok {
+					return configmodels.ToBsonM(devGroup), nil
+				}
+			}
+			return nil, nil
+		},
+		GetManyFn: func(collName string, filter bson.M) ([]map[string]any, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			var jobs []map[string]any
+			if collName == configmodels.SyncJobsColl {
+				for _, job := range m.jobs {
+					jobs = append(jobs, job)
+				}
+			}
+			return jobs, nil
+		},
+	}
+	return m
+}
+
+func useSyncJobsMock(t *testing.T, m *syncJobsMock) {
+	t.Helper()
+	origDBClient := dbadapter.CommonDBClient
+	origSync := syncDeviceGroupSubscriber
+	t.Cleanup(func() {
+		dbadapter.CommonDBClient = origDBClient
+		syncDeviceGroupSubscriber = origSync
+	})
+	dbadapter.CommonDBClient = m.mockDB
+}
+
+func waitForSyncJob(t *testing.T, jobId string) *configmodels.SyncJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := getSyncJob(jobId)
+		if err != nil {
+			t.Fatalf("could not get sync job %s: %+v", jobId, err)
+		}
+		if job != nil && job.Finished() {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("sync job %s did not finish", jobId)
+	return nil
+}
+
+func TestSyncJob_Succeeds(t *testing.T) {
+	m := newSyncJobsMock()
+	useSyncJobsMock(t, m)
+	syncDeviceGroupSubscriber = func(devGroup, prevDevGroup *configmodels.DeviceGroups, progress *syncJobProgress) (int, error) {
+		progress.done(2)
+		return http.StatusOK, nil
+	}
+
+	job, err := enqueueDeviceGroupSyncJob(&configmodels.DeviceGroups{DeviceGroupName: "group1"}, nil)
+	if err != nil {
+		t.Fatalf("could not enqueue sync job: %+v", err)
+	}
+	if job.Status != configmodels.SyncJobStatusQueued {
+		t.Errorf("expected status %s, got %s", configmodels.SyncJobStatusQueued, job.Status)
+	}
+
+	finished := waitForSyncJob(t, job.JobId)
+	if finished.Status != configmodels.SyncJobStatusSucceeded {
+		t.Errorf("expected status %s, got %s (%s)", configmodels.SyncJobStatusSucceeded, finished.Status, finished.Error)
+	}
+	if finished.Processed != 2 || finished.Attempts != 1 || finished.Duration == "" {
+		t.Errorf("unexpected finished job: %+v", finished)
+	}
+}
+
+func TestSyncJob_RecordsFailedImsis(t *testing.T) {
+	m := newSyncJobsMock()
+	useSyncJobsMock(t, m)
+	syncDeviceGroupSubscriber = func(devGroup, prevDevGroup *configmodels.DeviceGroups, progress *syncJobProgress) (int, error) {
+		progress.done(1)
+		progress.fail("001010000000002", "001010000000003")
+		return http.StatusInternalServerError, errors.New("syncDeviceGroupSubscriber failed, please check logs")
+	}
+
+	job, err := enqueueDeviceGroupSyncJob(&configmodels.DeviceGroups{DeviceGroupName: "group1"}, nil)
+	if err != nil {
+		t.Fatalf("could not enqueue sync job: %+v", err)
+	}
+
+	finished := waitForSyncJob(t, job.JobId)
+	if finished.Status != configmodels.SyncJobStatusFailed {
+		t.Errorf("expected status %s, got %s", configmodels.SyncJobStatusFailed, finished.Status)
+	}
+	if !slices.Equal(finished.FailedImsis, []string{"001010000000002", "001010000000003"}) {
+		t.Errorf("unexpected failed IMSIs: %v", finished.FailedImsis)
+	}
+	if finished.Processed != 1 || finished.Error == "" {
+		t.Errorf("unexpected finished job: %+v", finished)
+	}
+}
+
+func TestGetSyncJob(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	stored := configmodels.ToBsonM(configmodels.SyncJob{
+		JobId:     "job1",
+		Kind:      configmodels.SyncJobKindNetworkSlice,
+		Target:    "slice1",
+		Status:    configmodels.SyncJobStatusSucceeded,
+		Processed: 10,
+		CreatedAt: createdAt,
+	})
+	m := newSyncJobsMock(stored)
+	useSyncJobsMock(t, m)
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	AddConfigV1Service(router)
+
+	testCases := []struct {
+		name         string
+		jobId        string
+		expectedCode int
+	}{
+		{"job exists", "job1", http.StatusOK},
+		{"job not found", "job2", http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/config/v1/jobs/"+tc.jobId, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tc.expectedCode {
+				t.Fatalf("expected %d, got %d (body: %s)", tc.expectedCode, w.Code, w.Body.String())
+			}
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+			var job configmodels.SyncJob
+			if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+				t.Fatalf("could not unmarshal response: %+v", err)
+			}
+			if job.JobId != "job1" || job.Processed != 10 || !job.CreatedAt.Equal(createdAt) {
+				t.Errorf("unexpected job: %+v", job)
+			}
+		})
+	}
+}
+
+func TestResumeSyncJobs(t *testing.T) {
+	interrupted := configmodels.ToBsonM(syncJobRecord{
+		SyncJob: configmodels.SyncJob{
+			JobId:     "interrupted-job",
+			Kind:      configmodels.SyncJobKindDeviceGroup,
+			Target:    "group1",
+			Status:    configmodels.SyncJobStatusRunning,
+			Attempts:  1,
+			CreatedAt: time.Now().UTC(),
+		},
+	})
+	m := newSyncJobsMock(interrupted)
+	useSyncJobsMock(t, m)
+	var resumed []string
+	var mu sync.Mutex
+	syncDeviceGroupSubscriber = func(devGroup, prevDevGroup *configmodels.DeviceGroups, progress *syncJobProgress) (int, error) {
+		mu.Lock()
+		resumed = append(resumed, devGroup.DeviceGroupName)
+		mu.Unlock()
+		return http.StatusOK, nil
+	}
+
+	if err := ResumeSyncJobs(); err != nil {
+		t.Fatalf("could not resume sync jobs: %+v", err)
+	}
+
+	finished := waitForSyncJob(t, "interrupted-job")
+	if finished.Status != configmodels.SyncJobStatusSucceeded || finished.Attempts != 2 {
+		t.Errorf("unexpected resumed job: %+v", finished)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(resumed, []string{"group1"}) {
+		t.Errorf("expected device group group1 to be synced, got %v", resumed)
+	}
+}
+
+func TestSyncJob_StoresOnlyTheRemovedImsis(t *testing.T) {
+	m := newSyncJobsMock()
+	useSyncJobsMock(t, m)
+	current := &configmodels.DeviceGroups{
+		DeviceGroupName: "group1",
+		Imsis:           []string{"001010000000001", "001010000000002"},
+	}
+	m.devGroups["group1"] = current
+	prev := &configmodels.DeviceGroups{
+		DeviceGroupName: "group1",
+		Imsis:           []string{"001010000000001", "001010000000003"},
+		ImsiRanges:      []configmodels.DeviceGroupsImsiRange{{Start: "001010000000100", End: "001010000000199"}},
+	}
+	var synced, syncedPrev *configmodels.DeviceGroups
+	syncDeviceGroupSubscriber = func(devGroup, prevDevGroup *configmodels.DeviceGroups, progress *syncJobProgress) (int, error) {
+		synced, syncedPrev = devGroup, prevDevGroup
+		return http.StatusOK, nil
+	}
+
+	job, err := enqueueDeviceGroupSyncJob(current, prev)
+	if err != nil {
+		t.Fatalf("could not enqueue sync job: %+v", err)
+	}
+	waitForSyncJob(t, job.JobId)
+
+	m.mu.Lock()
+	stored := m.jobs[job.JobId]
+	m.mu.Unlock()
+	for _, field := range []string{"device-group", "prev-device-group", "imsis"} {
+		if _, ok := stored[field]; ok {
+			t.Errorf("expected the job not to store %s, got %+v", field, stored)
+		}
+	}
+	if _, ok := stored["finished-at"].(time.Time); !ok {
+		t.Errorf("expected finished-at to be stored as a date, got %T", stored["finished-at"])
+	}
+	if synced == nil || !slices.Equal(synced.Imsis, current.Imsis) {
+		t.Errorf("expected the device group to be read when the job runs, got %+v", synced)
+	}
+	expectedPrev := &configmodels.DeviceGroups{
+		DeviceGroupName: "group1",
+		Imsis:           []string{"001010000000003"},
+		ImsiRanges:      prev.ImsiRanges,
+	}
+	if !reflect.DeepEqual(syncedPrev, expectedPrev) {
+		t.Errorf("expected previous device group %+v, got %+v", expectedPrev, syncedPrev)
+	}
+}
@@ -5,7 +5,9 @@ package configapi
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 
 	"github.com/omec-project/webconsole/configmodels"
@@ -51,49 +53,104 @@ func isValidDeviceGroup(deviceGroup *configmodels.DeviceGroups) error {
 	if deviceGroup.DeviceGroupName == "" {
 		return errors.New("don't find the device group DeviceGroupName")
 	}
-	if deviceGroup.Imsis == nil {
+	if deviceGroup.Imsis == nil && len(deviceGroup.ImsiRanges) == 0 {
 		return errors.New("don't find the device group Imsis")
 	}
+	for _, imsiRange := range deviceGroup.ImsiRanges {
+		if err := imsiRange.Validate(); err != nil {
+			return fmt.Errorf("invalid device group ImsiRanges: %w", err)
+		}
+	}
 	if deviceGroup.SiteInfo == "" {
 		return errors.New("don't find the device group SiteInfo")
 	}
-	if deviceGroup.IpDomainName == "" {
-		return errors.New("don't find the device group IpDomainName")
+	if len(deviceGroup.IpDomains) == 0 {
+		return isValidIpDomain(deviceGroup.IpDomainName, &deviceGroup.IpDomainExpanded, "")
+	}
+	dnns := make([]string, 0, len(deviceGroup.IpDomains))
+	for i := range deviceGroup.IpDomains {
+		ipDomain := &deviceGroup.IpDomains[i]
+		if err := isValidIpDomain(ipDomain.IpDomainName, &ipDomain.IpDomainExpanded, fmt.Sprintf("IpDomains[%d].", i)); err != nil {
+			return err
+		}
+		if slices.Contains(dnns, ipDomain.IpDomainExpanded.Dnn) {
+			return fmt.Errorf("duplicate DNN %s in device group IpDomains", ipDomain.IpDomainExpanded.Dnn)
+		}
+		dnns = append(dnns, ipDomain.IpDomainExpanded.Dnn)
+	}
+	// the first IP domain is mirrored in the single IP domain fields
+	deviceGroup.IpDomainName = deviceGroup.IpDomains[0].IpDomainName
+	deviceGroup.IpDomainExpanded = deviceGroup.IpDomains[0].IpDomainExpanded
+	return nil
+}
+
+// ValidateSlice checks a network slice as the network slice API does, and
+// fills in the same defaults and bitrate conversions so that it renders like
+// a stored one.
+func ValidateSlice(slice *configmodels.Slice) error {
+	if err := validateSlice(slice); err != nil {
+		return err
+	}
+	normalizeApplicationFilteringRules(slice)
+	return nil
+}
+
+// ValidateDeviceGroup checks a device group as the device group API does, and
+// fills in the same defaults and bitrate conversions so that it renders like
+// a stored one.
+func ValidateDeviceGroup(deviceGroup *configmodels.DeviceGroups) error {
+	if !isValidName(deviceGroup.DeviceGroupName) {
+		return fmt.Errorf("invalid device group name %s, it needs to match regular expression: %s", deviceGroup.DeviceGroupName, NAME_PATTERN)
+	}
+	if err := isValidDeviceGroup(deviceGroup); err != nil {
+		return err
+	}
+	for _, ipDomain := range deviceGroup.GetIpDomains() {
+		normalizeUeDnnQos(ipDomain.IpDomainExpanded.UeDnnQos)
+	}
+	return nil
+}
+
+// isValidIpDomain checks an IP domain of a device group and fills in the QoS
+// defaults. field prefixes the field names in the errors.
+func isValidIpDomain(ipDomainName string, ipDomain *configmodels.DeviceGroupsIpDomainExpanded, field string) error {
+	if ipDomainName == "" {
+		return fmt.Errorf("don't find the device group %sIpDomainName", field)
 	}
-	if deviceGroup.IpDomainExpanded.Dnn == "" {
-		return errors.New("don't find the device group IpDomainExpanded.Dnn")
+	if ipDomain.Dnn == "" {
+		return fmt.Errorf("don't find the device group %sIpDomainExpanded.Dnn", field)
 	}
-	if deviceGroup.IpDomainExpanded.UeIpPool == "" {
-		return errors.New("don't find the device group IpDomainExpanded.UeIpPool")
+	if ipDomain.UeIpPool == "" {
+		return fmt.Errorf("don't find the device group %sIpDomainExpanded.UeIpPool", field)
 	}
-	if deviceGroup.IpDomainExpanded.DnsPrimary == "" {
-		return errors.New("don't find the device group IpDomainExpanded.DnsPrimary")
+	if ipDomain.DnsPrimary == "" {
+		return fmt.Errorf("don't find the device group %sIpDomainExpanded.DnsPrimary", field)
 	}
-	if deviceGroup.IpDomainExpanded.Mtu <= 0 {
-		return errors.New("invalid value for device group IpDomainExpanded.Mtu")
+	if ipDomain.Mtu <= 0 {
+		return fmt.Errorf("invalid value for device group %sIpDomainExpanded.Mtu", field)
 	}
-	if deviceGroup.IpDomainExpanded.UeDnnQos == nil {
-		return errors.New("don't find the device group IpDomainExpanded.UeDnnQos")
+	if ipDomain.UeDnnQos == nil {
+		return fmt.Errorf("don't find the device group %sIpDomainExpanded.UeDnnQos", field)
 	}
 	// Set default for DnnMbrUplink if negative
-	if deviceGroup.IpDomainExpanded.UeDnnQos.DnnMbrUplink < 0 {
+	if ipDomain.UeDnnQos.DnnMbrUplink < 0 {
 		// Default uplink bitrate: 1000000 (1 Mbps)
-		deviceGroup.IpDomainExpanded.UeDnnQos.DnnMbrUplink = 1000000
+		ipDomain.UeDnnQos.DnnMbrUplink = 1000000
 	}
 	// Set default for DnnMbrDownlink if negative
-	if deviceGroup.IpDomainExpanded.UeDnnQos.DnnMbrDownlink < 0 {
+	if ipDomain.UeDnnQos.DnnMbrDownlink < 0 {
 		// Default downlink bitrate: 1000000 (1 Mbps)
-		deviceGroup.IpDomainExpanded.UeDnnQos.DnnMbrDownlink = 1000000
+		ipDomain.UeDnnQos.DnnMbrDownlink = 1000000
 	}
 	// Set default for BitrateUnit if empty
-	if deviceGroup.IpDomainExpanded.UeDnnQos.BitrateUnit == "" {
+	if ipDomain.UeDnnQos.BitrateUnit == "" {
 		// Default bitrate unit: "bps"
-		deviceGroup.IpDomainExpanded.UeDnnQos.BitrateUnit = "bps"
+		ipDomain.UeDnnQos.BitrateUnit = "bps"
 	}
 	// Set default TrafficClass if nil
-	if deviceGroup.IpDomainExpanded.UeDnnQos.TrafficClass == nil {
+	if ipDomain.UeDnnQos.TrafficClass == nil {
 		// Default TrafficClass with typical values
-		deviceGroup.IpDomainExpanded.UeDnnQos.TrafficClass = &configmodels.TrafficClassInfo{
+		ipDomain.UeDnnQos.TrafficClass = &configmodels.TrafficClassInfo{
 			Name: "default",
 			Qci:  9,   // Default QCI value
 			Arp:  1,   // Default ARP value
@@ -102,29 +159,29 @@ func isValidDeviceGroup(deviceGroup *configmodels.DeviceGroups) error {
 		}
 	}
 	// Set default TrafficClass.Name if empty
-	if deviceGroup.IpDomainExpanded.UeDnnQos.TrafficClass.Name == "" {
+	if ipDomain.UeDnnQos.TrafficClass.Name == "" {
 		// Default traffic class name: "default"
-		deviceGroup.IpDomainExpanded.UeDnnQos.TrafficClass.Name = "default"
+		ipDomain.UeDnnQos.TrafficClass.Name = "default"
 	}
 	// Set default Qci if negative
-	if deviceGroup.IpDomainExpanded.UeDnnQos.TrafficClass.Qci < 0 {
+	if ipDomain.UeDnnQos.TrafficClass.Qci < 0 {
 		// Default QCI value: 9
-		deviceGroup.IpDomainExpanded.UeDnnQos.TrafficClass.Qci = 9
+		ipDomain.UeDnnQos.TrafficClass.Qci = 9
 	}
 	// Set default Arp if negative
-	if deviceGroup.IpDomainExpanded.UeDnnQos.TrafficClass.Arp < 0 {
+	if ipDomain.UeDnnQos.TrafficClass.Arp < 0 {
 		// Default ARP value: 1
-		deviceGroup.IpDomainExpanded.UeDnnQos.TrafficClass.Arp = 1
+		ipDomain.UeDnnQos.TrafficClass.Arp = 1
 	}
 	// Set default Pdb if negative
-	if deviceGroup.IpDomainExpanded.UeDnnQos.TrafficClass.Pdb < 0 {
+	if ipDomain.UeDnnQos.TrafficClass.Pdb < 0 {
 		// Default PDB value: 300 (ms)
-		deviceGroup.IpDomainExpanded.UeDnnQos.TrafficClass.Pdb = 300
+		ipDomain.UeDnnQos.TrafficClass.Pdb = 300
 	}
 	// Set default Pelr if negative
-	if deviceGroup.IpDomainExpanded.UeDnnQos.TrafficClass.Pelr < 0 {
+	if ipDomain.UeDnnQos.TrafficClass.Pelr < 0 {
 		// Default PELR value: 1
-		deviceGroup.IpDomainExpanded.UeDnnQos.TrafficClass.Pelr = 1
+		ipDomain.UeDnnQos.TrafficClass.Pelr = 1
 	}
 	return nil
 }
@@ -6,6 +6,8 @@ package configapi
 import (
 	"strings"
 	"testing"
+
+	"github.com/omec-project/webconsole/configmodels"
 )
 
 func TestValidateName(t *testing.T) {
@@ -109,3 +111,101 @@ func TestValidateGnbTac(t *testing.T) {
 func genLongString(length int) string {
 	return strings.Repeat("a", length)
 }
+
+func TestValidateImsiRange(t *testing.T) {
+	testCases := []struct {
+		name      string
+		imsiRange configmodels.DeviceGroupsImsiRange
+		expected  bool
+	}{
+		{"start and end", configmodels.DeviceGroupsImsiRange{Start: "001010000000000", End: "001010000099999"}, true},
+		{"single IMSI", configmodels.DeviceGroupsImsiRange{Start: "001010000000001", End: "001010000000001"}, true},
+		{"prefix and count", configmodels.DeviceGroupsImsiRange{Prefix: "00101", Count: 100000}, true},
+		{"prefix filled by count", configmodels.DeviceGroupsImsiRange{Prefix: "00101000000000", Count: 10}, true},
+		{"start after end", configmodels.DeviceGroupsImsiRange{Start: "001010000000002", End: "001010000000001"}, false},
+		{"different lengths", configmodels.DeviceGroupsImsiRange{Start: "00101000000000", End: "001010000000001"}, false},
+		{"non digit", configmodels.DeviceGroupsImsiRange{Start: "00101000000000a", End: "001010000000001"}, false},
+		{"missing end", configmodels.DeviceGroupsImsiRange{Start: "001010000000000"}, false},
+		{"count overflows prefix", configmodels.DeviceGroupsImsiRange{Prefix: "00101000000000", Count: 11}, false},
+		{"zero count", configmodels.DeviceGroupsImsiRange{Prefix: "00101"}, false},
+		{"both forms", configmodels.DeviceGroupsImsiRange{Start: "001010000000000", End: "001010000000001", Prefix: "00101", Count: 1}, false},
+	}
+
+	for _, tc := range testCases {
+		err := tc.imsiRange.Validate()
+		if (err == nil) != tc.expected {
+			t.Errorf("%s: unexpected result %v", tc.name, err)
+		}
+	}
+}
+
+func TestImsiRangeContains(t *testing.T) {
+	prefixRange := configmodels.DeviceGroupsImsiRange{Prefix: "0010100", Count: 1000}
+	testCases := []struct {
+		imsi     string
+		expected bool
+	}{
+		{"001010000000000", true},
+		{"001010000000999", true},
+		{"001010000001000", false},
+		{"00101000000099", false},
+		{"999990000000001", false},
+	}
+
+	for _, tc := range testCases {
+		if r := prefixRange.Contains(tc.imsi); r != tc.expected {
+			t.Errorf("%s", tc.imsi)
+		}
+	}
+	if size := prefixRange.Size(); size != 1000 {
+		t.Errorf("expected size 1000, got %d", size)
+	}
+}
+
+func TestIsValidDeviceGroup_IpDomains(t *testing.T) {
+	ipDomain := func(name, dnn string) configmodels.DeviceGroupsIpDomain {
+		return configmodels.DeviceGroupsIpDomain{
+			IpDomainName: name,
+			IpDomainExpanded: configmodels.DeviceGroupsIpDomainExpanded{
+				Dnn:        dnn,
+				UeIpPool:   "10.0.0.0/24",
+				DnsPrimary: "8.8.8.8",
+				Mtu:        1460,
+				UeDnnQos:   &configmodels.DeviceGroupsIpDomainExpandedUeDnnQos{TrafficClass: &configmodels.TrafficClassInfo{Name: "platinum", Qci: 8, Arp: 6}},
+			},
+		}
+	}
+	testCases := []struct {
+		name        string
+		ipDomains   []configmodels.DeviceGroupsIpDomain
+		expectedErr string
+	}{
+		{"two DNNs", []configmodels.DeviceGroupsIpDomain{ipDomain("pool1", "internet"), ipDomain("pool2", "iot")}, ""},
+		{"duplicate DNN", []configmodels.DeviceGroupsIpDomain{ipDomain("pool1", "internet"), ipDomain("pool2", "internet")}, "duplicate DNN internet in device group IpDomains"},
+		{"invalid second domain", []configmodels.DeviceGroupsIpDomain{ipDomain("pool1", "internet"), ipDomain("pool2", "")}, "don't find the device group IpDomains[1].IpDomainExpanded.Dnn"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			deviceGroup := &configmodels.DeviceGroups{
+				DeviceGroupName: "group1",
+				Imsis:           []string{"001010000000001"},
+				SiteInfo:        "site1",
+				IpDomains:       tc.ipDomains,
+			}
+			err := isValidDeviceGroup(deviceGroup)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if deviceGroup.IpDomainName != "pool1" || deviceGroup.IpDomainExpanded.Dnn != "internet" {
+				t.Errorf("expected the first IP domain to be mirrored, got %s/%s", deviceGroup.IpDomainName, deviceGroup.IpDomainExpanded.Dnn)
+			}
+		})
+	}
+}
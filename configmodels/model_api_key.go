@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package configmodels
+
+import "time"
+
+const APIKeyDataColl = "webconsoleData.snapshots.apiKeyData"
+
+// DBAPIKey is an API key of a service account. Only the hash of the key is
+// stored. Times are Unix seconds, 0 when unset.
+type DBAPIKey struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	KeyHash  string `json:"keyHash"`
+	// Permissions limit the permissions of the role of the service account,
+	// which are all granted if empty
+	Permissions []string `json:"permissions,omitempty"`
+	CreatedAt   int64    `json:"createdAt"`
+	ExpiresAt   int64    `json:"expiresAt,omitempty"`
+	LastUsedAt  int64    `json:"lastUsedAt,omitempty"`
+}
+
+type CreateAPIKeyParams struct {
+	Name        string     `json:"name"`
+	Permissions []string   `json:"permissions,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
+type APIKeyResponse struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Permissions []string   `json:"permissions,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// CreateAPIKeyResponse holds the key, which cannot be retrieved afterwards.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+func unixTime(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0).UTC()
+	return &t
+}
+
+func (k *DBAPIKey) ToResponse() APIKeyResponse {
+	return APIKeyResponse{
+		ID:          k.ID,
+		Name:        k.Name,
+		Permissions: k.Permissions,
+		CreatedAt:   time.Unix(k.CreatedAt, 0).UTC(),
+		ExpiresAt:   unixTime(k.ExpiresAt),
+		LastUsedAt:  unixTime(k.LastUsedAt),
+	}
+}
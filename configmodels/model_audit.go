@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package configmodels
+
+import "time"
+
+const AuditDataColl = "webconsoleData.snapshots.auditData"
+
+// AuditRecordedAtField is the date of a stored audit record, which the
+// retention index of the audit log expires the records on.
+const AuditRecordedAtField = "recordedAt"
+
+// Outcomes of an audited request.
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditChange is a field of a resource changed by a request. Fields are
+// dotted paths in the stored document, the values of secrets are redacted.
+type AuditChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// DBAuditRecord is a mutating request on the configuration. The time is in
+// Unix milliseconds, so that the requests of the same second are ordered.
+type DBAuditRecord struct {
+	Time       int64         `json:"time"`
+	Username   string        `json:"username,omitempty"`
+	ClientIP   string        `json:"clientIp"`
+	Method     string        `json:"method"`
+	Route      string        `json:"route"`
+	Path       string        `json:"path"`
+	ResourceID string        `json:"resourceId,omitempty"`
+	RequestID  string        `json:"requestId"`
+	StatusCode int           `json:"statusCode"`
+	Outcome    string        `json:"outcome"`
+	Changes    []AuditChange `json:"changes,omitempty"`
+}
+
+type AuditRecordResponse struct {
+	Time       time.Time     `json:"time"`
+	Username   string        `json:"username,omitempty"`
+	ClientIP   string        `json:"clientIp"`
+	Method     string        `json:"method"`
+	Route      string        `json:"route"`
+	Path       string        `json:"path"`
+	ResourceID string        `json:"resourceId,omitempty"`
+	RequestID  string        `json:"requestId"`
+	StatusCode int           `json:"statusCode"`
+	Outcome    string        `json:"outcome"`
+	Changes    []AuditChange `json:"changes,omitempty"`
+}
+
+func (r *DBAuditRecord) ToResponse() AuditRecordResponse {
+	return AuditRecordResponse{
+		Time:       time.UnixMilli(r.Time).UTC(),
+		Username:   r.Username,
+		ClientIP:   r.ClientIP,
+		Method:     r.Method,
+		Route:      r.Route,
+		Path:       r.Path,
+		ResourceID: r.ResourceID,
+		RequestID:  r.RequestID,
+		StatusCode: r.StatusCode,
+		Outcome:    r.Outcome,
+		Changes:    r.Changes,
+	}
+}
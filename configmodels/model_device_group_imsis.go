@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configmodels
+
+// DeviceGroupImsis - IMSIs to add to or remove from a device group
+type DeviceGroupImsis struct {
+	Imsis []string `json:"imsis"`
+}
+
+// DeviceGroupImsisResult - Outcome of an incremental IMSI update. Changed lists
+// the IMSIs that were added or removed, Unchanged the ones that already were
+// (or were not) members of the device group. Job tracks the sync of the changed
+// IMSIs, it is absent when nothing changed.
+type DeviceGroupImsisResult struct {
+	DeviceGroupName string `json:"group-name"`
+
+	Changed []string `json:"changed"`
+
+	Unchanged []string `json:"unchanged"`
+
+	Job *SyncJob `json:"job,omitempty"`
+}
@@ -22,9 +22,16 @@ type DeviceGroups struct {
 
 	Imsis []string `json:"imsis"`
 
+	ImsiRanges []DeviceGroupsImsiRange `json:"imsi-ranges,omitempty"`
+
 	SiteInfo string `json:"site-info,omitempty"`
 
 	IpDomainName string `json:"ip-domain-name,omitempty"`
 
 	IpDomainExpanded DeviceGroupsIpDomainExpanded `json:"ip-domain-expanded,omitempty"`
+
+	// IpDomains lists every IP domain of the device group when it serves more
+	// than one DNN. The first one is mirrored in IpDomainName and
+	// IpDomainExpanded.
+	IpDomains []DeviceGroupsIpDomain `json:"ip-domains,omitempty"`
 }
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configmodels
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	ImsiMinLength = 5
+	ImsiMaxLength = 15
+)
+
+// DeviceGroupsImsiRange - A contiguous block of IMSIs belonging to a device group.
+// It is declared either with an inclusive start/end pair or with a prefix and
+// a count, in which case the block starts at the prefix padded with zeros to
+// the full IMSI length.
+type DeviceGroupsImsiRange struct {
+	Start string `json:"start,omitempty"`
+
+	End string `json:"end,omitempty"`
+
+	Prefix string `json:"prefix,omitempty"`
+
+	Count uint64 `json:"count,omitempty"`
+}
+
+// Bounds returns the first and last IMSI of the range. Both have the same
+// number of digits, so they can be compared as strings.
+func (r DeviceGroupsImsiRange) Bounds() (string, string, error) {
+	if r.Prefix != "" || r.Count != 0 {
+		if r.Start != "" || r.End != "" {
+			return "", "", errors.New("IMSI range must use either start/end or prefix/count")
+		}
+		return prefixBounds(r.Prefix, r.Count)
+	}
+	if !IsValidImsi(r.Start) || !IsValidImsi(r.End) {
+		return "", "", fmt.Errorf("IMSI range start %q and end %q must be %d to %d digits", r.Start, r.End, ImsiMinLength, ImsiMaxLength)
+	}
+	if len(r.Start) != len(r.End) {
+		return "", "", fmt.Errorf("IMSI range start %q and end %q must have the same length", r.Start, r.End)
+	}
+	if r.Start > r.End {
+		return "", "", fmt.Errorf("IMSI range start %q is after end %q", r.Start, r.End)
+	}
+	return r.Start, r.End, nil
+}
+
+func prefixBounds(prefix string, count uint64) (string, string, error) {
+	if prefix == "" || len(prefix) >= ImsiMaxLength || strings.Trim(prefix, "0123456789") != "" {
+		return "", "", fmt.Errorf("IMSI range prefix %q must be 1 to %d digits", prefix, ImsiMaxLength-1)
+	}
+	free := ImsiMaxLength - len(prefix)
+	if count == 0 || count > uint64(math.Pow10(free)) {
+		return "", "", fmt.Errorf("IMSI range count %d does not fit prefix %q", count, prefix)
+	}
+	start := prefix + strings.Repeat("0", free)
+	first, _ := strconv.ParseUint(start, 10, 64)
+	return start, FormatImsi(first+count-1, ImsiMaxLength), nil
+}
+
+// Validate checks the range is well formed.
+func (r DeviceGroupsImsiRange) Validate() error {
+	_, _, err := r.Bounds()
+	return err
+}
+
+// Contains reports whether the IMSI (without the `imsi-` prefix) is in the range.
+func (r DeviceGroupsImsiRange) Contains(imsi string) bool {
+	start, end, err := r.Bounds()
+	if err != nil {
+		return false
+	}
+	return len(imsi) == len(start) && IsValidImsi(imsi) && start <= imsi && imsi <= end
+}
+
+// Size returns the number of IMSIs covered by the range.
+func (r DeviceGroupsImsiRange) Size() uint64 {
+	start, end, err := r.Bounds()
+	if err != nil {
+		return 0
+	}
+	first, _ := strconv.ParseUint(start, 10, 64)
+	last, _ := strconv.ParseUint(end, 10, 64)
+	return last - first + 1
+}
+
+// ContainsImsi reports whether the IMSI is enumerated in the device group or
+// covered by one of its ranges.
+func (dg *DeviceGroups) ContainsImsi(imsi string) bool {
+	if slices.Contains(dg.Imsis, imsi) {
+		return true
+	}
+	for _, r := range dg.ImsiRanges {
+		if r.Contains(imsi) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatImsi renders an IMSI number zero padded to the given length.
+func FormatImsi(imsi uint64, length int) string {
+	return fmt.Sprintf("%0*d", length, imsi)
+}
+
+// IsValidImsi reports whether the IMSI (without the `imsi-` prefix) is made of
+// ImsiMinLength to ImsiMaxLength digits.
+func IsValidImsi(imsi string) bool {
+	return len(imsi) >= ImsiMinLength && len(imsi) <= ImsiMaxLength && strings.Trim(imsi, "0123456789") == ""
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configmodels
+
+// DeviceGroupsIpDomain - An IP domain of a device group: a DNN with its own UE
+// pool, DNS, MTU and QoS
+type DeviceGroupsIpDomain struct {
+	IpDomainName string `json:"ip-domain-name,omitempty"`
+
+	IpDomainExpanded DeviceGroupsIpDomainExpanded `json:"ip-domain-expanded"`
+}
+
+// GetIpDomains returns the IP domains of the device group. A device group
+// configured with the single ip-domain-name and ip-domain-expanded fields has
+// one IP domain.
+func (dg *DeviceGroups) GetIpDomains() []DeviceGroupsIpDomain {
+	if len(dg.IpDomains) > 0 {
+		return dg.IpDomains
+	}
+	if dg.IpDomainName == "" && dg.IpDomainExpanded.Dnn == "" {
+		return nil
+	}
+	return []DeviceGroupsIpDomain{{IpDomainName: dg.IpDomainName, IpDomainExpanded: dg.IpDomainExpanded}}
+}
+
+// Dnns returns the DNN of every IP domain of the device group.
+func (dg *DeviceGroups) Dnns() []string {
+	ipDomains := dg.GetIpDomains()
+	dnns := make([]string, 0, len(ipDomains))
+	for _, ipDomain := range ipDomains {
+		dnns = append(dnns, ipDomain.IpDomainExpanded.Dnn)
+	}
+	return dnns
+}
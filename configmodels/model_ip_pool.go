@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configmodels
+
+// IpPool - Utilization of the UE IP pool of an IP domain of a device group
+type IpPool struct {
+	DeviceGroupName string `json:"group-name"`
+
+	Dnn string `json:"dnn"`
+
+	UeIpPool string `json:"ue-ip-pool"`
+
+	// Slices and UPFs the device group is served by
+	Slices []string `json:"slices,omitempty"`
+
+	Upfs []string `json:"upfs,omitempty"`
+
+	// Size is the number of addresses in the pool
+	Size uint64 `json:"size"`
+
+	// AssignedImsis is the number of IMSIs of the device group, ranges included
+	AssignedImsis uint64 `json:"assigned-imsis"`
+
+	// Headroom is the number of addresses left once every IMSI has one. It is
+	// negative when the pool is too small.
+	Headroom int64 `json:"headroom"`
+
+	// Overlaps lists the device groups with a pool overlapping this one on a
+	// shared slice, UPF or DNN
+	Overlaps []string `json:"overlaps,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package configmodels
+
+const RefreshTokenDataColl = "webconsoleData.snapshots.refreshTokenData"
+
+// DBRefreshToken is a login session. The access tokens of the session carry
+// its ID, and are revoked with it. Only the digest of the refresh token is
+// stored.
+type DBRefreshToken struct {
+	SessionID string `json:"sessionId"`
+	Username  string `json:"username"`
+	TokenHash string `json:"tokenHash"`
+	ExpiresAt int64  `json:"expiresAt"` // Unix time
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Canonical Ltd
+
+package configmodels
+
+import "slices"
+
+const RoleDataColl = "webconsoleData.snapshots.roleData"
+
+// Permissions a role can grant on the configuration API.
+const (
+	PermissionSubscribersRead  = "subscribers:read"
+	PermissionSubscribersWrite = "subscribers:write"
+	PermissionNetworkRead      = "network:read"
+	PermissionNetworkWrite     = "network:write"
+	PermissionK4Read           = "k4:read"
+	PermissionK4Write          = "k4:write"
+	PermissionAuditRead        = "audit:read"
+)
+
+var Permissions = []string{
+	PermissionSubscribersRead,
+	PermissionSubscribersWrite,
+	PermissionNetworkRead,
+	PermissionNetworkWrite,
+	PermissionK4Read,
+	PermissionK4Write,
+	PermissionAuditRead,
+}
+
+// DefaultRoleName is the role of the user accounts created without one. It
+// grants every permission, as UserRole did before roles.
+const DefaultRoleName = "operator"
+
+// Role is a named set of permissions given to UserRole accounts. AdminRole
+// accounts are granted every permission.
+type Role struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// BuiltinRoles are created when missing on start, and cannot be deleted.
+var BuiltinRoles = []Role{
+	{
+		Name:        DefaultRoleName,
+		Description: "Full access to the configuration",
+		Permissions: Permissions,
+	},
+	{
+		Name:        "read-only",
+		Description: "Read subscribers and network configuration",
+		Permissions: []string{PermissionSubscribersRead, PermissionNetworkRead},
+	},
+	{
+		Name:        "subscriber-operator",
+		Description: "Manage subscribers",
+		Permissions: []string{PermissionSubscribersRead, PermissionSubscribersWrite, PermissionNetworkRead},
+	},
+	{
+		Name:        "network-planner",
+		Description: "Manage network slices, device groups and inventory",
+		Permissions: []string{PermissionSubscribersRead, PermissionNetworkRead, PermissionNetworkWrite},
+	},
+	{
+		Name:        "key-custodian",
+		Description: "Manage K4 keys",
+		Permissions: []string{PermissionK4Read, PermissionK4Write},
+	},
+}
+
+func IsBuiltinRole(name string) bool {
+	return slices.ContainsFunc(BuiltinRoles, func(role Role) bool { return role.Name == name })
+}
+
+func (r *Role) HasPermission(permission string) bool {
+	return slices.Contains(r.Permissions, permission)
+}
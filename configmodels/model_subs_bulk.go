@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configmodels
+
+const (
+	SubsBulkStatusCreated    = "created"
+	SubsBulkStatusConflict   = "conflict"
+	SubsBulkStatusInvalid    = "invalid"
+	SubsBulkStatusFailed     = "failed"
+	SubsBulkStatusNotCreated = "not_created"
+)
+
+type SubsBulkEntry struct {
+	UeId string `json:"ueId"`
+	SubsOverrideData
+}
+
+type SubsBulkResult struct {
+	Row    int    `json:"row"`
+	UeId   string `json:"ueId"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type SubsBulkReport struct {
+	Mode      string           `json:"mode"`
+	Total     int              `json:"total"`
+	Created   int              `json:"created"`
+	Conflicts int              `json:"conflicts"`
+	Invalid   int              `json:"invalid"`
+	Failed    int              `json:"failed"`
+	Results   []SubsBulkResult `json:"results"`
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configmodels
+
+type SubsProfile struct {
+	UeId        string                         `json:"ueId"`
+	PlmnID      string                         `json:"plmnID,omitempty"`
+	DeviceGroup string                         `json:"deviceGroup,omitempty"`
+	Slice       *SubsProfileSlice              `json:"slice,omitempty"`
+	K4          *SubsProfileK4                 `json:"k4,omitempty"`
+	Documents   map[string]SubsProfileDocument `json:"documents"`
+	Consistent  bool                           `json:"consistent"`
+	Issues      []string                       `json:"issues,omitempty"`
+}
+
+type SubsProfileSlice struct {
+	SliceName string   `json:"sliceName"`
+	Sst       string   `json:"sst"`
+	Sd        string   `json:"sd,omitempty"`
+	Mcc       string   `json:"mcc"`
+	Mnc       string   `json:"mnc"`
+	Dnn       string   `json:"dnn,omitempty"`
+	Dnns      []string `json:"dnns,omitempty"`
+}
+
+// SubsProfileK4 describes the K4 key protecting the subscriber Ki without
+// exposing the key itself.
+type SubsProfileK4 struct {
+	K4Sno               byte   `json:"k4_sno"`
+	K4Label             string `json:"key_label,omitempty"`
+	K4Type              string `json:"key_type,omitempty"`
+	EncryptionAlgorithm int32  `json:"encryptionAlgorithm"`
+	Found               bool   `json:"found"`
+	KeyMatches          bool   `json:"keyMatches"`
+}
+
+type SubsProfileDocument struct {
+	Present bool   `json:"present"`
+	Stale   bool   `json:"stale"`
+	Reason  string `json:"reason,omitempty"`
+}
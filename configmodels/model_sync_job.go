@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2025 Canonical Ltd.
+
+package configmodels
+
+import "time"
+
+const SyncJobsColl = "webconsoleData.snapshots.syncJobs"
+
+const (
+	SyncJobKindNetworkSlice = "network-slice"
+	SyncJobKindDeviceGroup  = "device-group"
+	// SyncJobKindDeviceGroupImsis syncs only the IMSIs added to or removed
+	// from a device group
+	SyncJobKindDeviceGroupImsis = "device-group-imsis"
+)
+
+const (
+	SyncJobStatusQueued    = "queued"
+	SyncJobStatusRunning   = "running"
+	SyncJobStatusSucceeded = "succeeded"
+	SyncJobStatusFailed    = "failed"
+)
+
+// SyncJob - A background sync of the subscriber data of a network slice or a
+// device group
+type SyncJob struct {
+	JobId string `json:"job-id"`
+
+	Kind string `json:"kind"`
+
+	Target string `json:"target"`
+
+	Status string `json:"status"`
+
+	// Processed is the number of IMSIs synced so far
+	Processed int64 `json:"processed"`
+
+	FailedImsis []string `json:"failed-imsis,omitempty"`
+
+	Error string `json:"error,omitempty"`
+
+	// Attempts counts the runs of the job, including resumes after a restart
+	Attempts int `json:"attempts"`
+
+	CreatedAt time.Time `json:"created-at"`
+
+	StartedAt *time.Time `json:"started-at,omitempty"`
+
+	FinishedAt *time.Time `json:"finished-at,omitempty"`
+
+	Duration string `json:"duration,omitempty"`
+}
+
+func (j *SyncJob) Finished() bool {
+	return j.Status == SyncJobStatusSucceeded || j.Status == SyncJobStatusFailed
+}
@@ -4,6 +4,8 @@
 package configmodels
 
 import (
+	"time"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -18,11 +20,33 @@ type DBUserAccount struct {
 	Username       string `json:"username"`
 	HashedPassword string `json:"password,omitempty"`
 	Role           int    `json:"role"`
+	// RoleName is the name of the Role of UserRole accounts, DefaultRoleName if empty
+	RoleName string `json:"roleName,omitempty"`
+	// ServiceAccount accounts have no password and authenticate with API keys
+	ServiceAccount bool `json:"serviceAccount,omitempty"`
+	// IdentityProvider is set on the shadow accounts of the users of an
+	// external identity provider, which have no password
+	IdentityProvider string `json:"identityProvider,omitempty"`
+	// DisplayName is the name given by the identity provider to the user of a
+	// shadow account, whose username is derived from its stable identifier
+	DisplayName string `json:"displayName,omitempty"`
+	// Log in attempts, times are Unix seconds, 0 when unset. The account is
+	// locked until LockedUntil after too many consecutive failed attempts.
+	LastLoginAt         int64 `json:"lastLoginAt,omitempty"`
+	LastFailedLoginAt   int64 `json:"lastFailedLoginAt,omitempty"`
+	FailedLoginAttempts int   `json:"failedLoginAttempts,omitempty"`
+	LockedUntil         int64 `json:"lockedUntil,omitempty"`
 }
 
 type CreateUserAccountParams struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username       string `json:"username"`
+	Password       string `json:"password"`
+	RoleName       string `json:"roleName,omitempty"`
+	ServiceAccount bool   `json:"serviceAccount,omitempty"`
+}
+
+type ChangeRoleParams struct {
+	RoleName string `json:"roleName"`
 }
 
 type ChangePasswordParams struct {
@@ -30,8 +54,31 @@ type ChangePasswordParams struct {
 }
 
 type GetUserAccountResponse struct {
-	Username string `json:"username"`
-	Role     int    `json:"role"`
+	Username            string     `json:"username"`
+	Role                int        `json:"role"`
+	RoleName            string     `json:"roleName,omitempty"`
+	ServiceAccount      bool       `json:"serviceAccount,omitempty"`
+	IdentityProvider    string     `json:"identityProvider,omitempty"`
+	DisplayName         string     `json:"displayName,omitempty"`
+	LastLoginAt         *time.Time `json:"lastLoginAt,omitempty"`
+	LastFailedLoginAt   *time.Time `json:"lastFailedLoginAt,omitempty"`
+	FailedLoginAttempts int        `json:"failedLoginAttempts,omitempty"`
+	LockedUntil         *time.Time `json:"lockedUntil,omitempty"`
+}
+
+func (u *DBUserAccount) ToResponse() GetUserAccountResponse {
+	return GetUserAccountResponse{
+		Username:            u.Username,
+		Role:                u.Role,
+		RoleName:            u.RoleName,
+		ServiceAccount:      u.ServiceAccount,
+		IdentityProvider:    u.IdentityProvider,
+		DisplayName:         u.DisplayName,
+		LastLoginAt:         unixTime(u.LastLoginAt),
+		LastFailedLoginAt:   unixTime(u.LastFailedLoginAt),
+		FailedLoginAttempts: u.FailedLoginAttempts,
+		LockedUntil:         unixTime(u.LockedUntil),
+	}
 }
 
 func CreateNewDBUserAccount(username string, password string, role int) (*DBUserAccount, error) {
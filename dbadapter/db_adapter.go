@@ -7,6 +7,7 @@ package dbadapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 type DBInterface interface {
 	RestfulAPIGetOne(collName string, filter bson.M) (map[string]any, error)
 	RestfulAPIGetMany(collName string, filter bson.M) ([]map[string]any, error)
+	RestfulAPIGetManyWithOptions(collName string, filter bson.M, findOpts FindOptions) ([]map[string]any, error)
 	RestfulAPIPutOneTimeout(collName string, filter bson.M, putData map[string]any, timeout int32, timeField string) bool
 	RestfulAPIPutOne(collName string, filter bson.M, putData map[string]any) (bool, error)
 	RestfulAPIPutOneWithContext(context context.Context, collName string, filter bson.M, putData map[string]any) (bool, error)
@@ -42,7 +44,12 @@ type DBInterface interface {
 	RestfulAPICount(collName string, filter bson.M) (int64, error)
 	RestfulAPIPullOne(collName string, filter bson.M, putData map[string]any) error
 	RestfulAPIPullOneWithContext(context context.Context, collName string, filter bson.M, putData map[string]any) error
+	RestfulAPIAddToSetOne(collName string, filter bson.M, addData map[string]any) error
+	RestfulAPIUpdateOne(collName string, filter bson.M, setData map[string]any) (bool, error)
+	RestfulAPIIncrementOne(collName string, filter bson.M, incData map[string]any, setData map[string]any) (map[string]any, error)
 	CreateIndex(collName string, keyField string) (bool, error)
+	CreateTTLIndex(collName string, timeField string, expireAfter time.Duration) (bool, error)
+	CreateQueryIndex(collName string, keys bson.D) (bool, error)
 	StartSession() (mongo.Session, error)
 	SupportsTransactions() (bool, error)
 }
@@ -53,8 +60,22 @@ var (
 	WebuiDBClient  DBInterface
 )
 
+// indexOptionsConflictCode is the error of MongoDB creating an index that
+// exists with other options.
+const indexOptionsConflictCode = 85
+
 type MongoDBClient struct {
 	mongoapi.MongoClient
+	dbName string
+}
+
+// FindOptions controls the server-side paging of RestfulAPIGetManyWithOptions.
+// Zero values mean no skip, no limit, natural order and full documents.
+type FindOptions struct {
+	Skip       int64
+	Limit      int64
+	Sort       bson.D
+	Projection bson.M
 }
 type SessionRunner func(ctx context.Context, fn func(sc mongo.SessionContext) error) error
 
@@ -112,7 +133,7 @@ func setDBClient(url, dbname string, optConfig OptConfig) (DBInterface, error) {
 		return nil, errConnect
 	}
 
-	return &MongoDBClient{*mClient}, nil
+	return &MongoDBClient{MongoClient: *mClient, dbName: dbname}, nil
 }
 
 func ConnectMongo(url string, dbname string, client *DBInterface, opts OptConfig) {
@@ -209,6 +230,33 @@ func InitMongoDB() error {
 		logger.InitLog.Errorf("error creating gNB index in commonDB %v", err)
 		return err
 	}
+	if resp, err := CommonDBClient.CreateIndex(configmodels.SyncJobsColl, "job-id"); !resp || err != nil {
+		logger.InitLog.Errorf("error creating sync job index in commonDB %v", err)
+		return err
+	}
+	syncJobRetention := time.Duration(mongodb.SyncJobRetentionDay) * 24 * time.Hour
+	if resp, err := CommonDBClient.CreateTTLIndex(configmodels.SyncJobsColl, "finished-at", syncJobRetention); !resp || err != nil {
+		logger.InitLog.Errorf("error creating sync job retention index in commonDB %v", err)
+		return err
+	}
+	// the audit log is queried by time, and by user or resource from the most
+	// recent record
+	auditIndexes := []bson.D{
+		{{Key: "time", Value: -1}},
+		{{Key: "username", Value: 1}, {Key: "time", Value: -1}},
+		{{Key: "resourceId", Value: 1}, {Key: "time", Value: -1}},
+	}
+	for _, keys := range auditIndexes {
+		if resp, err := CommonDBClient.CreateQueryIndex(configmodels.AuditDataColl, keys); !resp || err != nil {
+			logger.InitLog.Errorf("error creating audit log index in commonDB %v", err)
+			return err
+		}
+	}
+	auditRetention := time.Duration(mongodb.AuditRetentionDay) * 24 * time.Hour
+	if resp, err := CommonDBClient.CreateTTLIndex(configmodels.AuditDataColl, configmodels.AuditRecordedAtField, auditRetention); !resp || err != nil {
+		logger.InitLog.Errorf("error creating audit log retention index in commonDB %v", err)
+		return err
+	}
 
 	if factory.WebUIConfig.Configuration.EnableAuthentication {
 		ConnectMongo(mongodb.WebuiDBUrl, mongodb.WebuiDBName, &WebuiDBClient, OptConfig{
@@ -219,6 +267,26 @@ func InitMongoDB() error {
 			logger.InitLog.Errorf("error initializing webuiDB %v", err)
 			return err
 		}
+		if resp, err := WebuiDBClient.CreateIndex(configmodels.RefreshTokenDataColl, "sessionId"); !resp || err != nil {
+			logger.InitLog.Errorf("error creating refresh token index in webuiDB %v", err)
+			return err
+		}
+		if resp, err := WebuiDBClient.CreateIndex(configmodels.RefreshTokenDataColl, "tokenHash"); !resp || err != nil {
+			logger.InitLog.Errorf("error creating refresh token index in webuiDB %v", err)
+			return err
+		}
+		if resp, err := WebuiDBClient.CreateIndex(configmodels.RoleDataColl, "name"); !resp || err != nil {
+			logger.InitLog.Errorf("error creating role index in webuiDB %v", err)
+			return err
+		}
+		if resp, err := WebuiDBClient.CreateIndex(configmodels.APIKeyDataColl, "id"); !resp || err != nil {
+			logger.InitLog.Errorf("error creating API key index in webuiDB %v", err)
+			return err
+		}
+		if resp, err := WebuiDBClient.CreateIndex(configmodels.APIKeyDataColl, "keyHash"); !resp || err != nil {
+			logger.InitLog.Errorf("error creating API key index in webuiDB %v", err)
+			return err
+		}
 	}
 
 	logger.InitLog.Info("MongoDB initialization completed successfully")
@@ -233,6 +301,32 @@ func (db *MongoDBClient) RestfulAPIGetMany(collName string, filter bson.M) ([]ma
 	return db.MongoClient.RestfulAPIGetMany(collName, filter)
 }
 
+func (db *MongoDBClient) RestfulAPIGetManyWithOptions(collName string, filter bson.M, findOpts FindOptions) ([]map[string]any, error) {
+	opts := options.Find()
+	if findOpts.Skip > 0 {
+		opts.SetSkip(findOpts.Skip)
+	}
+	if findOpts.Limit > 0 {
+		opts.SetLimit(findOpts.Limit)
+	}
+	if len(findOpts.Sort) > 0 {
+		opts.SetSort(findOpts.Sort)
+	}
+	if len(findOpts.Projection) > 0 {
+		opts.SetProjection(findOpts.Projection)
+	}
+	collection := db.MongoClient.Client.Database(db.dbName).Collection(collName)
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("RestfulAPIGetManyWithOptions err: %+v", err)
+	}
+	results := make([]map[string]any, 0)
+	if err = cursor.All(context.TODO(), &results); err != nil {
+		return nil, fmt.Errorf("RestfulAPIGetManyWithOptions err: %+v", err)
+	}
+	return results, nil
+}
+
 func (db *MongoDBClient) RestfulAPIPutOneTimeout(collName string, filter bson.M, putData map[string]any, timeout int32, timeField string) bool {
 	return db.MongoClient.RestfulAPIPutOneTimeout(collName, filter, putData, timeout, timeField)
 }
@@ -309,10 +403,93 @@ func (db *MongoDBClient) RestfulAPIPullOneWithContext(context context.Context, c
 	return db.MongoClient.RestfulAPIPullOneWithContext(context, collName, filter, putData)
 }
 
+func (db *MongoDBClient) RestfulAPIAddToSetOne(collName string, filter bson.M, addData map[string]any) error {
+	collection := db.MongoClient.Client.Database(db.dbName).Collection(collName)
+	if _, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$addToSet": addData}); err != nil {
+		return fmt.Errorf("RestfulAPIAddToSetOne err: %+v", err)
+	}
+	return nil
+}
+
+// RestfulAPIUpdateOne sets fields of the document matching the filter. Unlike
+// RestfulAPIPost it never creates a document, so the filter can be used as a
+// condition; it reports whether a document matched.
+func (db *MongoDBClient) RestfulAPIUpdateOne(collName string, filter bson.M, setData map[string]any) (bool, error) {
+	collection := db.MongoClient.Client.Database(db.dbName).Collection(collName)
+	result, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$set": setData})
+	if err != nil {
+		return false, fmt.Errorf("RestfulAPIUpdateOne err: %+v", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// RestfulAPIIncrementOne atomically increments and sets fields of the document
+// matching the filter, and returns the updated document. It returns nil if no
+// document matches, and never creates one.
+func (db *MongoDBClient) RestfulAPIIncrementOne(collName string, filter bson.M, incData map[string]any, setData map[string]any) (map[string]any, error) {
+	collection := db.MongoClient.Client.Database(db.dbName).Collection(collName)
+	update := bson.M{"$inc": incData}
+	if len(setData) != 0 {
+		update["$set"] = setData
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var result map[string]any
+	err := collection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("RestfulAPIIncrementOne err: %+v", err)
+	}
+	return result, nil
+}
+
 func (db *MongoDBClient) CreateIndex(collName string, keyField string) (bool, error) {
 	return db.MongoClient.CreateIndex(collName, keyField)
 }
 
+// CreateTTLIndex creates an index removing the documents whose timeField, a
+// date, is older than expireAfter. The expiry of an existing index is updated.
+func (db *MongoDBClient) CreateTTLIndex(collName string, timeField string, expireAfter time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	expireAfterSeconds := int32(expireAfter.Seconds())
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: timeField, Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(expireAfterSeconds),
+	}
+	database := db.MongoClient.Client.Database(db.dbName)
+	_, err := database.Collection(collName).Indexes().CreateOne(ctx, index)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == indexOptionsConflictCode {
+		cmd := bson.D{
+			{Key: "collMod", Value: collName},
+			{Key: "index", Value: bson.D{
+				{Key: "keyPattern", Value: bson.D{{Key: timeField, Value: 1}}},
+				{Key: "expireAfterSeconds", Value: expireAfterSeconds},
+			}},
+		}
+		err = database.RunCommand(ctx, cmd).Err()
+	}
+	if err != nil {
+		return false, fmt.Errorf("CreateTTLIndex err: %+v", err)
+	}
+	return true, nil
+}
+
+// CreateQueryIndex creates a non unique index on the keys, for the queries
+// filtering and sorting on them. Unlike CreateIndex, documents may share the
+// values of the keys.
+func (db *MongoDBClient) CreateQueryIndex(collName string, keys bson.D) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	collection := db.MongoClient.Client.Database(db.dbName).Collection(collName)
+	if _, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys}); err != nil {
+		return false, fmt.Errorf("CreateQueryIndex err: %+v", err)
+	}
+	return true, nil
+}
+
 func (db *MongoDBClient) StartSession() (mongo.Session, error) {
 	return db.MongoClient.StartSession()
 }
@@ -2,6 +2,7 @@ package dbadapter
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -12,6 +13,7 @@ import (
 type MockDBClient struct {
 	Docs                   []map[string]any
 	GetManyFn              func(collName string, filter bson.M) ([]map[string]any, error)
+	GetManyWithOptionsFn   func(collName string, filter bson.M, findOpts FindOptions) ([]map[string]any, error)
 	GetOneFn               func(collName string, filter bson.M) (map[string]any, error)
 	PostFn                 func(collName string, filter bson.M, postData map[string]any) (bool, error)
 	PostWithContextFn      func(ctx context.Context, collName string, filter bson.M, postData map[string]any) (bool, error)
@@ -32,7 +34,12 @@ type MockDBClient struct {
 	CountFn                func(collName string, filter bson.M) (int64, error)
 	PullOneFn              func(collName string, filter bson.M, putData map[string]any) error
 	PullOneWithContextFn   func(ctx context.Context, collName string, filter bson.M, putData map[string]any) error
+	AddToSetOneFn          func(collName string, filter bson.M, addData map[string]any) error
+	UpdateOneFn            func(collName string, filter bson.M, setData map[string]any) (bool, error)
+	IncrementOneFn         func(collName string, filter bson.M, incData map[string]any, setData map[string]any) (map[string]any, error)
 	CreateIndexFn          func(collName string, keyField string) (bool, error)
+	CreateTTLIndexFn       func(collName string, timeField string, expireAfter time.Duration) (bool, error)
+	CreateQueryIndexFn     func(collName string, keys bson.D) (bool, error)
 	StartSessionFn         func() (mongo.Session, error)
 	SupportsTransactionsFn func() (bool, error)
 }
@@ -45,6 +52,14 @@ func (m *MockDBClient) RestfulAPIGetMany(collName string, filter bson.M) ([]map[
 	return nil, nil
 }
 
+// RestfulAPIGetManyWithOptions implements the mock version of GetManyWithOptions
+func (m *MockDBClient) RestfulAPIGetManyWithOptions(collName string, filter bson.M, findOpts FindOptions) ([]map[string]any, error) {
+	if m.GetManyWithOptionsFn != nil {
+		return m.GetManyWithOptionsFn(collName, filter, findOpts)
+	}
+	return nil, nil
+}
+
 // RestfulAPIGetOne implements the mock version of GetOne
 func (m *MockDBClient) RestfulAPIGetOne(collName string, filter bson.M) (map[string]any, error) {
 	if m.GetOneFn != nil {
@@ -205,6 +220,30 @@ func (m *MockDBClient) RestfulAPIPullOneWithContext(ctx context.Context, collNam
 	return nil
 }
 
+// RestfulAPIAddToSetOne implements the mock version of AddToSetOne
+func (m *MockDBClient) RestfulAPIAddToSetOne(collName string, filter bson.M, addData map[string]any) error {
+	if m.AddToSetOneFn != nil {
+		return m.AddToSetOneFn(collName, filter, addData)
+	}
+	return nil
+}
+
+// RestfulAPIUpdateOne implements the mock version of UpdateOne
+func (m *MockDBClient) RestfulAPIUpdateOne(collName string, filter bson.M, setData map[string]any) (bool, error) {
+	if m.UpdateOneFn != nil {
+		return m.UpdateOneFn(collName, filter, setData)
+	}
+	return true, nil
+}
+
+// RestfulAPIIncrementOne implements the mock version of IncrementOne
+func (m *MockDBClient) RestfulAPIIncrementOne(collName string, filter bson.M, incData map[string]any, setData map[string]any) (map[string]any, error) {
+	if m.IncrementOneFn != nil {
+		return m.IncrementOneFn(collName, filter, incData, setData)
+	}
+	return nil, nil
+}
+
 // CreateIndex implements the mock version of CreateIndex
 func (m *MockDBClient) CreateIndex(collName string, keyField string) (bool, error) {
 	if m.CreateIndexFn != nil {
@@ -213,6 +252,22 @@ func (m *MockDBClient) CreateIndex(collName string, keyField string) (bool, erro
 	return true, nil
 }
 
+// CreateTTLIndex implements the mock version of CreateTTLIndex
+func (m *MockDBClient) CreateTTLIndex(collName string, timeField string, expireAfter time.Duration) (bool, error) {
+	if m.CreateTTLIndexFn != nil {
+		return m.CreateTTLIndexFn(collName, timeField, expireAfter)
+	}
+	return true, nil
+}
+
+// CreateQueryIndex implements the mock version of CreateQueryIndex
+func (m *MockDBClient) CreateQueryIndex(collName string, keys bson.D) (bool, error) {
+	if m.CreateQueryIndexFn != nil {
+		return m.CreateQueryIndexFn(collName, keys)
+	}
+	return true, nil
+}
+
 // StartSession implements the mock version of StartSession
 func (m *MockDBClient) StartSession() (mongo.Session, error) {
 	if m.StartSessionFn != nil {
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/omec-project/webconsole/backend/factory"
 	"github.com/omec-project/webconsole/backend/logger"
@@ -21,9 +22,11 @@ import (
 )
 
 var (
-	initMongoDB       = dbadapter.InitMongoDB
-	newNFConfigServer = nfconfig.NewNFConfigServer
-	runServer         = runWebUIAndNFConfig
+	initMongoDB             = dbadapter.InitMongoDB
+	newNFConfigServer       = nfconfig.NewNFConfigServer
+	newCachedNFConfigServer = nfconfig.NewCachedNFConfigServer
+	runServer               = runWebUIAndNFConfig
+	mongoDBRetryInterval    = 10 * time.Second
 )
 
 func main() {
@@ -68,11 +71,17 @@ func startApplication(config *factory.Config) error {
 	if config == nil || config.Configuration == nil {
 		return fmt.Errorf("configuration section is nil")
 	}
+	var webui webui_service.WebUIInterface = &webui_service.WEBUI{}
 	if err := initMongoDB(); err != nil {
 		logger.InitLog.Errorf("failed to initialize MongoDB: %v", err)
-		return err
+		// serve the cached NF configuration while MongoDB is retried
+		nfConfigServer, cacheErr := newCachedNFConfigServer(config)
+		if cacheErr != nil {
+			return fmt.Errorf("failed to initialize MongoDB: %w (NFConfig cache: %w)", err, cacheErr)
+		}
+		logger.InitLog.Warnln("NFConfig started from cache, WebUI waits for MongoDB")
+		return runServer(&mongoDBWaitingWebUI{webui: webui}, nfConfigServer)
 	}
-	webui := &webui_service.WEBUI{}
 	nfConfigServer, err := newNFConfigServer(config)
 	if err != nil {
 		return fmt.Errorf("failed to initialize NFConfig: %w", err)
@@ -81,6 +90,34 @@ func startApplication(config *factory.Config) error {
 	return runServer(webui, nfConfigServer)
 }
 
+// mongoDBWaitingWebUI retries the MongoDB initialization before starting the
+// WebUI, and then triggers the NFConfig sync that replaces the cached
+// configuration.
+type mongoDBWaitingWebUI struct {
+	webui webui_service.WebUIInterface
+}
+
+func (w *mongoDBWaitingWebUI) Start(ctx context.Context, syncChan chan<- struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(mongoDBRetryInterval):
+		}
+		err := initMongoDB()
+		if err == nil {
+			break
+		}
+		logger.InitLog.Errorf("failed to initialize MongoDB, retrying in %s: %v", mongoDBRetryInterval, err)
+	}
+	logger.InitLog.Infoln("MongoDB initialized, starting WebUI")
+	select {
+	case syncChan <- struct{}{}:
+	default:
+	}
+	w.webui.Start(ctx, syncChan)
+}
+
 func runWebUIAndNFConfig(webui webui_service.WebUIInterface, nfConf nfconfig.NFConfigInterface) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -130,11 +130,15 @@ func TestMainValidateCLIFlags(t *testing.T) {
 func TestStartApplication(t *testing.T) {
 	originalInit := initMongoDB
 	originalNewNF := newNFConfigServer
+	originalNewCachedNF := newCachedNFConfigServer
 	originalRun := runServer
+	originalRetryInterval := mongoDBRetryInterval
 	defer func() {
 		initMongoDB = originalInit
 		newNFConfigServer = originalNewNF
+		newCachedNFConfigServer = originalNewCachedNF
 		runServer = originalRun
+		mongoDBRetryInterval = originalRetryInterval
 	}()
 
 	t.Run("nil config", func(t *testing.T) {
@@ -148,12 +152,60 @@ func TestStartApplication(t *testing.T) {
 		initMongoDB = func() error {
 			return fmt.Errorf("mongo failed")
 		}
+		newCachedNFConfigServer = func(config *factory.Config) (nfconfig.NFConfigInterface, error) {
+			return nil, fmt.Errorf("no cache")
+		}
 		err := startApplication(&factory.Config{Configuration: &factory.Configuration{}})
-		if err == nil || !strings.Contains(err.Error(), "mongo failed") {
+		if err == nil || !strings.Contains(err.Error(), "mongo failed") || !strings.Contains(err.Error(), "no cache") {
 			t.Errorf("expected mongo init error, got: %v", err)
 		}
 	})
 
+	t.Run("mongo unreachable at startup", func(t *testing.T) {
+		attempts := 0
+		initMongoDB = func() error {
+			attempts++
+			if attempts < 3 {
+				return fmt.Errorf("mongo failed")
+			}
+			return nil
+		}
+		mongoDBRetryInterval = time.Millisecond
+		newNFConfigServer = func(config *factory.Config) (nfconfig.NFConfigInterface, error) {
+			t.Error("expected the NFConfig server to start from the cache")
+			return nil, fmt.Errorf("unexpected")
+		}
+		newCachedNFConfigServer = func(config *factory.Config) (nfconfig.NFConfigInterface, error) {
+			return &mockNFConfig{}, nil
+		}
+		webui := &mockWebUI{}
+		syncChan := make(chan struct{}, 1)
+		runServer = func(w webui_service.WebUIInterface, nf nfconfig.NFConfigInterface) error {
+			if attempts != 1 {
+				t.Errorf("expected the servers to run before MongoDB is retried, got %d attempts", attempts)
+			}
+			waiting, ok := w.(*mongoDBWaitingWebUI)
+			if !ok {
+				t.Fatalf("expected the WebUI to wait for MongoDB, got %T", w)
+			}
+			waiting.webui = webui
+			waiting.Start(context.Background(), syncChan)
+			return nil
+		}
+		err := startApplication(&factory.Config{Configuration: &factory.Configuration{}})
+		if err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+		if attempts != 3 || !webui.started {
+			t.Errorf("expected the WebUI to start once MongoDB is up, got %d attempts, started %v", attempts, webui.started)
+		}
+		select {
+		case <-syncChan:
+		default:
+			t.Error("expected an NFConfig sync to be triggered once MongoDB is up")
+		}
+	})
+
 	t.Run("nfconfig init failure", func(t *testing.T) {
 		initMongoDB = func() error { return nil }
 		newNFConfigServer = func(config *factory.Config) (nfconfig.NFConfigInterface, error) {